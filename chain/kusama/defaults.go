@@ -78,7 +78,7 @@
 		"system", "author", "chain",
 		"state", "rpc", "grandpa",
 		"offchain", "childstate", "syncstate",
-		"payment",
+		"payment", "chainSpec",
 	}
 	// DefaultRPCWSPort rpc websocket port
 	DefaultRPCWSPort = uint32(8546)
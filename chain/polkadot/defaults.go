@@ -77,7 +77,8 @@
 	// DefaultRPCModules rpc modules
 	DefaultRPCModules = []string{
 		"system", "author", "chain", "state", "rpc",
-		"grandpa", "offchain", "childstate", "syncstate", "payment"}
+		"grandpa", "offchain", "childstate", "syncstate", "payment",
+		"chainSpec"}
 	// DefaultRPCWSPort rpc websocket port
 	DefaultRPCWSPort = uint32(8546)
 )
@@ -5,6 +5,7 @@
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/ChainSafe/gossamer/lib/crypto"
@@ -17,6 +18,7 @@
 // accountAction executes the action for the "account" subcommand
 // first, if the generate flag is set, if so, it generates a new keypair
 // then, if the import flag is set, if so, it imports a keypair
+// then, if the migrate flag is set, it migrates the key files to the polkadot.js format
 // finally, if the list flag is set, it lists all the keys in the keystore
 func accountAction(ctx *cli.Context) error {
 	// create dot configuration
@@ -64,6 +66,15 @@ func accountAction(ctx *cli.Context) error {
 		}
 	}
 
+	// check if --migrate is set
+	if migrate := ctx.Bool(MigrateFlag.Name); migrate {
+		err = migrateKeystore(basepath, getKeystorePassword(ctx))
+		if err != nil {
+			logger.Errorf("failed to migrate keystore: %s", err)
+			return err
+		}
+	}
+
 	// check if --list is set
 	if keylist := ctx.Bool(ListFlag.Name); keylist {
 		_, err = utils.KeystoreFilepaths(basepath)
@@ -87,6 +98,33 @@ func accountAction(ctx *cli.Context) error {
 	return nil
 }
 
+// migrateKeystore rewrites the key files of the keystore in the legacy gossamer format
+// in the format of polkadot.js, the keys are decrypted and encrypted with the password
+func migrateKeystore(basepath string, password []byte) error {
+	keyDir, err := utils.KeystoreDir(basepath)
+	if err != nil {
+		return err
+	}
+
+	files, err := utils.KeystoreFiles(basepath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		migrated, err := keystore.MigrateKeyFile(filepath.Join(keyDir, file), password)
+		if err != nil {
+			return fmt.Errorf("failed to migrate key file %s: %w", file, err)
+		}
+
+		if migrated {
+			logger.Info("migrated key file " + file)
+		}
+	}
+
+	return nil
+}
+
 // getKeystorePassword checks if the --password flag is set, if not,
 func getKeystorePassword(ctx *cli.Context) []byte {
 	// check if --password is set
@@ -4,9 +4,17 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/utils"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -156,6 +164,45 @@ func TestAccountImportRaw(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestAccountMigrate test "gossamer account --migrate --password"
+func TestAccountMigrate(t *testing.T) {
+	testDir := t.TempDir()
+	directory := fmt.Sprintf("--basepath=%s", testDir)
+
+	kp, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	ciphertext, err := keystore.EncryptPrivateKey(kp.Private(), []byte("1234"))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(&keystore.EncryptedKeystore{
+		Type:       crypto.Ed25519Type,
+		PublicKey:  kp.Public().Hex(),
+		Ciphertext: ciphertext,
+	})
+	require.NoError(t, err)
+
+	keyDir, err := utils.KeystoreDir(testDir)
+	require.NoError(t, err)
+	keyFile := filepath.Join(keyDir, kp.Public().Hex()[2:]+".key")
+	err = os.WriteFile(keyFile, data, 0600)
+	require.NoError(t, err)
+
+	err = app.Run([]string{"irrelevant", "account", directory, "--migrate", "--password=1234"})
+	require.NoError(t, err)
+
+	data, err = os.ReadFile(keyFile)
+	require.NoError(t, err)
+
+	migrated := new(keystore.KeyFile)
+	err = json.Unmarshal(data, migrated)
+	require.NoError(t, err)
+
+	priv, err := migrated.Decrypt([]byte("1234"))
+	require.NoError(t, err)
+	require.Equal(t, kp.Private().Encode(), priv.Encode())
+}
+
 // TestAccountList test "gossamer account --list"
 func TestAccountList(t *testing.T) {
 	testDir := t.TempDir()
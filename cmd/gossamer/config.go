@@ -143,6 +143,8 @@ func createDotConfig(ctx *cli.Context) (*dot.Config, error) {
 		cfg.State.Rewind = rewind
 	}
 
+	cfg.State.TransactionIndexRetention = uint32(ctx.GlobalUint(TransactionIndexRetentionFlag.Name))
+
 	// set system info
 	setSystemInfoConfig(ctx, cfg)
 
@@ -560,6 +562,10 @@ func setDotAccountConfig(ctx *cli.Context, tomlCfg ctoml.AccountConfig, cfg *dot
 		cfg.Unlock = tomlCfg.Unlock
 	}
 
+	if tomlCfg.Signer != "" {
+		cfg.Signer = tomlCfg.Signer
+	}
+
 	// check --key flag and update node configuration
 	if key := ctx.GlobalString(KeyFlag.Name); key != "" {
 		cfg.Key = key
@@ -570,8 +576,13 @@ func setDotAccountConfig(ctx *cli.Context, tomlCfg ctoml.AccountConfig, cfg *dot
 		cfg.Unlock = unlock
 	}
 
+	// check --signer flag and update node configuration
+	if signer := ctx.GlobalString(SignerFlag.Name); signer != "" {
+		cfg.Signer = signer
+	}
+
 	logger.Debug("account configuration has key " + cfg.Key +
-		" and unlock " + cfg.Unlock)
+		", unlock " + cfg.Unlock + " and signer " + cfg.Signer)
 }
 
 // setDotCoreConfig sets dot.CoreConfig using flag values from the cli context
@@ -586,11 +597,27 @@ func setDotCoreConfig(ctx *cli.Context, tomlCfg ctoml.CoreConfig, cfg *dot.CoreC
 		cfg.BABELead = ctx.GlobalBool(BABELeadFlag.Name)
 	}
 
+	// check --warp-sync flag and update node configuration
+	cfg.WarpSync = tomlCfg.WarpSync
+	if warpSync := ctx.GlobalBool(WarpSyncFlag.Name); warpSync {
+		cfg.WarpSync = true
+	}
+
+	// check --runtime-pool-size flag and update node configuration
+	cfg.RuntimePoolSize = tomlCfg.RuntimePoolSize
+	if poolSize := ctx.GlobalInt(RuntimePoolSizeFlag.Name); poolSize > 0 {
+		cfg.RuntimePoolSize = poolSize
+	}
+
+	// check --runtime-cache-size flag and update node configuration
+	cfg.RuntimeCacheSize = tomlCfg.RuntimeCacheSize
+	if ctx.IsSet(RuntimeCacheSizeFlag.Name) {
+		cfg.RuntimeCacheSize = ctx.GlobalInt(RuntimeCacheSizeFlag.Name)
+	}
+
 	// check --roles flag and update node configuration
 	if roles := ctx.GlobalString(RolesFlag.Name); roles != "" {
-		// convert string to byte
-		n, err := strconv.Atoi(roles)
-		b := byte(n)
+		b, err := parseRoles(roles)
 		if err != nil {
 			logger.Errorf("failed to convert Roles to byte: %s", err)
 		} else if b == types.AuthorityRole {
@@ -635,8 +662,30 @@ func setDotCoreConfig(ctx *cli.Context, tomlCfg ctoml.CoreConfig, cfg *dot.CoreC
 	}
 
 	logger.Debugf(
-		"core configuration: babe-authority=%t, grandpa-authority=%t wasm-interpreter=%s grandpa-interval=%s",
-		cfg.BabeAuthority, cfg.GrandpaAuthority, cfg.WasmInterpreter, cfg.GrandpaInterval)
+		"core configuration: babe-authority=%t, grandpa-authority=%t wasm-interpreter=%s grandpa-interval=%s "+
+			"warp-sync=%t runtime-pool-size=%d runtime-cache-size=%d",
+		cfg.BabeAuthority, cfg.GrandpaAuthority, cfg.WasmInterpreter, cfg.GrandpaInterval, cfg.WarpSync,
+		cfg.RuntimePoolSize, cfg.RuntimeCacheSize)
+}
+
+// parseRoles parses the --roles flag value, which is either the name of a
+// role (full, light or authority) or the roles byte (see Table D.2)
+func parseRoles(roles string) (byte, error) {
+	switch roles {
+	case "full":
+		return types.FullNodeRole, nil
+	case "light":
+		return types.LightClientRole, nil
+	case "authority":
+		return types.AuthorityRole, nil
+	}
+
+	n, err := strconv.Atoi(roles)
+	if err != nil {
+		return 0, err
+	}
+
+	return byte(n), nil
 }
 
 // setDotNetworkConfig sets dot.NetworkConfig using flag values from the cli context
@@ -649,6 +698,7 @@ func setDotNetworkConfig(ctx *cli.Context, tomlCfg ctoml.NetworkConfig, cfg *dot
 	cfg.MinPeers = tomlCfg.MinPeers
 	cfg.MaxPeers = tomlCfg.MaxPeers
 	cfg.PersistentPeers = tomlCfg.PersistentPeers
+	cfg.ReservedOnly = tomlCfg.ReservedOnly
 	cfg.DiscoveryInterval = time.Second * time.Duration(tomlCfg.DiscoveryInterval)
 
 	// check --port flag and update node configuration
@@ -681,6 +731,11 @@ func setDotNetworkConfig(ctx *cli.Context, tomlCfg ctoml.NetworkConfig, cfg *dot
 		cfg.NoMDNS = true
 	}
 
+	// check --reserved-only flag and update node configuration
+	if reservedOnly := ctx.GlobalBool(ReservedOnlyFlag.Name); reservedOnly {
+		cfg.ReservedOnly = true
+	}
+
 	// check --pubip flag and update node configuration
 	if pubip := ctx.GlobalString(PublicIPFlag.Name); pubip != "" {
 		cfg.PublicIP = pubip
@@ -698,10 +753,10 @@ func setDotNetworkConfig(ctx *cli.Context, tomlCfg ctoml.NetworkConfig, cfg *dot
 	logger.Debugf(
 		"network configuration: port=%d bootnodes=%s protocol=%s nobootstrap=%t "+
 			"nomdns=%t minpeers=%d maxpeers=%d persistent-peers=%s "+
-			"discovery-interval=%s",
+			"reserved-only=%t discovery-interval=%s",
 		cfg.Port, strings.Join(cfg.Bootnodes, ","), cfg.ProtocolID, cfg.NoBootstrap,
 		cfg.NoMDNS, cfg.MinPeers, cfg.MaxPeers, strings.Join(cfg.PersistentPeers, ","),
-		cfg.DiscoveryInterval,
+		cfg.ReservedOnly, cfg.DiscoveryInterval,
 	)
 }
 
@@ -719,6 +774,11 @@ func setDotRPCConfig(ctx *cli.Context, tomlCfg ctoml.RPCConfig, cfg *dot.RPCConf
 	cfg.WSExternal = tomlCfg.WSExternal
 	cfg.WSUnsafe = tomlCfg.WSUnsafe
 	cfg.WSUnsafeExternal = tomlCfg.WSUnsafeExternal
+	cfg.MaxQueryStorageRange = tomlCfg.MaxQueryStorageRange
+	cfg.CORS = tomlCfg.CORS
+	cfg.RateLimit = tomlCfg.RateLimit
+	cfg.MethodRateLimits = tomlCfg.MethodRateLimits
+	cfg.MaxResponseSize = tomlCfg.MaxResponseSize
 
 	// check --rpc flag and update node configuration
 	if enabled := ctx.GlobalBool(RPCEnabledFlag.Name); enabled || cfg.Enabled {
@@ -769,6 +829,35 @@ func setDotRPCConfig(ctx *cli.Context, tomlCfg ctoml.RPCConfig, cfg *dot.RPCConf
 		cfg.WSExternal = false
 	}
 
+	if maxRange := ctx.GlobalUint(RPCMaxQueryStorageRangeFlag.Name); maxRange != 0 {
+		cfg.MaxQueryStorageRange = uint32(maxRange)
+	}
+
+	// check --rpc-cors flag and update node configuration
+	if cors := ctx.GlobalString(RPCCorsFlag.Name); cors != "" {
+		cfg.CORS = strings.Split(cors, ",")
+	}
+
+	// check --rpc-rate-limit flag and update node configuration
+	if limit := ctx.GlobalUint(RPCRateLimitFlag.Name); limit != 0 {
+		cfg.RateLimit = uint32(limit)
+	}
+
+	// check --rpc-method-rate-limits flag and update node configuration
+	if limits := ctx.GlobalString(RPCMethodRateLimitsFlag.Name); limits != "" {
+		methodLimits, err := parseMethodRateLimits(limits)
+		if err != nil {
+			logger.Errorf("failed to parse %s: %s", RPCMethodRateLimitsFlag.Name, err)
+		} else {
+			cfg.MethodRateLimits = methodLimits
+		}
+	}
+
+	// check --rpc-max-response-size flag and update node configuration
+	if size := ctx.GlobalUint(RPCMaxResponseSizeFlag.Name); size != 0 {
+		cfg.MaxResponseSize = uint32(size)
+	}
+
 	// format rpc modules
 	if len(cfg.Modules) == 0 {
 		cfg.Modules = []string(nil)
@@ -777,6 +866,27 @@ func setDotRPCConfig(ctx *cli.Context, tomlCfg ctoml.RPCConfig, cfg *dot.RPCConf
 	logger.Debugf("rpc configuration: %s", cfg)
 }
 
+// parseMethodRateLimits parses the --rpc-method-rate-limits flag value, which is a comma
+// separated list of method=limit
+func parseMethodRateLimits(limits string) (map[string]uint32, error) {
+	methodLimits := make(map[string]uint32)
+	for _, methodLimit := range strings.Split(limits, ",") {
+		splits := strings.Split(methodLimit, "=")
+		if len(splits) != 2 || splits[0] == "" {
+			return nil, fmt.Errorf("%q must be in the format method=limit", methodLimit)
+		}
+
+		limit, err := strconv.ParseUint(splits[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit for method %s: %w", splits[0], err)
+		}
+
+		methodLimits[splits[0]] = uint32(limit)
+	}
+
+	return methodLimits, nil
+}
+
 func setSystemInfoConfig(ctx *cli.Context, cfg *dot.Config) {
 	// load system information
 	if ctx.App != nil {
@@ -789,6 +899,7 @@ func setSystemInfoConfig(ctx *cli.Context, cfg *dot.Config) {
 func updateDotConfigFromGenesisJSONRaw(tomlCfg ctoml.Config, cfg *dot.Config) {
 	cfg.Account.Key = tomlCfg.Account.Key
 	cfg.Account.Unlock = tomlCfg.Account.Unlock
+	cfg.Account.Signer = tomlCfg.Account.Signer
 	cfg.Core.Roles = tomlCfg.Core.Roles
 	cfg.Core.BabeAuthority = tomlCfg.Core.Roles == types.AuthorityRole
 	cfg.Core.GrandpaAuthority = tomlCfg.Core.Roles == types.AuthorityRole
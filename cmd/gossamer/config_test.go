@@ -352,6 +352,16 @@ func TestAccountConfigFromFlags(t *testing.T) {
 				Unlock: "0",
 			},
 		},
+		{
+			"Test gossamer --signer",
+			[]string{"config", "signer"},
+			[]interface{}{testCfgFile.Name(), "unix:///tmp/signer.sock"},
+			dot.AccountConfig{
+				Key:    testCfg.Account.Key,
+				Unlock: testCfg.Account.Unlock,
+				Signer: "unix:///tmp/signer.sock",
+			},
+		},
 	}
 
 	for _, c := range testcases {
@@ -405,6 +415,19 @@ func TestCoreConfigFromFlags(t *testing.T) {
 				GrandpaInterval:  testCfg.Core.GrandpaInterval,
 			},
 		},
+		{
+			"Test gossamer --warp-sync",
+			[]string{"config", "warp-sync"},
+			[]interface{}{testCfgFile.Name(), true},
+			dot.CoreConfig{
+				Roles:            testCfg.Core.Roles,
+				BabeAuthority:    true,
+				GrandpaAuthority: true,
+				WasmInterpreter:  gssmr.DefaultWasmInterpreter,
+				GrandpaInterval:  testCfg.Core.GrandpaInterval,
+				WarpSync:         true,
+			},
+		},
 	}
 
 	for _, c := range testcases {
@@ -509,6 +532,22 @@ func TestNetworkConfigFromFlags(t *testing.T) {
 				MaxPeers:          testCfg.Network.MaxPeers,
 			},
 		},
+		{
+			"Test gossamer --reserved-only",
+			[]string{"config", "reserved-only"},
+			[]interface{}{testCfgFile.Name(), "true"},
+			dot.NetworkConfig{
+				Port:              testCfg.Network.Port,
+				Bootnodes:         testCfg.Network.Bootnodes,
+				ProtocolID:        testCfg.Network.ProtocolID,
+				NoBootstrap:       testCfg.Network.NoBootstrap,
+				NoMDNS:            false,
+				DiscoveryInterval: time.Second * 10,
+				MinPeers:          testCfg.Network.MinPeers,
+				MaxPeers:          testCfg.Network.MaxPeers,
+				ReservedOnly:      true,
+			},
+		},
 		{
 			"Test gossamer --pubip",
 			[]string{"config", "pubip"},
@@ -750,6 +789,40 @@ func TestRPCConfigFromFlags(t *testing.T) {
 				WSExternal: false,
 			},
 		},
+		{
+			"Test gossamer --rpc-cors",
+			[]string{"config", "rpc-cors"},
+			[]interface{}{testCfgFile.Name(), "https://polkadot.js.org,http://localhost:3000"},
+			dot.RPCConfig{
+				Enabled:    testCfg.RPC.Enabled,
+				External:   testCfg.RPC.External,
+				Port:       testCfg.RPC.Port,
+				Host:       testCfg.RPC.Host,
+				Modules:    testCfg.RPC.Modules,
+				WSPort:     testCfg.RPC.WSPort,
+				WS:         testCfg.RPC.WS,
+				WSExternal: testCfg.RPC.WSExternal,
+				CORS:       []string{"https://polkadot.js.org", "http://localhost:3000"},
+			},
+		},
+		{
+			"Test gossamer --rpc-rate-limit --rpc-method-rate-limits --rpc-max-response-size",
+			[]string{"config", "rpc-rate-limit", "rpc-method-rate-limits", "rpc-max-response-size"},
+			[]interface{}{testCfgFile.Name(), uint(100), "state_getStorage=10,chain_getBlock=5", uint(15)},
+			dot.RPCConfig{
+				Enabled:          testCfg.RPC.Enabled,
+				External:         testCfg.RPC.External,
+				Port:             testCfg.RPC.Port,
+				Host:             testCfg.RPC.Host,
+				Modules:          testCfg.RPC.Modules,
+				WSPort:           testCfg.RPC.WSPort,
+				WS:               testCfg.RPC.WS,
+				WSExternal:       testCfg.RPC.WSExternal,
+				RateLimit:        100,
+				MethodRateLimits: map[string]uint32{"state_getStorage": 10, "chain_getBlock": 5},
+				MaxResponseSize:  15,
+			},
+		},
 	}
 
 	for _, c := range testcases {
@@ -1211,6 +1284,53 @@ func Test_parseLogLevelString(t *testing.T) {
 	}
 }
 
+func Test_parseRoles(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		roles    string
+		expected byte
+		err      error
+	}{
+		"full": {
+			roles:    "full",
+			expected: types.FullNodeRole,
+		},
+		"light": {
+			roles:    "light",
+			expected: types.LightClientRole,
+		},
+		"authority": {
+			roles:    "authority",
+			expected: types.AuthorityRole,
+		},
+		"integer": {
+			roles:    "2",
+			expected: types.LightClientRole,
+		},
+		"invalid": {
+			roles: "archive",
+			err:   errors.New(`strconv.Atoi: parsing "archive": invalid syntax`),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			roles, err := parseRoles(testCase.roles)
+
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.expected, roles)
+		})
+	}
+}
+
 func Test_setLogConfig(t *testing.T) {
 	t.Parallel()
 
@@ -1302,3 +1422,15 @@ func Test_setLogConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMethodRateLimits(t *testing.T) {
+	limits, err := parseMethodRateLimits("state_getStorage=10,chain_getBlock=5")
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint32{"state_getStorage": 10, "chain_getBlock": 5}, limits)
+
+	_, err = parseMethodRateLimits("state_getStorage")
+	require.EqualError(t, err, `"state_getStorage" must be in the format method=limit`)
+
+	_, err = parseMethodRateLimits("state_getStorage=noot")
+	require.EqualError(t, err, `invalid limit for method state_getStorage: strconv.ParseUint: parsing "noot": invalid syntax`)
+}
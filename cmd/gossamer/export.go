@@ -101,6 +101,9 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 		BabeAuthority:    dcfg.Core.BabeAuthority,
 		GrandpaAuthority: dcfg.Core.GrandpaAuthority,
 		GrandpaInterval:  uint32(dcfg.Core.GrandpaInterval / time.Second),
+		WarpSync:         dcfg.Core.WarpSync,
+		RuntimePoolSize:  dcfg.Core.RuntimePoolSize,
+		RuntimeCacheSize: dcfg.Core.RuntimeCacheSize,
 	}
 
 	cfg.Network = ctoml.NetworkConfig{
@@ -112,6 +115,7 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 		DiscoveryInterval: int(dcfg.Network.DiscoveryInterval / time.Second),
 		MinPeers:          dcfg.Network.MinPeers,
 		MaxPeers:          dcfg.Network.MaxPeers,
+		ReservedOnly:      dcfg.Network.ReservedOnly,
 	}
 
 	cfg.RPC = ctoml.RPCConfig{
@@ -123,6 +127,12 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 		WSPort:     dcfg.RPC.WSPort,
 		WS:         dcfg.RPC.WS,
 		WSExternal: dcfg.RPC.WSExternal,
+
+		MaxQueryStorageRange: dcfg.RPC.MaxQueryStorageRange,
+		CORS:                 dcfg.RPC.CORS,
+		RateLimit:            dcfg.RPC.RateLimit,
+		MethodRateLimits:     dcfg.RPC.MethodRateLimits,
+		MaxResponseSize:      dcfg.RPC.MaxResponseSize,
 	}
 
 	return cfg
@@ -27,16 +27,27 @@
 		Name:  "key",
 		Usage: "Specify a test keyring account to use: eg --key=alice",
 	}
+	// SignerFlag is the endpoint of a remote signer holding the authority keys
+	SignerFlag = cli.StringFlag{
+		Name: "signer",
+		Usage: "Endpoint of a remote signer holding the BABE and GRANDPA keys, " +
+			"eg. --signer=unix:///path/to/signer.sock or --signer=http://localhost:8600",
+	}
 	// RolesFlag role of the node (see Table D.2)
 	RolesFlag = cli.StringFlag{
 		Name:  "roles",
-		Usage: "Roles of the gossamer node",
+		Usage: "Roles of the gossamer node: full (1), light (2) or authority (4)",
 	}
 	// RewindFlag rewinds the head of the chain to the given block number. Useful for development
 	RewindFlag = cli.IntFlag{
 		Name:  "rewind",
 		Usage: "Rewind head of chain to the given block number",
 	}
+	// TransactionIndexRetentionFlag sets the number of finalised blocks for which the indexed transactions are kept
+	TransactionIndexRetentionFlag = cli.UintFlag{
+		Name:  "transaction-index-retention",
+		Usage: "Number of finalised blocks for which the indexed transactions are kept, 0 keeps them forever",
+	}
 )
 
 // Global node configuration flags
@@ -179,6 +190,20 @@
 	}
 )
 
+// TraceBlock-only flags
+var (
+	// BlockFlag is the hash of the block to trace
+	BlockFlag = cli.StringFlag{
+		Name:  "block",
+		Usage: "Hash of the block to trace",
+	}
+	// SlashingProtectionFileFlag is the path of the file of the slashing protection database export
+	SlashingProtectionFileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "Path to the JSON file of the exported slashing protection database",
+	}
+)
+
 // BuildSpec-only flags
 var (
 	RawFlag = cli.BoolFlag{
@@ -222,6 +247,11 @@
 		Name:  "nomdns",
 		Usage: "Disables network mDNS discovery",
 	}
+	// ReservedOnlyFlag only connects to and accepts connections from reserved peers
+	ReservedOnlyFlag = cli.BoolFlag{
+		Name:  "reserved-only",
+		Usage: "Only connect to and accept connections from reserved peers",
+	}
 	// PublicIPFlag uses the supplied IP for broadcasting
 	PublicIPFlag = cli.StringFlag{
 		Name:  "pubip",
@@ -271,6 +301,32 @@
 		Name:  "rpcmods",
 		Usage: "API modules to enable via HTTP-RPC, comma separated list",
 	}
+	// RPCMaxQueryStorageRangeFlag maximum number of blocks state_queryStorage can query
+	RPCMaxQueryStorageRangeFlag = cli.UintFlag{
+		Name:  "rpc-max-query-storage-range",
+		Usage: "Maximum number of blocks a state_queryStorage call can span",
+	}
+	// RPCCorsFlag origins allowed to access the HTTP-RPC and websockets servers
+	RPCCorsFlag = cli.StringFlag{
+		Name:  "rpc-cors",
+		Usage: "Origins allowed to access the HTTP-RPC and websockets servers, comma separated list, or 'all'",
+	}
+	// RPCRateLimitFlag maximum number of calls per second of each client
+	RPCRateLimitFlag = cli.UintFlag{
+		Name:  "rpc-rate-limit",
+		Usage: "Maximum number of RPC calls per second of each client IP address, 0 means no limit",
+	}
+	// RPCMethodRateLimitsFlag maximum number of calls per second of each client to each method
+	RPCMethodRateLimitsFlag = cli.StringFlag{
+		Name: "rpc-method-rate-limits",
+		Usage: "Maximum number of calls per second of each client IP address to each RPC method, " +
+			"comma separated list of method=limit",
+	}
+	// RPCMaxResponseSizeFlag maximum size of the response to a call
+	RPCMaxResponseSizeFlag = cli.UintFlag{
+		Name:  "rpc-max-response-size",
+		Usage: "Maximum size in MB of the response to an RPC call, 0 means no limit",
+	}
 	// WSPortFlag WebSocket server listening port
 	WSPortFlag = cli.IntFlag{
 		Name:  "wsport",
@@ -313,13 +369,18 @@
 	// ImportFlag Import encrypted keystore
 	ImportFlag = cli.StringFlag{
 		Name:  "import",
-		Usage: "Import encrypted keystore file generated with gossamer",
+		Usage: "Import encrypted keystore file generated with gossamer or exported from polkadot.js",
 	}
 	// ImportRawFlag imports a raw private key
 	ImportRawFlag = cli.StringFlag{
 		Name:  "import-raw",
 		Usage: "Import  a raw private key",
 	}
+	// MigrateFlag Migrate the keystore files to the polkadot.js format
+	MigrateFlag = cli.BoolFlag{
+		Name:  "migrate",
+		Usage: "Migrate the keystore files of the legacy gossamer format to the polkadot.js format. Used with --password",
+	}
 	// ListFlag List node keys
 	ListFlag = cli.BoolFlag{
 		Name:  "list",
@@ -375,6 +436,29 @@
 	}
 )
 
+// sync flags
+var (
+	// WarpSyncFlag syncs a new node to the latest finalised block of the network using warp sync
+	WarpSyncFlag = cli.BoolFlag{
+		Name:  "warp-sync",
+		Usage: "Download the state of the latest finalised block, proven by GRANDPA authority set changes, instead of executing every block from genesis", //nolint:lll
+	}
+)
+
+// core flags
+var (
+	// RuntimePoolSizeFlag sets the number of instances of a runtime used for concurrent runtime calls
+	RuntimePoolSizeFlag = cli.IntFlag{
+		Name:  "runtime-pool-size",
+		Usage: "Number of instances of a runtime used for concurrent runtime calls",
+	}
+	// RuntimeCacheSizeFlag sets the number of compiled runtimes kept on disk
+	RuntimeCacheSizeFlag = cli.IntFlag{
+		Name:  "runtime-cache-size",
+		Usage: "Number of compiled runtimes kept on disk, a negative size disables the cache",
+	}
+)
+
 // BABE flags
 var (
 	BABELeadFlag = cli.BoolFlag{
@@ -415,6 +499,7 @@
 		// keystore flags
 		KeyFlag,
 		UnlockFlag,
+		SignerFlag,
 
 		// network flags
 		PortFlag,
@@ -423,6 +508,7 @@
 		RolesFlag,
 		NoBootstrapFlag,
 		NoMDNSFlag,
+		ReservedOnlyFlag,
 		PublicIPFlag,
 		PublicDNSFlag,
 
@@ -434,6 +520,11 @@
 		RPCHostFlag,
 		RPCPortFlag,
 		RPCModulesFlag,
+		RPCMaxQueryStorageRangeFlag,
+		RPCCorsFlag,
+		RPCRateLimitFlag,
+		RPCMethodRateLimitsFlag,
+		RPCMaxResponseSizeFlag,
 		WSFlag,
 		WSExternalFlag,
 		WSUnsafeFlag,
@@ -448,6 +539,16 @@
 		NoTelemetryFlag,
 		TelemetryURLFlag,
 
+		// sync flags
+		WarpSyncFlag,
+
+		// state flags
+		TransactionIndexRetentionFlag,
+
+		// core flags
+		RuntimePoolSizeFlag,
+		RuntimeCacheSizeFlag,
+
 		// BABE flags
 		BABELeadFlag,
 	}
@@ -484,6 +585,7 @@
 		PasswordFlag,
 		ImportFlag,
 		ImportRawFlag,
+		MigrateFlag,
 		ListFlag,
 		Ed25519Flag,
 		Sr25519Flag,
@@ -499,6 +601,20 @@
 		FirstSlotFlag,
 	}
 
+	TraceBlockFlags = []cli.Flag{
+		BasePathFlag,
+		ChainFlag,
+		ConfigFlag,
+		BlockFlag,
+	}
+
+	SlashingProtectionFlags = []cli.Flag{
+		BasePathFlag,
+		ChainFlag,
+		ConfigFlag,
+		SlashingProtectionFileFlag,
+	}
+
 	PruningFlags = []cli.Flag{
 		ChainFlag,
 		ConfigFlag,
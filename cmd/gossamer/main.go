@@ -4,6 +4,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -11,6 +12,7 @@
 	"github.com/ChainSafe/gossamer/dot"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/utils"
 	"github.com/urfave/cli"
@@ -24,6 +26,10 @@
 	importRuntimeCommandName = "import-runtime"
 	importStateCommandName   = "import-state"
 	pruningStateCommandName  = "prune-state"
+	traceBlockCommandName    = "trace-block"
+
+	exportSlashingProtectionCommandName = "export-slashing-protection"
+	importSlashingProtectionCommandName = "import-slashing-protection"
 )
 
 // app is the cli application
@@ -67,6 +73,7 @@
 			"\tTo generate a new ed25519 account: gossamer account --generate --ed25519\n" +
 			"\tTo generate a new secp256k1 account: gossamer account --generate --secp256k1\n" +
 			"\tTo import a keystore file: gossamer account --import=path/to/file\n" +
+			"\tTo migrate the keystore files to the polkadot.js format: gossamer account --migrate\n" +
 			"\tTo list keys: gossamer account --list",
 	}
 	// buildSpecCommand creates a raw genesis file from a human readable genesis file.
@@ -121,6 +128,44 @@
 
 		The default pruning target is the HEAD-256 state`,
 	}
+
+	traceBlockCommand = cli.Command{
+		Action:    FixFlagOrder(traceBlockAction),
+		Name:      traceBlockCommandName,
+		Usage:     "Re-execute a stored block and print the runtime host function calls",
+		ArgsUsage: "",
+		Flags:     TraceBlockFlags,
+		Category:  "TRACE-BLOCK",
+		Description: "The trace-block command re-executes a block from the node database on top of its parent " +
+			"state, and prints the host function calls made by the runtime as JSON, along with the " +
+			"resulting state root and the execution error, if any.\n" +
+			"\tUsage: gossamer trace-block --chain <chain-name> --block <block hash>\n",
+	}
+
+	exportSlashingProtectionCommand = cli.Command{
+		Action:    FixFlagOrder(exportSlashingProtectionAction),
+		Name:      exportSlashingProtectionCommandName,
+		Usage:     "Export the blocks authored and votes signed by the node authorities to a JSON file",
+		ArgsUsage: "",
+		Flags:     SlashingProtectionFlags,
+		Category:  "SLASHING-PROTECTION",
+		Description: "The export-slashing-protection command exports the slashing protection database, " +
+			"to move the authorities of the node to another machine. The node must be stopped.\n" +
+			"\tUsage: gossamer export-slashing-protection --chain <chain-name> --file slashing.json\n",
+	}
+
+	importSlashingProtectionCommand = cli.Command{
+		Action:    FixFlagOrder(importSlashingProtectionAction),
+		Name:      importSlashingProtectionCommandName,
+		Usage:     "Import the blocks authored and votes signed by authorities on another machine from a JSON file",
+		ArgsUsage: "",
+		Flags:     SlashingProtectionFlags,
+		Category:  "SLASHING-PROTECTION",
+		Description: "The import-slashing-protection command imports a slashing protection database exported " +
+			"with export-slashing-protection, so that the node does not sign blocks or votes conflicting " +
+			"with the ones signed on the other machine. The node must be stopped.\n" +
+			"\tUsage: gossamer import-slashing-protection --chain <chain-name> --file slashing.json\n",
+	}
 )
 
 // init initialises the cli application
@@ -139,6 +184,9 @@ func init() {
 		importRuntimeCommand,
 		importStateCommand,
 		pruningCommand,
+		traceBlockCommand,
+		exportSlashingProtectionCommand,
+		importSlashingProtectionCommand,
 	}
 	app.Flags = RootFlags
 }
@@ -179,6 +227,89 @@ func importStateAction(ctx *cli.Context) error {
 	return dot.ImportState(cfg.Global.BasePath, stateFP, headerFP, uint64(firstSlot))
 }
 
+// traceBlockAction re-executes the given block with tracing enabled and prints the trace
+func traceBlockAction(ctx *cli.Context) error {
+	blockHash := ctx.String(BlockFlag.Name)
+	if blockHash == "" {
+		return errors.New("must provide argument to --block")
+	}
+
+	hash, err := common.HexToHash(blockHash)
+	if err != nil {
+		return fmt.Errorf("invalid block hash: %w", err)
+	}
+
+	cfg, err := createImportStateConfig(ctx)
+	if err != nil {
+		logger.Errorf("failed to create node configuration: %s", err)
+		return err
+	}
+	cfg.Global.BasePath = utils.ExpandDir(cfg.Global.BasePath)
+
+	trace, err := dot.TraceBlock(cfg, hash)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(trace, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
+
+// exportSlashingProtectionAction exports the slashing protection database to a JSON file
+func exportSlashingProtectionAction(ctx *cli.Context) error {
+	fp := ctx.String(SlashingProtectionFileFlag.Name)
+	if fp == "" {
+		return errors.New("must provide argument to --file")
+	}
+
+	cfg, err := createImportStateConfig(ctx)
+	if err != nil {
+		logger.Errorf("failed to create node configuration: %s", err)
+		return err
+	}
+	cfg.Global.BasePath = utils.ExpandDir(cfg.Global.BasePath)
+
+	data, err := dot.ExportSlashingProtection(cfg)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(fp, out, 0600)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("exported %d blocks and %d votes to %s", len(data.Blocks), len(data.Votes), fp)
+	return nil
+}
+
+// importSlashingProtectionAction imports a slashing protection database exported from another machine
+func importSlashingProtectionAction(ctx *cli.Context) error {
+	fp := ctx.String(SlashingProtectionFileFlag.Name)
+	if fp == "" {
+		return errors.New("must provide argument to --file")
+	}
+
+	cfg, err := createImportStateConfig(ctx)
+	if err != nil {
+		logger.Errorf("failed to create node configuration: %s", err)
+		return err
+	}
+	cfg.Global.BasePath = utils.ExpandDir(cfg.Global.BasePath)
+
+	return dot.ImportSlashingProtection(cfg, fp)
+}
+
 // importRuntimeAction generates a genesis file given a .wasm runtime binary.
 func importRuntimeAction(ctx *cli.Context) error {
 	arguments := ctx.Args()
@@ -87,6 +87,7 @@ type InitConfig struct {
 type AccountConfig struct {
 	Key    string
 	Unlock string // TODO: change to []int (#1849)
+	Signer string // endpoint of a remote signer holding the authority keys
 }
 
 // NetworkConfig is to marshal/unmarshal toml network config vars
@@ -99,6 +100,7 @@ type NetworkConfig struct {
 	MinPeers          int
 	MaxPeers          int
 	PersistentPeers   []string
+	ReservedOnly      bool
 	DiscoveryInterval time.Duration
 	PublicIP          string
 	PublicDNS         string
@@ -112,6 +114,11 @@ type CoreConfig struct {
 	GrandpaAuthority bool
 	WasmInterpreter  string
 	GrandpaInterval  time.Duration
+	WarpSync         bool
+	// RuntimePoolSize is the number of instances of a runtime used for concurrent runtime calls
+	RuntimePoolSize int
+	// RuntimeCacheSize is the number of compiled runtimes kept on disk, the cache is disabled if negative
+	RuntimeCacheSize int
 }
 
 // RPCConfig is to marshal/unmarshal toml RPC config vars
@@ -128,6 +135,17 @@ type RPCConfig struct {
 	WSExternal       bool
 	WSUnsafe         bool
 	WSUnsafeExternal bool
+	// MaxQueryStorageRange is the maximum number of blocks state_queryStorage can query,
+	// 0 means the rpc default is used
+	MaxQueryStorageRange uint32
+	// CORS is the list of origins allowed to access the servers, every origin is allowed if empty
+	CORS []string
+	// RateLimit is the maximum number of calls per second of each client IP address, 0 means no limit
+	RateLimit uint32
+	// MethodRateLimits is the maximum number of calls per second of each client IP address to each method
+	MethodRateLimits map[string]uint32
+	// MaxResponseSize is the maximum size in MB of the response to a call, 0 means no limit
+	MaxResponseSize uint32
 }
 
 func (r *RPCConfig) isRPCEnabled() bool {
@@ -153,12 +171,20 @@ func (r *RPCConfig) String() string {
 		"ws=" + fmt.Sprint(r.WS) + " " +
 		"wsexternal=" + fmt.Sprint(r.WSExternal) + " " +
 		"wsunsafe=" + fmt.Sprint(r.WSUnsafe) + " " +
-		"wsunsafeexternal=" + fmt.Sprint(r.WSUnsafeExternal)
+		"wsunsafeexternal=" + fmt.Sprint(r.WSUnsafeExternal) + " " +
+		"maxquerystoragerange=" + fmt.Sprint(r.MaxQueryStorageRange) + " " +
+		"cors=" + strings.Join(r.CORS, ",") + " " +
+		"ratelimit=" + fmt.Sprint(r.RateLimit) + " " +
+		"methodratelimits=" + fmt.Sprint(r.MethodRateLimits) + " " +
+		"maxresponsesize=" + fmt.Sprint(r.MaxResponseSize)
 }
 
 // StateConfig is the config for the State service
 type StateConfig struct {
 	Rewind int
+	// TransactionIndexRetention is the number of finalised blocks for which the
+	// indexed transactions are kept, they are kept forever if it is zero.
+	TransactionIndexRetention uint32
 }
 
 // networkServiceEnabled returns true if the network service is enabled
@@ -48,6 +48,7 @@ type InitConfig struct {
 type AccountConfig struct {
 	Key    string `toml:"key,omitempty"`
 	Unlock string `toml:"unlock,omitempty"`
+	Signer string `toml:"signer,omitempty"`
 }
 
 // NetworkConfig is to marshal/unmarshal toml network config vars
@@ -60,6 +61,7 @@ type NetworkConfig struct {
 	MinPeers          int      `toml:"min-peers,omitempty"`
 	MaxPeers          int      `toml:"max-peers,omitempty"`
 	PersistentPeers   []string `toml:"persistent-peers,omitempty"`
+	ReservedOnly      bool     `toml:"reserved-only,omitempty"`
 	DiscoveryInterval int      `toml:"discovery-interval,omitempty"`
 	PublicIP          string   `toml:"public-ip,omitempty"`
 	PublicDNS         string   `toml:"public-dns,omitempty"`
@@ -75,6 +77,9 @@ type CoreConfig struct {
 	WasmInterpreter  string `toml:"wasm-interpreter,omitempty"`
 	GrandpaInterval  uint32 `toml:"grandpa-interval,omitempty"`
 	BABELead         bool   `toml:"babe-lead,omitempty"`
+	WarpSync         bool   `toml:"warp-sync,omitempty"`
+	RuntimePoolSize  int    `toml:"runtime-pool-size,omitempty"`
+	RuntimeCacheSize int    `toml:"runtime-cache-size,omitempty"`
 }
 
 // RPCConfig is to marshal/unmarshal toml RPC config vars
@@ -91,6 +96,12 @@ type RPCConfig struct {
 	WSExternal       bool     `toml:"ws-external,omitempty"`
 	WSUnsafe         bool     `toml:"ws-unsafe,omitempty"`
 	WSUnsafeExternal bool     `toml:"ws-unsafe-external,omitempty"`
+
+	MaxQueryStorageRange uint32            `toml:"max-query-storage-range,omitempty"`
+	CORS                 []string          `toml:"cors,omitempty"`
+	RateLimit            uint32            `toml:"rate-limit,omitempty"`
+	MethodRateLimits     map[string]uint32 `toml:"method-rate-limits,omitempty"`
+	MaxResponseSize      uint32            `toml:"max-response-size,omitempty"`
 }
 
 // PprofConfig contains the configuration for Pprof.
@@ -0,0 +1,88 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+)
+
+// ReportBabeEquivocation submits the report of the given BABE equivocation to the transaction pool.
+// The proof of the ownership of the key of the offender is generated at the parent of the equivocating
+// headers, or at the best block if the offender isn't an authority of the session of the parent.
+func (s *Service) ReportBabeEquivocation(proof *types.BabeEquivocationProof) error {
+	bestHash := s.blockState.BestBlockHash()
+
+	var keyOwnershipProof types.OpaqueKeyOwnershipProof
+	for _, hash := range []common.Hash{proof.FirstHeader.ParentHash, bestHash} {
+		err := s.callTransactionInstance(hash, func(instance runtime.Instance) (err error) {
+			keyOwnershipProof, err = instance.BabeGenerateKeyOwnershipProof(proof.Slot, proof.Offender)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("cannot generate key ownership proof at block %s: %w", hash, err)
+		}
+
+		if keyOwnershipProof != nil {
+			break
+		}
+	}
+
+	if keyOwnershipProof == nil {
+		return fmt.Errorf("%w: 0x%x", errOffenderNotAuthority, proof.Offender)
+	}
+
+	return s.callTransactionInstance(bestHash, func(instance runtime.Instance) error {
+		return instance.BabeSubmitReportEquivocationUnsignedExtrinsic(*proof, keyOwnershipProof)
+	})
+}
+
+// ReportGrandpaEquivocation submits the report of the given GRANDPA equivocation to the transaction pool.
+// The proof of the ownership of the key of the offender is generated at the best block.
+func (s *Service) ReportGrandpaEquivocation(proof *types.GrandpaEquivocationProof) error {
+	offender := proof.Equivocation.ID
+
+	return s.callTransactionInstance(s.blockState.BestBlockHash(), func(instance runtime.Instance) error {
+		keyOwnershipProof, err := instance.GrandpaGenerateKeyOwnershipProof(proof.SetID, offender)
+		if err != nil {
+			return fmt.Errorf("cannot generate key ownership proof: %w", err)
+		}
+
+		if keyOwnershipProof == nil {
+			return fmt.Errorf("%w: 0x%x", errOffenderNotAuthority, offender)
+		}
+
+		return instance.GrandpaSubmitReportEquivocationUnsignedExtrinsic(*proof, keyOwnershipProof)
+	})
+}
+
+// callTransactionInstance calls fn with a runtime instance running at the state of the given block,
+// which can submit transactions to the transaction pool.
+func (s *Service) callTransactionInstance(hash common.Hash, fn func(instance runtime.Instance) error) error {
+	rt, err := s.blockState.GetRuntime(&hash)
+	if err != nil {
+		return fmt.Errorf("cannot get runtime: %w", err)
+	}
+
+	stateRoot, err := s.blockState.GetBlockStateRoot(hash)
+	if err != nil {
+		return fmt.Errorf("cannot get state root: %w", err)
+	}
+
+	state, err := s.storageState.TrieState(&stateRoot)
+	if err != nil {
+		return fmt.Errorf("cannot get state: %w", err)
+	}
+
+	instance, err := s.newTransactionInstance(rt, state)
+	if err != nil {
+		return fmt.Errorf("cannot create runtime instance: %w", err)
+	}
+	defer instance.Stop()
+
+	return fn(instance)
+}
@@ -45,7 +45,14 @@
 	// ErrNilDigestHandler is returned when the DigestHandler interface is nil
 	ErrNilDigestHandler = errors.New("cannot have nil DigestHandler")
 
+	// ErrQueryRangeTooLarge is returned when a storage query spans more blocks than allowed
+	ErrQueryRangeTooLarge = errors.New("query block range is too large")
+
+	// ErrTransactionBanned is returned when submitting a transaction that is temporarily banned
+	ErrTransactionBanned = errors.New("transaction is temporarily banned")
+
 	errNilCodeSubstitutedState = errors.New("cannot have nil CodeSubstitutedStat")
+	errOffenderNotAuthority    = errors.New("equivocation offender is not an authority")
 )
 
 // ErrNilChannel is returned if a channel is nil
@@ -30,6 +30,7 @@ type BlockState interface {
 	AddBlock(*types.Block) error
 	GetAllBlocksAtDepth(hash common.Hash) []common.Hash
 	GetBlockByHash(common.Hash) (*types.Block, error)
+	GetHeader(common.Hash) (*types.Header, error)
 	GetBlockStateRoot(bhash common.Hash) (common.Hash, error)
 	GenesisHash() common.Hash
 	GetSlotForBlock(common.Hash) (uint64, error)
@@ -44,7 +45,9 @@ type BlockState interface {
 	GetBlockBody(hash common.Hash) (*types.Body, error)
 	HandleRuntimeChanges(newState *rtstorage.TrieState, in runtime.Instance, bHash common.Hash) error
 	GetRuntime(*common.Hash) (runtime.Instance, error)
+	GetRuntimePool(*common.Hash) (*runtime.InstancePool, error)
 	StoreRuntime(common.Hash, runtime.Instance)
+	SetIndexOperations(hash common.Hash, ops []types.IndexOperation)
 }
 
 // StorageState interface for storage state methods
@@ -66,6 +69,7 @@ type TransactionState interface {
 	RemoveExtrinsic(ext types.Extrinsic)
 	RemoveExtrinsicFromPool(ext types.Extrinsic)
 	PendingInPool() []*transaction.ValidTransaction
+	IsBanned(hash common.Hash) bool
 }
 
 // Network is the interface for the network service
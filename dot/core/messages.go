@@ -16,7 +16,7 @@
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
-func (s *Service) validateTransaction(peerID peer.ID, head *types.Header, rt runtime.Instance,
+func (s *Service) validateTransaction(peerID peer.ID, head *types.Header, pool *runtime.InstancePool,
 	tx types.Extrinsic) (validity *transaction.Validity, valid bool, err error) {
 	s.storageState.Lock()
 
@@ -26,11 +26,12 @@ func (s *Service) validateTransaction(peerID peer.ID, head *types.Header, rt run
 		return nil, false, fmt.Errorf("cannot get trie state from storage for root %s: %w", head.StateRoot, err)
 	}
 
-	rt.SetContextStorage(ts)
-
 	// validate each transaction
 	externalExt := types.Extrinsic(append([]byte{byte(types.TxnExternal)}, tx...))
-	validity, err = rt.ValidateTransaction(externalExt)
+	err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+		validity, err = rt.ValidateTransaction(externalExt)
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, runtime.ErrInvalidTransaction) {
 			s.net.ReportPeer(peerset.ReputationChange{
@@ -73,14 +74,19 @@ func (s *Service) HandleTransactionMessage(peerID peer.ID, msg *network.Transact
 	}
 
 	hash := head.Hash()
-	rt, err := s.blockState.GetRuntime(&hash)
+	pool, err := s.blockState.GetRuntimePool(&hash)
 	if err != nil {
 		return false, err
 	}
 
 	allTxsAreValid := true
 	for _, tx := range txs {
-		validity, isValidTxn, err := s.validateTransaction(peerID, head, rt, tx)
+		if s.transactionState.IsBanned(tx.Hash()) {
+			logger.Debugf("ignoring banned transaction with hash %s", tx.Hash())
+			continue
+		}
+
+		validity, isValidTxn, err := s.validateTransaction(peerID, head, pool, tx)
 		if err != nil {
 			return false, fmt.Errorf("failed validating transaction for peerID %s: %w", peerID, err)
 		}
@@ -19,6 +19,7 @@
 	"github.com/golang/mock/gomock"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var errDummyErr = errors.New("dummy error for testing")
@@ -55,8 +56,9 @@ type mockStorageState struct {
 }
 
 type mockTxnState struct {
-	input *transaction.ValidTransaction
-	hash  common.Hash
+	input  *transaction.ValidTransaction
+	hash   common.Hash
+	banned bool
 }
 
 type mockSetContextStorage struct {
@@ -315,6 +317,36 @@ type args struct {
 			},
 			exp: true,
 		},
+		{
+			name: "banned transaction",
+			mockNetwork: &mockNetwork{
+				IsSynced: true,
+				ReportPeer: &mockReportPeer{
+					change: peerset.ReputationChange{
+						Value:  peerset.GoodTransactionValue,
+						Reason: peerset.GoodTransactionReason,
+					},
+					id: peer.ID("jimbo"),
+				},
+			},
+			mockBlockState: &mockBlockState{
+				bestHeader: &mockBestHeader{
+					header: testEmptyHeader,
+				},
+				getRuntime: &mockGetRuntime{
+					runtime: runtimeMock,
+				},
+			},
+			mockTxnState: &mockTxnState{
+				banned: true,
+			},
+			args: args{
+				peerID: peer.ID("jimbo"),
+				msg: &network.TransactionMessage{
+					Extrinsics: []types.Extrinsic{{1, 2, 3}},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -335,8 +367,14 @@ type args struct {
 					tt.mockBlockState.bestHeader.err)
 
 				if tt.mockBlockState.getRuntime != nil {
-					blockState.EXPECT().GetRuntime(gomock.Any()).Return(
-						tt.mockBlockState.getRuntime.runtime,
+					var pool *runtime.InstancePool
+					if tt.mockBlockState.getRuntime.runtime != nil {
+						var err error
+						pool, err = runtime.NewInstancePool(tt.mockBlockState.getRuntime.runtime, 1)
+						require.NoError(t, err)
+					}
+					blockState.EXPECT().GetRuntimePool(gomock.Any()).Return(
+						pool,
 						tt.mockBlockState.getRuntime.err)
 				}
 				s.blockState = blockState
@@ -350,11 +388,13 @@ type args struct {
 					tt.mockStorageState.err)
 				s.storageState = storageState
 			}
-			if tt.mockTxnState != nil {
-				txnState := NewMockTransactionState(ctrl)
+			txnState := NewMockTransactionState(ctrl)
+			txnState.EXPECT().IsBanned(gomock.Any()).
+				Return(tt.mockTxnState != nil && tt.mockTxnState.banned).AnyTimes()
+			if tt.mockTxnState != nil && tt.mockTxnState.input != nil {
 				txnState.EXPECT().AddToPool(tt.mockTxnState.input).Return(tt.mockTxnState.hash)
-				s.transactionState = txnState
 			}
+			s.transactionState = txnState
 			if tt.mockRuntime != nil {
 				rt := tt.mockRuntime.runtime
 				rt.On("SetContextStorage", tt.mockRuntime.setContextStorage.trieState)
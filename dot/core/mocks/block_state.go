@@ -316,6 +316,29 @@ func (_m *BlockState) GetFinalisedNotifierChannel() chan *types.FinalisationInfo
 	return r0
 }
 
+// GetHeader provides a mock function with given fields: _a0
+func (_m *BlockState) GetHeader(_a0 common.Hash) (*types.Header, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *types.Header
+	if rf, ok := ret.Get(0).(func(common.Hash) *types.Header); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Header)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetImportedBlockNotifierChannel provides a mock function with given fields:
 func (_m *BlockState) GetImportedBlockNotifierChannel() chan *types.Block {
 	ret := _m.Called()
@@ -355,6 +378,29 @@ func (_m *BlockState) GetRuntime(_a0 *common.Hash) (runtime.Instance, error) {
 	return r0, r1
 }
 
+// GetRuntimePool provides a mock function with given fields: _a0
+func (_m *BlockState) GetRuntimePool(_a0 *common.Hash) (*runtime.InstancePool, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *runtime.InstancePool
+	if rf, ok := ret.Get(0).(func(*common.Hash) *runtime.InstancePool); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*runtime.InstancePool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*common.Hash) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSlotForBlock provides a mock function with given fields: _a0
 func (_m *BlockState) GetSlotForBlock(_a0 common.Hash) (uint64, error) {
 	ret := _m.Called(_a0)
@@ -413,6 +459,11 @@ func (_m *BlockState) HighestCommonAncestor(a common.Hash, b common.Hash) (commo
 	return r0, r1
 }
 
+// SetIndexOperations provides a mock function with given fields: hash, ops
+func (_m *BlockState) SetIndexOperations(hash common.Hash, ops []types.IndexOperation) {
+	_m.Called(hash, ops)
+}
+
 // StoreRuntime provides a mock function with given fields: _a0, _a1
 func (_m *BlockState) StoreRuntime(_a0 common.Hash, _a1 runtime.Instance) {
 	_m.Called(_a0, _a1)
@@ -4,10 +4,12 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -29,8 +31,15 @@
 	logger                  = log.NewFromGlobal(log.AddContext("pkg", "core"))
 )
 
-// QueryKeyValueChanges represents the key-value data inside a block storage
-type QueryKeyValueChanges map[string]string
+// QueryKeyValueChanges represents the key-value data inside a block storage,
+// keys are hex encoded and a nil value means the key has no value in the block storage
+type QueryKeyValueChanges map[string][]byte
+
+// StorageChangeSet holds the storage changes of the queried keys at a block
+type StorageChangeSet struct {
+	Block   common.Hash
+	Changes QueryKeyValueChanges
+}
 
 // Service is an overhead layer that allows communication between the runtime,
 // BABE session, and network service. It deals with the validation of transactions
@@ -55,6 +64,15 @@ type Service struct {
 
 	// Keystore
 	keys *keystore.GlobalKeystore
+
+	// node role, passed to the runtime instances created by the service
+	role byte
+
+	// offchain worker runtime instance, reused while the runtime code doesn't change
+	offchainWorkerRunning  uint32
+	offchainWorkerLock     sync.Mutex
+	offchainWorker         runtime.Instance
+	offchainWorkerCodeHash common.Hash
 }
 
 // Config holds the configuration for the core Service.
@@ -69,6 +87,7 @@ type Config struct {
 	Keystore         *keystore.GlobalKeystore
 	Runtime          runtime.Instance
 	DigestHandler    DigestHandler
+	Role             byte
 
 	CodeSubstitutes      map[common.Hash]string
 	CodeSubstitutedState CodeSubstitutedState
@@ -119,6 +138,7 @@ func NewService(cfg *Config) (*Service, error) {
 		codeSubstitute:       cfg.CodeSubstitutes,
 		codeSubstitutedState: cfg.CodeSubstitutedState,
 		digestHandler:        cfg.DigestHandler,
+		role:                 cfg.Role,
 	}
 
 	return srv, nil
@@ -137,6 +157,14 @@ func (s *Service) Stop() error {
 
 	s.cancel()
 	close(s.blockAddCh)
+
+	s.offchainWorkerLock.Lock()
+	defer s.offchainWorkerLock.Unlock()
+	if s.offchainWorker != nil {
+		s.offchainWorker.Stop()
+		s.offchainWorker = nil
+	}
+
 	return nil
 }
 
@@ -154,7 +182,8 @@ func (s *Service) StorageRoot() (common.Hash, error) {
 	return ts.Root()
 }
 
-// HandleBlockImport handles a block that was imported via the network
+// HandleBlockImport handles a block that was imported via the network. The block was
+// already executed by the sync service, which rejects the blocks whose inherents are invalid.
 func (s *Service) HandleBlockImport(block *types.Block, state *rtstorage.TrieState) error {
 	return s.handleBlock(block, state)
 }
@@ -201,6 +230,9 @@ func (s *Service) handleBlock(block *types.Block, state *rtstorage.TrieState) er
 		return err
 	}
 
+	// store the transactions indexed by the block, which are written to the database upon finalisation
+	s.blockState.SetIndexOperations(block.Header.Hash(), state.IndexOperations())
+
 	// store block in database
 	if err = s.blockState.AddBlock(block); err != nil {
 		if err == blocktree.ErrParentNotFound && block.Header.Number.Cmp(big.NewInt(0)) != 0 {
@@ -275,6 +307,11 @@ func (s *Service) handleCodeSubstitution(hash common.Hash, state *rtstorage.Trie
 	cfg.Keystore = rt.Keystore()
 	cfg.NodeStorage = rt.NodeStorage()
 	cfg.Network = rt.NetworkService()
+	// the runtime instance pools are keyed by code hash
+	cfg.CodeHash, err = common.Blake2bHash(code)
+	if err != nil {
+		return err
+	}
 
 	if rt.Validator() {
 		cfg.Role = 4
@@ -315,12 +352,150 @@ func (s *Service) handleBlocksAsync() {
 			}
 
 			s.maintainTransactionPool(block)
+
+			if block.Header.Hash() == s.blockState.BestBlockHash() {
+				s.startOffchainWorker(block)
+			}
 		case <-s.ctx.Done():
 			return
 		}
 	}
 }
 
+// startOffchainWorker runs the offchain worker for the given block in a separate goroutine, unless
+// the worker of a previous block is still running, in which case the block is skipped.
+func (s *Service) startOffchainWorker(block *types.Block) {
+	if !atomic.CompareAndSwapUint32(&s.offchainWorkerRunning, 0, 1) {
+		logger.Debugf("offchain worker is still running, skipping block %s", block.Header.Hash())
+		return
+	}
+
+	go func() {
+		defer atomic.StoreUint32(&s.offchainWorkerRunning, 0)
+		s.runOffchainWorker(block)
+	}()
+}
+
+// runOffchainWorker calls the runtime offchain worker for the given block. It runs in a separate
+// runtime instance so it doesn't block or modify the state of the instance used for block import.
+func (s *Service) runOffchainWorker(block *types.Block) {
+	hash := block.Header.Hash()
+
+	rt, err := s.blockState.GetRuntime(&hash)
+	if err != nil {
+		logger.Warnf("failed to get runtime for block %s: %s", hash, err)
+		return
+	}
+
+	if !rt.Validator() {
+		return
+	}
+
+	version, err := rt.Version()
+	if err != nil {
+		logger.Warnf("failed to get runtime version for block %s: %s", hash, err)
+		return
+	}
+
+	apiVersion, ok := runtime.APIVersion(version, runtime.OffchainWorkerAPIID)
+	if !ok || apiVersion < 2 {
+		logger.Debugf("runtime at block %s does not support offchain workers", hash)
+		return
+	}
+
+	state, err := s.storageState.TrieState(&block.Header.StateRoot)
+	if err != nil {
+		logger.Warnf("failed to get state for block %s: %s", hash, err)
+		return
+	}
+
+	s.offchainWorkerLock.Lock()
+	defer s.offchainWorkerLock.Unlock()
+
+	instance, err := s.offchainWorkerInstance(rt, state)
+	if err != nil {
+		logger.Warnf("failed to create offchain worker runtime instance for block %s: %s", hash, err)
+		return
+	}
+
+	err = instance.OffchainWorker(&block.Header)
+	if err != nil {
+		logger.Warnf("failed to run offchain worker for block %s: %s", hash, err)
+	}
+}
+
+// offchainWorkerInstance returns the offchain worker runtime instance set to the given state. The
+// instance is only created again when the runtime code changes. It must be called with the
+// offchain worker lock held.
+func (s *Service) offchainWorkerInstance(rt runtime.Instance, state *rtstorage.TrieState) (runtime.Instance, error) {
+	codeHash, err := state.LoadCodeHash()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.offchainWorker != nil && s.offchainWorkerCodeHash == codeHash {
+		s.offchainWorker.SetContextStorage(state)
+		return s.offchainWorker, nil
+	}
+
+	instance, err := s.newTransactionInstance(rt, state)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.offchainWorker != nil {
+		s.offchainWorker.Stop()
+	}
+
+	s.offchainWorker = instance
+	s.offchainWorkerCodeHash = codeHash
+	return instance, nil
+}
+
+// newTransactionInstance creates a runtime instance running the code of the given state, which
+// can submit transactions to the transaction pool. It is used for the runtime calls which submit
+// transactions, such as offchain workers and equivocation reports.
+func (s *Service) newTransactionInstance(rt runtime.Instance, state *rtstorage.TrieState) (runtime.Instance, error) {
+	code := state.LoadCode()
+	if len(code) == 0 {
+		return nil, ErrEmptyRuntimeCode
+	}
+
+	cfg := &wasmer.Config{
+		Imports: wasmer.ImportsNodeRuntime,
+	}
+	cfg.Storage = state
+	cfg.Keystore = rt.Keystore()
+	cfg.NodeStorage = rt.NodeStorage()
+	cfg.Network = rt.NetworkService()
+	cfg.Transaction = &offchainTransactionPool{service: s}
+	cfg.Role = s.role
+
+	return wasmer.NewInstance(code, cfg)
+}
+
+// offchainTransactionPool handles the transactions submitted by offchain workers
+// the same way as the extrinsics submitted through RPC.
+type offchainTransactionPool struct {
+	service *Service
+}
+
+// AddToPool validates, adds to the pool and gossips the extrinsic of the given transaction
+func (p *offchainTransactionPool) AddToPool(vt *transaction.ValidTransaction) common.Hash {
+	err := p.service.HandleSubmittedExtrinsic(vt.Extrinsic)
+	if err != nil {
+		logger.Debugf("failed to submit offchain worker transaction: %s", err)
+	}
+
+	return vt.Extrinsic.Hash()
+}
+
+// SubmitTransaction validates, adds to the pool and gossips the given extrinsic. It returns an
+// error if the extrinsic is invalid.
+func (p *offchainTransactionPool) SubmitTransaction(ext types.Extrinsic) error {
+	return p.service.HandleSubmittedExtrinsic(ext)
+}
+
 // handleChainReorg checks if there is a chain re-org (ie. new chain head is on a different chain than the
 // previous chain head). If there is a re-org, it moves the transactions that were included on the previous
 // chain back into the transaction pool.
@@ -349,13 +524,14 @@ func (s *Service) handleChainReorg(prev, curr common.Hash) error {
 	}
 
 	// Check transaction validation on the best block.
-	rt, err := s.blockState.GetRuntime(nil)
+	ts, err := s.storageState.TrieState(nil)
 	if err != nil {
 		return err
 	}
 
-	if rt == nil {
-		return ErrNilRuntime
+	pool, err := s.blockState.GetRuntimePool(nil)
+	if err != nil {
+		return err
 	}
 
 	// for each block in the previous chain, re-add its extrinsics back into the pool
@@ -384,7 +560,11 @@ func (s *Service) handleChainReorg(prev, curr common.Hash) error {
 			}
 
 			externalExt := types.Extrinsic(append([]byte{byte(types.TxnExternal)}, encExt...))
-			txv, err := rt.ValidateTransaction(externalExt)
+			var txv *transaction.Validity
+			err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+				txv, err = rt.ValidateTransaction(externalExt)
+				return err
+			})
 			if err != nil {
 				logger.Debugf("failed to validate transaction for extrinsic %s: %s", ext, err)
 				continue
@@ -456,12 +636,22 @@ func (s *Service) HasKey(pubKeyStr, keystoreType string) (bool, error) {
 
 // DecodeSessionKeys executes the runtime DecodeSessionKeys and return the scale encoded keys
 func (s *Service) DecodeSessionKeys(enc []byte) ([]byte, error) {
-	rt, err := s.blockState.GetRuntime(nil)
+	ts, err := s.storageState.TrieState(nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return rt.DecodeSessionKeys(enc)
+	pool, err := s.blockState.GetRuntimePool(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []byte
+	err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+		keys, err = rt.DecodeSessionKeys(enc)
+		return err
+	})
+	return keys, err
 }
 
 // GetRuntimeVersion gets the current RuntimeVersion
@@ -482,13 +672,17 @@ func (s *Service) GetRuntimeVersion(bhash *common.Hash) (runtime.Version, error)
 		return nil, err
 	}
 
-	rt, err := s.blockState.GetRuntime(bhash)
+	pool, err := s.blockState.GetRuntimePool(bhash)
 	if err != nil {
 		return nil, err
 	}
 
-	rt.SetContextStorage(ts)
-	return rt.Version()
+	var version runtime.Version
+	err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+		version, err = rt.Version()
+		return err
+	})
+	return version, err
 }
 
 // HandleSubmittedExtrinsic is used to send a Transaction message containing a Extrinsic @ext
@@ -497,21 +691,28 @@ func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
 		return nil
 	}
 
+	if s.transactionState.IsBanned(ext.Hash()) {
+		return fmt.Errorf("%w: %s", ErrTransactionBanned, ext.Hash())
+	}
+
 	ts, err := s.storageState.TrieState(nil)
 	if err != nil {
 		return err
 	}
 
-	rt, err := s.blockState.GetRuntime(nil)
+	pool, err := s.blockState.GetRuntimePool(nil)
 	if err != nil {
 		logger.Critical("failed to get runtime")
 		return err
 	}
 
-	rt.SetContextStorage(ts)
 	// the transaction source is External
 	externalExt := types.Extrinsic(append([]byte{byte(types.TxnExternal)}, ext...))
-	txv, err := rt.ValidateTransaction(externalExt)
+	var txv *transaction.Validity
+	err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+		txv, err = rt.ValidateTransaction(externalExt)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -545,39 +746,153 @@ func (s *Service) GetMetadata(bhash *common.Hash) ([]byte, error) {
 		return nil, err
 	}
 
-	rt, err := s.blockState.GetRuntime(bhash)
+	pool, err := s.blockState.GetRuntimePool(bhash)
 	if err != nil {
 		return nil, err
 	}
 
-	rt.SetContextStorage(ts)
-	return rt.Metadata()
+	var metadata []byte
+	err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+		metadata, err = rt.Metadata()
+		return err
+	})
+	return metadata, err
 }
 
-// QueryStorage returns the key-value data by block based on `keys` params
-// on every block starting `from` until `to` block, if `to` is not nil
-func (s *Service) QueryStorage(from, to common.Hash, keys ...string) (map[common.Hash]QueryKeyValueChanges, error) {
+// CallAt executes the runtime API function `method` with the given SCALE encoded `data`
+// against the state of the block `bhash`. If `bhash` is nil the best block is used.
+// Any state changes made by the call are discarded.
+func (s *Service) CallAt(bhash *common.Hash, method string, data []byte) ([]byte, error) {
+	if bhash == nil {
+		best := s.blockState.BestBlockHash()
+		bhash = &best
+	}
+
+	stateRootHash, err := s.storageState.GetStateRootFromBlock(bhash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get state root for block %s: %w", bhash, err)
+	}
+
+	// TrieState returns a snapshot of the stored trie, so writes
+	// performed by the runtime call never reach the database.
+	ts, err := s.storageState.TrieState(stateRootHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get trie state for block %s: %w", bhash, err)
+	}
+
+	pool, err := s.blockState.GetRuntimePool(bhash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get runtime for block %s: %w", bhash, err)
+	}
+
+	var res []byte
+	err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+		res, err = rt.Exec(method, data)
+		return err
+	})
+	return res, err
+}
+
+// QueryStorage returns the changes of the `keys` values for every block starting at `from`
+// until `to` block, or the best block if `to` is empty. The first change set holds the values
+// of all `keys`, the following ones only hold the keys whose value changed since the previous
+// block, and blocks without changes are omitted. If `maxRange` is not zero, an error is returned
+// when the range contains more than `maxRange` blocks.
+func (s *Service) QueryStorage(from, to common.Hash, maxRange uint32, keys ...string) ([]StorageChangeSet, error) {
 	if to.IsEmpty() {
 		to = s.blockState.BestBlockHash()
 	}
 
+	if maxRange != 0 {
+		// check the range from the block numbers so a large range doesn't load the whole sub chain
+		fromHeader, err := s.blockState.GetHeader(from)
+		if err != nil {
+			return nil, err
+		}
+
+		toHeader, err := s.blockState.GetHeader(to)
+		if err != nil {
+			return nil, err
+		}
+
+		blocks := new(big.Int).Sub(toHeader.Number, fromHeader.Number)
+		blocks.Add(blocks, big.NewInt(1))
+		if blocks.Cmp(big.NewInt(int64(maxRange))) > 0 {
+			return nil, fmt.Errorf("%w: %s blocks requested, limit is %d",
+				ErrQueryRangeTooLarge, blocks, maxRange)
+		}
+	}
+
 	blocksToQuery, err := s.blockState.SubChain(from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	queries := make(map[common.Hash]QueryKeyValueChanges)
+	var (
+		changeSets []StorageChangeSet
+		prevRoot   *common.Hash
+		prevValues QueryKeyValueChanges
+	)
 
 	for _, hash := range blocksToQuery {
-		changes, err := s.tryQueryStorage(hash, keys...)
+		stateRootHash, err := s.storageState.GetStateRootFromBlock(&hash)
 		if err != nil {
 			return nil, err
 		}
 
-		queries[hash] = changes
+		// blocks sharing the state root of their parent cannot have changed any key
+		if prevRoot != nil && *prevRoot == *stateRootHash {
+			continue
+		}
+
+		values, err := s.queryStorageAtRoot(stateRootHash, keys...)
+		if err != nil {
+			return nil, err
+		}
+
+		changes := values
+		if prevValues != nil {
+			changes = make(QueryKeyValueChanges)
+			for k, v := range values {
+				if !bytes.Equal(v, prevValues[k]) || (v == nil) != (prevValues[k] == nil) {
+					changes[k] = v
+				}
+			}
+		}
+
+		prevRoot = stateRootHash
+		prevValues = values
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		changeSets = append(changeSets, StorageChangeSet{
+			Block:   hash,
+			Changes: changes,
+		})
+	}
+
+	return changeSets, nil
+}
+
+// QueryStorageAt returns the values of the `keys` at the block `at`,
+// if `at` is nil then the best block is used
+func (s *Service) QueryStorageAt(at *common.Hash, keys ...string) (*StorageChangeSet, error) {
+	if at == nil {
+		best := s.blockState.BestBlockHash()
+		at = &best
 	}
 
-	return queries, nil
+	changes, err := s.tryQueryStorage(*at, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StorageChangeSet{
+		Block:   *at,
+		Changes: changes,
+	}, nil
 }
 
 // tryQueryStorage will try to get all the `keys` inside the block's current state
@@ -587,7 +902,13 @@ func (s *Service) tryQueryStorage(block common.Hash, keys ...string) (QueryKeyVa
 		return nil, err
 	}
 
-	changes := make(QueryKeyValueChanges)
+	return s.queryStorageAtRoot(stateRootHash, keys...)
+}
+
+// queryStorageAtRoot returns the values of the `keys` in the trie with the given state root,
+// keys without a value are set to nil
+func (s *Service) queryStorageAtRoot(stateRootHash *common.Hash, keys ...string) (QueryKeyValueChanges, error) {
+	changes := make(QueryKeyValueChanges, len(keys))
 	for _, k := range keys {
 		keyBytes, err := common.HexToBytes(k)
 		if err != nil {
@@ -599,11 +920,7 @@ func (s *Service) tryQueryStorage(block common.Hash, keys ...string) (QueryKeyVa
 			return nil, err
 		}
 
-		if storedData == nil {
-			continue
-		}
-
-		changes[k] = common.BytesToHex(storedData)
+		changes[k] = storedData
 	}
 
 	return changes, nil
@@ -503,6 +503,33 @@ func TestService_HandleSubmittedExtrinsic(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_runOffchainWorker(t *testing.T) {
+	s := NewTestService(t, nil)
+
+	genHeader, err := s.blockState.BestBlockHeader()
+	require.NoError(t, err)
+
+	rt, err := s.blockState.GetRuntime(nil)
+	require.NoError(t, err)
+
+	version, err := rt.Version()
+	require.NoError(t, err)
+	apiVersion, ok := runtime.APIVersion(version, runtime.OffchainWorkerAPIID)
+	require.True(t, ok)
+	require.GreaterOrEqual(t, apiVersion, uint32(2))
+
+	ts, err := s.storageState.TrieState(nil)
+	require.NoError(t, err)
+	rt.SetContextStorage(ts)
+
+	block := sync.BuildBlock(t, rt, genHeader, nil)
+
+	err = s.handleBlock(block, ts)
+	require.NoError(t, err)
+
+	s.runOffchainWorker(block)
+}
+
 func TestService_GetMetadata(t *testing.T) {
 	s := NewTestService(t, nil)
 	res, err := s.GetMetadata(nil)
@@ -510,6 +537,27 @@ func TestService_GetMetadata(t *testing.T) {
 	require.Greater(t, len(res), 10000)
 }
 
+func TestService_CallAt(t *testing.T) {
+	s := NewTestService(t, nil)
+
+	rt, err := s.blockState.GetRuntime(nil)
+	require.NoError(t, err)
+	expected, err := rt.Exec("Core_version", []byte{})
+	require.NoError(t, err)
+
+	res, err := s.CallAt(nil, "Core_version", []byte{})
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	genesisHash := s.blockState.GenesisHash()
+	res, err = s.CallAt(&genesisHash, "Core_version", []byte{})
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	_, err = s.CallAt(nil, "Core_does_not_exist", []byte{})
+	require.Error(t, err)
+}
+
 func TestService_HandleRuntimeChanges(t *testing.T) {
 	const (
 		updatedSpecVersion        = uint32(262)
@@ -696,7 +744,7 @@ func TestTryQueryStore_WhenThereIsDataToRetrieve(t *testing.T) {
 	changes, err := s.tryQueryStorage(blockhash, keys...)
 	require.NoError(t, err)
 
-	require.Equal(t, changes[hexKey], common.BytesToHex(testValue))
+	require.Equal(t, testValue, changes[hexKey])
 }
 
 func TestTryQueryStore_WhenDoesNotHaveDataToRetrieve(t *testing.T) {
@@ -727,7 +775,7 @@ func TestTryQueryStore_WhenDoesNotHaveDataToRetrieve(t *testing.T) {
 	changes, err := s.tryQueryStorage(blockhash, keys...)
 	require.NoError(t, err)
 
-	require.Empty(t, changes)
+	require.Equal(t, QueryKeyValueChanges{hexKey: nil}, changes)
 }
 
 func TestTryQueryState_WhenDoesNotHaveStateRoot(t *testing.T) {
@@ -782,33 +830,58 @@ func TestQueryStorate_WhenBlocksHasData(t *testing.T) {
 	)
 
 	from := firstBlock.Header.Hash()
-	data, err := s.QueryStorage(from, common.Hash{}, keys...)
+	data, err := s.QueryStorage(from, common.Hash{}, 0, keys...)
 	require.NoError(t, err)
-	require.Len(t, data, 3)
-
-	require.Equal(t, data[firstBlock.Header.Hash()], QueryKeyValueChanges(
-		map[string]string{
-			common.BytesToHex(firstKey): common.BytesToHex(firstValue),
+	require.Equal(t, []StorageChangeSet{
+		{
+			Block: firstBlock.Header.Hash(),
+			Changes: QueryKeyValueChanges{
+				keys[0]: firstValue,
+				keys[1]: nil,
+				keys[2]: nil,
+			},
+		},
+		{
+			Block: secondBlock.Header.Hash(),
+			Changes: QueryKeyValueChanges{
+				keys[0]: nil,
+				keys[1]: secondValue,
+			},
+		},
+		{
+			Block: thirdBlock.Header.Hash(),
+			Changes: QueryKeyValueChanges{
+				keys[1]: nil,
+				keys[2]: thirdValue,
+			},
 		},
-	))
+	}, data)
 
 	from = secondBlock.Header.Hash()
 	to := thirdBlock.Header.Hash()
 
-	data, err = s.QueryStorage(from, to, keys...)
+	data, err = s.QueryStorage(from, to, 0, keys...)
 	require.NoError(t, err)
 	require.Len(t, data, 2)
-
-	require.Equal(t, data[secondBlock.Header.Hash()], QueryKeyValueChanges(
-		map[string]string{
-			common.BytesToHex(secondKey): common.BytesToHex(secondValue),
-		},
-	))
-	require.Equal(t, data[thirdBlock.Header.Hash()], QueryKeyValueChanges(
-		map[string]string{
-			common.BytesToHex(thirdKey): common.BytesToHex(thirdValue),
+	require.Equal(t, QueryKeyValueChanges{
+		keys[0]: nil,
+		keys[1]: secondValue,
+		keys[2]: nil,
+	}, data[0].Changes)
+
+	_, err = s.QueryStorage(firstBlock.Header.Hash(), to, 2, keys...)
+	require.ErrorIs(t, err, ErrQueryRangeTooLarge)
+
+	changeSet, err := s.QueryStorageAt(&to, keys...)
+	require.NoError(t, err)
+	require.Equal(t, &StorageChangeSet{
+		Block: to,
+		Changes: QueryKeyValueChanges{
+			keys[0]: nil,
+			keys[1]: nil,
+			keys[2]: thirdValue,
 		},
-	))
+	}, changeSet)
 }
 
 func createNewBlockAndStoreDataAtBlock(t *testing.T, s *Service,
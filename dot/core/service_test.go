@@ -0,0 +1,47 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_QueryStorage_RangeTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from, to := common.Hash{1}, common.Hash{2}
+
+	// the sub chain is not loaded when the range is too large
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHeader(from).Return(&types.Header{Number: big.NewInt(10)}, nil)
+	blockState.EXPECT().GetHeader(to).Return(&types.Header{Number: big.NewInt(1_000_000)}, nil)
+
+	s := &Service{blockState: blockState}
+	_, err := s.QueryStorage(from, to, 1000, "0x01")
+	require.ErrorIs(t, err, ErrQueryRangeTooLarge)
+	require.EqualError(t, err, "query block range is too large: 999991 blocks requested, limit is 1000")
+}
+
+func TestService_startOffchainWorker_skipsWhileRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the runtime isn't loaded when a worker is already running
+	s := &Service{
+		blockState:            NewMockBlockState(ctrl),
+		offchainWorkerRunning: 1,
+	}
+
+	block := &types.Block{Header: types.Header{Number: big.NewInt(1)}}
+	s.startOffchainWorker(block)
+	require.Equal(t, uint32(1), s.offchainWorkerRunning)
+}
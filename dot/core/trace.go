@@ -0,0 +1,98 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+)
+
+// BlockTrace is the trace of a block execution
+type BlockTrace struct {
+	BlockHash common.Hash `json:"blockHash"`
+	// ExpectedStateRoot is the state root in the block header
+	ExpectedStateRoot common.Hash `json:"expectedStateRoot"`
+	// StateRoot is the state root after executing the block
+	StateRoot common.Hash `json:"stateRoot"`
+	// Error is the error returned by the block execution, if any
+	Error  string               `json:"error,omitempty"`
+	Events []runtime.TraceEvent `json:"events"`
+}
+
+// TraceBlock re-executes the block with the given hash on top of its parent state,
+// recording the host function calls made by the runtime.
+func (s *Service) TraceBlock(hash common.Hash) (*BlockTrace, error) {
+	block, err := s.blockState.GetBlockByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block: %w", err)
+	}
+
+	parentRoot, err := s.blockState.GetBlockStateRoot(block.Header.ParentHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get parent state root: %w", err)
+	}
+
+	state, err := s.storageState.TrieState(&parentRoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get parent state: %w", err)
+	}
+
+	rt, err := s.blockState.GetRuntime(&block.Header.ParentHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get parent runtime: %w", err)
+	}
+
+	return TraceBlock(block, state, rt.NodeStorage())
+}
+
+// TraceBlock executes the block on top of the given parent state in a new runtime
+// instance with tracing enabled. A failing block execution is reported in the trace
+// rather than returned as an error.
+func TraceBlock(block *types.Block, parentState *rtstorage.TrieState,
+	nodeStorage runtime.NodeStorage) (*BlockTrace, error) {
+	code := parentState.LoadCode()
+	if len(code) == 0 {
+		return nil, ErrEmptyRuntimeCode
+	}
+
+	tracer := runtime.NewTracer()
+
+	cfg := &wasmer.Config{
+		Imports: wasmer.ImportsNodeRuntime,
+	}
+	cfg.Storage = parentState
+	cfg.Keystore = keystore.NewGlobalKeystore()
+	cfg.NodeStorage = nodeStorage
+	cfg.Tracer = tracer
+
+	instance, err := wasmer.NewInstance(code, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create runtime instance: %w", err)
+	}
+	defer instance.Stop()
+
+	trace := &BlockTrace{
+		BlockHash:         block.Header.Hash(),
+		ExpectedStateRoot: block.Header.StateRoot,
+	}
+
+	_, err = instance.ExecuteBlock(block)
+	if err != nil {
+		trace.Error = err.Error()
+	}
+
+	trace.StateRoot, err = parentState.Root()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get state root: %w", err)
+	}
+
+	trace.Events = tracer.Events()
+	return trace, nil
+}
@@ -0,0 +1,14 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package light
+
+import "errors"
+
+var (
+	errUnsupported        = errors.New("unsupported in light client mode")
+	errNoPeers            = errors.New("no full node peers to request storage from")
+	errStateRootNotFound  = errors.New("state root not found in recent blocks")
+	errInvalidProof       = errors.New("invalid storage proof")
+	errStorageUnavailable = errors.New("failed to get storage from any peer")
+)
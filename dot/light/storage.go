@@ -0,0 +1,216 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package light
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// maxStateRootLookup is the maximum number of ancestors of the best block
+// searched for a block with a given state root
+const maxStateRootLookup = 256
+
+var logger = log.NewFromGlobal(log.AddContext("pkg", "light"))
+
+// Storage serves storage reads for light clients, which do not store the chain state.
+// Values are requested on demand from full node peers using the light protocol,
+// and each value is verified against the state root of the header it was requested at.
+type Storage struct {
+	blockState BlockState
+	network    Network
+}
+
+// NewStorage returns a new *Storage
+func NewStorage(blockState BlockState, net Network) *Storage {
+	return &Storage{
+		blockState: blockState,
+		network:    net,
+	}
+}
+
+// GetStorage returns the value stored at the given key in the state with the given root.
+// If the root is nil, the state of the best block is used.
+func (s *Storage) GetStorage(root *common.Hash, key []byte) ([]byte, error) {
+	if root == nil {
+		hash := s.blockState.BestBlockHash()
+		return s.GetStorageByBlockHash(&hash, key)
+	}
+
+	header, err := s.headerWithStateRoot(*root)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getStorage(header, key)
+}
+
+// GetStorageByBlockHash returns the value stored at the given key in the state of the given block.
+func (s *Storage) GetStorageByBlockHash(bhash *common.Hash, key []byte) ([]byte, error) {
+	if bhash == nil {
+		return s.GetStorage(nil, key)
+	}
+
+	header, err := s.blockState.GetHeader(*bhash)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getStorage(header, key)
+}
+
+// GetStateRootFromBlock returns the state root of the given block
+func (s *Storage) GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error) {
+	header, err := s.blockState.GetHeader(*bhash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &header.StateRoot, nil
+}
+
+// GetStorageChild is not supported by light clients
+func (*Storage) GetStorageChild(_ *common.Hash, _ []byte) (*trie.Trie, error) {
+	return nil, errUnsupported
+}
+
+// GetStorageFromChild is not supported by light clients
+func (*Storage) GetStorageFromChild(_ *common.Hash, _, _ []byte) ([]byte, error) {
+	return nil, errUnsupported
+}
+
+// Entries is not supported by light clients
+func (*Storage) Entries(_ *common.Hash) (map[string][]byte, error) {
+	return nil, errUnsupported
+}
+
+// GetKeysWithPrefix is not supported by light clients
+func (*Storage) GetKeysWithPrefix(_ *common.Hash, _ []byte) ([][]byte, error) {
+	return nil, errUnsupported
+}
+
+// TrieState is not supported by light clients, since they do not store the chain state
+func (*Storage) TrieState(_ *common.Hash) (*rtstorage.TrieState, error) {
+	return nil, errUnsupported
+}
+
+// RegisterStorageObserver does nothing, since light clients are not notified of storage changes
+func (*Storage) RegisterStorageObserver(_ state.Observer) {
+	logger.Debug("storage subscriptions are not supported in light client mode")
+}
+
+// UnregisterStorageObserver does nothing, since light clients are not notified of storage changes
+func (*Storage) UnregisterStorageObserver(_ state.Observer) {}
+
+// headerWithStateRoot returns the header of the best block or of one of its
+// recent ancestors that has the given state root
+func (s *Storage) headerWithStateRoot(root common.Hash) (*types.Header, error) {
+	hash := s.blockState.BestBlockHash()
+	for i := 0; i < maxStateRootLookup; i++ {
+		header, err := s.blockState.GetHeader(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if header.StateRoot == root {
+			return header, nil
+		}
+
+		if header.Number.Sign() == 0 {
+			break
+		}
+
+		hash = header.ParentHash
+	}
+
+	return nil, fmt.Errorf("%w: %s", errStateRootNotFound, root)
+}
+
+// getStorage requests a proof of the value stored at the given key from each
+// full node peer until one of them returns a valid proof.
+func (s *Storage) getStorage(header *types.Header, key []byte) ([]byte, error) {
+	req := &network.LightRequest{
+		RemoteReadRequest: &network.RemoteReadRequest{
+			Block: header.Hash().ToBytes(),
+			Keys:  [][]byte{key},
+		},
+	}
+
+	var hasPeers bool
+	for _, info := range s.network.Peers() {
+		if info.Roles == types.LightClientRole {
+			continue
+		}
+
+		to, err := peer.Decode(info.PeerID)
+		if err != nil {
+			continue
+		}
+		hasPeers = true
+
+		resp, err := s.network.DoLightRequest(to, req)
+		if err != nil {
+			logger.Debugf("failed to request storage from peer %s: %s", to, err)
+			continue
+		}
+
+		value, err := verifyReadResponse(resp, header.StateRoot, key)
+		if err != nil {
+			logger.Debugf("received invalid storage proof from peer %s: %s", to, err)
+			continue
+		}
+
+		return value, nil
+	}
+
+	if !hasPeers {
+		return nil, errNoPeers
+	}
+
+	return nil, fmt.Errorf("%w: key 0x%x at block %s", errStorageUnavailable, key, header.Hash())
+}
+
+// verifyReadResponse returns the value stored at the given key in the proof
+// of the response, after checking the proof against the given state root.
+// It returns a nil value if the proof shows that the key is not in the state.
+func verifyReadResponse(resp *network.LightResponse, stateRoot common.Hash, key []byte) ([]byte, error) {
+	if resp == nil || resp.RemoteReadResponse == nil {
+		return nil, fmt.Errorf("%w: missing read response", errInvalidProof)
+	}
+
+	var proof [][]byte
+	if err := scale.Unmarshal(resp.RemoteReadResponse.Proof, &proof); err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidProof, err)
+	}
+
+	value, err := trie.GetFromProof(proof, stateRoot.ToBytes(), key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidProof, err)
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	ok, err := trie.VerifyProof(proof, stateRoot.ToBytes(), []trie.Pair{{Key: key, Value: value}})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidProof, err)
+	}
+
+	if !ok {
+		return nil, errInvalidProof
+	}
+
+	return value, nil
+}
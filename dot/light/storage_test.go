@@ -0,0 +1,229 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package light
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+const testPeerID = "12D3KooWAHPrUbbaS4WSvdXBaAZt7oWU8Md3yTqZQptuWsxMdUpQ"
+
+func newTestReadResponse(t *testing.T, tr *trie.Trie, keys ...[]byte) *network.LightResponse {
+	t.Helper()
+
+	db := runtime.NewInMemoryDB(t)
+	err := tr.Store(db)
+	require.NoError(t, err)
+
+	proof, err := trie.GenerateProof(tr.MustHash().ToBytes(), keys, db)
+	require.NoError(t, err)
+
+	enc, err := scale.Marshal(proof)
+	require.NoError(t, err)
+
+	resp := network.NewLightResponse()
+	resp.RemoteReadResponse.Proof = enc
+	return resp
+}
+
+// newTestRootOnlyResponse returns a read response whose proof only holds the root node
+func newTestRootOnlyResponse(t *testing.T, tr *trie.Trie) *network.LightResponse {
+	t.Helper()
+
+	resp := newTestReadResponse(t, tr, []byte("noot"))
+
+	var proof [][]byte
+	err := scale.Unmarshal(resp.RemoteReadResponse.Proof, &proof)
+	require.NoError(t, err)
+
+	stateRoot := tr.MustHash()
+	for _, entry := range proof {
+		if common.MustBlake2bHash(entry) == stateRoot {
+			resp.RemoteReadResponse.Proof, err = scale.Marshal([][]byte{entry})
+			require.NoError(t, err)
+			return resp
+		}
+	}
+
+	t.Fatal("root node not found in proof")
+	return nil
+}
+
+func newTestTrie() *trie.Trie {
+	tr := trie.NewEmptyTrie()
+	tr.Put([]byte("noot"), common.Hash{1}.ToBytes())
+	tr.Put([]byte("other"), common.Hash{2}.ToBytes())
+	return tr
+}
+
+func TestStorage_GetStorage(t *testing.T) {
+	t.Parallel()
+
+	tr := newTestTrie()
+	header := &types.Header{
+		Number:    big.NewInt(1),
+		StateRoot: tr.MustHash(),
+	}
+	blockHash := header.Hash()
+
+	testPeer, err := peer.Decode(testPeerID)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().BestBlockHash().Return(blockHash).Times(2)
+	blockState.EXPECT().GetHeader(blockHash).Return(header, nil).Times(2)
+
+	net := NewMockNetwork(ctrl)
+	net.EXPECT().Peers().Return([]common.PeerInfo{
+		{PeerID: "light", Roles: types.LightClientRole},
+		{PeerID: testPeerID, Roles: types.FullNodeRole},
+	}).Times(2)
+	net.EXPECT().DoLightRequest(testPeer, &network.LightRequest{
+		RemoteReadRequest: &network.RemoteReadRequest{
+			Block: blockHash.ToBytes(),
+			Keys:  [][]byte{[]byte("noot")},
+		},
+	}).Return(newTestReadResponse(t, tr, []byte("noot")), nil).Times(2)
+
+	s := NewStorage(blockState, net)
+
+	value, err := s.GetStorage(nil, []byte("noot"))
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{1}.ToBytes(), value)
+
+	stateRoot := tr.MustHash()
+	value, err = s.GetStorage(&stateRoot, []byte("noot"))
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{1}.ToBytes(), value)
+}
+
+func TestStorage_GetStorageByBlockHash(t *testing.T) {
+	t.Parallel()
+
+	tr := newTestTrie()
+	header := &types.Header{
+		Number:    big.NewInt(1),
+		StateRoot: tr.MustHash(),
+	}
+	blockHash := header.Hash()
+
+	otherTrie := trie.NewEmptyTrie()
+	otherTrie.Put([]byte("noot"), common.Hash{3}.ToBytes())
+	otherTrie.Put([]byte("other"), common.Hash{2}.ToBytes())
+
+	testPeer, err := peer.Decode(testPeerID)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		peers    []common.PeerInfo
+		resp     *network.LightResponse
+		respErr  error
+		expected []byte
+		errIs    error
+	}{
+		"valid proof": {
+			peers:    []common.PeerInfo{{PeerID: testPeerID}},
+			resp:     newTestReadResponse(t, tr, []byte("noot")),
+			expected: common.Hash{1}.ToBytes(),
+		},
+		"root only proof": {
+			peers: []common.PeerInfo{{PeerID: testPeerID}},
+			resp:  newTestRootOnlyResponse(t, tr),
+			errIs: errStorageUnavailable,
+		},
+		"proof for another state root": {
+			peers: []common.PeerInfo{{PeerID: testPeerID}},
+			resp:  newTestReadResponse(t, otherTrie, []byte("noot")),
+			errIs: errStorageUnavailable,
+		},
+		"request error": {
+			peers:   []common.PeerInfo{{PeerID: testPeerID}},
+			respErr: errors.New("test error"),
+			errIs:   errStorageUnavailable,
+		},
+		"no peers": {
+			errIs: errNoPeers,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			blockState := NewMockBlockState(ctrl)
+			blockState.EXPECT().GetHeader(blockHash).Return(header, nil)
+
+			net := NewMockNetwork(ctrl)
+			net.EXPECT().Peers().Return(testCase.peers)
+			if len(testCase.peers) > 0 {
+				net.EXPECT().DoLightRequest(testPeer, gomock.Any()).Return(testCase.resp, testCase.respErr)
+			}
+
+			s := NewStorage(blockState, net)
+			value, err := s.GetStorageByBlockHash(&blockHash, []byte("noot"))
+			require.ErrorIs(t, err, testCase.errIs)
+			require.Equal(t, testCase.expected, value)
+		})
+	}
+}
+
+func TestStorage_GetStorage_stateRootNotFound(t *testing.T) {
+	t.Parallel()
+
+	genesis := &types.Header{
+		Number:    big.NewInt(0),
+		StateRoot: common.Hash{1},
+	}
+	header := &types.Header{
+		ParentHash: genesis.Hash(),
+		Number:     big.NewInt(1),
+		StateRoot:  common.Hash{2},
+	}
+
+	ctrl := gomock.NewController(t)
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().BestBlockHash().Return(header.Hash())
+	blockState.EXPECT().GetHeader(header.Hash()).Return(header, nil)
+	blockState.EXPECT().GetHeader(genesis.Hash()).Return(genesis, nil)
+
+	s := NewStorage(blockState, NewMockNetwork(ctrl))
+
+	root := common.Hash{3}
+	_, err := s.GetStorage(&root, []byte("noot"))
+	require.ErrorIs(t, err, errStateRootNotFound)
+}
+
+func TestStorage_unsupported(t *testing.T) {
+	t.Parallel()
+
+	s := NewStorage(nil, nil)
+
+	_, err := s.Entries(nil)
+	require.ErrorIs(t, err, errUnsupported)
+
+	_, err = s.GetKeysWithPrefix(nil, nil)
+	require.ErrorIs(t, err, errUnsupported)
+
+	_, err = s.GetStorageChild(nil, nil)
+	require.ErrorIs(t, err, errUnsupported)
+
+	_, err = s.GetStorageFromChild(nil, nil, nil)
+	require.ErrorIs(t, err, errUnsupported)
+}
@@ -64,6 +64,7 @@ type Config struct {
 	BlockState         BlockState
 	Syncer             Syncer
 	TransactionHandler TransactionHandler
+	StorageState       StorageState
 
 	// Used to specify the address broadcasted to other peers, and avoids using pubip.Get
 	PublicIP string
@@ -91,6 +92,9 @@ type Config struct {
 	// PersistentPeers is a list of multiaddrs which the node should remain connected to
 	PersistentPeers []string
 
+	// ReservedOnly if true, the node only connects to and accepts connections from reserved peers
+	ReservedOnly bool
+
 	// privateKey the private key for the network p2p identity
 	privateKey crypto.PrivKey
 
@@ -231,6 +231,7 @@ func TestSetReservedPeer(t *testing.T) {
 		Port:            availablePort(t),
 		NoMDNS:          true,
 		PersistentPeers: []string{addrA.String(), addrB.String()},
+		ReservedOnly:    true,
 	}
 
 	node3 := createTestService(t, config)
@@ -243,7 +244,48 @@ func TestSetReservedPeer(t *testing.T) {
 	node3.host.cm.peerSetHandler.SetReservedPeer(0, addrC.ID)
 	time.Sleep(200 * time.Millisecond)
 
-	// reservedOnly mode is not yet implemented, so nodeA and nodeB won't be disconnected (#1888).
-	// TODO: once reservedOnly mode is implemented and reservedOnly is set to true, change expected value to 1 (nodeC)
-	require.Equal(t, 3, node3.host.peerCount())
+	// node3 is in reserved-only mode, so nodeA and nodeB are disconnected once they are no longer reserved.
+	require.Equal(t, 1, node3.host.peerCount())
+	require.NotEmpty(t, node3.host.h.Network().ConnsToPeer(addrC.ID))
+}
+
+func TestReservedOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip() // this sometimes fails on CI
+	}
+
+	t.Parallel()
+
+	configA := &Config{
+		BasePath:     t.TempDir(),
+		Port:         availablePort(t),
+		NoBootstrap:  true,
+		NoMDNS:       true,
+		ReservedOnly: true,
+	}
+
+	nodeA := createTestService(t, configA)
+	nodeA.noGossip = true
+
+	addrA := nodeA.host.multiaddrs()[0]
+
+	configB := &Config{
+		BasePath:  t.TempDir(),
+		Port:      availablePort(t),
+		Bootnodes: []string{addrA.String()},
+		NoMDNS:    true,
+	}
+
+	nodeB := createTestService(t, configB)
+	nodeB.noGossip = true
+	time.Sleep(time.Millisecond * 600)
+
+	// nodeB isn't a reserved peer of nodeA, so its connection is refused.
+	require.Equal(t, 0, nodeA.host.peerCount())
+
+	err := nodeA.host.addReservedPeers(nodeB.host.multiaddrs()[0].String())
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond * 200)
+
+	require.Equal(t, 1, nodeA.host.peerCount())
 }
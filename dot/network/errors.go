@@ -14,4 +14,10 @@
 	errMissingHandshakeMutex   = errors.New("outboundHandshakeMutex does not exist")
 	errInvalidHandshakeForPeer = errors.New("peer previously sent invalid handshake")
 	errHandshakeTimeout        = errors.New("handshake timeout reached")
+	errNoStorageState          = errors.New("storage state is not set")
+	errInvalidBlockHash        = errors.New("invalid block hash")
+	errInvalidBlockNumber      = errors.New("invalid block number")
+	errCHTNotFinalised         = errors.New("canonical hash trie is not finalised yet")
+	errTooManyCHTBuilds        = errors.New("too many canonical hash tries built for peer")
+	errInvalidBlockRange       = errors.New("invalid block range")
 )
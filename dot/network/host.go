@@ -16,6 +16,8 @@
 	"github.com/ChainSafe/gossamer/dot/peerset"
 	"github.com/chyeh/pubip"
 	"github.com/dgraph-io/ristretto"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
 	badger "github.com/ipfs/go-ds-badger2"
 	"github.com/libp2p/go-libp2p"
 	libp2phost "github.com/libp2p/go-libp2p-core/host"
@@ -65,6 +67,9 @@ func init() {
 	connectTimeout       = time.Second * 5
 )
 
+// reservedPeersPrefix is the datastore prefix of the reserved peers added at runtime
+var reservedPeersPrefix = datastore.NewKey("/gossamer/reserved")
+
 // host wraps libp2p host with network host configuration and services
 type host struct {
 	ctx             context.Context
@@ -133,11 +138,10 @@ func newHost(ctx context.Context, cfg *Config) (*host, error) {
 
 	// We have tried to set maxInPeers and maxOutPeers such that number of peer
 	// connections remain between min peers and max peers
-	const reservedOnly = false
 	peerCfgSet := peerset.NewConfigSet(
 		uint32(cfg.MaxPeers-cfg.MinPeers),
 		uint32(cfg.MaxPeers/2),
-		reservedOnly,
+		cfg.ReservedOnly,
 		peerSetSlotAllocTime,
 	)
 
@@ -279,6 +283,17 @@ func (h *host) bootstrap() {
 		h.cm.peerSetHandler.AddReservedPeer(0, info.ID)
 	}
 
+	reserved, err := h.loadReservedPeers()
+	if err != nil {
+		logger.Warnf("failed to load reserved peers: %s", err)
+	}
+
+	for _, info := range reserved {
+		h.cm.persistentPeers.Store(info.ID, struct{}{})
+		h.h.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.PermanentAddrTTL)
+		h.cm.peerSetHandler.AddReservedPeer(0, info.ID)
+	}
+
 	for _, addrInfo := range h.bootnodes {
 		logger.Debugf("bootstrapping to peer %s", addrInfo.ID)
 		h.h.Peerstore().AddAddrs(addrInfo.ID, addrInfo.Addrs, peerstore.PermanentAddrTTL)
@@ -354,6 +369,13 @@ func (h *host) addReservedPeers(addrs ...string) error {
 		if err != nil {
 			return err
 		}
+
+		err = h.ds.Put(reservedPeerKey(addrInfo.ID), []byte(addr))
+		if err != nil {
+			return fmt.Errorf("cannot store reserved peer %s: %w", addrInfo.ID, err)
+		}
+
+		h.cm.persistentPeers.Store(addrInfo.ID, struct{}{})
 		h.h.Peerstore().AddAddrs(addrInfo.ID, addrInfo.Addrs, peerstore.PermanentAddrTTL)
 		h.cm.peerSetHandler.AddReservedPeer(0, addrInfo.ID)
 	}
@@ -368,6 +390,13 @@ func (h *host) removeReservedPeers(ids ...string) error {
 		if err != nil {
 			return err
 		}
+
+		err = h.ds.Delete(reservedPeerKey(peerID))
+		if err != nil {
+			return fmt.Errorf("cannot delete reserved peer %s: %w", peerID, err)
+		}
+
+		h.cm.persistentPeers.Delete(peerID)
 		h.cm.peerSetHandler.RemoveReservedPeer(0, peerID)
 		h.h.ConnManager().Unprotect(peerID, "")
 	}
@@ -375,6 +404,42 @@ func (h *host) removeReservedPeers(ids ...string) error {
 	return nil
 }
 
+// reservedPeers returns the ids of the reserved peers
+func (h *host) reservedPeers() []string {
+	peers := <-h.cm.peerSetHandler.ReservedPeers(0)
+	ids := make([]string, len(peers))
+	for i, p := range peers {
+		ids[i] = p.String()
+	}
+
+	return ids
+}
+
+// loadReservedPeers returns the reserved peers added at runtime which were stored in the
+// datastore, so that they are kept across restarts.
+func (h *host) loadReservedPeers() ([]peer.AddrInfo, error) {
+	results, err := h.ds.Query(query.Query{Prefix: reservedPeersPrefix.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := results.Rest()
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, len(entries))
+	for i, entry := range entries {
+		addrs[i] = string(entry.Value)
+	}
+
+	return stringsToAddrInfos(addrs)
+}
+
+func reservedPeerKey(id peer.ID) datastore.Key {
+	return reservedPeersPrefix.ChildString(id.String())
+}
+
 // supportsProtocol checks if the protocol is supported by peerID
 // returns an error if could not get peer protocols
 func (h *host) supportsProtocol(peerID peer.ID, protocol protocol.ID) (bool, error) {
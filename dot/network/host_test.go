@@ -462,6 +462,15 @@ func Test_AddReservedPeers(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	require.Equal(t, 1, nodeA.host.peerCount())
+
+	nodeBID := nodeB.host.id()
+	require.Equal(t, []string{nodeBID.String()}, nodeA.host.reservedPeers())
+
+	// the reserved peer is stored so it's restored on restart
+	reserved, err := nodeA.host.loadReservedPeers()
+	require.NoError(t, err)
+	require.Len(t, reserved, 1)
+	require.Equal(t, nodeBID, reserved[0].ID)
 }
 
 func Test_RemoveReservedPeers(t *testing.T) {
@@ -505,6 +514,11 @@ func Test_RemoveReservedPeers(t *testing.T) {
 	isProtected := nodeA.host.h.ConnManager().IsProtected(nodeB.host.addrInfo().ID, "")
 	require.False(t, isProtected)
 
+	require.Empty(t, nodeA.host.reservedPeers())
+	reserved, err := nodeA.host.loadReservedPeers()
+	require.NoError(t, err)
+	require.Empty(t, reserved)
+
 	err = nodeA.host.removeReservedPeers("unknown_perr_id")
 	require.Error(t, err)
 }
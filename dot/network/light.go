@@ -4,16 +4,84 @@
 package network
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"math/big"
+	"sync"
+	"time"
 
+	"github.com/ChainSafe/gossamer/dot/peerset"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 
 	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
+const (
+	maxLightResponseSize = 1024 * 1024 * 4 // 4mb
+	lightRequestTimeout  = time.Second * 10
+)
+
+// DoLightRequest sends a request to the given peer and returns its response.
+func (s *Service) DoLightRequest(to peer.ID, req *LightRequest) (*LightResponse, error) {
+	fullLightID := s.host.protocolID + lightID
+
+	s.lightRequestMu.Lock()
+	s.lightRequest[to] = struct{}{}
+	s.lightRequestMu.Unlock()
+
+	defer func() {
+		s.lightRequestMu.Lock()
+		delete(s.lightRequest, to)
+		s.lightRequestMu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(s.ctx, lightRequestTimeout)
+	defer cancel()
+
+	stream, err := s.host.h.NewStream(ctx, to, fullLightID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	if err = s.host.writeToStream(stream, req); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxLightResponseSize)
+	n, err := readStream(stream, buf)
+	if err != nil {
+		return nil, fmt.Errorf("read stream error: %w", err)
+	}
+
+	if n == 0 {
+		return nil, errors.New("received empty message")
+	}
+
+	resp, err := newLightResponseFromBytes(buf[:n])
+	if err != nil {
+		s.host.cm.peerSetHandler.ReportPeer(peerset.ReputationChange{
+			Value:  peerset.BadMessageValue,
+			Reason: peerset.BadMessageReason,
+		}, to)
+		return nil, fmt.Errorf("failed to decode light response: %w", err)
+	}
+
+	return resp, nil
+}
+
 // handleLightStream handles streams with the <protocol-id>/light/2 protocol ID
 func (s *Service) handleLightStream(stream libp2pnetwork.Stream) {
 	s.readStream(stream, s.decodeLightMessage, s.handleLightMsg)
@@ -46,15 +114,15 @@ func (s *Service) handleLightMsg(stream libp2pnetwork.Stream, msg Message) (err
 	resp := NewLightResponse()
 	switch {
 	case lr.RemoteCallRequest != nil:
-		resp.RemoteCallResponse, err = remoteCallResp(lr.RemoteCallRequest)
+		resp.RemoteCallResponse, err = s.remoteCallResp(lr.RemoteCallRequest)
 	case lr.RemoteHeaderRequest != nil:
-		resp.RemoteHeaderResponse, err = remoteHeaderResp(lr.RemoteHeaderRequest)
+		resp.RemoteHeaderResponse, err = s.remoteHeaderResp(stream.Conn().RemotePeer(), lr.RemoteHeaderRequest)
 	case lr.RemoteChangesRequest != nil:
-		resp.RemoteChangesResponse, err = remoteChangeResp(lr.RemoteChangesRequest)
+		resp.RemoteChangesResponse, err = s.remoteChangesResp(lr.RemoteChangesRequest)
 	case lr.RemoteReadRequest != nil:
-		resp.RemoteReadResponse, err = remoteReadResp(lr.RemoteReadRequest)
+		resp.RemoteReadResponse, err = s.remoteReadResp(lr.RemoteReadRequest)
 	case lr.RemoteReadChildRequest != nil:
-		resp.RemoteReadResponse, err = remoteReadChildResp(lr.RemoteReadChildRequest)
+		resp.RemoteReadResponse, err = s.remoteReadChildResp(lr.RemoteReadChildRequest)
 	default:
 		logger.Warn("ignoring LightRequest without request data")
 		return nil
@@ -64,9 +132,6 @@ func (s *Service) handleLightMsg(stream libp2pnetwork.Stream, msg Message) (err
 		return err
 	}
 
-	// TODO(arijit): Remove once we implement the internal APIs. Added to increase code coverage. (#1856)
-	logger.Debugf("LightResponse message: %s", resp)
-
 	err = s.host.writeToStream(stream, resp)
 	if err != nil {
 		logger.Warnf("failed to send LightResponse message to peer %s: %s", stream.Conn().RemotePeer(), err)
@@ -130,19 +195,30 @@ func (l *LightRequest) SubProtocol() string {
 	return lightID
 }
 
-// Encode encodes a LightRequest message using SCALE and appends the type byte to the start
+// Encode encodes a LightRequest message using SCALE and appends the type byte to the start.
+// Requests that are not set are encoded as empty requests.
 func (l *LightRequest) Encode() ([]byte, error) {
-	req := request{
-		RemoteCallRequest:      *l.RemoteCallRequest,
-		RemoteReadRequest:      *l.RemoteReadRequest,
-		RemoteHeaderRequest:    *l.RemoteHeaderRequest,
-		RemoteReadChildRequest: *l.RemoteReadChildRequest,
-		RemoteChangesRequest:   *l.RemoteChangesRequest,
+	req := newRequest()
+	if l.RemoteCallRequest != nil {
+		req.RemoteCallRequest = *l.RemoteCallRequest
 	}
-	return scale.Marshal(req)
+	if l.RemoteReadRequest != nil {
+		req.RemoteReadRequest = *l.RemoteReadRequest
+	}
+	if l.RemoteHeaderRequest != nil {
+		req.RemoteHeaderRequest = *l.RemoteHeaderRequest
+	}
+	if l.RemoteReadChildRequest != nil {
+		req.RemoteReadChildRequest = *l.RemoteReadChildRequest
+	}
+	if l.RemoteChangesRequest != nil {
+		req.RemoteChangesRequest = *l.RemoteChangesRequest
+	}
+	return scale.Marshal(*req)
 }
 
-// Decode the message into a LightRequest, it assumes the type byte has been removed
+// Decode the message into a LightRequest, it assumes the type byte has been removed.
+// Empty requests are left unset, so that only the requests made by the peer are non-nil.
 func (l *LightRequest) Decode(in []byte) error {
 	msg := newRequest()
 	err := scale.Unmarshal(in, msg)
@@ -150,11 +226,30 @@ func (l *LightRequest) Decode(in []byte) error {
 		return err
 	}
 
-	l.RemoteCallRequest = &msg.RemoteCallRequest
-	l.RemoteReadRequest = &msg.RemoteReadRequest
-	l.RemoteHeaderRequest = &msg.RemoteHeaderRequest
-	l.RemoteReadChildRequest = &msg.RemoteReadChildRequest
-	l.RemoteChangesRequest = &msg.RemoteChangesRequest
+	l.RemoteCallRequest = nil
+	if msg.RemoteCallRequest.Method != "" {
+		l.RemoteCallRequest = &msg.RemoteCallRequest
+	}
+
+	l.RemoteReadRequest = nil
+	if len(msg.RemoteReadRequest.Keys) > 0 {
+		l.RemoteReadRequest = &msg.RemoteReadRequest
+	}
+
+	l.RemoteHeaderRequest = nil
+	if len(msg.RemoteHeaderRequest.Block) > 0 {
+		l.RemoteHeaderRequest = &msg.RemoteHeaderRequest
+	}
+
+	l.RemoteReadChildRequest = nil
+	if len(msg.RemoteReadChildRequest.Keys) > 0 {
+		l.RemoteReadChildRequest = &msg.RemoteReadChildRequest
+	}
+
+	l.RemoteChangesRequest = nil
+	if msg.RemoteChangesRequest.FirstBlock != nil || msg.RemoteChangesRequest.LastBlock != nil {
+		l.RemoteChangesRequest = &msg.RemoteChangesRequest
+	}
 	return nil
 }
 
@@ -303,7 +398,7 @@ type RemoteChangesRequest struct {
 	Min        []byte
 	Max        []byte
 	StorageKey *[]byte
-	key        []byte
+	Key        []byte
 }
 
 func newRemoteChangesRequest() RemoteChangesRequest {
@@ -313,6 +408,7 @@ func newRemoteChangesRequest() RemoteChangesRequest {
 		Min:        []byte{},
 		Max:        []byte{},
 		StorageKey: nil,
+		Key:        []byte{},
 	}
 }
 
@@ -341,12 +437,13 @@ func newRemoteReadResponse() *RemoteReadResponse {
 // RemoteHeaderResponse ...
 type RemoteHeaderResponse struct {
 	Header []*types.Header
-	proof  []byte
+	Proof  []byte
 }
 
 func newRemoteHeaderResponse() *RemoteHeaderResponse {
 	return &RemoteHeaderResponse{
 		Header: nil,
+		Proof:  []byte{},
 	}
 }
 
@@ -391,7 +488,7 @@ func (rc *RemoteChangesRequest) String() string {
 		string(rc.Min),
 		string(rc.Max),
 		storageKey,
-		string(rc.key),
+		string(rc.Key),
 	)
 }
 
@@ -450,21 +547,628 @@ func (rr *RemoteReadResponse) String() string {
 
 // String formats a RemoteHeaderResponse as a string
 func (rh *RemoteHeaderResponse) String() string {
-	return fmt.Sprintf("Header =%+v Proof =%s", rh.Header, string(rh.proof))
+	return fmt.Sprintf("Header =%+v Proof =%s", rh.Header, string(rh.Proof))
+}
+
+// chtSize is the number of blocks covered by a single canonical hash trie (CHT)
+const chtSize = 2048
+
+// chtCacheSize is the number of canonical hash tries kept in memory
+const chtCacheSize = 16
+
+const (
+	// maxCHTBuildsPerPeer is the number of canonical hash tries a peer can cause to be built
+	// per chtBuildsInterval, the requests answered from the cache are not limited
+	maxCHTBuildsPerPeer = 4
+	chtBuildsInterval   = time.Minute
+)
+
+// maxChangesRange is the maximum number of blocks a RemoteChangesRequest may span
+const maxChangesRange = 128
+
+// remoteCallResp executes the requested runtime call against the state of the requested block,
+// and returns the proof of all the storage entries read during its execution.
+func (s *Service) remoteCallResp(req *RemoteCallRequest) (*RemoteCallResponse, error) {
+	if s.storageState == nil {
+		return nil, errNoStorageState
+	}
+
+	hash, stateRoot, err := s.stateRootAt(req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := s.storageState.TrieState(&stateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get trie state for block %s: %w", hash, err)
+	}
+
+	pool, err := s.blockState.GetRuntimePool(&hash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get runtime for block %s: %w", hash, err)
+	}
+
+	recorder := newRecordingStorage(ts)
+	err = pool.Call(recorder, func(rt runtime.Instance) error {
+		_, err := rt.Exec(req.Method, req.Data)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute %s at block %s: %w", req.Method, hash, err)
+	}
+
+	proof, err := s.storageState.GenerateTrieProof(stateRoot, recorder.recordedKeys())
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate execution proof: %w", err)
+	}
+
+	for keyToChild, keys := range recorder.recordedChildKeys() {
+		childProof, err := s.childReadProof(stateRoot, []byte(keyToChild), keys)
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, childProof...)
+	}
+
+	enc, err := scale.Marshal(dedupProof(proof))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteCallResponse{
+		Proof: enc,
+	}, nil
+}
+
+// remoteChangesResp returns, for each block in the requested range where the value stored
+// at the requested key changed, the block number and state root along with the storage proof
+// of the value at that block.
+func (s *Service) remoteChangesResp(req *RemoteChangesRequest) (*RemoteChangesResponse, error) {
+	if s.storageState == nil {
+		return nil, errNoStorageState
+	}
+
+	if req.FirstBlock == nil || req.LastBlock == nil {
+		return nil, fmt.Errorf("%w: first and last blocks must be set", errInvalidBlockRange)
+	}
+
+	first, err := s.blockState.GetHeader(*req.FirstBlock)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get header for block %s: %w", req.FirstBlock, err)
+	}
+
+	last, err := s.blockState.GetHeader(*req.LastBlock)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get header for block %s: %w", req.LastBlock, err)
+	}
+
+	if last.Number.Cmp(first.Number) < 0 {
+		return nil, fmt.Errorf("%w: last block %s is lower than first block %s",
+			errInvalidBlockRange, last.Number, first.Number)
+	}
+
+	if blocks := new(big.Int).Sub(last.Number, first.Number); blocks.Cmp(big.NewInt(maxChangesRange)) >= 0 {
+		return nil, fmt.Errorf("%w: range of %s blocks exceeds the maximum of %d",
+			errInvalidBlockRange, blocks, maxChangesRange)
+	}
+
+	var keyToChild []byte
+	if req.StorageKey != nil {
+		keyToChild = *req.StorageKey
+	}
+
+	var (
+		prevValue []byte
+		prevRoot  common.Hash
+		proof     [][]byte
+		roots     [][]Pair
+	)
+
+	if first.Number.Sign() > 0 {
+		parent, err := s.blockState.GetHeader(first.ParentHash)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get parent header of block %s: %w", req.FirstBlock, err)
+		}
+
+		prevRoot = parent.StateRoot
+		prevValue, err = s.storageValue(prevRoot, keyToChild, req.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for number := new(big.Int).Set(first.Number); number.Cmp(last.Number) <= 0; number.Add(number, big.NewInt(1)) {
+		hash, err := s.blockState.GetHashByNumber(number)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get hash of block %s: %w", number, err)
+		}
+
+		header, err := s.blockState.GetHeader(hash)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get header for block %s: %w", hash, err)
+		}
+
+		if header.StateRoot == prevRoot {
+			continue
+		}
+		prevRoot = header.StateRoot
+
+		value, err := s.storageValue(header.StateRoot, keyToChild, req.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Equal(value, prevValue) {
+			continue
+		}
+		prevValue = value
+
+		var valueProof [][]byte
+		if keyToChild == nil {
+			valueProof, err = s.storageState.GenerateTrieProof(header.StateRoot, [][]byte{req.Key})
+		} else {
+			valueProof, err = s.childReadProof(header.StateRoot, keyToChild, [][]byte{req.Key})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate proof for block %s: %w", hash, err)
+		}
+		proof = append(proof, valueProof...)
+
+		encNumber, err := scale.Marshal(uint32(number.Uint64()))
+		if err != nil {
+			return nil, err
+		}
+
+		roots = append(roots, []Pair{{
+			First:  encNumber,
+			Second: header.StateRoot.ToBytes(),
+		}})
+	}
+
+	max, err := scale.Marshal(uint32(last.Number.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteChangesResponse{
+		Max:        max,
+		Proof:      dedupProof(proof),
+		Roots:      roots,
+		RootsProof: []byte{},
+	}, nil
+}
+
+// remoteHeaderResp returns the header of the requested block number along with the proof
+// of its hash in the canonical hash trie containing it.
+func (s *Service) remoteHeaderResp(from peer.ID, req *RemoteHeaderRequest) (*RemoteHeaderResponse, error) {
+	var number uint32
+	err := scale.Unmarshal(req.Block, &number)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidBlockNumber, err)
+	}
+
+	if number == 0 {
+		return nil, fmt.Errorf("%w: genesis block is not part of any canonical hash trie", errInvalidBlockNumber)
+	}
+
+	hash, err := s.blockState.GetHashByNumber(big.NewInt(int64(number)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get hash of block %d: %w", number, err)
+	}
+
+	header, err := s.blockState.GetHeader(hash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get header for block %s: %w", hash, err)
+	}
+
+	proof, err := s.chtProof(from, number)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := scale.Marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteHeaderResponse{
+		Header: []*types.Header{header},
+		Proof:  enc,
+	}, nil
+}
+
+// remoteReadChildResp returns the proof of the requested keys in the requested child trie.
+func (s *Service) remoteReadChildResp(req *RemoteReadChildRequest) (*RemoteReadResponse, error) {
+	if s.storageState == nil {
+		return nil, errNoStorageState
+	}
+
+	_, stateRoot, err := s.stateRootAt(req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := s.childReadProof(stateRoot, req.StorageKey, req.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := scale.Marshal(dedupProof(proof))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteReadResponse{
+		Proof: enc,
+	}, nil
 }
 
-func remoteCallResp(_ *RemoteCallRequest) (*RemoteCallResponse, error) {
-	return &RemoteCallResponse{}, nil
+// remoteReadResp returns the proof of the requested keys in the state of the requested block.
+func (s *Service) remoteReadResp(req *RemoteReadRequest) (*RemoteReadResponse, error) {
+	if s.storageState == nil {
+		return nil, errNoStorageState
+	}
+
+	_, stateRoot, err := s.stateRootAt(req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := s.storageState.GenerateTrieProof(stateRoot, req.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate read proof: %w", err)
+	}
+
+	enc, err := scale.Marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteReadResponse{
+		Proof: enc,
+	}, nil
 }
-func remoteChangeResp(_ *RemoteChangesRequest) (*RemoteChangesResponse, error) {
-	return &RemoteChangesResponse{}, nil
+
+// stateRootAt returns the hash and state root of the block referenced by a light request
+func (s *Service) stateRootAt(block []byte) (common.Hash, common.Hash, error) {
+	if len(block) != common.HashLength {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("%w: expected %d bytes, got %d",
+			errInvalidBlockHash, common.HashLength, len(block))
+	}
+
+	hash := common.BytesToHash(block)
+	header, err := s.blockState.GetHeader(hash)
+	if err != nil {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("cannot get header for block %s: %w", hash, err)
+	}
+
+	return hash, header.StateRoot, nil
 }
-func remoteHeaderResp(_ *RemoteHeaderRequest) (*RemoteHeaderResponse, error) {
-	return &RemoteHeaderResponse{}, nil
+
+// storageValue returns the value stored at the given key, in the child trie keyToChild if it is set
+func (s *Service) storageValue(stateRoot common.Hash, keyToChild, key []byte) ([]byte, error) {
+	ts, err := s.storageState.TrieState(&stateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get trie state for state root %s: %w", stateRoot, err)
+	}
+
+	if keyToChild == nil {
+		return ts.Get(key), nil
+	}
+
+	value, err := ts.GetChildStorage(keyToChild, key)
+	if errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+		return nil, nil
+	}
+	return value, err
 }
-func remoteReadChildResp(_ *RemoteReadChildRequest) (*RemoteReadResponse, error) {
-	return &RemoteReadResponse{}, nil
+
+// childReadProof returns the proof of the child trie root in the main trie, followed by the
+// proof of the given keys in the child trie. If the child trie does not exist, only the proof
+// of its absence from the main trie is returned.
+func (s *Service) childReadProof(stateRoot common.Hash, keyToChild []byte, keys [][]byte) ([][]byte, error) {
+	childKey := append(append([]byte{}, trie.ChildStorageKeyPrefix...), keyToChild...)
+	proof, err := s.storageState.GenerateTrieProof(stateRoot, [][]byte{childKey})
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate child trie root proof: %w", err)
+	}
+
+	ts, err := s.storageState.TrieState(&stateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get trie state for state root %s: %w", stateRoot, err)
+	}
+
+	child, err := ts.GetChild(keyToChild)
+	if errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+		return proof, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	childRoot, err := child.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	childProof, err := s.storageState.GenerateTrieProof(childRoot, keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate child trie proof: %w", err)
+	}
+
+	return append(proof, childProof...), nil
+}
+
+// chtCache holds the canonical hash tries of finalised sections. They never change once
+// built, so they are kept until they are evicted in the order they were added.
+type chtCache struct {
+	sync.Mutex
+	tries    map[uint32]*trie.Trie
+	order    []uint32
+	building map[uint32]*chtBuild
+	builds   map[peer.ID][]time.Time // the times at which each peer caused a trie to be built
+}
+
+// chtBuild is a canonical hash trie being built, the requests for its section wait for it
+// to be built instead of building it again
+type chtBuild struct {
+	done chan struct{}
+	trie *trie.Trie
+	err  error
+}
+
+// chtProof returns the proof of the hash of the given block number in the canonical hash
+// trie containing it. The trie is only built once per section and then kept in the cache.
+func (s *Service) chtProof(from peer.ID, number uint32) ([][]byte, error) {
+	cht, err := s.getCHT(from, number)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := cht.GenerateProof([][]byte{chtKey(number)})
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate canonical hash trie proof: %w", err)
+	}
+
+	return proof, nil
 }
-func remoteReadResp(_ *RemoteReadRequest) (*RemoteReadResponse, error) {
-	return &RemoteReadResponse{}, nil
+
+// getCHT returns the canonical hash trie containing the given block number from the cache,
+// or builds it. The trie is built without holding the lock of the cache, so that the requests
+// answered from the cache do not wait for it, and the requests for the same section wait for
+// the trie being built. A peer can only cause maxCHTBuildsPerPeer tries to be built per
+// chtBuildsInterval.
+func (s *Service) getCHT(from peer.ID, number uint32) (*trie.Trie, error) {
+	chtNumber := (number - 1) / chtSize
+
+	s.chtCache.Lock()
+	if cht, ok := s.chtCache.tries[chtNumber]; ok {
+		s.chtCache.Unlock()
+		return cht, nil
+	}
+
+	build, ok := s.chtCache.building[chtNumber]
+	if ok {
+		s.chtCache.Unlock()
+		<-build.done
+		return build.trie, build.err
+	}
+
+	if !s.chtCache.allowBuild(from, time.Now()) {
+		s.chtCache.Unlock()
+		return nil, fmt.Errorf("%w: %s", errTooManyCHTBuilds, from)
+	}
+
+	if s.chtCache.building == nil {
+		s.chtCache.building = make(map[uint32]*chtBuild)
+	}
+
+	build = &chtBuild{done: make(chan struct{})}
+	s.chtCache.building[chtNumber] = build
+	s.chtCache.Unlock()
+
+	build.trie, build.err = s.buildCHT(number)
+
+	s.chtCache.Lock()
+	delete(s.chtCache.building, chtNumber)
+	if build.err == nil {
+		s.chtCache.add(chtNumber, build.trie)
+	}
+	s.chtCache.Unlock()
+	close(build.done)
+
+	return build.trie, build.err
+}
+
+// add adds a canonical hash trie to the cache, evicting the oldest trie if the cache is full.
+// The caller must hold the lock of the cache.
+func (c *chtCache) add(chtNumber uint32, cht *trie.Trie) {
+	if c.tries == nil {
+		c.tries = make(map[uint32]*trie.Trie, chtCacheSize)
+	}
+
+	if len(c.order) == chtCacheSize {
+		delete(c.tries, c.order[0])
+		c.order = c.order[1:]
+	}
+
+	c.tries[chtNumber] = cht
+	c.order = append(c.order, chtNumber)
+}
+
+// allowBuild returns true and records the build if the peer caused less than
+// maxCHTBuildsPerPeer tries to be built in the last chtBuildsInterval. The builds older than
+// chtBuildsInterval are forgotten. The caller must hold the lock of the cache.
+func (c *chtCache) allowBuild(from peer.ID, now time.Time) bool {
+	if c.builds == nil {
+		c.builds = make(map[peer.ID][]time.Time)
+	}
+
+	for p, times := range c.builds {
+		recent := times[:0]
+		for _, t := range times {
+			if now.Sub(t) < chtBuildsInterval {
+				recent = append(recent, t)
+			}
+		}
+
+		if len(recent) == 0 {
+			delete(c.builds, p)
+			continue
+		}
+		c.builds[p] = recent
+	}
+
+	if len(c.builds[from]) >= maxCHTBuildsPerPeer {
+		return false
+	}
+
+	c.builds[from] = append(c.builds[from], now)
+	return true
+}
+
+// buildCHT builds the canonical hash trie containing the given block number. It maps the
+// number of every block in the CHT range to its hash, and can only be built once the
+// whole range is finalised.
+func (s *Service) buildCHT(number uint32) (*trie.Trie, error) {
+	first, last := chtRange(number)
+
+	finalised, err := s.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get highest finalised header: %w", err)
+	}
+
+	if finalised.Number.Cmp(big.NewInt(int64(last))) < 0 {
+		return nil, fmt.Errorf("%w: block %d is above highest finalised block %s",
+			errCHTNotFinalised, last, finalised.Number)
+	}
+
+	cht := trie.NewEmptyTrie()
+	for n := first; n <= last; n++ {
+		hash, err := s.blockState.GetHashByNumber(big.NewInt(int64(n)))
+		if err != nil {
+			return nil, fmt.Errorf("cannot get hash of block %d: %w", n, err)
+		}
+
+		cht.Put(chtKey(n), hash.ToBytes())
+	}
+
+	return cht, nil
+}
+
+// chtRange returns the first and last block numbers of the canonical hash trie containing
+// the given block number
+func chtRange(number uint32) (first, last uint32) {
+	chtNumber := (number - 1) / chtSize
+	first = chtNumber*chtSize + 1
+	last = first + chtSize - 1
+	return first, last
+}
+
+// chtKey returns the key of a block number in a canonical hash trie
+func chtKey(number uint32) []byte {
+	key := make([]byte, 4)
+	binary.LittleEndian.PutUint32(key, number)
+	return key
+}
+
+// dedupProof removes duplicate nodes from a proof
+func dedupProof(proof [][]byte) [][]byte {
+	seen := make(map[string]struct{}, len(proof))
+	deduped := make([][]byte, 0, len(proof))
+	for _, node := range proof {
+		if _, ok := seen[string(node)]; ok {
+			continue
+		}
+		seen[string(node)] = struct{}{}
+		deduped = append(deduped, node)
+	}
+	return deduped
+}
+
+// recordingStorage is a runtime storage recording all the keys read from the
+// trie state it wraps, so that a proof of execution can be generated
+type recordingStorage struct {
+	*rtstorage.TrieState
+	keys      map[string]struct{}
+	childKeys map[string]map[string]struct{}
+}
+
+func newRecordingStorage(ts *rtstorage.TrieState) *recordingStorage {
+	return &recordingStorage{
+		TrieState: ts,
+		keys:      make(map[string]struct{}),
+		childKeys: make(map[string]map[string]struct{}),
+	}
+}
+
+func (r *recordingStorage) recordChild(keyToChild []byte, keys ...[]byte) {
+	childKeys, ok := r.childKeys[string(keyToChild)]
+	if !ok {
+		childKeys = make(map[string]struct{})
+		r.childKeys[string(keyToChild)] = childKeys
+	}
+
+	for _, key := range keys {
+		childKeys[string(key)] = struct{}{}
+	}
+}
+
+// Get records the key and returns the value stored at it
+func (r *recordingStorage) Get(key []byte) []byte {
+	r.keys[string(key)] = struct{}{}
+	return r.TrieState.Get(key)
+}
+
+// NextKey records the key and the next key, and returns the next key
+func (r *recordingStorage) NextKey(key []byte) []byte {
+	next := r.TrieState.NextKey(key)
+	r.keys[string(key)] = struct{}{}
+	if next != nil {
+		r.keys[string(next)] = struct{}{}
+	}
+	return next
+}
+
+// LoadCode records the runtime code key and returns the runtime code
+func (r *recordingStorage) LoadCode() []byte {
+	r.keys[string(common.CodeKey)] = struct{}{}
+	return r.TrieState.LoadCode()
+}
+
+// GetChildStorage records the child key and returns the value stored at it
+func (r *recordingStorage) GetChildStorage(keyToChild, key []byte) ([]byte, error) {
+	r.recordChild(keyToChild, key)
+	return r.TrieState.GetChildStorage(keyToChild, key)
+}
+
+// GetChildNextKey records the child key and the next child key, and returns the next child key
+func (r *recordingStorage) GetChildNextKey(keyToChild, key []byte) ([]byte, error) {
+	next, err := r.TrieState.GetChildNextKey(keyToChild, key)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordChild(keyToChild, key)
+	if next != nil {
+		r.recordChild(keyToChild, next)
+	}
+	return next, nil
+}
+
+func (r *recordingStorage) recordedKeys() [][]byte {
+	keys := make([][]byte, 0, len(r.keys))
+	for key := range r.keys {
+		keys = append(keys, []byte(key))
+	}
+	return keys
+}
+
+func (r *recordingStorage) recordedChildKeys() map[string][][]byte {
+	childKeys := make(map[string][][]byte, len(r.childKeys))
+	for keyToChild, keys := range r.childKeys {
+		for key := range keys {
+			childKeys[keyToChild] = append(childKeys[keyToChild], []byte(key))
+		}
+	}
+	return childKeys
 }
@@ -4,34 +4,65 @@
 package network
 
 import (
+	"errors"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
 
+	"github.com/golang/mock/gomock"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+//go:generate mockgen -destination=mock_storage_state_test.go -package $GOPACKAGE . StorageState
+
 func TestEncodeLightRequest(t *testing.T) {
 	t.Parallel()
-	exp := common.MustHexToBytes("0x0000000000000000000000000000")
+	exp := common.MustHexToBytes("0x000000000000000000000000000000")
 
 	testLightRequest := NewLightRequest()
 	enc, err := testLightRequest.Encode()
 	require.NoError(t, err)
 	require.Equal(t, exp, enc)
 
+	// empty requests are decoded as unset
 	testLightRequest2 := NewLightRequest()
 	err = testLightRequest2.Decode(enc)
 	require.NoError(t, err)
-	require.Equal(t, testLightRequest, testLightRequest2)
+	require.Equal(t, &LightRequest{}, testLightRequest2)
+
+	enc, err = testLightRequest2.Encode()
+	require.NoError(t, err)
+	require.Equal(t, exp, enc)
+
+	testLightRequest3 := &LightRequest{
+		RemoteReadRequest: &RemoteReadRequest{
+			Block: common.Hash{1}.ToBytes(),
+			Keys:  [][]byte{[]byte("noot")},
+		},
+	}
+	enc, err = testLightRequest3.Encode()
+	require.NoError(t, err)
+
+	testLightRequest4 := NewLightRequest()
+	err = testLightRequest4.Decode(enc)
+	require.NoError(t, err)
+	require.Equal(t, testLightRequest3, testLightRequest4)
 }
 
 func TestEncodeLightResponse(t *testing.T) {
 	t.Parallel()
-	exp := common.MustHexToBytes("0x00000000000000")
+	exp := common.MustHexToBytes("0x0000000000000000")
 
 	testLightResponse := NewLightResponse()
 	enc, err := testLightResponse.Encode()
@@ -158,3 +189,500 @@ func TestHandleLightMessage_Response(t *testing.T) {
 	err = s.handleLightMsg(stream, msg)
 	require.Error(t, err, expectedErr, msg.String())
 }
+
+func TestService_DoLightRequest(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	tr := trie.NewEmptyTrie()
+	tr.Put([]byte("noot"), common.Hash{1}.ToBytes())
+	tr.Put([]byte("other"), common.Hash{2}.ToBytes())
+	stateRoot := tr.MustHash()
+
+	header := &types.Header{
+		Number:    big.NewInt(1),
+		StateRoot: stateRoot,
+		Digest:    types.NewDigest(),
+	}
+	blockHash := header.Hash()
+
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().BestBlockHeader().Return(header, nil).AnyTimes()
+	blockState.EXPECT().GetHighestFinalisedHeader().Return(header, nil).AnyTimes()
+	blockState.EXPECT().GenesisHash().Return(common.NewHash([]byte{})).AnyTimes()
+	blockState.EXPECT().BestBlockNumber().Return(big.NewInt(1), nil).AnyTimes()
+	blockState.EXPECT().GetHeader(blockHash).Return(header, nil).AnyTimes()
+
+	config := &Config{
+		BasePath:    t.TempDir(),
+		Port:        availablePort(t),
+		NoBootstrap: true,
+		NoMDNS:      true,
+	}
+	s := createTestService(t, config)
+
+	configB := &Config{
+		BasePath:     t.TempDir(),
+		Port:         availablePort(t),
+		NoBootstrap:  true,
+		NoMDNS:       true,
+		BlockState:   blockState,
+		StorageState: newTestStorageState(t, ctrl, tr),
+	}
+	b := createTestService(t, configB)
+
+	addrInfoB := b.host.addrInfo()
+	err := s.host.connect(addrInfoB)
+	// retry connect if "failed to dial" error
+	if failedToDial(err) {
+		time.Sleep(TestBackoffTimeout)
+		err = s.host.connect(addrInfoB)
+	}
+	require.NoError(t, err)
+
+	resp, err := s.DoLightRequest(b.host.id(), &LightRequest{
+		RemoteReadRequest: &RemoteReadRequest{
+			Block: blockHash.ToBytes(),
+			Keys:  [][]byte{[]byte("noot")},
+		},
+	})
+	require.NoError(t, err)
+
+	proof := decodeTestProof(t, resp.RemoteReadResponse.Proof)
+	value, err := trie.GetFromProof(proof, stateRoot.ToBytes(), []byte("noot"))
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{1}.ToBytes(), value)
+}
+
+// newTestStorageState stores the given trie in an in-memory database and returns
+// a storage state mock serving trie states and proofs from it.
+func newTestStorageState(t *testing.T, ctrl *gomock.Controller, tr *trie.Trie) *MockStorageState {
+	t.Helper()
+
+	db := runtime.NewInMemoryDB(t)
+	err := tr.Store(db)
+	require.NoError(t, err)
+
+	storageState := NewMockStorageState(ctrl)
+	storageState.EXPECT().TrieState(gomock.Any()).DoAndReturn(
+		func(root *common.Hash) (*rtstorage.TrieState, error) {
+			loaded := trie.NewEmptyTrie()
+			err := loaded.Load(db, *root)
+			if err != nil {
+				return nil, err
+			}
+			return rtstorage.NewTrieState(loaded)
+		}).AnyTimes()
+	storageState.EXPECT().GenerateTrieProof(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(root common.Hash, keys [][]byte) ([][]byte, error) {
+			return trie.GenerateProof(root.ToBytes(), keys, db)
+		}).AnyTimes()
+
+	return storageState
+}
+
+func decodeTestProof(t *testing.T, enc []byte) [][]byte {
+	t.Helper()
+
+	var proof [][]byte
+	err := scale.Unmarshal(enc, &proof)
+	require.NoError(t, err)
+	return proof
+}
+
+func TestService_remoteReadResp(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	tr := trie.NewEmptyTrie()
+	tr.Put([]byte("noot"), common.Hash{1}.ToBytes())
+	tr.Put([]byte("other"), common.Hash{2}.ToBytes())
+	stateRoot := tr.MustHash()
+
+	blockHash := common.Hash{1}
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHeader(blockHash).Return(&types.Header{StateRoot: stateRoot}, nil)
+
+	s := &Service{
+		blockState:   blockState,
+		storageState: newTestStorageState(t, ctrl, tr),
+	}
+
+	resp, err := s.remoteReadResp(&RemoteReadRequest{
+		Block: blockHash.ToBytes(),
+		Keys:  [][]byte{[]byte("noot")},
+	})
+	require.NoError(t, err)
+
+	proof := decodeTestProof(t, resp.Proof)
+	ok, err := trie.VerifyProof(proof, stateRoot.ToBytes(), []trie.Pair{
+		{Key: []byte("noot"), Value: common.Hash{1}.ToBytes()},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = s.remoteReadResp(&RemoteReadRequest{Block: []byte{1}})
+	require.ErrorIs(t, err, errInvalidBlockHash)
+}
+
+func TestService_remoteReadChildResp(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	child := trie.NewEmptyTrie()
+	child.Put([]byte("childkey"), common.Hash{3}.ToBytes())
+	childRoot := child.MustHash()
+
+	tr := trie.NewEmptyTrie()
+	tr.Put([]byte("noot"), common.Hash{1}.ToBytes())
+	err := tr.PutChild([]byte("child"), child)
+	require.NoError(t, err)
+	stateRoot := tr.MustHash()
+
+	blockHash := common.Hash{1}
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHeader(blockHash).Return(&types.Header{StateRoot: stateRoot}, nil)
+
+	s := &Service{
+		blockState:   blockState,
+		storageState: newTestStorageState(t, ctrl, tr),
+	}
+
+	resp, err := s.remoteReadChildResp(&RemoteReadChildRequest{
+		Block:      blockHash.ToBytes(),
+		StorageKey: []byte("child"),
+		Keys:       [][]byte{[]byte("childkey")},
+	})
+	require.NoError(t, err)
+
+	proof := decodeTestProof(t, resp.Proof)
+	childKey := append(append([]byte{}, trie.ChildStorageKeyPrefix...), []byte("child")...)
+	ok, err := trie.VerifyProof(proof, stateRoot.ToBytes(), []trie.Pair{
+		{Key: childKey, Value: childRoot.ToBytes()},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = trie.VerifyProof(proof, childRoot.ToBytes(), []trie.Pair{
+		{Key: []byte("childkey"), Value: common.Hash{3}.ToBytes()},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestService_remoteCallResp(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	tr := trie.NewEmptyTrie()
+	tr.Put(common.CodeKey, common.Hash{4}.ToBytes())
+	tr.Put([]byte("noot"), common.Hash{1}.ToBytes())
+	tr.Put([]byte("other"), common.Hash{2}.ToBytes())
+	stateRoot := tr.MustHash()
+
+	blockHash := common.Hash{1}
+
+	var storage runtime.Storage
+	instance := new(mocks.Instance)
+	instance.On("SetContextStorage", mock.Anything).Run(func(args mock.Arguments) {
+		storage = args.Get(0).(runtime.Storage)
+	})
+	instance.On("Exec", "Core_version", []byte{1}).Run(func(_ mock.Arguments) {
+		storage.Get([]byte("noot"))
+	}).Return([]byte{}, nil)
+
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHeader(blockHash).Return(&types.Header{StateRoot: stateRoot}, nil)
+	pool, err := runtime.NewInstancePool(instance, 1)
+	require.NoError(t, err)
+	blockState.EXPECT().GetRuntimePool(&blockHash).Return(pool, nil)
+
+	s := &Service{
+		blockState:   blockState,
+		storageState: newTestStorageState(t, ctrl, tr),
+	}
+
+	resp, err := s.remoteCallResp(&RemoteCallRequest{
+		Block:  blockHash.ToBytes(),
+		Method: "Core_version",
+		Data:   []byte{1},
+	})
+	require.NoError(t, err)
+	instance.AssertExpectations(t)
+
+	proof := decodeTestProof(t, resp.Proof)
+	ok, err := trie.VerifyProof(proof, stateRoot.ToBytes(), []trie.Pair{
+		{Key: []byte("noot"), Value: common.Hash{1}.ToBytes()},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestService_remoteHeaderResp(t *testing.T) {
+	t.Parallel()
+
+	hashOf := func(number *big.Int) common.Hash {
+		return common.BytesToHash(number.Bytes())
+	}
+
+	testCases := map[string]struct {
+		number      uint32
+		finalised   int64
+		errSentinel error
+	}{
+		"genesis": {
+			number:      0,
+			finalised:   chtSize,
+			errSentinel: errInvalidBlockNumber,
+		},
+		"not finalised": {
+			number:      1,
+			finalised:   chtSize - 1,
+			errSentinel: errCHTNotFinalised,
+		},
+		"first CHT": {
+			number:    7,
+			finalised: chtSize,
+		},
+		"second CHT": {
+			number:    chtSize + 1,
+			finalised: 2 * chtSize,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			blockState := NewMockBlockState(ctrl)
+			blockState.EXPECT().GetHashByNumber(gomock.Any()).DoAndReturn(
+				func(number *big.Int) (common.Hash, error) {
+					return hashOf(number), nil
+				}).AnyTimes()
+			blockState.EXPECT().GetHeader(gomock.Any()).DoAndReturn(
+				func(hash common.Hash) (*types.Header, error) {
+					return &types.Header{
+						Number: big.NewInt(int64(testCase.number)),
+						Digest: types.NewDigest(),
+					}, nil
+				}).AnyTimes()
+			blockState.EXPECT().GetHighestFinalisedHeader().Return(
+				&types.Header{Number: big.NewInt(testCase.finalised)}, nil).AnyTimes()
+
+			s := &Service{
+				blockState: blockState,
+			}
+
+			block, err := scale.Marshal(testCase.number)
+			require.NoError(t, err)
+
+			resp, err := s.remoteHeaderResp(peer.ID("alice"), &RemoteHeaderRequest{Block: block})
+			if testCase.errSentinel != nil {
+				assert.True(t, errors.Is(err, testCase.errSentinel))
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, resp.Header, 1)
+
+			first, last := chtRange(testCase.number)
+			cht := trie.NewEmptyTrie()
+			for n := first; n <= last; n++ {
+				hash := hashOf(big.NewInt(int64(n)))
+				cht.Put(chtKey(n), hash.ToBytes())
+			}
+
+			proof := decodeTestProof(t, resp.Proof)
+			expectedHash := hashOf(big.NewInt(int64(testCase.number)))
+			ok, err := trie.VerifyProof(proof, cht.MustHash().ToBytes(), []trie.Pair{
+				{Key: chtKey(testCase.number), Value: expectedHash.ToBytes()},
+			})
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func TestService_remoteHeaderResp_cachesCHT(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	// the hashes of the CHT range are only loaded once, then one hash is loaded per request
+	const requests = 3
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHashByNumber(gomock.Any()).DoAndReturn(
+		func(number *big.Int) (common.Hash, error) {
+			return common.BytesToHash(number.Bytes()), nil
+		}).Times(chtSize + requests)
+	blockState.EXPECT().GetHeader(gomock.Any()).Return(
+		&types.Header{Number: big.NewInt(1), Digest: types.NewDigest()}, nil).Times(requests)
+	blockState.EXPECT().GetHighestFinalisedHeader().Return(
+		&types.Header{Number: big.NewInt(chtSize)}, nil).Times(1)
+
+	s := &Service{
+		blockState: blockState,
+	}
+
+	for number := uint32(1); number <= requests; number++ {
+		block, err := scale.Marshal(number)
+		require.NoError(t, err)
+
+		_, err = s.remoteHeaderResp(peer.ID("alice"), &RemoteHeaderRequest{Block: block})
+		require.NoError(t, err)
+	}
+}
+
+func TestService_remoteHeaderResp_buildsCHTWithoutLock(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	// the build of the second CHT blocks until released
+	release := make(chan struct{})
+	building := make(chan struct{}, 1)
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHashByNumber(gomock.Any()).DoAndReturn(
+		func(number *big.Int) (common.Hash, error) {
+			if number.Int64() == 2*chtSize {
+				select {
+				case building <- struct{}{}:
+				default:
+				}
+				<-release
+			}
+			return common.BytesToHash(number.Bytes()), nil
+		}).AnyTimes()
+	blockState.EXPECT().GetHeader(gomock.Any()).Return(
+		&types.Header{Number: big.NewInt(1), Digest: types.NewDigest()}, nil).AnyTimes()
+	blockState.EXPECT().GetHighestFinalisedHeader().Return(
+		&types.Header{Number: big.NewInt(2 * chtSize)}, nil).Times(2)
+
+	s := &Service{
+		blockState: blockState,
+	}
+
+	request := func(from peer.ID, number uint32) error {
+		block, err := scale.Marshal(number)
+		require.NoError(t, err)
+		_, err = s.remoteHeaderResp(from, &RemoteHeaderRequest{Block: block})
+		return err
+	}
+
+	require.NoError(t, request("alice", 1))
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- request("alice", chtSize+1)
+	}()
+	<-building
+
+	// the requests for the same CHT wait for the build instead of building it again
+	go func() {
+		errs <- request("bob", chtSize+2)
+	}()
+
+	// the requests answered from the cache don't wait for the build
+	require.NoError(t, request("bob", 2))
+
+	select {
+	case err := <-errs:
+		t.Fatalf("request completed while the CHT is being built: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+}
+
+func TestService_remoteHeaderResp_limitsCHTBuilds(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHashByNumber(gomock.Any()).DoAndReturn(
+		func(number *big.Int) (common.Hash, error) {
+			return common.BytesToHash(number.Bytes()), nil
+		}).AnyTimes()
+	blockState.EXPECT().GetHeader(gomock.Any()).Return(
+		&types.Header{Number: big.NewInt(1), Digest: types.NewDigest()}, nil).AnyTimes()
+	blockState.EXPECT().GetHighestFinalisedHeader().Return(
+		&types.Header{Number: big.NewInt(chtCacheSize * chtSize)}, nil).AnyTimes()
+
+	s := &Service{
+		blockState: blockState,
+	}
+
+	request := func(from peer.ID, number uint32) error {
+		block, err := scale.Marshal(number)
+		require.NoError(t, err)
+		_, err = s.remoteHeaderResp(from, &RemoteHeaderRequest{Block: block})
+		return err
+	}
+
+	for i := uint32(0); i < maxCHTBuildsPerPeer; i++ {
+		require.NoError(t, request("alice", i*chtSize+1))
+	}
+
+	// the peer cannot cause another CHT to be built, but the cached CHTs are still served
+	err := request("alice", maxCHTBuildsPerPeer*chtSize+1)
+	require.ErrorIs(t, err, errTooManyCHTBuilds)
+	require.NoError(t, request("alice", 1))
+
+	// other peers are not limited by the builds of the peer
+	require.NoError(t, request("bob", maxCHTBuildsPerPeer*chtSize+1))
+}
+
+func Test_chtCache_allowBuild(t *testing.T) {
+	t.Parallel()
+
+	var c chtCache
+	now := time.Now()
+	for i := 0; i < maxCHTBuildsPerPeer; i++ {
+		require.True(t, c.allowBuild("alice", now))
+	}
+	require.False(t, c.allowBuild("alice", now))
+	require.True(t, c.allowBuild("bob", now))
+
+	// the builds older than the interval are forgotten
+	later := now.Add(chtBuildsInterval)
+	require.True(t, c.allowBuild("alice", later))
+	require.Len(t, c.builds["alice"], 1)
+	require.Len(t, c.builds, 1)
+}
+
+func Test_chtRange(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		number      uint32
+		first, last uint32
+	}{
+		"first block": {
+			number: 1,
+			first:  1,
+			last:   chtSize,
+		},
+		"last block of first CHT": {
+			number: chtSize,
+			first:  1,
+			last:   chtSize,
+		},
+		"first block of second CHT": {
+			number: chtSize + 1,
+			first:  chtSize + 1,
+			last:   2 * chtSize,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			first, last := chtRange(testCase.number)
+			assert.Equal(t, testCase.first, first)
+			assert.Equal(t, testCase.last, last)
+		})
+	}
+}
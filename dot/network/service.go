@@ -13,6 +13,7 @@
 
 	"github.com/ChainSafe/gossamer/dot/peerset"
 	"github.com/ChainSafe/gossamer/dot/telemetry"
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/internal/metrics"
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -30,11 +31,16 @@
 
 	// the following are sub-protocols used by the node
 	syncID          = "/sync/2"
+	warpSyncID      = "/sync/warp"
+	stateID         = "/state/2"
 	lightID         = "/light/2"
 	blockAnnounceID = "/block-announces/1"
 	transactionsID  = "/transactions/1"
 
 	maxMessageSize = 1024 * 63 // 63kb for now
+
+	// reservedPeerRetryDelay is how long we wait before dialing a reserved peer again
+	reservedPeerRetryDelay = time.Second * 10
 )
 
 var (
@@ -115,11 +121,14 @@ type Service struct {
 
 	lightRequest   map[peer.ID]struct{} // set if we have sent a light request message to the given peer
 	lightRequestMu sync.RWMutex
+	chtCache       chtCache
 
 	// Service interfaces
 	blockState         BlockState
 	syncer             Syncer
 	transactionHandler TransactionHandler
+	storageState       StorageState
+	warpSyncProvider   WarpSyncProvider
 
 	// Configuration options
 	noBootstrap bool
@@ -201,6 +210,7 @@ func NewService(cfg *Config) (*Service, error) {
 		gossip:                 newGossip(),
 		blockState:             cfg.BlockState,
 		transactionHandler:     cfg.TransactionHandler,
+		storageState:           cfg.StorageState,
 		noBootstrap:            cfg.NoBootstrap,
 		noMDNS:                 cfg.NoMDNS,
 		syncer:                 cfg.Syncer,
@@ -234,7 +244,9 @@ func (s *Service) Start() error {
 		return errors.New("service Syncer is nil")
 	}
 
-	if s.transactionHandler == nil {
+	// light clients do not have a transaction pool
+	isLightClient := s.cfg.Roles == types.LightClientRole
+	if s.transactionHandler == nil && !isLightClient {
 		return errors.New("service TransactionHandler is nil")
 	}
 
@@ -245,6 +257,14 @@ func (s *Service) Start() error {
 	s.host.registerStreamHandler(s.host.protocolID+syncID, s.handleSyncStream)
 	s.host.registerStreamHandler(s.host.protocolID+lightID, s.handleLightStream)
 
+	if s.storageState != nil {
+		s.host.registerStreamHandler(s.host.protocolID+stateID, s.handleStateStream)
+	}
+
+	if s.warpSyncProvider != nil {
+		s.host.registerStreamHandler(s.host.protocolID+warpSyncID, s.handleWarpSyncStream)
+	}
+
 	// register block announce protocol
 	err := s.RegisterNotificationsProtocol(
 		s.host.protocolID+blockAnnounceID,
@@ -261,22 +281,24 @@ func (s *Service) Start() error {
 			blockAnnounceID, err)
 	}
 
-	txnBatch := make(chan *BatchMessage, s.cfg.batchSize)
-	txnBatchHandler := s.createBatchMessageHandler(txnBatch)
-
-	// register transactions protocol
-	err = s.RegisterNotificationsProtocol(
-		s.host.protocolID+transactionsID,
-		TransactionMsgType,
-		s.getTransactionHandshake,
-		decodeTransactionHandshake,
-		validateTransactionHandshake,
-		decodeTransactionMessage,
-		s.handleTransactionMessage,
-		txnBatchHandler,
-	)
-	if err != nil {
-		logger.Warnf("failed to register notifications protocol with transaction id %s: %s", transactionsID, err)
+	if !isLightClient {
+		txnBatch := make(chan *BatchMessage, s.cfg.batchSize)
+		txnBatchHandler := s.createBatchMessageHandler(txnBatch)
+
+		// register transactions protocol
+		err = s.RegisterNotificationsProtocol(
+			s.host.protocolID+transactionsID,
+			TransactionMsgType,
+			s.getTransactionHandshake,
+			decodeTransactionHandshake,
+			validateTransactionHandshake,
+			decodeTransactionMessage,
+			s.handleTransactionMessage,
+			txnBatchHandler,
+		)
+		if err != nil {
+			logger.Warnf("failed to register notifications protocol with transaction id %s: %s", transactionsID, err)
+		}
 	}
 
 	// since this opens block announce streams, it should happen after the protocol is registered
@@ -298,6 +320,11 @@ func (s *Service) Start() error {
 			prtl.inboundHandshakeData.Delete(peerID)
 			prtl.outboundHandshakeData.Delete(peerID)
 		}
+
+		// let the peerSet know a reserved peer is gone, so it reconnects to it.
+		if s.host.cm.isPersistent(peerID) && len(s.host.h.Network().ConnsToPeer(peerID)) == 0 {
+			s.host.cm.peerSetHandler.DisconnectPeer(0, peerID)
+		}
 	}
 
 	// log listening addresses to console
@@ -667,6 +694,11 @@ func (s *Service) RemoveReservedPeers(addrs ...string) error {
 	return s.host.removeReservedPeers(addrs...)
 }
 
+// ReservedPeers returns the peer ids of the reserved peers
+func (s *Service) ReservedPeers() []string {
+	return s.host.reservedPeers()
+}
+
 // NodeRoles Returns the roles the node is running as.
 func (s *Service) NodeRoles() byte {
 	return s.cfg.Roles
@@ -725,6 +757,9 @@ func (s *Service) processMessage(msg peerset.Message) {
 		err := s.host.connect(addrInfo)
 		if err != nil {
 			logger.Warnf("failed to open connection for peer %s: %s", peerID, err)
+			if s.host.cm.isPersistent(peerID) {
+				go s.retryReservedPeer(peerID)
+			}
 			return
 		}
 		logger.Debugf("connection successful with peer %s", peerID)
@@ -738,6 +773,19 @@ func (s *Service) processMessage(msg peerset.Message) {
 	}
 }
 
+// retryReservedPeer marks the reserved peer as disconnected in the peerSet after
+// reservedPeerRetryDelay, so the peerSet tries to connect to it again.
+func (s *Service) retryReservedPeer(peerID peer.ID) {
+	timer := time.NewTimer(reservedPeerRetryDelay)
+	defer timer.Stop()
+
+	select {
+	case <-s.ctx.Done():
+	case <-timer.C:
+		s.host.cm.peerSetHandler.DisconnectPeer(0, peerID)
+	}
+}
+
 func (s *Service) startProcessingMsg() {
 	msgCh := s.host.cm.peerSetHandler.Messages()
 	for {
@@ -12,6 +12,8 @@
 	"github.com/ChainSafe/gossamer/dot/peerset"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 )
 
 // BlockState interface for block state methods
@@ -22,6 +24,14 @@ type BlockState interface {
 	HasBlockBody(common.Hash) (bool, error)
 	GetHighestFinalisedHeader() (*types.Header, error)
 	GetHashByNumber(num *big.Int) (common.Hash, error)
+	GetHeader(common.Hash) (*types.Header, error)
+	GetRuntimePool(*common.Hash) (*runtime.InstancePool, error)
+}
+
+// StorageState interface for storage state methods used to answer light client requests
+type StorageState interface {
+	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
+	GenerateTrieProof(stateRoot common.Hash, keys [][]byte) ([][]byte, error)
 }
 
 // Syncer is implemented by the syncing service
@@ -74,5 +84,6 @@ type PeerRemove interface {
 type Peer interface {
 	PeerReputation(peer.ID) (peerset.Reputation, error)
 	SortedPeers(idx int) chan peer.IDSlice
+	ReservedPeers(idx int) chan peer.IDSlice
 	Messages() chan peerset.Message
 }
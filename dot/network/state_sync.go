@@ -0,0 +1,399 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/peerset"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// maxStateResponseEntriesSize is the size of the entries after which a state response is cut
+	maxStateResponseEntriesSize = 1024 * 1024 // 1mb
+	stateRequestTimeout         = time.Second * 20
+)
+
+var (
+	errNilStorageState     = errors.New("storage state is nil")
+	errInvalidStateRequest = errors.New("invalid state request")
+)
+
+// StateRequest is sent to download the storage entries of the state at the given block.
+// Start is empty to start from the first key of the state, it is the last received key
+// to continue a download, and it holds the child storage key followed by the last
+// received key in the child trie to download a child trie.
+type StateRequest struct {
+	Block   common.Hash
+	Start   [][]byte
+	NoProof bool
+}
+
+// StateEntry is a storage entry of a StateResponse
+type StateEntry struct {
+	Key, Value []byte
+}
+
+// KeyValueStateEntry is a set of storage entries of the state or of one of its child tries
+type KeyValueStateEntry struct {
+	// StateRoot is empty for the entries of the state, and is the child storage
+	// key for the entries of a child trie
+	StateRoot []byte
+	Entries   []StateEntry
+	// Complete is true if there are no more entries after the last one
+	Complete bool
+}
+
+// StateResponse is the response to a StateRequest. Unless the request set NoProof,
+// Proof is the SCALE encoded list of trie nodes proving the entries.
+type StateResponse struct {
+	Entries []KeyValueStateEntry
+	Proof   []byte
+}
+
+// SubProtocol returns the state sub-protocol
+func (r *StateRequest) SubProtocol() string {
+	return stateID
+}
+
+// Encode returns the protobuf encoding of the StateRequest
+func (r *StateRequest) Encode() ([]byte, error) {
+	var enc []byte
+	enc = protowire.AppendTag(enc, 1, protowire.BytesType)
+	enc = protowire.AppendBytes(enc, r.Block[:])
+
+	for _, start := range r.Start {
+		enc = protowire.AppendTag(enc, 2, protowire.BytesType)
+		enc = protowire.AppendBytes(enc, start)
+	}
+
+	if r.NoProof {
+		enc = protowire.AppendTag(enc, 3, protowire.VarintType)
+		enc = protowire.AppendVarint(enc, protowire.EncodeBool(r.NoProof))
+	}
+
+	return enc, nil
+}
+
+// Decode decodes the protobuf encoded StateRequest
+func (r *StateRequest) Decode(in []byte) error {
+	*r = StateRequest{}
+	return decodeProtobuf(in, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		switch num {
+		case 1:
+			if len(value) != len(r.Block) {
+				return fmt.Errorf("%w: invalid block hash length %d", errInvalidStateRequest, len(value))
+			}
+			copy(r.Block[:], value)
+		case 2:
+			r.Start = append(r.Start, value)
+		case 3:
+			r.NoProof = protowire.DecodeBool(varint)
+		}
+		return nil
+	})
+}
+
+// String formats a StateRequest as a string
+func (r *StateRequest) String() string {
+	return fmt.Sprintf("StateRequest Block=%s Start=%d keys NoProof=%t", r.Block, len(r.Start), r.NoProof)
+}
+
+// SubProtocol returns the state sub-protocol
+func (r *StateResponse) SubProtocol() string {
+	return stateID
+}
+
+// Encode returns the protobuf encoding of the StateResponse
+func (r *StateResponse) Encode() ([]byte, error) {
+	var enc []byte
+	for _, kv := range r.Entries {
+		var entry []byte
+		if len(kv.StateRoot) > 0 {
+			entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+			entry = protowire.AppendBytes(entry, kv.StateRoot)
+		}
+
+		for _, e := range kv.Entries {
+			var stateEntry []byte
+			stateEntry = protowire.AppendTag(stateEntry, 1, protowire.BytesType)
+			stateEntry = protowire.AppendBytes(stateEntry, e.Key)
+			stateEntry = protowire.AppendTag(stateEntry, 2, protowire.BytesType)
+			stateEntry = protowire.AppendBytes(stateEntry, e.Value)
+
+			entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+			entry = protowire.AppendBytes(entry, stateEntry)
+		}
+
+		if kv.Complete {
+			entry = protowire.AppendTag(entry, 3, protowire.VarintType)
+			entry = protowire.AppendVarint(entry, protowire.EncodeBool(kv.Complete))
+		}
+
+		enc = protowire.AppendTag(enc, 1, protowire.BytesType)
+		enc = protowire.AppendBytes(enc, entry)
+	}
+
+	if len(r.Proof) > 0 {
+		enc = protowire.AppendTag(enc, 2, protowire.BytesType)
+		enc = protowire.AppendBytes(enc, r.Proof)
+	}
+
+	return enc, nil
+}
+
+// Decode decodes the protobuf encoded StateResponse
+func (r *StateResponse) Decode(in []byte) error {
+	*r = StateResponse{}
+	return decodeProtobuf(in, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			kv, err := decodeKeyValueStateEntry(value)
+			if err != nil {
+				return err
+			}
+			r.Entries = append(r.Entries, kv)
+		case 2:
+			r.Proof = value
+		}
+		return nil
+	})
+}
+
+// String formats a StateResponse as a string
+func (r *StateResponse) String() string {
+	return fmt.Sprintf("StateResponse Entries=%d ProofLength=%d", len(r.Entries), len(r.Proof))
+}
+
+func decodeKeyValueStateEntry(in []byte) (kv KeyValueStateEntry, err error) {
+	err = decodeProtobuf(in, func(num protowire.Number, _ protowire.Type, value []byte, varint uint64) error {
+		switch num {
+		case 1:
+			kv.StateRoot = value
+		case 2:
+			var e StateEntry
+			err := decodeProtobuf(value, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+				switch num {
+				case 1:
+					e.Key = value
+				case 2:
+					e.Value = value
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			kv.Entries = append(kv.Entries, e)
+		case 3:
+			kv.Complete = protowire.DecodeBool(varint)
+		}
+		return nil
+	})
+	return kv, err
+}
+
+// decodeProtobuf calls the handler with each field of the protobuf encoded message.
+// Length delimited field values are passed as value, and varint field values as varint.
+func decodeProtobuf(in []byte, handler func(num protowire.Number, typ protowire.Type,
+	value []byte, varint uint64) error) error {
+	for len(in) > 0 {
+		num, typ, n := protowire.ConsumeTag(in)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		in = in[n:]
+
+		var (
+			value  []byte
+			varint uint64
+		)
+
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(in)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(in)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, in)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		in = in[n:]
+
+		if err := handler(num, typ, value, varint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DoStateRequest sends a state request to the given peer and returns its response
+func (s *Service) DoStateRequest(to peer.ID, req *StateRequest) (*StateResponse, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, stateRequestTimeout)
+	defer cancel()
+
+	stream, err := s.host.h.NewStream(ctx, to, s.host.protocolID+stateID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	if err = s.host.writeToStream(stream, req); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxBlockResponseSize)
+	n, err := readStream(stream, buf)
+	if err != nil {
+		return nil, fmt.Errorf("read stream error: %w", err)
+	}
+
+	if n == 0 {
+		return nil, errors.New("received empty message")
+	}
+
+	resp := new(StateResponse)
+	err = resp.Decode(buf[:n])
+	if err != nil {
+		s.host.cm.peerSetHandler.ReportPeer(peerset.ReputationChange{
+			Value:  peerset.BadMessageValue,
+			Reason: peerset.BadMessageReason,
+		}, to)
+		return nil, fmt.Errorf("failed to decode state response: %w", err)
+	}
+
+	return resp, nil
+}
+
+// handleStateStream handles streams with the <protocol-id>/state/2 protocol ID
+func (s *Service) handleStateStream(stream libp2pnetwork.Stream) {
+	if stream == nil {
+		return
+	}
+
+	s.readStream(stream, decodeStateRequest, s.handleStateMessage)
+}
+
+func decodeStateRequest(in []byte, _ peer.ID, _ bool) (Message, error) {
+	msg := new(StateRequest)
+	err := msg.Decode(in)
+	return msg, err
+}
+
+func (s *Service) handleStateMessage(stream libp2pnetwork.Stream, msg Message) error {
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	req, ok := msg.(*StateRequest)
+	if !ok {
+		return nil
+	}
+
+	resp, err := s.createStateResponse(req)
+	if err != nil {
+		logger.Debugf("cannot create response for request %s: %s", req, err)
+		return nil
+	}
+
+	if err = s.host.writeToStream(stream, resp); err != nil {
+		logger.Debugf("failed to send state response to peer %s: %s", stream.Conn().RemotePeer(), err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) createStateResponse(req *StateRequest) (*StateResponse, error) {
+	if s.storageState == nil {
+		return nil, errNilStorageState
+	}
+
+	if len(req.Start) > 2 {
+		return nil, fmt.Errorf("%w: too many start keys", errInvalidStateRequest)
+	}
+
+	header, err := s.blockState.GetHeader(req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := s.storageState.TrieState(&header.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	t := ts.Trie()
+	kv := KeyValueStateEntry{}
+
+	var start []byte
+	switch len(req.Start) {
+	case 1:
+		start = req.Start[0]
+	case 2:
+		if !bytes.HasPrefix(req.Start[0], trie.ChildStorageKeyPrefix) {
+			return nil, fmt.Errorf("%w: invalid child storage key", errInvalidStateRequest)
+		}
+
+		t, err = t.GetChild(req.Start[0][len(trie.ChildStorageKeyPrefix):])
+		if err != nil {
+			return nil, err
+		}
+
+		kv.StateRoot = req.Start[0]
+		start = req.Start[1]
+	}
+
+	var (
+		size int
+		keys [][]byte
+	)
+
+	key := t.NextKey(start)
+	for key != nil && size < maxStateResponseEntriesSize {
+		value := t.Get(key)
+		kv.Entries = append(kv.Entries, StateEntry{Key: key, Value: value})
+		keys = append(keys, key)
+		size += len(key) + len(value)
+		key = t.NextKey(key)
+	}
+	kv.Complete = key == nil
+
+	resp := &StateResponse{
+		Entries: []KeyValueStateEntry{kv},
+	}
+
+	if req.NoProof || len(keys) == 0 {
+		return resp, nil
+	}
+
+	proof, err := t.GenerateProof(keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate proof: %w", err)
+	}
+
+	resp.Proof, err = scale.Marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateRequest_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	req := &StateRequest{
+		Block:   common.Hash{1},
+		Start:   [][]byte{[]byte("noot"), []byte("other")},
+		NoProof: true,
+	}
+
+	enc, err := req.Encode()
+	require.NoError(t, err)
+
+	decoded := new(StateRequest)
+	err = decoded.Decode(enc)
+	require.NoError(t, err)
+	require.Equal(t, req, decoded)
+
+	err = decoded.Decode(append([]byte{0x0a, 0x01}, 0x01))
+	require.ErrorIs(t, err, errInvalidStateRequest)
+}
+
+func TestStateResponse_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	resp := &StateResponse{
+		Entries: []KeyValueStateEntry{
+			{
+				Entries: []StateEntry{
+					{Key: []byte("noot"), Value: []byte{1}},
+					{Key: []byte("other"), Value: []byte{2}},
+				},
+			},
+			{
+				StateRoot: []byte(":child_storage:default:child"),
+				Entries:   []StateEntry{{Key: []byte("childkey"), Value: []byte{3}}},
+				Complete:  true,
+			},
+		},
+		Proof: []byte{4, 5, 6},
+	}
+
+	enc, err := resp.Encode()
+	require.NoError(t, err)
+
+	decoded := new(StateResponse)
+	err = decoded.Decode(enc)
+	require.NoError(t, err)
+	require.Equal(t, resp, decoded)
+}
+
+func TestService_createStateResponse(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	child := trie.NewEmptyTrie()
+	child.Put([]byte("childkey"), common.Hash{3}.ToBytes())
+
+	tr := trie.NewEmptyTrie()
+	tr.Put([]byte("noot"), common.Hash{1}.ToBytes())
+	tr.Put([]byte("other"), common.Hash{2}.ToBytes())
+	err := tr.PutChild([]byte("child"), child)
+	require.NoError(t, err)
+	stateRoot := tr.MustHash()
+
+	blockHash := common.Hash{1}
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetHeader(blockHash).Return(&types.Header{StateRoot: stateRoot}, nil).AnyTimes()
+
+	s := &Service{
+		blockState:   blockState,
+		storageState: newTestStorageState(t, ctrl, tr),
+	}
+
+	resp, err := s.createStateResponse(&StateRequest{Block: blockHash})
+	require.NoError(t, err)
+	require.Len(t, resp.Entries, 1)
+
+	kv := resp.Entries[0]
+	require.True(t, kv.Complete)
+	require.Nil(t, kv.StateRoot)
+	require.Len(t, kv.Entries, 3)
+
+	pairs := make([]trie.Pair, len(kv.Entries))
+	for i, entry := range kv.Entries {
+		require.Equal(t, tr.Get(entry.Key), entry.Value)
+		pairs[i] = trie.Pair{Key: entry.Key, Value: entry.Value}
+	}
+
+	ok, err := trie.VerifyProof(decodeTestProof(t, resp.Proof), stateRoot.ToBytes(), pairs)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// continue after the last received key
+	resp, err = s.createStateResponse(&StateRequest{
+		Block:   blockHash,
+		Start:   [][]byte{[]byte("noot")},
+		NoProof: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []StateEntry{{Key: []byte("other"), Value: common.Hash{2}.ToBytes()}}, resp.Entries[0].Entries)
+	require.Nil(t, resp.Proof)
+
+	// download the child trie
+	childKey := append(append([]byte{}, trie.ChildStorageKeyPrefix...), []byte("child")...)
+	resp, err = s.createStateResponse(&StateRequest{
+		Block: blockHash,
+		Start: [][]byte{childKey, nil},
+	})
+	require.NoError(t, err)
+	require.Equal(t, childKey, resp.Entries[0].StateRoot)
+	require.Equal(t, []StateEntry{{Key: []byte("childkey"), Value: common.Hash{3}.ToBytes()}}, resp.Entries[0].Entries)
+
+	ok, err = trie.VerifyProof(decodeTestProof(t, resp.Proof), child.MustHash().ToBytes(), []trie.Pair{
+		{Key: []byte("childkey"), Value: common.Hash{3}.ToBytes()},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = s.createStateResponse(&StateRequest{Block: blockHash, Start: [][]byte{[]byte("noot"), nil}})
+	require.ErrorIs(t, err, errInvalidStateRequest)
+}
@@ -0,0 +1,256 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/peerset"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// MaxWarpSyncProofSize is the maximum encoded size of a warp sync proof
+	MaxWarpSyncProofSize   = 1024 * 1024 * 3 // 3mb
+	warpSyncRequestTimeout = time.Second * 30
+)
+
+var errNilWarpSyncProvider = errors.New("warp sync provider is nil")
+
+// WarpSyncProvider generates the proofs answering warp sync requests
+type WarpSyncProvider interface {
+	GenerateWarpSyncProof(begin common.Hash) (*WarpSyncProof, error)
+}
+
+// WarpProofRequest is sent to request the authority set changes finalised
+// since the given block
+type WarpProofRequest struct {
+	Begin common.Hash
+}
+
+// SubProtocol returns the warp sync sub-protocol
+func (r *WarpProofRequest) SubProtocol() string {
+	return warpSyncID
+}
+
+// Encode returns the SCALE encoding of the WarpProofRequest
+func (r *WarpProofRequest) Encode() ([]byte, error) {
+	return scale.Marshal(*r)
+}
+
+// Decode decodes the SCALE encoded WarpProofRequest
+func (r *WarpProofRequest) Decode(in []byte) error {
+	return scale.Unmarshal(in, r)
+}
+
+// String formats a WarpProofRequest as a string
+func (r *WarpProofRequest) String() string {
+	return fmt.Sprintf("WarpProofRequest Begin=%s", r.Begin)
+}
+
+// WarpSyncFragment is a block enacting an authority set change along with the
+// justification finalising it, signed by the previous authority set
+type WarpSyncFragment struct {
+	Header types.Header
+	// Justification is the SCALE encoded GRANDPA justification, including its votes ancestries
+	Justification []byte
+}
+
+// WarpSyncProof is the response to a WarpProofRequest
+type WarpSyncProof struct {
+	Fragments []WarpSyncFragment
+	// IsFinished is true if the last fragment is the latest finalised block of the responder
+	IsFinished bool
+}
+
+// SubProtocol returns the warp sync sub-protocol
+func (p *WarpSyncProof) SubProtocol() string {
+	return warpSyncID
+}
+
+// Encode returns the SCALE encoding of the WarpSyncProof
+func (p *WarpSyncProof) Encode() ([]byte, error) {
+	enc, err := scale.Marshal(uint(len(p.Fragments)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fragment := range p.Fragments {
+		header, err := scale.Marshal(fragment.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		enc = append(enc, header...)
+		enc = append(enc, fragment.Justification...)
+	}
+
+	isFinished, err := scale.Marshal(p.IsFinished)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(enc, isFinished...), nil
+}
+
+// Decode decodes the SCALE encoded WarpSyncProof
+func (p *WarpSyncProof) Decode(in []byte) error {
+	r := bytes.NewReader(in)
+	decoder := scale.NewDecoder(r)
+
+	var length uint
+	if err := decoder.Decode(&length); err != nil {
+		return err
+	}
+
+	p.Fragments = make([]WarpSyncFragment, length)
+	for i := range p.Fragments {
+		header := types.NewEmptyHeader()
+		if err := decoder.Decode(header); err != nil {
+			return fmt.Errorf("cannot decode header of fragment %d: %w", i, err)
+		}
+
+		start := len(in) - r.Len()
+		if err := skipJustification(decoder); err != nil {
+			return fmt.Errorf("cannot decode justification of fragment %d: %w", i, err)
+		}
+		end := len(in) - r.Len()
+
+		p.Fragments[i] = WarpSyncFragment{
+			Header:        *header,
+			Justification: in[start:end],
+		}
+	}
+
+	return decoder.Decode(&p.IsFinished)
+}
+
+// String formats a WarpSyncProof as a string
+func (p *WarpSyncProof) String() string {
+	return fmt.Sprintf("WarpSyncProof Fragments=%d IsFinished=%t", len(p.Fragments), p.IsFinished)
+}
+
+// skipJustification reads a GRANDPA justification and its votes ancestries from the decoder.
+// The justification is kept encoded in the WarpSyncFragment, it is only decoded here to find its length.
+func skipJustification(decoder *scale.Decoder) error {
+	var (
+		round      uint64
+		target     types.GrandpaVote
+		precommits []types.GrandpaSignedVote
+		length     uint
+	)
+
+	for _, dst := range []interface{}{&round, &target, &precommits, &length} {
+		if err := decoder.Decode(dst); err != nil {
+			return err
+		}
+	}
+
+	for i := uint(0); i < length; i++ {
+		if err := decoder.Decode(types.NewEmptyHeader()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetWarpSyncProvider sets the WarpSyncProvider answering warp sync requests.
+// It must be called before the service is started for the protocol to be registered.
+func (s *Service) SetWarpSyncProvider(provider WarpSyncProvider) {
+	s.warpSyncProvider = provider
+}
+
+// DoWarpSyncRequest sends a warp sync request to the given peer and returns its response
+func (s *Service) DoWarpSyncRequest(to peer.ID, req *WarpProofRequest) (*WarpSyncProof, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, warpSyncRequestTimeout)
+	defer cancel()
+
+	stream, err := s.host.h.NewStream(ctx, to, s.host.protocolID+warpSyncID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	if err = s.host.writeToStream(stream, req); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxBlockResponseSize)
+	n, err := readStream(stream, buf)
+	if err != nil {
+		return nil, fmt.Errorf("read stream error: %w", err)
+	}
+
+	if n == 0 {
+		return nil, errors.New("received empty message")
+	}
+
+	proof := new(WarpSyncProof)
+	err = proof.Decode(buf[:n])
+	if err != nil {
+		s.host.cm.peerSetHandler.ReportPeer(peerset.ReputationChange{
+			Value:  peerset.BadMessageValue,
+			Reason: peerset.BadMessageReason,
+		}, to)
+		return nil, fmt.Errorf("failed to decode warp sync proof: %w", err)
+	}
+
+	return proof, nil
+}
+
+// handleWarpSyncStream handles streams with the <protocol-id>/sync/warp protocol ID
+func (s *Service) handleWarpSyncStream(stream libp2pnetwork.Stream) {
+	if stream == nil {
+		return
+	}
+
+	s.readStream(stream, decodeWarpProofRequest, s.handleWarpSyncMessage)
+}
+
+func decodeWarpProofRequest(in []byte, _ peer.ID, _ bool) (Message, error) {
+	msg := new(WarpProofRequest)
+	err := msg.Decode(in)
+	return msg, err
+}
+
+func (s *Service) handleWarpSyncMessage(stream libp2pnetwork.Stream, msg Message) error {
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	req, ok := msg.(*WarpProofRequest)
+	if !ok {
+		return nil
+	}
+
+	if s.warpSyncProvider == nil {
+		return errNilWarpSyncProvider
+	}
+
+	proof, err := s.warpSyncProvider.GenerateWarpSyncProof(req.Begin)
+	if err != nil {
+		logger.Debugf("cannot generate warp sync proof for request %s: %s", req, err)
+		return nil
+	}
+
+	if err = s.host.writeToStream(stream, proof); err != nil {
+		logger.Debugf("failed to send warp sync proof to peer %s: %s", stream.Conn().RemotePeer(), err)
+		return err
+	}
+
+	return nil
+}
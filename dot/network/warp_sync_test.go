@@ -0,0 +1,85 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarpProofRequest_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	req := &WarpProofRequest{Begin: common.Hash{1}}
+	enc, err := req.Encode()
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{1}.ToBytes(), enc)
+
+	decoded := new(WarpProofRequest)
+	err = decoded.Decode(enc)
+	require.NoError(t, err)
+	require.Equal(t, req, decoded)
+}
+
+func TestWarpSyncProof_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	digest := types.NewDigest()
+	err := digest.Add(types.PreRuntimeDigest{
+		ConsensusEngineID: types.BabeEngineID,
+		Data:              []byte{1, 2, 3},
+	})
+	require.NoError(t, err)
+
+	header, err := types.NewHeader(common.Hash{1}, common.Hash{2}, common.Hash{3}, big.NewInt(10), digest)
+	require.NoError(t, err)
+
+	ancestry, err := types.NewHeader(header.Hash(), common.Hash{4}, common.Hash{5}, big.NewInt(11), types.NewDigest())
+	require.NoError(t, err)
+
+	justification, err := scale.Marshal(struct {
+		Round      uint64
+		Target     types.GrandpaVote
+		Precommits []types.GrandpaSignedVote
+		Ancestries []types.Header
+	}{
+		Round:  2,
+		Target: types.GrandpaVote{Hash: header.Hash(), Number: 10},
+		Precommits: []types.GrandpaSignedVote{
+			{Vote: types.GrandpaVote{Hash: ancestry.Hash(), Number: 11}, Signature: [64]byte{1}},
+		},
+		Ancestries: []types.Header{*ancestry},
+	})
+	require.NoError(t, err)
+
+	proof := &WarpSyncProof{
+		Fragments: []WarpSyncFragment{
+			{Header: *header, Justification: justification},
+			{Header: *ancestry, Justification: justification},
+		},
+		IsFinished: true,
+	}
+
+	enc, err := proof.Encode()
+	require.NoError(t, err)
+
+	decoded := new(WarpSyncProof)
+	err = decoded.Decode(enc)
+	require.NoError(t, err)
+	require.True(t, decoded.IsFinished)
+	require.Len(t, decoded.Fragments, 2)
+	require.Equal(t, header.Hash(), decoded.Fragments[0].Header.Hash())
+	require.Equal(t, ancestry.Hash(), decoded.Fragments[1].Header.Hash())
+	require.Equal(t, justification, decoded.Fragments[0].Justification)
+	require.Equal(t, justification, decoded.Fragments[1].Justification)
+
+	err = decoded.Decode(enc[:len(enc)-10])
+	require.Error(t, err)
+}
@@ -14,8 +14,10 @@
 	"syscall"
 	"time"
 
+	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/rpc"
+	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/telemetry"
@@ -91,6 +93,11 @@ func InitNode(cfg *Config) error {
 		Metrics:   metrics.NewIntervalConfig(cfg.Global.PublishMetrics),
 	}
 
+	err = setupModuleCache(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to setup runtime cache: %w", err)
+	}
+
 	// create new state service
 	stateSrvc := state.NewService(config)
 
@@ -188,8 +195,9 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore) (*Node, error) {
 
 	logger.Patch(log.SetLevel(cfg.Global.LogLvl))
 
-	// if authority node, should have at least 1 key in keystore
-	if cfg.Core.Roles == types.AuthorityRole && (ks.Babe.Size() == 0 || ks.Gran.Size() == 0) {
+	// if authority node, should have at least 1 key in keystore, unless the keys are held by a remote signer
+	if cfg.Core.Roles == types.AuthorityRole && cfg.Account.Signer == "" &&
+		(ks.Babe.Size() == 0 || ks.Gran.Size() == 0) {
 		return nil, ErrNoKeysProvided
 	}
 
@@ -206,6 +214,11 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore) (*Node, error) {
 		nodeSrvcs = append(nodeSrvcs, createPprofService(cfg.Pprof.Settings))
 	}
 
+	err := setupModuleCache(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup runtime cache: %w", err)
+	}
+
 	stateSrvc, err := createStateService(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create state service: %s", err)
@@ -287,34 +300,51 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore) (*Node, error) {
 	}
 	nodeSrvcs = append(nodeSrvcs, dh)
 
-	coreSrvc, err := createCoreService(cfg, ks, stateSrvc, networkSrvc, dh)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create core service: %s", err)
+	// light clients only sync block headers, so they do not need the core and BABE services
+	isLightClient := cfg.Core.Roles == types.LightClientRole
+
+	var coreSrvc *core.Service
+	if !isLightClient {
+		coreSrvc, err = createCoreService(cfg, ks, stateSrvc, networkSrvc, dh)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create core service: %s", err)
+		}
+		nodeSrvcs = append(nodeSrvcs, coreSrvc)
+		ver.SetEquivocationReporter(coreSrvc)
 	}
-	nodeSrvcs = append(nodeSrvcs, coreSrvc)
 
 	fg, err := createGRANDPAService(cfg, stateSrvc, dh, ks.Gran, networkSrvc, telemetryMailer)
 	if err != nil {
 		return nil, err
 	}
+	if coreSrvc != nil {
+		fg.SetEquivocationReporter(coreSrvc)
+	}
 	nodeSrvcs = append(nodeSrvcs, fg)
 
-	syncer, err := newSyncService(cfg, stateSrvc, fg, ver, coreSrvc, networkSrvc, telemetryMailer)
+	syncer, err := newSyncService(cfg, stateSrvc, fg, ver, coreSrvc, dh, networkSrvc, telemetryMailer)
 	if err != nil {
 		return nil, err
 	}
 
 	if networkSrvc != nil {
 		networkSrvc.SetSyncer(syncer)
-		networkSrvc.SetTransactionHandler(coreSrvc)
+		networkSrvc.SetWarpSyncProvider(fg)
+		if coreSrvc != nil {
+			networkSrvc.SetTransactionHandler(coreSrvc)
+		}
 	}
 	nodeSrvcs = append(nodeSrvcs, syncer)
 
-	bp, err := createBABEService(cfg, stateSrvc, ks.Babe, coreSrvc, telemetryMailer)
-	if err != nil {
-		return nil, err
+	var bp modules.BlockProducerAPI
+	if !isLightClient {
+		babeSrvc, err := createBABEService(cfg, stateSrvc, ks.Babe, coreSrvc, telemetryMailer)
+		if err != nil {
+			return nil, err
+		}
+		nodeSrvcs = append(nodeSrvcs, babeSrvc)
+		bp = babeSrvc
 	}
-	nodeSrvcs = append(nodeSrvcs, bp)
 
 	// check if rpc service is enabled
 	if enabled := cfg.RPC.isRPCEnabled() || cfg.RPC.isWSEnabled(); enabled {
@@ -57,6 +57,27 @@ func (h *Handler) SetReservedPeer(setID int, peers ...peer.ID) {
 	}
 }
 
+// SetReservedOnly enables or disables the reserved-only mode of the peerSet.
+func (h *Handler) SetReservedOnly(setID int, reservedOnly bool) {
+	h.actionQueue <- action{
+		actionCall:   setReservedOnly,
+		setID:        setID,
+		reservedOnly: reservedOnly,
+	}
+}
+
+// ReservedPeers return chan for the reserved peers of the peerSet.
+func (h *Handler) ReservedPeers(setIdx int) chan peer.IDSlice {
+	resultPeersCh := make(chan peer.IDSlice)
+	h.actionQueue <- action{
+		actionCall:    reservedPeers,
+		resultPeersCh: resultPeersCh,
+		setID:         setIdx,
+	}
+
+	return resultPeersCh
+}
+
 // AddPeer adds peer to peerSet.
 func (h *Handler) AddPeer(setID int, peers ...peer.ID) {
 	h.actionQueue <- action{
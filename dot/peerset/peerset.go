@@ -7,6 +7,7 @@
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -38,7 +39,7 @@
 	removeReservedPeer
 	// setReservedPeers is for setting peerList in peerSet reserved peers
 	setReservedPeers
-	// setReservedOnly is for setting peerList in peerSet reserved peers only
+	// setReservedOnly is for enabling or disabling the reserved-only mode of the peerSet
 	setReservedOnly
 	// reportPeer is for reporting peers if it misbehaves
 	reportPeer
@@ -52,6 +53,8 @@
 	sortedPeers
 	// disconnect peer
 	disconnect
+	// reservedPeers is for the list of reserved peers
+	reservedPeers
 )
 
 func (a ActionReceiver) String() string {
@@ -76,6 +79,8 @@ func (a ActionReceiver) String() string {
 		return "sortedPeers"
 	case disconnect:
 		return "disconnect"
+	case reservedPeers:
+		return "reservedPeers"
 	default:
 		return "invalid action"
 	}
@@ -88,6 +93,7 @@ type action struct {
 	reputation    ReputationChange
 	peers         peer.IDSlice
 	resultPeersCh chan peer.IDSlice
+	reservedOnly  bool
 }
 
 func (a action) String() string {
@@ -166,8 +172,7 @@ type PeerSet struct {
 	peerState *PeersState
 
 	reservedNode map[peer.ID]struct{}
-	// TODO: this will be useful for reserved only mode
-	// this is for future purpose if reserved-only flag is enabled (#1888).
+	// if true, we only connect to and accept connections from reservedNode.
 	isReservedOnly bool
 	resultMsgCh    chan Message
 	// time when the PeerSet was created.
@@ -188,8 +193,7 @@ type config struct {
 	// maximum number of slot occupying nodes for outgoing connections.
 	maxOutPeers uint32
 
-	// TODO Use in future for reserved only peers
-	// if true, we only accept reservedNodes (#1888).
+	// if true, we only accept reservedNodes.
 	reservedOnly bool
 
 	// time duration for a peerSet to periodically call allocSlots.
@@ -379,7 +383,7 @@ func (ps *PeerSet) allocSlots(setIdx int) error {
 		if n.getReputation() < BannedThresholdValue {
 			logger.Warnf("reputation is lower than banned threshold value, reputation: %d, banned threshold value: %d",
 				n.getReputation(), BannedThresholdValue)
-			break
+			continue
 		}
 
 		if err = peerState.tryOutgoing(setIdx, reservePeer); err != nil {
@@ -430,7 +434,7 @@ func (ps *PeerSet) addReservedPeers(setID int, peers ...peer.ID) error {
 	for _, peerID := range peers {
 		if _, ok := ps.reservedNode[peerID]; ok {
 			logger.Debugf("peer %s already exists in peerSet", peerID)
-			return nil
+			continue
 		}
 
 		ps.peerState.discover(setID, peerID)
@@ -450,7 +454,7 @@ func (ps *PeerSet) removeReservedPeers(setID int, peers ...peer.ID) error {
 	for _, peerID := range peers {
 		if _, ok := ps.reservedNode[peerID]; !ok {
 			logger.Debugf("peer %s doesn't exist in the peerSet", peerID)
-			return nil
+			continue
 		}
 
 		delete(ps.reservedNode, peerID)
@@ -460,10 +464,9 @@ func (ps *PeerSet) removeReservedPeers(setID int, peers ...peer.ID) error {
 
 		// nothing more to do if not in reservedOnly mode.
 		if !ps.isReservedOnly {
-			return nil
+			continue
 		}
 
-		// reservedOnly mode is not yet implemented for future this code will help.
 		// If however the peerSet is in reserved-only mode, then non-reserved node peers needs to be
 		// disconnected.
 		if ps.peerState.peerStatus(setID, peerID) == connectedPeer {
@@ -508,6 +511,48 @@ func (ps *PeerSet) setReservedPeer(setID int, peers ...peer.ID) error {
 	return ps.removeReservedPeers(setID, toRemove...)
 }
 
+// setReservedOnly enables or disables the reserved-only mode. When enabled, every connected
+// peer which isn't a reserved peer is dropped.
+func (ps *PeerSet) setReservedOnly(setID int, reservedOnly bool) error {
+	ps.isReservedOnly = reservedOnly
+	if !reservedOnly {
+		return ps.allocSlots(setID)
+	}
+
+	for _, pid := range ps.peerState.peers() {
+		if _, ok := ps.reservedNode[pid]; ok {
+			continue
+		}
+
+		if ps.peerState.peerStatus(setID, pid) != connectedPeer {
+			continue
+		}
+
+		if err := ps.peerState.disconnect(setID, pid); err != nil {
+			return err
+		}
+
+		ps.resultMsgCh <- Message{
+			Status: Drop,
+			setID:  uint64(setID),
+			PeerID: pid,
+		}
+	}
+
+	return nil
+}
+
+// reservedPeers returns the sorted list of reserved peers.
+func (ps *PeerSet) reservedPeers() peer.IDSlice {
+	peers := make(peer.IDSlice, 0, len(ps.reservedNode))
+	for pid := range ps.reservedNode {
+		peers = append(peers, pid)
+	}
+
+	sort.Sort(peers)
+	return peers
+}
+
 func (ps *PeerSet) addPeer(setID int, peers peer.IDSlice) error {
 	for _, pid := range peers {
 		if ps.peerState.peerStatus(setID, pid) != unknownPeer {
@@ -704,11 +749,9 @@ func (ps *PeerSet) doWork(ctx context.Context) {
 			case removeReservedPeer:
 				err = ps.removeReservedPeers(act.setID, act.peers...)
 			case setReservedPeers:
-				// TODO: this is not used yet, might required to implement RPC Call for this.
 				err = ps.setReservedPeer(act.setID, act.peers...)
 			case setReservedOnly:
-				// TODO: not yet implemented (#1888)
-				err = fmt.Errorf("not implemented yet")
+				err = ps.setReservedOnly(act.setID, act.reservedOnly)
 			case reportPeer:
 				err = ps.reportPeer(act.reputation, act.peers...)
 			case addToPeerSet:
@@ -721,6 +764,8 @@ func (ps *PeerSet) doWork(ctx context.Context) {
 				act.resultPeersCh <- ps.peerState.sortedPeers(act.setID)
 			case disconnect:
 				err = ps.disconnect(act.setID, UnknownDrop, act.peers...)
+			case reservedPeers:
+				act.resultPeersCh <- ps.reservedPeers()
 			}
 
 			if err != nil {
@@ -183,3 +183,53 @@ func TestSetReservePeer(t *testing.T) {
 		require.Contains(t, ps.reservedNode, p)
 	}
 }
+
+func TestSetReservedOnly(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestPeerSet(t, 2, 2, []peer.ID{discovered1}, []peer.ID{reservedPeer}, false)
+	ps := handler.peerSet
+
+	require.Equal(t, 2, len(ps.resultMsgCh))
+	for len(ps.resultMsgCh) != 0 {
+		checkMessageStatus(t, <-ps.resultMsgCh, Connect)
+	}
+
+	handler.SetReservedOnly(0, true)
+	time.Sleep(100 * time.Millisecond)
+
+	// the non-reserved peer gets dropped.
+	require.Equal(t, 1, len(ps.resultMsgCh))
+	require.Equal(t, Message{Status: Drop, setID: 0, PeerID: discovered1}, <-ps.resultMsgCh)
+	require.Equal(t, connectedPeer, ps.peerState.peerStatus(0, reservedPeer))
+
+	// non-reserved peers are neither accepted nor connected to.
+	handler.Incoming(0, incomingPeer)
+	checkMessageStatus(t, <-ps.resultMsgCh, Reject)
+
+	handler.AddPeer(0, discovered2)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 0, len(ps.resultMsgCh))
+
+	handler.SetReservedOnly(0, false)
+	time.Sleep(100 * time.Millisecond)
+
+	require.Equal(t, 2, len(ps.resultMsgCh))
+	for len(ps.resultMsgCh) != 0 {
+		checkMessageStatus(t, <-ps.resultMsgCh, Connect)
+	}
+}
+
+func TestReservedPeers(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestPeerSet(t, 0, 2, nil, []peer.ID{reservedPeer2, reservedPeer}, true)
+
+	peers := <-handler.ReservedPeers(0)
+	require.Equal(t, peer.IDSlice{reservedPeer, reservedPeer2}, peers)
+
+	handler.RemoveReservedPeer(0, reservedPeer, reservedPeer2)
+
+	peers = <-handler.ReservedPeers(0)
+	require.Empty(t, peers)
+}
@@ -72,6 +72,11 @@ func (c *DotUpCodecRequest) Method() (string, error) {
 		r, n := utf8.DecodeRuneInString(method) // get the first rune, and it's length
 		if unicode.IsLower(r) {
 			upMethod := service + "." + string(unicode.ToUpper(r)) + method[n:]
+			// versioned methods, eg. chainSpec_v1_chainName, are mapped to chainSpec.V1ChainName
+			for _, part := range parts[2:] {
+				r, n = utf8.DecodeRuneInString(part)
+				upMethod += string(unicode.ToUpper(r)) + part[n:]
+			}
 			return upMethod, err
 		}
 	}
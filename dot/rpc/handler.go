@@ -0,0 +1,300 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/subscription"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+const (
+	// maxRequestSize is the maximum size of a request to the RPC server
+	maxRequestSize = 15 << 20
+	// maxBatchSize is the maximum number of calls in a batch request
+	maxBatchSize = 1000
+
+	// corsAll allows every origin to access the RPC servers
+	corsAll = "all"
+)
+
+// errorResponse is a JSON-RPC error response
+type errorResponse struct {
+	Version string           `json:"jsonrpc"`
+	Error   *json2.Error     `json:"error"`
+	ID      *json.RawMessage `json:"id"`
+}
+
+func newErrorResponse(id *json.RawMessage, code json2.ErrorCode, message string) []byte {
+	enc, _ := json.Marshal(&errorResponse{
+		Version: "2.0",
+		Error: &json2.Error{
+			Code:    code,
+			Message: message,
+		},
+		ID: id,
+	})
+	return enc
+}
+
+// rpcHandler serves the single and batch JSON-RPC requests to the HTTP server. It checks the
+// origin of the requests, the rate limits of the clients and the size of the responses.
+type rpcHandler struct {
+	logger          *log.Logger
+	rpcServer       http.Handler
+	cors            []string
+	limiter         *rateLimiter
+	maxResponseSize uint64 // 0 means no limit
+}
+
+// ServeHTTP serves a single JSON-RPC request, or a batch of JSON-RPC requests
+func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.handleCORS(w, r) {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.rpcServer.ServeHTTP(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		writeJSON(w, newErrorResponse(nil, json2.E_PARSE, err.Error()))
+		return
+	}
+
+	ip := clientIP(r)
+
+	if !isBatch(body) {
+		buf := h.serveCall(r, ip, body)
+		if buf == nil {
+			return
+		}
+
+		for key, values := range buf.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(buf.status)
+		_, _ = w.Write(buf.body.Bytes())
+		return
+	}
+
+	var calls []json.RawMessage
+	err = json.Unmarshal(body, &calls)
+	if err != nil {
+		writeJSON(w, newErrorResponse(nil, json2.E_PARSE, err.Error()))
+		return
+	}
+
+	if len(calls) == 0 || len(calls) > maxBatchSize {
+		writeJSON(w, newErrorResponse(nil, json2.E_INVALID_REQ, subscription.InvalidRequestMessage))
+		return
+	}
+
+	responses := make([]json.RawMessage, 0, len(calls))
+	size := uint64(2)
+	for _, call := range calls {
+		buf := h.serveCall(r, ip, call)
+		if buf == nil {
+			// no response for notifications
+			continue
+		}
+
+		resp := bytes.TrimSpace(buf.body.Bytes())
+		if !json.Valid(resp) {
+			// the request was refused before reaching the codec, eg. for an invalid content type
+			resp = newErrorResponse(buf.id, json2.E_SERVER, strings.TrimSpace(string(resp)))
+		}
+
+		if h.maxResponseSize != 0 && size+uint64(len(resp))+1 > h.maxResponseSize {
+			resp = newErrorResponse(buf.id, subscription.ResponseTooBigCode, subscription.ResponseTooBigMessage)
+		}
+
+		size += uint64(len(resp)) + 1
+		responses = append(responses, resp)
+	}
+
+	if len(responses) == 0 {
+		return
+	}
+
+	enc, err := json.Marshal(responses)
+	if err != nil {
+		writeJSON(w, newErrorResponse(nil, json2.E_INTERNAL, err.Error()))
+		return
+	}
+
+	writeJSON(w, enc)
+}
+
+// serveCall serves a single JSON-RPC request, it returns nil if there is no response to the request
+func (h *rpcHandler) serveCall(r *http.Request, ip string, call []byte) *responseBuffer {
+	var req struct {
+		Method string           `json:"method"`
+		ID     *json.RawMessage `json:"id"`
+	}
+
+	trimmed := bytes.TrimSpace(call)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return newErrorBuffer(nil, json2.E_INVALID_REQ, subscription.InvalidRequestMessage)
+	}
+
+	// a request which cannot be decoded is refused by the codec
+	_ = json.Unmarshal(call, &req)
+
+	if !h.limiter.Allow(ip, req.Method) {
+		if req.ID == nil {
+			return nil
+		}
+		return newErrorBuffer(req.ID, subscription.RateLimitedCode, subscription.RateLimitedMessage)
+	}
+
+	callReq := r.Clone(r.Context())
+	callReq.Body = io.NopCloser(bytes.NewReader(call))
+	callReq.ContentLength = int64(len(call))
+
+	buf := newResponseBuffer(req.ID, h.maxResponseSize)
+	h.rpcServer.ServeHTTP(buf, callReq)
+
+	if buf.tooBig {
+		return newErrorBuffer(req.ID, subscription.ResponseTooBigCode, subscription.ResponseTooBigMessage)
+	}
+
+	if buf.body.Len() == 0 {
+		return nil
+	}
+
+	return buf
+}
+
+// handleCORS sets the CORS headers of the response if the origin of the request is allowed, and
+// answers preflight requests. It returns false if the request must not be served.
+func (h *rpcHandler) handleCORS(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(h.cors) == 0 || origin == "" {
+		return true
+	}
+
+	if !originAllowed(h.cors, origin) {
+		h.logger.Debugf("refused HTTP request from origin %s", origin)
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return false
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Add("Vary", "Origin")
+
+	if r.Method != http.MethodOptions {
+		return true
+	}
+
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.WriteHeader(http.StatusOK)
+	return false
+}
+
+// originAllowed returns true if the origin is in the list of allowed origins. If the list is
+// empty, every origin is allowed.
+func originAllowed(cors []string, origin string) bool {
+	if len(cors) == 0 || origin == "" {
+		return true
+	}
+
+	for _, allowed := range cors {
+		if allowed == corsAll || allowed == origin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIP returns the IP address of the client of the request. The requests forwarded from the
+// loopback interface, ie. by the websocket server or by a reverse proxy, are attributed to the last
+// address of their X-Forwarded-For header.
+func clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	if parsed := net.ParseIP(ip); parsed == nil || !parsed.IsLoopback() {
+		return ip
+	}
+
+	forwarded := r.Header.Values("X-Forwarded-For")
+	if len(forwarded) == 0 {
+		return ip
+	}
+
+	addrs := strings.Split(forwarded[len(forwarded)-1], ",")
+	if addr := strings.TrimSpace(addrs[len(addrs)-1]); net.ParseIP(addr) != nil {
+		return addr
+	}
+
+	return ip
+}
+
+func isBatch(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) != 0 && trimmed[0] == '['
+}
+
+func writeJSON(w http.ResponseWriter, enc []byte) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(enc)
+}
+
+// responseBuffer buffers the response to a single JSON-RPC request, up to the maximum response size
+type responseBuffer struct {
+	id      *json.RawMessage
+	header  http.Header
+	status  int
+	body    bytes.Buffer
+	maxSize uint64
+	tooBig  bool
+}
+
+func newResponseBuffer(id *json.RawMessage, maxSize uint64) *responseBuffer {
+	return &responseBuffer{
+		id:      id,
+		header:  make(http.Header),
+		status:  http.StatusOK,
+		maxSize: maxSize,
+	}
+}
+
+func newErrorBuffer(id *json.RawMessage, code json2.ErrorCode, message string) *responseBuffer {
+	buf := newResponseBuffer(id, 0)
+	buf.header.Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = buf.body.Write(newErrorResponse(id, code, message))
+	return buf
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	if b.tooBig || b.maxSize != 0 && uint64(b.body.Len()+len(p)) > b.maxSize {
+		b.tooBig = true
+		b.body.Reset()
+		return len(p), nil
+	}
+
+	return b.body.Write(p)
+}
@@ -0,0 +1,213 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type echoService struct{}
+
+func (*echoService) Echo(_ *http.Request, req *string, res *string) error {
+	*res = *req
+	return nil
+}
+
+func newTestRPCHandler(t *testing.T, limiter *rateLimiter, cors []string, maxResponseSize uint64) *rpcHandler {
+	t.Helper()
+
+	rpcServer := rpc.NewServer()
+	rpcServer.RegisterCodec(NewDotUpCodec(), "application/json")
+
+	err := rpcServer.RegisterService(new(echoService), "echo")
+	require.NoError(t, err)
+
+	return &rpcHandler{
+		logger:          log.New(log.SetWriter(io.Discard)),
+		rpcServer:       rpcServer,
+		cors:            cors,
+		limiter:         limiter,
+		maxResponseSize: maxResponseSize,
+	}
+}
+
+func serveTestRequest(h http.Handler, method, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRPCHandler_Batch(t *testing.T) {
+	h := newTestRPCHandler(t, newRateLimiter(0, nil), nil, 0)
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "single call",
+			body:     `{"jsonrpc":"2.0","method":"echo_echo","params":["noot"],"id":1}`,
+			expected: `{"jsonrpc":"2.0","result":"noot","id":1}`,
+		},
+		{
+			name: "batch",
+			body: `[{"jsonrpc":"2.0","method":"echo_echo","params":["noot"],"id":1},` +
+				`{"jsonrpc":"2.0","method":"echo_echo","params":["no response"]},` +
+				`1,` +
+				`{"jsonrpc":"2.0","method":"echo_unknown","params":[],"id":2}]`,
+			expected: `[{"jsonrpc":"2.0","result":"noot","id":1},` +
+				`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request","data":null},"id":null},` +
+				`{"jsonrpc":"2.0","error":{"code":-32000,` +
+				`"message":"rpc: can't find method \"echo.Unknown\"","data":null},"id":2}]`,
+		},
+		{
+			name:     "empty batch",
+			body:     `[]`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request","data":null},"id":null}`,
+		},
+		{
+			name: "invalid batch",
+			body: `[{"jsonrpc":"2.0"`,
+			expected: `{"jsonrpc":"2.0",` +
+				`"error":{"code":-32700,"message":"unexpected end of JSON input","data":null},"id":null}`,
+		},
+		{
+			name:     "batch of notifications",
+			body:     `[{"jsonrpc":"2.0","method":"echo_echo","params":["noot"]}]`,
+			expected: ``,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveTestRequest(h, http.MethodPost, tc.body, nil)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			if tc.expected == "" {
+				require.Empty(t, rec.Body.String())
+				return
+			}
+			require.JSONEq(t, tc.expected, rec.Body.String())
+		})
+	}
+}
+
+func TestRPCHandler_CORS(t *testing.T) {
+	h := newTestRPCHandler(t, newRateLimiter(0, nil), []string{"https://polkadot.js.org"}, 0)
+	body := `{"jsonrpc":"2.0","method":"echo_echo","params":["noot"],"id":1}`
+
+	rec := serveTestRequest(h, http.MethodPost, body, map[string]string{"Origin": "https://polkadot.js.org"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "https://polkadot.js.org", rec.Header().Get("Access-Control-Allow-Origin"))
+	require.JSONEq(t, `{"jsonrpc":"2.0","result":"noot","id":1}`, rec.Body.String())
+
+	rec = serveTestRequest(h, http.MethodOptions, "", map[string]string{"Origin": "https://polkadot.js.org"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "https://polkadot.js.org", rec.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "POST, OPTIONS", rec.Header().Get("Access-Control-Allow-Methods"))
+
+	rec = serveTestRequest(h, http.MethodPost, body, map[string]string{"Origin": "https://noot.io"})
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// requests without origin are not sent by browsers
+	rec = serveTestRequest(h, http.MethodPost, body, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestRPCHandler_RateLimit(t *testing.T) {
+	h := newTestRPCHandler(t, newRateLimiter(0, map[string]uint32{"echo_echo": 1}), nil, 0)
+	body := `{"jsonrpc":"2.0","method":"echo_echo","params":["noot"],"id":1}`
+
+	rec := serveTestRequest(h, http.MethodPost, body, nil)
+	require.JSONEq(t, `{"jsonrpc":"2.0","result":"noot","id":1}`, rec.Body.String())
+
+	rec = serveTestRequest(h, http.MethodPost, body, nil)
+	require.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Rate limit exceeded","data":null},"id":1}`,
+		rec.Body.String())
+
+	// the calls forwarded by the websocket server are limited with the address of the websocket client
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", "192.0.2.2")
+	req.RemoteAddr = "127.0.0.1:1234"
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.JSONEq(t, `{"jsonrpc":"2.0","result":"noot","id":1}`, rec.Body.String())
+}
+
+func TestRPCHandler_MaxResponseSize(t *testing.T) {
+	h := newTestRPCHandler(t, newRateLimiter(0, nil), nil, 64)
+
+	rec := serveTestRequest(h, http.MethodPost, `{"jsonrpc":"2.0","method":"echo_echo","params":["noot"],"id":1}`, nil)
+	require.JSONEq(t, `{"jsonrpc":"2.0","result":"noot","id":1}`, rec.Body.String())
+
+	long := strings.Repeat("a", 64)
+	body := `{"jsonrpc":"2.0","method":"echo_echo","params":["` + long + `"],"id":1}`
+	rec = serveTestRequest(h, http.MethodPost, body, nil)
+	require.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32008,"message":"Response is too big","data":null},"id":1}`,
+		rec.Body.String())
+}
+
+func TestClientIP(t *testing.T) {
+	testCases := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		expected   string
+	}{
+		{
+			name:       "remote address",
+			remoteAddr: "192.0.2.1:1234",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "forwarded by external address",
+			remoteAddr: "192.0.2.1:1234",
+			forwarded:  "192.0.2.2",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "forwarded by loopback address",
+			remoteAddr: "127.0.0.1:1234",
+			forwarded:  "192.0.2.2, 192.0.2.3",
+			expected:   "192.0.2.3",
+		},
+		{
+			name:       "invalid forwarded address",
+			remoteAddr: "127.0.0.1:1234",
+			forwarded:  "noot",
+			expected:   "127.0.0.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+
+			require.Equal(t, tc.expected, clientIP(req))
+		})
+	}
+}
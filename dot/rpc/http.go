@@ -26,6 +26,7 @@ type HTTPServer struct {
 	rpcServer    *rpc.Server // Actual RPC call handler
 	serverConfig *HTTPServerConfig
 	wsConns      []*subscription.WSConn
+	limiter      *rateLimiter
 }
 
 // HTTPServerConfig configures the HTTPServer
@@ -55,6 +56,18 @@ type HTTPServerConfig struct {
 	WSUnsafeExternal    bool
 	WSPort              uint32
 	Modules             []string
+
+	// MaxQueryStorageRange is the maximum number of blocks state_queryStorage can query
+	MaxQueryStorageRange uint32
+
+	// CORS is the list of origins allowed to access the servers, every origin is allowed if empty
+	CORS []string
+	// RateLimit is the maximum number of calls per second of each client IP address, 0 means no limit
+	RateLimit uint32
+	// MethodRateLimits is the maximum number of calls per second of each client IP address to each method
+	MethodRateLimits map[string]uint32
+	// MaxResponseSize is the maximum size in bytes of the response to a call, 0 means no limit
+	MaxResponseSize uint64
 }
 
 func (h *HTTPServerConfig) rpcUnsafeEnabled() bool {
@@ -84,6 +97,7 @@ func NewHTTPServer(cfg *HTTPServerConfig) *HTTPServer {
 		logger:       logger,
 		rpcServer:    rpc.NewServer(),
 		serverConfig: cfg,
+		limiter:      newRateLimiter(cfg.RateLimit, cfg.MethodRateLimits),
 	}
 
 	server.RegisterModules(cfg.Modules)
@@ -107,7 +121,8 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 		case "grandpa":
 			srvc = modules.NewGrandpaModule(h.serverConfig.BlockAPI, h.serverConfig.BlockFinalityAPI)
 		case "state":
-			srvc = modules.NewStateModule(h.serverConfig.NetworkAPI, h.serverConfig.StorageAPI, h.serverConfig.CoreAPI)
+			srvc = modules.NewStateModule(h.serverConfig.NetworkAPI, h.serverConfig.StorageAPI,
+				h.serverConfig.CoreAPI, h.serverConfig.MaxQueryStorageRange)
 		case "rpc":
 			srvc = modules.NewRPCModule(h.serverConfig.RPCAPI)
 		case "dev":
@@ -119,7 +134,11 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 		case "syncstate":
 			srvc = modules.NewSyncStateModule(h.serverConfig.SyncStateAPI)
 		case "payment":
-			srvc = modules.NewPaymentModule(h.serverConfig.BlockAPI)
+			srvc = modules.NewPaymentModule(h.serverConfig.BlockAPI, h.serverConfig.StorageAPI)
+		case "debug":
+			srvc = modules.NewDebugModule(h.serverConfig.CoreAPI)
+		case "chainSpec":
+			srvc = modules.NewChainSpecModule(h.serverConfig.SystemAPI, h.serverConfig.BlockAPI)
 		default:
 			h.logger.Warn("Unrecognised module: " + mod)
 			continue
@@ -144,7 +163,13 @@ func (h *HTTPServer) Start() error {
 
 	h.logger.Infof("Starting HTTP Server on host %s and port %d...", h.serverConfig.Host, h.serverConfig.RPCPort)
 	r := mux.NewRouter()
-	r.Handle("/", h.rpcServer)
+	r.Handle("/", &rpcHandler{
+		logger:          h.logger,
+		rpcServer:       h.rpcServer,
+		cors:            h.serverConfig.CORS,
+		limiter:         h.limiter,
+		maxResponseSize: h.serverConfig.MaxResponseSize,
+	})
 
 	validate := validator.New()
 	// Add custom validator for `common.Hash`
@@ -188,6 +213,11 @@ func (h *HTTPServer) Stop() error {
 					h.serverConfig.StorageAPI.UnregisterStorageObserver(v)
 				case *subscription.BlockListener:
 					h.serverConfig.BlockAPI.FreeImportedBlockNotifierChannel(v.Channel)
+				case *subscription.ChainHeadFollower:
+					err := v.Stop()
+					if err != nil {
+						h.logger.Errorf("error stopping follow subscription: %s", err)
+					}
 				}
 			}
 
@@ -204,6 +234,11 @@ func (h *HTTPServer) Stop() error {
 func (h *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var upg = websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool {
+			if origin := r.Header.Get("Origin"); !originAllowed(h.serverConfig.CORS, origin) {
+				logger.Debugf("websocket request from origin %s refused", origin)
+				return false
+			}
+
 			if !h.serverConfig.exposeWS() {
 				ip, _, err := net.SplitHostPort(r.RemoteAddr)
 				if err != nil {
@@ -231,6 +266,8 @@ func (h *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	// create wsConn
 	wsc := NewWSConn(ws, h.serverConfig)
+	wsc.ClientIP = clientIP(r)
+	wsc.RateLimiter = h.limiter
 	h.wsConns = append(h.wsConns, wsc)
 
 	go wsc.HandleComm()
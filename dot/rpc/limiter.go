@@ -0,0 +1,105 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules"
+)
+
+// bucketsPruneInterval is the interval at which the buckets of the clients which did not call
+// recently are removed
+const bucketsPruneInterval = time.Minute
+
+// rateLimiter limits the number of calls per second of each client IP address, and the number
+// of calls per second of each client IP address to the methods with a rate limit.
+// Bursts of up to one second of calls are allowed.
+type rateLimiter struct {
+	sync.Mutex
+	limit        uint32            // 0 means no limit
+	methodLimits map[string]uint32 // method name -> limit
+	buckets      map[string]*tokenBucket
+	lastPruned   time.Time
+	now          func() time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newRateLimiter(limit uint32, methodLimits map[string]uint32) *rateLimiter {
+	return &rateLimiter{
+		limit:        limit,
+		methodLimits: methodLimits,
+		buckets:      make(map[string]*tokenBucket),
+		lastPruned:   time.Now(),
+		now:          time.Now,
+	}
+}
+
+// Allow returns true if the client with the given IP address is allowed to call the method
+func (l *rateLimiter) Allow(ip, method string) bool {
+	if concreteMethod, ok := modules.AliasesMethods[method]; ok {
+		method = concreteMethod
+	}
+
+	methodLimit := l.methodLimits[method]
+	if l.limit == 0 && methodLimit == 0 {
+		return true
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastPruned) >= bucketsPruneInterval {
+		l.prune(now)
+	}
+
+	if l.limit != 0 && !l.take(ip, l.limit, now) {
+		return false
+	}
+
+	return methodLimit == 0 || l.take(ip+" "+method, methodLimit, now)
+}
+
+// take takes a token from the bucket with the given key, which is refilled with the given number
+// of tokens per second. It returns false if the bucket is empty.
+func (l *rateLimiter) take(key string, limit uint32, now time.Time) bool {
+	bucket, has := l.buckets[key]
+	if !has {
+		bucket = &tokenBucket{
+			tokens:  float64(limit),
+			updated: now,
+		}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.updated).Seconds() * float64(limit)
+	if bucket.tokens > float64(limit) {
+		bucket.tokens = float64(limit)
+	}
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
+
+// prune removes the buckets which are full again, they are the same as new buckets
+func (l *rateLimiter) prune(now time.Time) {
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= time.Second {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastPruned = now
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(3, map[string]uint32{"chain_getBlockHash": 1})
+	l.now = func() time.Time { return now }
+	l.lastPruned = now
+
+	require.True(t, l.Allow("192.0.2.1", "chain_getBlockHash"))
+	// chain_getHead is an alias of chain_getBlockHash
+	require.False(t, l.Allow("192.0.2.1", "chain_getHead"))
+	// the refused calls count towards the limit of the client
+	require.True(t, l.Allow("192.0.2.1", "chain_getHeader"))
+	require.False(t, l.Allow("192.0.2.1", "chain_getHeader"))
+
+	// the limits are per client
+	require.True(t, l.Allow("192.0.2.2", "chain_getBlockHash"))
+
+	now = now.Add(500 * time.Millisecond)
+	require.True(t, l.Allow("192.0.2.1", "chain_getHeader"))
+	require.False(t, l.Allow("192.0.2.1", "chain_getBlockHash"))
+
+	now = now.Add(time.Second)
+	require.True(t, l.Allow("192.0.2.1", "chain_getBlockHash"))
+
+	now = now.Add(bucketsPruneInterval)
+	require.True(t, l.Allow("192.0.2.1", "chain_getHeader"))
+	require.Len(t, l.buckets, 1)
+}
+
+func TestRateLimiter_NoLimit(t *testing.T) {
+	l := newRateLimiter(0, nil)
+
+	for i := 0; i < 100; i++ {
+		require.True(t, l.Allow("192.0.2.1", "chain_getHeader"))
+	}
+	require.Empty(t, l.buckets)
+}
@@ -15,6 +15,7 @@
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/lib/trie"
 )
@@ -32,6 +33,7 @@ type StorageAPI interface {
 	GetKeysWithPrefix(root *common.Hash, prefix []byte) ([][]byte, error)
 	RegisterStorageObserver(observer state.Observer)
 	UnregisterStorageObserver(observer state.Observer)
+	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
 }
 
 //go:generate mockery --name BlockAPI --structname BlockAPI --case underscore --keeptree
@@ -53,7 +55,10 @@ type BlockAPI interface {
 	SubChain(start, end common.Hash) ([]common.Hash, error)
 	RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error)
 	UnregisterRuntimeUpdatedChannel(id uint32) bool
-	GetRuntime(hash *common.Hash) (runtime.Instance, error)
+	GetRuntimePool(hash *common.Hash) (*runtime.InstancePool, error)
+	GetNonFinalisedBlocks() []common.Hash
+	PinBlock(hash common.Hash) error
+	UnpinBlock(hash common.Hash) error
 }
 
 //go:generate mockery --name NetworkAPI --structname NetworkAPI --case underscore --keeptree
@@ -70,6 +75,7 @@ type NetworkAPI interface {
 	StartingBlock() int64
 	AddReservedPeers(addrs ...string) error
 	RemoveReservedPeers(addrs ...string) error
+	ReservedPeers() []string
 }
 
 // BlockProducerAPI is the interface for BlockProducer methods
@@ -90,6 +96,7 @@ type TransactionStateAPI interface {
 	Pending() []*transaction.ValidTransaction
 	GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status
 	FreeStatusNotifierChannel(ch chan transaction.Status)
+	RemoveAndBan(hashes ...common.Hash) []common.Hash
 }
 
 //go:generate mockery --name CoreAPI --structname CoreAPI --case underscore --keeptree
@@ -101,9 +108,12 @@ type CoreAPI interface {
 	GetRuntimeVersion(bhash *common.Hash) (runtime.Version, error)
 	HandleSubmittedExtrinsic(types.Extrinsic) error
 	GetMetadata(bhash *common.Hash) ([]byte, error)
-	QueryStorage(from, to common.Hash, keys ...string) (map[common.Hash]core.QueryKeyValueChanges, error)
+	QueryStorage(from, to common.Hash, maxRange uint32, keys ...string) ([]core.StorageChangeSet, error)
+	QueryStorageAt(at *common.Hash, keys ...string) (*core.StorageChangeSet, error)
 	DecodeSessionKeys(enc []byte) ([]byte, error)
 	GetReadProofAt(block common.Hash, keys [][]byte) (common.Hash, [][]byte, error)
+	CallAt(bhash *common.Hash, method string, data []byte) ([]byte, error)
+	TraceBlock(hash common.Hash) (*core.BlockTrace, error)
 }
 
 //go:generate mockery --name RPCAPI --structname RPCAPI --case underscore --keeptree
@@ -134,6 +144,7 @@ type BlockFinalityAPI interface {
 	GetVoters() grandpa.Voters
 	PreVotes() []ed25519.PublicKeyBytes
 	PreCommits() []ed25519.PublicKeyBytes
+	Misbehaviours() []grandpa.Misbehaviour
 }
 
 //go:generate mockery --name RuntimeStorageAPI --structname RuntimeStorageAPI --case underscore --keeptree
@@ -39,10 +39,10 @@ type Extrinsic struct {
 	Data string
 }
 
-// ExtrinsicOrHash is a type for Hash and Extrinsic array of bytes
+// ExtrinsicOrHash is a type for an extrinsic hash or a hex-encoded extrinsic
 type ExtrinsicOrHash struct {
-	Hash      common.Hash
-	Extrinsic []byte
+	Hash      common.Hash `json:"hash"`
+	Extrinsic string      `json:"extrinsic"`
 }
 
 // ExtrinsicOrHashRequest is a array of ExtrinsicOrHash
@@ -191,7 +191,30 @@ func (am *AuthorModule) PendingExtrinsics(r *http.Request, req *EmptyRequest, re
 }
 
 // RemoveExtrinsic Remove given extrinsic from the pool and temporarily ban it to prevent reimporting
-func (am *AuthorModule) RemoveExtrinsic(r *http.Request, _ *ExtrinsicOrHashRequest, _ *RemoveExtrinsicsResponse) error {
+func (am *AuthorModule) RemoveExtrinsic(
+	r *http.Request, req *ExtrinsicOrHashRequest, res *RemoveExtrinsicsResponse) error {
+	hashes := make([]common.Hash, len(*req))
+	for i, extOrHash := range *req {
+		if extOrHash.Extrinsic == "" {
+			if extOrHash.Hash.IsEmpty() {
+				return errors.New("either an extrinsic or an extrinsic hash must be provided")
+			}
+
+			hashes[i] = extOrHash.Hash
+			continue
+		}
+
+		extBytes, err := common.HexToBytes(extOrHash.Extrinsic)
+		if err != nil {
+			return err
+		}
+		hashes[i] = types.Extrinsic(extBytes).Hash()
+	}
+
+	removed := am.txStateAPI.RemoveAndBan(hashes...)
+	am.logger.Debugf("removed and banned %d extrinsics out of %d requested", len(removed), len(hashes))
+
+	*res = RemoveExtrinsicsResponse(removed)
 	return nil
 }
 
@@ -294,6 +294,54 @@ type args struct {
 	}
 }
 
+func TestAuthorModule_RemoveExtrinsic(t *testing.T) {
+	ext := types.Extrinsic{0x01, 0x02}
+	extHash := ext.Hash()
+	otherHash := common.Hash{1}
+
+	mockTransactionStateAPI := &mocks.TransactionStateAPI{}
+	mockTransactionStateAPI.On("RemoveAndBan", extHash, otherHash).Return([]common.Hash{extHash})
+
+	tests := []struct {
+		name    string
+		req     *ExtrinsicOrHashRequest
+		expErr  error
+		wantRes RemoveExtrinsicsResponse
+	}{
+		{
+			name: "extrinsic and hash",
+			req: &ExtrinsicOrHashRequest{
+				{Extrinsic: common.BytesToHex(ext)},
+				{Hash: otherHash},
+			},
+			wantRes: RemoveExtrinsicsResponse{extHash},
+		},
+		{
+			name:   "empty request item",
+			req:    &ExtrinsicOrHashRequest{{}},
+			expErr: errors.New("either an extrinsic or an extrinsic hash must be provided"),
+		},
+		{
+			name:   "invalid extrinsic",
+			req:    &ExtrinsicOrHashRequest{{Extrinsic: "0x0"}},
+			expErr: errors.New("encoding/hex: odd length hex string: 0x0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := NewAuthorModule(log.New(log.SetWriter(io.Discard)), nil, mockTransactionStateAPI)
+			var res RemoveExtrinsicsResponse
+			err := am.RemoveExtrinsic(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRes, res)
+		})
+	}
+}
+
 func TestAuthorModule_InsertKey(t *testing.T) {
 	kp1, err := sr25519.NewKeypairFromSeed(
 		common.MustHexToBytes("0x6246ddf254e0b4b4e7dffefc8adf69d212b98ac2b579c362b473fec8c40b4c0a"))
@@ -0,0 +1,46 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"math/big"
+	"net/http"
+)
+
+// ChainSpecModule is an RPC module providing the chainSpec_v1 methods of the new JSON-RPC interface
+type ChainSpecModule struct {
+	systemAPI SystemAPI
+	blockAPI  BlockAPI
+}
+
+// NewChainSpecModule creates a new chainSpec module
+func NewChainSpecModule(systemAPI SystemAPI, blockAPI BlockAPI) *ChainSpecModule {
+	return &ChainSpecModule{
+		systemAPI: systemAPI,
+		blockAPI:  blockAPI,
+	}
+}
+
+// V1ChainName returns the name of the chain
+func (cm *ChainSpecModule) V1ChainName(_ *http.Request, _ *EmptyRequest, res *string) error {
+	*res = cm.systemAPI.ChainName()
+	return nil
+}
+
+// V1GenesisHash returns the hash of the genesis block
+func (cm *ChainSpecModule) V1GenesisHash(_ *http.Request, _ *EmptyRequest, res *string) error {
+	hash, err := cm.blockAPI.GetHashByNumber(big.NewInt(0))
+	if err != nil {
+		return err
+	}
+
+	*res = hash.String()
+	return nil
+}
+
+// V1Properties returns the properties of the chain
+func (cm *ChainSpecModule) V1Properties(_ *http.Request, _ *EmptyRequest, res *interface{}) error {
+	*res = cm.systemAPI.Properties()
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainSpecModule_V1ChainName(t *testing.T) {
+	mockSystemAPI := new(mocks.SystemAPI)
+	mockSystemAPI.On("ChainName").Return("polkadot")
+	cm := NewChainSpecModule(mockSystemAPI, new(mocks.BlockAPI))
+
+	var res string
+	err := cm.V1ChainName(nil, &EmptyRequest{}, &res)
+	require.NoError(t, err)
+	require.Equal(t, "polkadot", res)
+}
+
+func TestChainSpecModule_V1GenesisHash(t *testing.T) {
+	genesisHash := common.Hash{1, 2, 3}
+	mockBlockAPI := new(mocks.BlockAPI)
+	mockBlockAPI.On("GetHashByNumber", big.NewInt(0)).Return(genesisHash, nil).Once()
+	mockBlockAPI.On("GetHashByNumber", big.NewInt(0)).Return(common.Hash{}, errors.New("noot")).Once()
+	cm := NewChainSpecModule(new(mocks.SystemAPI), mockBlockAPI)
+
+	var res string
+	err := cm.V1GenesisHash(nil, &EmptyRequest{}, &res)
+	require.NoError(t, err)
+	require.Equal(t, genesisHash.String(), res)
+
+	err = cm.V1GenesisHash(nil, &EmptyRequest{}, &res)
+	require.EqualError(t, err, "noot")
+}
+
+func TestChainSpecModule_V1Properties(t *testing.T) {
+	properties := map[string]interface{}{
+		"ss58Format":    0,
+		"tokenDecimals": 10,
+		"tokenSymbol":   "DOT",
+	}
+	mockSystemAPI := new(mocks.SystemAPI)
+	mockSystemAPI.On("Properties").Return(properties)
+	cm := NewChainSpecModule(mockSystemAPI, new(mocks.BlockAPI))
+
+	var res interface{}
+	err := cm.V1Properties(nil, &EmptyRequest{}, &res)
+	require.NoError(t, err)
+	require.Equal(t, properties, res)
+}
@@ -0,0 +1,40 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/dot/core"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// DebugTraceBlockRequest represents the request to trace the execution of a block
+type DebugTraceBlockRequest struct {
+	Hash common.Hash
+}
+
+// DebugModule holds the RPC methods used to debug the node
+type DebugModule struct {
+	coreAPI CoreAPI
+}
+
+// NewDebugModule returns a pointer to DebugModule
+func NewDebugModule(coreAPI CoreAPI) *DebugModule {
+	return &DebugModule{
+		coreAPI: coreAPI,
+	}
+}
+
+// TraceBlock re-executes the stored block with the given hash and responds with the
+// host function calls made by the runtime, the resulting state root and the execution error, if any
+func (dm *DebugModule) TraceBlock(_ *http.Request, req *DebugTraceBlockRequest, res *core.BlockTrace) error {
+	trace, err := dm.coreAPI.TraceBlock(req.Hash)
+	if err != nil {
+		return err
+	}
+
+	*res = *trace
+	return nil
+}
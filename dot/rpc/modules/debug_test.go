@@ -0,0 +1,67 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/core"
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugModule_TraceBlock(t *testing.T) {
+	testHash := common.NewHash([]byte{0x01, 0x02})
+	errTest := errors.New("test error")
+
+	trace := &core.BlockTrace{
+		BlockHash:         testHash,
+		ExpectedStateRoot: common.Hash{1},
+		StateRoot:         common.Hash{2},
+		Error:             "execution failed",
+		Events: []runtime.TraceEvent{
+			{Type: runtime.TraceHostCall, Name: "ext_storage_get_version_1"},
+			{Type: runtime.TraceStorageGet, Key: "0x01", Value: "0x02"},
+		},
+	}
+
+	tests := map[string]struct {
+		coreAPI  func() CoreAPI
+		expected core.BlockTrace
+		err      error
+	}{
+		"trace": {
+			coreAPI: func() CoreAPI {
+				coreAPI := new(mocks.CoreAPI)
+				coreAPI.On("TraceBlock", testHash).Return(trace, nil)
+				return coreAPI
+			},
+			expected: *trace,
+		},
+		"error": {
+			coreAPI: func() CoreAPI {
+				coreAPI := new(mocks.CoreAPI)
+				coreAPI.On("TraceBlock", testHash).Return(nil, errTest)
+				return coreAPI
+			},
+			err: errTest,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			dm := NewDebugModule(tt.coreAPI())
+
+			var res core.BlockTrace
+			err := dm.TraceBlock(nil, &DebugTraceBlockRequest{Hash: testHash}, &res)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
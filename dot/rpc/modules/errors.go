@@ -5,5 +5,11 @@
 
 import "errors"
 
-// ErrSubscriptionTransport error sent when trying to access websocket subscriptions via http
-var ErrSubscriptionTransport = errors.New("subscriptions are not available on this transport")
+var (
+	// ErrSubscriptionTransport error sent when trying to access websocket subscriptions via http
+	ErrSubscriptionTransport = errors.New("subscriptions are not available on this transport")
+
+	// ErrCoreAPINotAvailable error sent when calling a method which needs the core service on a node
+	// running without it, such as a light client
+	ErrCoreAPINotAvailable = errors.New("method is not available on this node")
+)
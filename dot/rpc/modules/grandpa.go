@@ -5,6 +5,7 @@
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
@@ -46,6 +47,23 @@ type RoundStateResponse struct {
 	Background []RoundState `json:"background"`
 }
 
+// MisbehaviourVote is a vote of an equivocation
+type MisbehaviourVote struct {
+	Hash   common.Hash `json:"hash"`
+	Number uint32      `json:"number"`
+}
+
+// MisbehaviourResponse is an equivocation of a GRANDPA voter detected by the node
+type MisbehaviourResponse struct {
+	Offender   string           `json:"offender"`
+	SetID      uint64           `json:"setId"`
+	Round      uint64           `json:"round"`
+	Stage      string           `json:"stage"`
+	FirstVote  MisbehaviourVote `json:"firstVote"`
+	SecondVote MisbehaviourVote `json:"secondVote"`
+	Detected   time.Time        `json:"detected"`
+}
+
 // ProveFinalityRequest request struct
 type ProveFinalityRequest struct {
 	blockHashStart common.Hash
@@ -129,6 +147,43 @@ func (gm *GrandpaModule) RoundState(r *http.Request, req *EmptyRequest, res *Rou
 	return nil
 }
 
+// Misbehaviours returns the most recent equivocations of GRANDPA voters detected by the node, oldest first.
+func (gm *GrandpaModule) Misbehaviours(r *http.Request, req *EmptyRequest, res *[]MisbehaviourResponse) error {
+	misbehaviours := gm.blockFinalityAPI.Misbehaviours()
+
+	offenders := make([]ed25519.PublicKeyBytes, len(misbehaviours))
+	for i, m := range misbehaviours {
+		offenders[i] = m.Proof.Equivocation.ID
+	}
+
+	addrs, err := toAddress(offenders)
+	if err != nil {
+		return err
+	}
+
+	*res = make([]MisbehaviourResponse, len(misbehaviours))
+	for i, m := range misbehaviours {
+		equivocation := m.Proof.Equivocation
+		(*res)[i] = MisbehaviourResponse{
+			Offender: addrs[i],
+			SetID:    m.Proof.SetID,
+			Round:    equivocation.RoundNumber,
+			Stage:    m.Proof.Stage.String(),
+			FirstVote: MisbehaviourVote{
+				Hash:   equivocation.FirstVote.Hash,
+				Number: equivocation.FirstVote.Number,
+			},
+			SecondVote: MisbehaviourVote{
+				Hash:   equivocation.SecondVote.Hash,
+				Number: equivocation.SecondVote.Number,
+			},
+			Detected: m.Detected,
+		}
+	}
+
+	return nil
+}
+
 func thresholdWeight(totalWeight uint32) uint32 {
 	return totalWeight * 2 / 3
 }
@@ -7,6 +7,7 @@
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -239,3 +240,49 @@ type args struct {
 		})
 	}
 }
+
+func TestGrandpaModule_Misbehaviours(t *testing.T) {
+	kr, err := keystore.NewEd25519Keyring()
+	assert.NoError(t, err)
+
+	alice := kr.Alice().Public().(*ed25519.PublicKey)
+	detected := time.Unix(1000, 0)
+	firstVote := types.GrandpaVote{Hash: common.Hash{1}, Number: 1}
+	secondVote := types.GrandpaVote{Hash: common.Hash{2}, Number: 1}
+
+	mockBlockFinalityAPI := new(mocks.BlockFinalityAPI)
+	mockBlockFinalityAPI.On("Misbehaviours").Return([]grandpa.Misbehaviour{
+		{
+			Proof: types.GrandpaEquivocationProof{
+				SetID: 1,
+				Stage: types.PrecommitEquivocation,
+				Equivocation: types.GrandpaEquivocation{
+					RoundNumber: 2,
+					ID:          alice.AsBytes(),
+					FirstVote:   firstVote,
+					SecondVote:  secondVote,
+				},
+			},
+			Detected: detected,
+		},
+	})
+
+	gm := NewGrandpaModule(new(mocks.BlockAPI), mockBlockFinalityAPI)
+
+	var res []MisbehaviourResponse
+	err = gm.Misbehaviours(nil, &EmptyRequest{}, &res)
+	assert.NoError(t, err)
+
+	expected := []MisbehaviourResponse{
+		{
+			Offender:   string(alice.Address()),
+			SetID:      1,
+			Round:      2,
+			Stage:      "precommit",
+			FirstVote:  MisbehaviourVote{Hash: common.Hash{1}, Number: 1},
+			SecondVote: MisbehaviourVote{Hash: common.Hash{2}, Number: 1},
+			Detected:   detected,
+		},
+	}
+	assert.Equal(t, expected, res)
+}
@@ -214,16 +214,32 @@ func (_m *BlockAPI) GetJustification(hash common.Hash) ([]byte, error) {
 	return r0, r1
 }
 
-// GetRuntime provides a mock function with given fields: hash
-func (_m *BlockAPI) GetRuntime(hash *common.Hash) (runtime.Instance, error) {
+// GetNonFinalisedBlocks provides a mock function with given fields:
+func (_m *BlockAPI) GetNonFinalisedBlocks() []common.Hash {
+	ret := _m.Called()
+
+	var r0 []common.Hash
+	if rf, ok := ret.Get(0).(func() []common.Hash); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	return r0
+}
+
+// GetRuntimePool provides a mock function with given fields: hash
+func (_m *BlockAPI) GetRuntimePool(hash *common.Hash) (*runtime.InstancePool, error) {
 	ret := _m.Called(hash)
 
-	var r0 runtime.Instance
-	if rf, ok := ret.Get(0).(func(*common.Hash) runtime.Instance); ok {
+	var r0 *runtime.InstancePool
+	if rf, ok := ret.Get(0).(func(*common.Hash) *runtime.InstancePool); ok {
 		r0 = rf(hash)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(runtime.Instance)
+			r0 = ret.Get(0).(*runtime.InstancePool)
 		}
 	}
 
@@ -258,6 +274,20 @@ func (_m *BlockAPI) HasJustification(hash common.Hash) (bool, error) {
 	return r0, r1
 }
 
+// PinBlock provides a mock function with given fields: hash
+func (_m *BlockAPI) PinBlock(hash common.Hash) error {
+	ret := _m.Called(hash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Hash) error); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RegisterRuntimeUpdatedChannel provides a mock function with given fields: ch
 func (_m *BlockAPI) RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error) {
 	ret := _m.Called(ch)
@@ -302,6 +332,20 @@ func (_m *BlockAPI) SubChain(start common.Hash, end common.Hash) ([]common.Hash,
 	return r0, r1
 }
 
+// UnpinBlock provides a mock function with given fields: hash
+func (_m *BlockAPI) UnpinBlock(hash common.Hash) error {
+	ret := _m.Called(hash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Hash) error); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnregisterRuntimeUpdatedChannel provides a mock function with given fields: id
 func (_m *BlockAPI) UnregisterRuntimeUpdatedChannel(id uint32) bool {
 	ret := _m.Called(id)
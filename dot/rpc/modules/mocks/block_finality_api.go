@@ -4,6 +4,8 @@
 
 import (
 	ed25519 "github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	grandpa "github.com/ChainSafe/gossamer/lib/grandpa"
+
 	mock "github.com/stretchr/testify/mock"
 
 	types "github.com/ChainSafe/gossamer/dot/types"
@@ -58,6 +60,22 @@ func (_m *BlockFinalityAPI) GetVoters() types.GrandpaVoters {
 	return r0
 }
 
+// Misbehaviours provides a mock function with given fields:
+func (_m *BlockFinalityAPI) Misbehaviours() []grandpa.Misbehaviour {
+	ret := _m.Called()
+
+	var r0 []grandpa.Misbehaviour
+	if rf, ok := ret.Get(0).(func() []grandpa.Misbehaviour); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]grandpa.Misbehaviour)
+		}
+	}
+
+	return r0
+}
+
 // PreCommits provides a mock function with given fields:
 func (_m *BlockFinalityAPI) PreCommits() []ed25519.PublicKeyBytes {
 	ret := _m.Called()
@@ -20,6 +20,29 @@ type CoreAPI struct {
 	mock.Mock
 }
 
+// CallAt provides a mock function with given fields: bhash, method, data
+func (_m *CoreAPI) CallAt(bhash *common.Hash, method string, data []byte) ([]byte, error) {
+	ret := _m.Called(bhash, method, data)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(*common.Hash, string, []byte) []byte); ok {
+		r0 = rf(bhash, method, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*common.Hash, string, []byte) error); ok {
+		r1 = rf(bhash, method, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DecodeSessionKeys provides a mock function with given fields: enc
 func (_m *CoreAPI) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	ret := _m.Called(enc)
@@ -170,29 +193,82 @@ func (_m *CoreAPI) InsertKey(kp crypto.Keypair, keystoreType string) error {
 	return r0
 }
 
-// QueryStorage provides a mock function with given fields: from, to, keys
-func (_m *CoreAPI) QueryStorage(from common.Hash, to common.Hash, keys ...string) (map[common.Hash]core.QueryKeyValueChanges, error) {
+// QueryStorage provides a mock function with given fields: from, to, maxRange, keys
+func (_m *CoreAPI) QueryStorage(from common.Hash, to common.Hash, maxRange uint32, keys ...string) ([]core.StorageChangeSet, error) {
 	_va := make([]interface{}, len(keys))
 	for _i := range keys {
 		_va[_i] = keys[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, from, to)
+	_ca = append(_ca, from, to, maxRange)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 map[common.Hash]core.QueryKeyValueChanges
-	if rf, ok := ret.Get(0).(func(common.Hash, common.Hash, ...string) map[common.Hash]core.QueryKeyValueChanges); ok {
-		r0 = rf(from, to, keys...)
+	var r0 []core.StorageChangeSet
+	if rf, ok := ret.Get(0).(func(common.Hash, common.Hash, uint32, ...string) []core.StorageChangeSet); ok {
+		r0 = rf(from, to, maxRange, keys...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.StorageChangeSet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash, common.Hash, uint32, ...string) error); ok {
+		r1 = rf(from, to, maxRange, keys...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueryStorageAt provides a mock function with given fields: at, keys
+func (_m *CoreAPI) QueryStorageAt(at *common.Hash, keys ...string) (*core.StorageChangeSet, error) {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, at)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *core.StorageChangeSet
+	if rf, ok := ret.Get(0).(func(*common.Hash, ...string) *core.StorageChangeSet); ok {
+		r0 = rf(at, keys...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.StorageChangeSet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*common.Hash, ...string) error); ok {
+		r1 = rf(at, keys...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TraceBlock provides a mock function with given fields: hash
+func (_m *CoreAPI) TraceBlock(hash common.Hash) (*core.BlockTrace, error) {
+	ret := _m.Called(hash)
+
+	var r0 *core.BlockTrace
+	if rf, ok := ret.Get(0).(func(common.Hash) *core.BlockTrace); ok {
+		r0 = rf(hash)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[common.Hash]core.QueryKeyValueChanges)
+			r0 = ret.Get(0).(*core.BlockTrace)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Hash, common.Hash, ...string) error); ok {
-		r1 = rf(from, to, keys...)
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(hash)
 	} else {
 		r1 = ret.Error(1)
 	}
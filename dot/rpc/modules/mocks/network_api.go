@@ -124,6 +124,22 @@ func (_m *NetworkAPI) RemoveReservedPeers(addrs ...string) error {
 	return r0
 }
 
+// ReservedPeers provides a mock function with given fields:
+func (_m *NetworkAPI) ReservedPeers() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *NetworkAPI) Start() error {
 	ret := _m.Called()
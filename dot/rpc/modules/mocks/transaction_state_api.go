@@ -100,3 +100,25 @@ func (_m *TransactionStateAPI) Pop() *transaction.ValidTransaction {
 
 	return r0
 }
+
+// RemoveAndBan provides a mock function with given fields: hashes
+func (_m *TransactionStateAPI) RemoveAndBan(hashes ...common.Hash) []common.Hash {
+	_va := make([]interface{}, len(hashes))
+	for _i := range hashes {
+		_va[_i] = hashes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []common.Hash
+	if rf, ok := ret.Get(0).(func(...common.Hash) []common.Hash); ok {
+		r0 = rf(hashes...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	return r0
+}
@@ -6,7 +6,9 @@
 import (
 	"net/http"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 )
 
 // PaymentQueryInfoRequest represents the request to get the fee of an extrinsic in a given block
@@ -26,13 +28,15 @@ type PaymentQueryInfoResponse struct {
 
 // PaymentModule holds all the RPC implementation of polkadot payment rpc api
 type PaymentModule struct {
-	blockAPI BlockAPI
+	blockAPI   BlockAPI
+	storageAPI StorageAPI
 }
 
 // NewPaymentModule returns a pointer to PaymentModule
-func NewPaymentModule(blockAPI BlockAPI) *PaymentModule {
+func NewPaymentModule(blockAPI BlockAPI, storageAPI StorageAPI) *PaymentModule {
 	return &PaymentModule{
-		blockAPI: blockAPI,
+		blockAPI:   blockAPI,
+		storageAPI: storageAPI,
 	}
 }
 
@@ -45,17 +49,31 @@ func (p *PaymentModule) QueryInfo(_ *http.Request, req *PaymentQueryInfoRequest,
 		hash = *req.Hash
 	}
 
-	r, err := p.blockAPI.GetRuntime(&hash)
+	ext, err := common.HexToBytes(req.Ext)
 	if err != nil {
 		return err
 	}
 
-	ext, err := common.HexToBytes(req.Ext)
+	stateRoot, err := p.storageAPI.GetStateRootFromBlock(&hash)
+	if err != nil {
+		return err
+	}
+
+	ts, err := p.storageAPI.TrieState(stateRoot)
 	if err != nil {
 		return err
 	}
 
-	encQueryInfo, err := r.PaymentQueryInfo(ext)
+	pool, err := p.blockAPI.GetRuntimePool(&hash)
+	if err != nil {
+		return err
+	}
+
+	var encQueryInfo *types.TransactionPaymentQueryInfo
+	err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+		encQueryInfo, err = rt.PaymentQueryInfo(ext)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -16,7 +16,7 @@
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
-	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
 )
 
@@ -38,15 +38,19 @@ func TestPaymentQueryInfo(t *testing.T) {
 		}
 
 		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", mock.AnythingOfType("*storage.TrieState"))
 		runtimeMock.On("PaymentQueryInfo", mock.AnythingOfType("[]uint8")).Return(mockedQueryInfo, nil)
 
 		blockAPIMock := new(mocks.BlockAPI)
 		blockAPIMock.On("BestBlockHash").Return(bestBlockHash)
 
-		blockAPIMock.On("GetRuntime", mock.AnythingOfType("*common.Hash")).Return(runtimeMock, nil)
+		pool, err := runtime.NewInstancePool(runtimeMock, 1)
+		require.NoError(t, err)
+		blockAPIMock.On("GetRuntimePool", mock.AnythingOfType("*common.Hash")).Return(pool, nil)
 
 		mod := &PaymentModule{
-			blockAPI: blockAPIMock,
+			blockAPI:   blockAPIMock,
+			storageAPI: state.Storage,
 		}
 
 		var req PaymentQueryInfoRequest
@@ -54,14 +58,14 @@ func TestPaymentQueryInfo(t *testing.T) {
 		req.Hash = nil
 
 		var res PaymentQueryInfoResponse
-		err := mod.QueryInfo(nil, &req, &res)
+		err = mod.QueryInfo(nil, &req, &res)
 
 		require.NoError(t, err)
 		require.Equal(t, expected, res)
 
 		// should be called because req.Hash is nil
 		blockAPIMock.AssertCalled(t, "BestBlockHash")
-		blockAPIMock.AssertCalled(t, "GetRuntime", mock.AnythingOfType("*common.Hash"))
+		blockAPIMock.AssertCalled(t, "GetRuntimePool", mock.AnythingOfType("*common.Hash"))
 		runtimeMock.AssertCalled(t, "PaymentQueryInfo", mock.AnythingOfType("[]uint8"))
 	})
 
@@ -69,11 +73,12 @@ func TestPaymentQueryInfo(t *testing.T) {
 		blockAPIMock := new(mocks.BlockAPI)
 		blockAPIMock.On("BestBlockHash").Return(bestBlockHash)
 
-		blockAPIMock.On("GetRuntime", mock.AnythingOfType("*common.Hash")).
+		blockAPIMock.On("GetRuntimePool", mock.AnythingOfType("*common.Hash")).
 			Return(nil, errors.New("mocked problems"))
 
 		mod := &PaymentModule{
-			blockAPI: blockAPIMock,
+			blockAPI:   blockAPIMock,
+			storageAPI: state.Storage,
 		}
 
 		var req PaymentQueryInfoRequest
@@ -87,62 +92,68 @@ func TestPaymentQueryInfo(t *testing.T) {
 		require.Equal(t, res, PaymentQueryInfoResponse{})
 
 		blockAPIMock.AssertCalled(t, "BestBlockHash")
-		blockAPIMock.AssertCalled(t, "GetRuntime", mock.AnythingOfType("*common.Hash"))
+		blockAPIMock.AssertCalled(t, "GetRuntimePool", mock.AnythingOfType("*common.Hash"))
 	})
 
 	t.Run("When PaymentQueryInfo returns error", func(t *testing.T) {
 		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", mock.AnythingOfType("*storage.TrieState"))
 		runtimeMock.On("PaymentQueryInfo", mock.AnythingOfType("[]uint8")).Return(nil, errors.New("mocked error"))
 
 		blockAPIMock := new(mocks.BlockAPI)
-		blockAPIMock.On("GetRuntime", mock.AnythingOfType("*common.Hash")).Return(runtimeMock, nil)
+		pool, err := runtime.NewInstancePool(runtimeMock, 1)
+		require.NoError(t, err)
+		blockAPIMock.On("GetRuntimePool", mock.AnythingOfType("*common.Hash")).Return(pool, nil)
 
 		mod := &PaymentModule{
-			blockAPI: blockAPIMock,
+			blockAPI:   blockAPIMock,
+			storageAPI: state.Storage,
 		}
 
-		mockedHash := common.NewHash([]byte{0x01, 0x02})
 		var req PaymentQueryInfoRequest
 		req.Ext = "0x0000"
-		req.Hash = &mockedHash
+		req.Hash = &bestBlockHash
 
 		var res PaymentQueryInfoResponse
-		err := mod.QueryInfo(nil, &req, &res)
+		err = mod.QueryInfo(nil, &req, &res)
 
 		require.Error(t, err)
 		require.Equal(t, res, PaymentQueryInfoResponse{})
 
 		// should be called because req.Hash is nil
 		blockAPIMock.AssertNotCalled(t, "BestBlockHash")
-		blockAPIMock.AssertCalled(t, "GetRuntime", mock.AnythingOfType("*common.Hash"))
+		blockAPIMock.AssertCalled(t, "GetRuntimePool", mock.AnythingOfType("*common.Hash"))
 		runtimeMock.AssertCalled(t, "PaymentQueryInfo", mock.AnythingOfType("[]uint8"))
 	})
 
 	t.Run("When PaymentQueryInfo returns a nil info", func(t *testing.T) {
 		runtimeMock := new(mocksruntime.Instance)
+		runtimeMock.On("SetContextStorage", mock.AnythingOfType("*storage.TrieState"))
 		runtimeMock.On("PaymentQueryInfo", mock.AnythingOfType("[]uint8")).Return(nil, nil)
 
 		blockAPIMock := new(mocks.BlockAPI)
-		blockAPIMock.On("GetRuntime", mock.AnythingOfType("*common.Hash")).Return(runtimeMock, nil)
+		pool, err := runtime.NewInstancePool(runtimeMock, 1)
+		require.NoError(t, err)
+		blockAPIMock.On("GetRuntimePool", mock.AnythingOfType("*common.Hash")).Return(pool, nil)
 
 		mod := &PaymentModule{
-			blockAPI: blockAPIMock,
+			blockAPI:   blockAPIMock,
+			storageAPI: state.Storage,
 		}
 
-		mockedHash := common.NewHash([]byte{0x01, 0x02})
 		var req PaymentQueryInfoRequest
 		req.Ext = "0x0020"
-		req.Hash = &mockedHash
+		req.Hash = &bestBlockHash
 
 		var res PaymentQueryInfoResponse
-		err := mod.QueryInfo(nil, &req, &res)
+		err = mod.QueryInfo(nil, &req, &res)
 
 		require.NoError(t, err)
 		require.Equal(t, res, PaymentQueryInfoResponse{})
 
 		// should be called because req.Hash is nil
 		blockAPIMock.AssertNotCalled(t, "BestBlockHash")
-		blockAPIMock.AssertCalled(t, "GetRuntime", mock.AnythingOfType("*common.Hash"))
+		blockAPIMock.AssertCalled(t, "GetRuntimePool", mock.AnythingOfType("*common.Hash"))
 		runtimeMock.AssertCalled(t, "PaymentQueryInfo", mock.AnythingOfType("[]uint8"))
 	})
 }
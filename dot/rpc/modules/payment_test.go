@@ -12,7 +12,9 @@
 	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 
 	"github.com/stretchr/testify/assert"
@@ -28,19 +30,34 @@ func TestPaymentModule_QueryInfo(t *testing.T) {
 	runtimeMock2 := new(mocksruntime.Instance)
 	runtimeErrorMock := new(mocksruntime.Instance)
 
+	ts, err := rtstorage.NewTrieState(nil)
+	require.NoError(t, err)
+	stateRoot := ts.MustRoot()
+
+	storageAPIMock := new(mocks.StorageAPI)
+	storageAPIMock.On("GetStateRootFromBlock", &testHash).Return(&stateRoot, nil)
+	storageAPIMock.On("TrieState", &stateRoot).Return(ts, nil)
+
 	blockAPIMock := new(mocks.BlockAPI)
 	blockAPIMock2 := new(mocks.BlockAPI)
 	blockErrorAPIMock1 := new(mocks.BlockAPI)
 	blockErrorAPIMock2 := new(mocks.BlockAPI)
 
+	newPool := func(instance *mocksruntime.Instance) *runtime.InstancePool {
+		instance.On("SetContextStorage", ts)
+		pool, err := runtime.NewInstancePool(instance, 1)
+		require.NoError(t, err)
+		return pool
+	}
+
 	blockAPIMock.On("BestBlockHash").Return(testHash, nil)
-	blockAPIMock.On("GetRuntime", &testHash).Return(runtimeMock, nil)
+	blockAPIMock.On("GetRuntimePool", &testHash).Return(newPool(runtimeMock), nil)
 
-	blockAPIMock2.On("GetRuntime", &testHash).Return(runtimeMock2, nil)
+	blockAPIMock2.On("GetRuntimePool", &testHash).Return(newPool(runtimeMock2), nil)
 
-	blockErrorAPIMock1.On("GetRuntime", &testHash).Return(runtimeErrorMock, nil)
+	blockErrorAPIMock1.On("GetRuntimePool", &testHash).Return(newPool(runtimeErrorMock), nil)
 
-	blockErrorAPIMock2.On("GetRuntime", &testHash).Return(nil, errors.New("GetRuntime error"))
+	blockErrorAPIMock2.On("GetRuntimePool", &testHash).Return(nil, errors.New("GetRuntimePool error"))
 
 	runtimeMock.On("PaymentQueryInfo", common.MustHexToBytes("0x0000")).Return(nil, nil)
 	runtimeMock2.On("PaymentQueryInfo", common.MustHexToBytes("0x0000")).Return(&types.TransactionPaymentQueryInfo{
@@ -51,9 +68,10 @@ func TestPaymentModule_QueryInfo(t *testing.T) {
 	runtimeErrorMock.On("PaymentQueryInfo", common.MustHexToBytes("0x0000")).
 		Return(nil, errors.New("PaymentQueryInfo error"))
 
-	paymentModule := NewPaymentModule(blockAPIMock)
+	paymentModule := NewPaymentModule(blockAPIMock, storageAPIMock)
 	type fields struct {
-		blockAPI BlockAPI
+		blockAPI   BlockAPI
+		storageAPI StorageAPI
 	}
 	type args struct {
 		in0 *http.Request
@@ -70,6 +88,7 @@ type args struct {
 			name: "Nil Query Info",
 			fields: fields{
 				paymentModule.blockAPI,
+				storageAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -83,6 +102,7 @@ type args struct {
 			name: "Not Nil Query Info",
 			fields: fields{
 				blockAPIMock2,
+				storageAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -102,6 +122,7 @@ type args struct {
 			name: "Nil Hash",
 			fields: fields{
 				paymentModule.blockAPI,
+				storageAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -114,6 +135,7 @@ type args struct {
 			name: "Invalid Ext",
 			fields: fields{
 				paymentModule.blockAPI,
+				storageAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -126,6 +148,7 @@ type args struct {
 			name: "PaymentQueryInfo error",
 			fields: fields{
 				blockErrorAPIMock1,
+				storageAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -136,9 +159,10 @@ type args struct {
 			expErr: errors.New("PaymentQueryInfo error"),
 		},
 		{
-			name: "GetRuntime error",
+			name: "GetRuntimePool error",
 			fields: fields{
 				blockErrorAPIMock2,
+				storageAPIMock,
 			},
 			args: args{
 				req: &PaymentQueryInfoRequest{
@@ -146,13 +170,14 @@ type args struct {
 					Hash: &testHash,
 				},
 			},
-			expErr: errors.New("GetRuntime error"),
+			expErr: errors.New("GetRuntimePool error"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &PaymentModule{
-				blockAPI: tt.fields.blockAPI,
+				blockAPI:   tt.fields.blockAPI,
+				storageAPI: tt.fields.storageAPI,
 			}
 			res := PaymentQueryInfoResponse{}
 			err := p.QueryInfo(tt.args.in0, tt.args.req, &res)
@@ -10,6 +10,7 @@
 	"net/http"
 	"strings"
 
+	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -24,7 +25,7 @@ type StateGetReadProofRequest struct {
 // StateCallRequest holds json fields
 type StateCallRequest struct {
 	Method string       `json:"method"`
-	Data   []byte       `json:"data"`
+	Data   string       `json:"data"`
 	Block  *common.Hash `json:"block"`
 }
 
@@ -77,11 +78,17 @@ type StateStorageQueryRangeRequest struct {
 	EndBlock   common.Hash `json:"block"`
 }
 
+// StateStorageQueryAtRequest holds json fields
+type StateStorageQueryAtRequest struct {
+	Keys []string     `json:"keys" validate:"required"`
+	At   *common.Hash `json:"at"`
+}
+
 // StateStorageKeysQuery field to store storage keys
 type StateStorageKeysQuery [][]byte
 
-// StateCallResponse holds json fields
-type StateCallResponse []byte
+// StateCallResponse is the hex encoded output of a runtime call
+type StateCallResponse string
 
 // StateKeysResponse field to store the state keys
 type StateKeysResponse [][]byte
@@ -120,9 +127,10 @@ type StateGetReadProofResponse struct {
 }
 
 // StorageChangeSetResponse is the struct that holds the block and changes
+//  where a nil value means the key has no value at that block
 type StorageChangeSetResponse struct {
 	Block   *common.Hash `json:"block"`
-	Changes [][]string   `json:"changes"`
+	Changes [][]*string  `json:"changes"`
 }
 
 // KeyValueOption struct holds json fields
@@ -142,19 +150,29 @@ type StateRuntimeVersionResponse struct {
 	Apis               []interface{} `json:"apis"`
 }
 
+// DefaultMaxQueryStorageRange is the default maximum number of blocks state_queryStorage can query
+const DefaultMaxQueryStorageRange uint32 = 1000
+
 // StateModule is an RPC module providing access to storage API points.
 type StateModule struct {
-	networkAPI NetworkAPI
-	storageAPI StorageAPI
-	coreAPI    CoreAPI
+	networkAPI           NetworkAPI
+	storageAPI           StorageAPI
+	coreAPI              CoreAPI
+	maxQueryStorageRange uint32
 }
 
-// NewStateModule creates a new State module.
-func NewStateModule(net NetworkAPI, storage StorageAPI, core CoreAPI) *StateModule {
+// NewStateModule creates a new State module. If maxQueryStorageRange is 0,
+//  DefaultMaxQueryStorageRange is used.
+func NewStateModule(net NetworkAPI, storage StorageAPI, core CoreAPI, maxQueryStorageRange uint32) *StateModule {
+	if maxQueryStorageRange == 0 {
+		maxQueryStorageRange = DefaultMaxQueryStorageRange
+	}
+
 	return &StateModule{
-		networkAPI: net,
-		storageAPI: storage,
-		coreAPI:    core,
+		networkAPI:           net,
+		storageAPI:           storage,
+		coreAPI:              core,
+		maxQueryStorageRange: maxQueryStorageRange,
 	}
 }
 
@@ -211,10 +229,28 @@ func (sm *StateModule) GetPairs(_ *http.Request, req *StatePairRequest, res *Sta
 	return nil
 }
 
-// Call isn't implemented properly yet.
-func (sm *StateModule) Call(_ *http.Request, _ *StateCallRequest, _ *StateCallResponse) error {
-	_ = sm.networkAPI
-	_ = sm.storageAPI
+// Call executes a runtime API function at the given block's state and returns the
+// hex encoded SCALE output. If no block hash is provided, the best block is used.
+func (sm *StateModule) Call(_ *http.Request, req *StateCallRequest, res *StateCallResponse) error {
+	if req.Method == "" {
+		return errors.New("method name cannot be empty")
+	}
+
+	data, err := common.HexToBytes(req.Data)
+	if err != nil {
+		return fmt.Errorf("cannot convert hex data %s to bytes: %w", req.Data, err)
+	}
+
+	if sm.coreAPI == nil {
+		return ErrCoreAPINotAvailable
+	}
+
+	ret, err := sm.coreAPI.CallAt(req.Block, req.Method, data)
+	if err != nil {
+		return err
+	}
+
+	*res = StateCallResponse(common.BytesToHex(ret))
 	return nil
 }
 
@@ -249,6 +285,10 @@ func (sm *StateModule) GetKeysPaged(_ *http.Request, req *StateStorageKeyRequest
 
 // GetMetadata calls runtime Metadata_metadata function
 func (sm *StateModule) GetMetadata(_ *http.Request, req *StateRuntimeMetadataQuery, res *StateMetadataResponse) error {
+	if sm.coreAPI == nil {
+		return ErrCoreAPINotAvailable
+	}
+
 	metadata, err := sm.coreAPI.GetMetadata(req.Bhash)
 	if err != nil {
 		return err
@@ -263,6 +303,10 @@ func (sm *StateModule) GetMetadata(_ *http.Request, req *StateRuntimeMetadataQue
 // GetReadProof returns the proof to the received storage keys
 func (sm *StateModule) GetReadProof(
 	_ *http.Request, req *StateGetReadProofRequest, res *StateGetReadProofResponse) error {
+	if sm.coreAPI == nil {
+		return ErrCoreAPINotAvailable
+	}
+
 	keys := make([][]byte, len(req.Keys))
 	for i, hexKey := range req.Keys {
 		bKey, err := common.HexToBytes(hexKey)
@@ -295,6 +339,10 @@ func (sm *StateModule) GetReadProof(
 //  If no block hash is provided, the latest version gets returned.
 func (sm *StateModule) GetRuntimeVersion(
 	_ *http.Request, req *StateRuntimeVersionRequest, res *StateRuntimeVersionResponse) error {
+	if sm.coreAPI == nil {
+		return ErrCoreAPINotAvailable
+	}
+
 	rtVersion, err := sm.coreAPI.GetRuntimeVersion(req.Bhash)
 	if err != nil {
 		return err
@@ -403,35 +451,47 @@ func (sm *StateModule) GetStorageSize(
 	return nil
 }
 
-// QueryStorage isn't implemented properly yet.
+// QueryStorage returns the changes of the given storage keys for every block between the start block
+//  and the end block (or the best block if no end block is provided). The first change set holds the
+//  value of every key, following change sets only hold the keys that changed since the previous block.
 func (sm *StateModule) QueryStorage(
 	_ *http.Request, req *StateStorageQueryRangeRequest, res *[]StorageChangeSetResponse) error {
+	if sm.coreAPI == nil {
+		return ErrCoreAPINotAvailable
+	}
+
 	if req.StartBlock.IsEmpty() {
 		return errors.New("the start block hash cannot be an empty value")
 	}
 
-	changesByBlock, err := sm.coreAPI.QueryStorage(req.StartBlock, req.EndBlock, req.Keys...)
+	changeSets, err := sm.coreAPI.QueryStorage(req.StartBlock, req.EndBlock, sm.maxQueryStorageRange, req.Keys...)
 	if err != nil {
 		return err
 	}
 
-	response := make([]StorageChangeSetResponse, 0, len(changesByBlock))
+	response := make([]StorageChangeSetResponse, len(changeSets))
+	for i, changeSet := range changeSets {
+		response[i] = newStorageChangeSetResponse(changeSet, req.Keys)
+	}
 
-	for block, c := range changesByBlock {
-		var changes [][]string
+	*res = response
+	return nil
+}
 
-		for key, value := range c {
-			changes = append(changes, []string{key, value})
-		}
+// QueryStorageAt returns the values of the given storage keys at the given block,
+//  if no block hash is provided, the best block is used.
+func (sm *StateModule) QueryStorageAt(
+	_ *http.Request, req *StateStorageQueryAtRequest, res *[]StorageChangeSetResponse) error {
+	if sm.coreAPI == nil {
+		return ErrCoreAPINotAvailable
+	}
 
-		blochHash := block
-		response = append(response, StorageChangeSetResponse{
-			Block:   &blochHash,
-			Changes: changes,
-		})
+	changeSet, err := sm.coreAPI.QueryStorageAt(req.At, req.Keys...)
+	if err != nil {
+		return err
 	}
 
-	*res = response
+	*res = []StorageChangeSetResponse{newStorageChangeSetResponse(*changeSet, req.Keys)}
 	return nil
 }
 
@@ -444,13 +504,56 @@ func (sm *StateModule) SubscribeRuntimeVersion(
 
 // SubscribeStorage Storage subscription. If storage keys are specified, it creates a message for each block which
 //  changes the specified storage keys. If none are specified, then it creates a message for every block.
-//  This endpoint communicates over the Websocket protocol, but this func should remain here so it's
-// 	added to rpc_methods list
-func (*StateModule) SubscribeStorage(
-	_ *http.Request, _ *StateStorageQueryRangeRequest, _ *StorageChangeSetResponse) error {
+//  Subscriptions are handled over the Websocket protocol, over HTTP this returns the values of the
+//  specified storage keys at the end block (or the best block if no end block is provided), which is
+//  the first message a websocket subscription would receive.
+func (sm *StateModule) SubscribeStorage(
+	_ *http.Request, req *StateStorageQueryRangeRequest, res *StorageChangeSetResponse) error {
+	if sm.coreAPI == nil {
+		return ErrCoreAPINotAvailable
+	}
+
+	var at *common.Hash
+	if !req.EndBlock.IsEmpty() {
+		at = &req.EndBlock
+	}
+
+	changeSet, err := sm.coreAPI.QueryStorageAt(at, req.Keys...)
+	if err != nil {
+		return err
+	}
+
+	*res = newStorageChangeSetResponse(*changeSet, req.Keys)
 	return nil
 }
 
+// newStorageChangeSetResponse converts a change set to its json representation,
+//  keeping the changes in the order the keys were requested.
+func newStorageChangeSetResponse(changeSet core.StorageChangeSet, keys []string) StorageChangeSetResponse {
+	block := changeSet.Block
+	changes := make([][]*string, 0, len(changeSet.Changes))
+	for _, key := range keys {
+		value, ok := changeSet.Changes[key]
+		if !ok {
+			continue
+		}
+
+		k := key
+		var v *string
+		if value != nil {
+			hexValue := common.BytesToHex(value)
+			v = &hexValue
+		}
+
+		changes = append(changes, []*string{&k, v})
+	}
+
+	return StorageChangeSetResponse{
+		Block:   &block,
+		Changes: changes,
+	}
+}
+
 // ConvertAPIs runtime.APIItems to []interface
 func ConvertAPIs(in []runtime.APIItem) []interface{} {
 	ret := make([]interface{}, 0)
@@ -320,7 +320,8 @@ func TestStateModule_QueryStorage(t *testing.T) {
 
 	t.Run("When coreAPI QueryStorage returns error", func(t *testing.T) {
 		coreapimock := new(mocks.CoreAPI)
-		coreapimock.On("QueryStorage", mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash")).
+		coreapimock.On("QueryStorage",
+			mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash"), mock.AnythingOfType("uint32")).
 			Return(nil, errors.New("problem while querying"))
 
 		module := new(StateModule)
@@ -332,21 +333,26 @@ func TestStateModule_QueryStorage(t *testing.T) {
 		var res []StorageChangeSetResponse
 		err := module.QueryStorage(nil, req, &res)
 		require.Error(t, err)
-		coreapimock.AssertCalled(t, "QueryStorage", mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash"))
+		coreapimock.AssertCalled(t, "QueryStorage",
+			mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash"), mock.AnythingOfType("uint32"))
 	})
 
 	t.Run("When QueryStorage returns data", func(t *testing.T) {
 		blockhash := common.NewHash([]byte{123})
 
-		changes := map[common.Hash]core.QueryKeyValueChanges{
-			blockhash: core.QueryKeyValueChanges(map[string]string{
-				"0x80": "value",
-				"0x90": "another value",
-			}),
+		changes := []core.StorageChangeSet{
+			{
+				Block: blockhash,
+				Changes: core.QueryKeyValueChanges{
+					"0x80": []byte("value"),
+					"0x90": []byte("another value"),
+				},
+			},
 		}
 		coreapimock := new(mocks.CoreAPI)
 		coreapimock.On("QueryStorage",
-			mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash"), "0x90", "0x80").Return(changes, nil)
+			mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash"), mock.AnythingOfType("uint32"),
+			"0x90", "0x80").Return(changes, nil)
 
 		module := new(StateModule)
 		module.coreAPI = coreapimock
@@ -359,9 +365,11 @@ func TestStateModule_QueryStorage(t *testing.T) {
 		err := module.QueryStorage(nil, req, &res)
 		require.NoError(t, err)
 		require.Len(t, res, 1)
+		require.Len(t, res[0].Changes, 2)
 
 		coreapimock.AssertCalled(t, "QueryStorage",
-			mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash"), "0x90", "0x80")
+			mock.AnythingOfType("common.Hash"), mock.AnythingOfType("common.Hash"), mock.AnythingOfType("uint32"),
+			"0x90", "0x80")
 	})
 }
 
@@ -551,5 +559,5 @@ func setupStateModule(t *testing.T) (*StateModule, *common.Hash, *common.Hash) {
 	require.NoError(t, err)
 
 	core := newCoreService(t, chain)
-	return NewStateModule(net, chain.Storage, core), &hash, &sr1
+	return NewStateModule(net, chain.Storage, core, 0), &hash, &sr1
 }
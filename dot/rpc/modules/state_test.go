@@ -278,14 +278,84 @@ type args struct {
 	}
 }
 
-// Implement Tests once function is implemented
-func TestCall(t *testing.T) {
-	mockNetworkAPI := new(mocks.NetworkAPI)
-	mockStorageAPI := new(mocks.StorageAPI)
-	sm := NewStateModule(mockNetworkAPI, mockStorageAPI, nil)
+func TestStateModuleCall(t *testing.T) {
+	hash := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
 
-	err := sm.Call(nil, nil, nil)
-	assert.NoError(t, err)
+	mockCoreAPI := new(mocks.CoreAPI)
+	mockCoreAPI.On("CallAt", &hash, "AccountNonceApi_account_nonce", []byte{1, 2}).
+		Return([]byte{3, 0, 0, 0}, nil)
+	mockCoreAPI.On("CallAt", (*common.Hash)(nil), "Core_version", []byte{}).
+		Return([]byte{4}, nil)
+
+	mockCoreAPIErr := new(mocks.CoreAPI)
+	mockCoreAPIErr.On("CallAt", &hash, "Core_version", []byte{}).
+		Return(nil, errors.New("CallAt Error"))
+
+	tests := []struct {
+		name    string
+		coreAPI CoreAPI
+		req     *StateCallRequest
+		expErr  error
+		exp     StateCallResponse
+	}{
+		{
+			name:    "OK Case",
+			coreAPI: mockCoreAPI,
+			req: &StateCallRequest{
+				Method: "AccountNonceApi_account_nonce",
+				Data:   "0x0102",
+				Block:  &hash,
+			},
+			exp: StateCallResponse("0x03000000"),
+		},
+		{
+			name:    "Nil block",
+			coreAPI: mockCoreAPI,
+			req: &StateCallRequest{
+				Method: "Core_version",
+				Data:   "0x",
+			},
+			exp: StateCallResponse("0x04"),
+		},
+		{
+			name: "Empty method",
+			req: &StateCallRequest{
+				Data: "0x",
+			},
+			expErr: errors.New("method name cannot be empty"),
+		},
+		{
+			name: "Invalid data",
+			req: &StateCallRequest{
+				Method: "Core_version",
+				Data:   "0102",
+			},
+			expErr: errors.New("cannot convert hex data 0102 to bytes: could not byteify non 0x prefixed string: 0102"),
+		},
+		{
+			name:    "CallAt Error",
+			coreAPI: mockCoreAPIErr,
+			req: &StateCallRequest{
+				Method: "Core_version",
+				Data:   "0x",
+				Block:  &hash,
+			},
+			expErr: errors.New("CallAt Error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := NewStateModule(nil, nil, tt.coreAPI, 0)
+			res := StateCallResponse("")
+			err := sm.Call(nil, tt.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
 }
 
 func TestStateModuleGetMetadata(t *testing.T) {
@@ -297,7 +367,7 @@ func TestStateModuleGetMetadata(t *testing.T) {
 	mockCoreAPIErr := new(mocks.CoreAPI)
 	mockCoreAPIErr.On("GetMetadata", &hash).Return(nil, errors.New("GetMetadata Error"))
 
-	mockStateModule := NewStateModule(nil, nil, mockCoreAPIErr)
+	mockStateModule := NewStateModule(nil, nil, mockCoreAPIErr, 0)
 
 	var expRes []byte
 	err := scale.Unmarshal(common.MustHexToBytes(testdata.NewTestMetadata()), &expRes)
@@ -334,6 +404,13 @@ type args struct {
 			},
 			expErr: errors.New("GetMetadata Error"),
 		},
+		{
+			name: "no core API",
+			args: args{
+				req: &StateRuntimeMetadataQuery{Bhash: &hash},
+			},
+			expErr: ErrCoreAPINotAvailable,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -797,73 +874,74 @@ type args struct {
 }
 
 func TestStateModuleQueryStorage(t *testing.T) {
-	qkvc1 := core.QueryKeyValueChanges{}
-	qkvc1["p1"] = "jimmy"
-	qkvc2 := core.QueryKeyValueChanges{}
-	qkvc2["p2"] = "jimbo"
+	value := "0x0102"
+	key1, key2 := "0x10", "0x20"
 
 	hash1 := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
-	hash2 := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
+	hash2 := common.MustHexToHash("0x4aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
 
-	m := map[common.Hash]core.QueryKeyValueChanges{}
-	m[hash1] = qkvc1
-	m[hash2] = qkvc2
+	changeSets := []core.StorageChangeSet{
+		{
+			Block: hash1,
+			Changes: core.QueryKeyValueChanges{
+				key1: common.MustHexToBytes(value),
+				key2: nil,
+			},
+		},
+		{
+			Block: hash2,
+			Changes: core.QueryKeyValueChanges{
+				key1: nil,
+			},
+		},
+	}
 
 	mockCoreAPI := new(mocks.CoreAPI)
-	mockCoreAPI.On("QueryStorage", hash1, hash2, "jimbo").Return(m, nil)
+	mockCoreAPI.On("QueryStorage", hash1, hash2, DefaultMaxQueryStorageRange, key1, key2).Return(changeSets, nil)
 
 	mockCoreAPIErr := new(mocks.CoreAPI)
-	mockCoreAPIErr.On("QueryStorage", hash1, hash2, "jimbo").Return(nil, errors.New("QueryStorage Error"))
+	mockCoreAPIErr.On("QueryStorage", hash1, hash2, uint32(10), key1, key2).
+		Return(nil, errors.New("QueryStorage Error"))
 
-	type fields struct {
-		networkAPI NetworkAPI
-		storageAPI StorageAPI
-		coreAPI    CoreAPI
-	}
-	type args struct {
-		in0 *http.Request
-		req *StateStorageQueryRangeRequest
-	}
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		expErr error
-		exp    []StorageChangeSetResponse
+		name     string
+		coreAPI  CoreAPI
+		maxRange uint32
+		req      *StateStorageQueryRangeRequest
+		expErr   error
+		exp      []StorageChangeSetResponse
 	}{
 		{
-			name:   "OK Case",
-			fields: fields{nil, nil, mockCoreAPI},
-			args: args{
-				req: &StateStorageQueryRangeRequest{
-					Keys:       []string{"jimbo"},
-					StartBlock: hash1,
-					EndBlock:   hash2,
-				},
+			name:    "OK Case",
+			coreAPI: mockCoreAPI,
+			req: &StateStorageQueryRangeRequest{
+				Keys:       []string{key1, key2},
+				StartBlock: hash1,
+				EndBlock:   hash2,
+			},
+			exp: []StorageChangeSetResponse{
+				{Block: &hash1, Changes: [][]*string{{&key1, &value}, {&key2, nil}}},
+				{Block: &hash2, Changes: [][]*string{{&key1, nil}}},
 			},
-			exp: []StorageChangeSetResponse{{Block: &hash1, Changes: [][]string{{"p2", "jimbo"}}}},
 		},
 		{
-			name:   "QueryStorage Error",
-			fields: fields{nil, nil, mockCoreAPIErr},
-			args: args{
-				req: &StateStorageQueryRangeRequest{
-					Keys:       []string{"jimbo"},
-					StartBlock: hash1,
-					EndBlock:   hash2,
-				},
+			name:     "QueryStorage Error",
+			coreAPI:  mockCoreAPIErr,
+			maxRange: 10,
+			req: &StateStorageQueryRangeRequest{
+				Keys:       []string{key1, key2},
+				StartBlock: hash1,
+				EndBlock:   hash2,
 			},
 			exp:    []StorageChangeSetResponse{},
 			expErr: errors.New("QueryStorage Error"),
 		},
 		{
-			name:   "Empty Start Block Error",
-			fields: fields{nil, nil, mockCoreAPI},
-			args: args{
-				req: &StateStorageQueryRangeRequest{
-					Keys:     []string{"jimbo"},
-					EndBlock: hash2,
-				},
+			name:    "Empty Start Block Error",
+			coreAPI: mockCoreAPI,
+			req: &StateStorageQueryRangeRequest{
+				Keys:     []string{key1},
+				EndBlock: hash2,
 			},
 			exp:    []StorageChangeSetResponse{},
 			expErr: errors.New("the start block hash cannot be an empty value"),
@@ -871,13 +949,9 @@ type args struct {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sm := &StateModule{
-				networkAPI: tt.fields.networkAPI,
-				storageAPI: tt.fields.storageAPI,
-				coreAPI:    tt.fields.coreAPI,
-			}
+			sm := NewStateModule(nil, nil, tt.coreAPI, tt.maxRange)
 			res := []StorageChangeSetResponse{}
-			err := sm.QueryStorage(tt.args.in0, tt.args.req, &res)
+			err := sm.QueryStorage(nil, tt.req, &res)
 			if tt.expErr != nil {
 				assert.EqualError(t, err, tt.expErr.Error())
 			} else {
@@ -887,3 +961,52 @@ type args struct {
 		})
 	}
 }
+
+func TestStateModuleQueryStorageAt(t *testing.T) {
+	value := "0x0102"
+	key1, key2 := "0x10", "0x20"
+	hash := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
+
+	changeSet := &core.StorageChangeSet{
+		Block: hash,
+		Changes: core.QueryKeyValueChanges{
+			key1: common.MustHexToBytes(value),
+			key2: nil,
+		},
+	}
+
+	mockCoreAPI := new(mocks.CoreAPI)
+	mockCoreAPI.On("QueryStorageAt", &hash, key2, key1).Return(changeSet, nil)
+	mockCoreAPI.On("QueryStorageAt", (*common.Hash)(nil), key1).Return(nil, errors.New("QueryStorageAt Error"))
+
+	sm := NewStateModule(nil, nil, mockCoreAPI, 0)
+
+	var res []StorageChangeSetResponse
+	err := sm.QueryStorageAt(nil, &StateStorageQueryAtRequest{Keys: []string{key2, key1}, At: &hash}, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, []StorageChangeSetResponse{
+		{Block: &hash, Changes: [][]*string{{&key2, nil}, {&key1, &value}}},
+	}, res)
+
+	err = sm.QueryStorageAt(nil, &StateStorageQueryAtRequest{Keys: []string{key1}}, &res)
+	assert.EqualError(t, err, "QueryStorageAt Error")
+}
+
+func TestStateModuleSubscribeStorage(t *testing.T) {
+	value := "0x0102"
+	key := "0x10"
+	hash := common.MustHexToHash("0x3aa96b0149b6ca3688878bdbd19464448624136398e3ce45b9e755d3ab61355a")
+
+	mockCoreAPI := new(mocks.CoreAPI)
+	mockCoreAPI.On("QueryStorageAt", (*common.Hash)(nil), key).Return(&core.StorageChangeSet{
+		Block:   hash,
+		Changes: core.QueryKeyValueChanges{key: common.MustHexToBytes(value)},
+	}, nil)
+
+	sm := NewStateModule(nil, nil, mockCoreAPI, 0)
+
+	var res StorageChangeSetResponse
+	err := sm.SubscribeStorage(nil, &StateStorageQueryRangeRequest{Keys: []string{key}}, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, StorageChangeSetResponse{Block: &hash, Changes: [][]*string{{&key, &value}}}, res)
+}
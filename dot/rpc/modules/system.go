@@ -191,6 +191,10 @@ func (sm *SystemModule) AccountNextIndex(r *http.Request, req *StringRequest, re
 		return nil
 	}
 
+	if sm.coreAPI == nil {
+		return ErrCoreAPINotAvailable
+	}
+
 	// no extrinsic signed by request found in pending transactions, so look in storage
 	// get metadata to build storage storageKey
 	rawMeta, err := sm.coreAPI.GetMetadata(nil)
@@ -272,6 +276,12 @@ func (sm *SystemModule) LocalPeerId(r *http.Request, req *EmptyRequest, res *str
 	return nil
 }
 
+// ReservedPeers returns the peer ids of the reserved peers.
+func (sm *SystemModule) ReservedPeers(r *http.Request, req *EmptyRequest, res *[]string) error {
+	*res = sm.networkAPI.ReservedPeers()
+	return nil
+}
+
 // AddReservedPeer adds a reserved peer. The string parameter should encode a p2p multiaddr.
 func (sm *SystemModule) AddReservedPeer(r *http.Request, req *StringRequest, res *[]byte) error {
 	if strings.TrimSpace(req.String) == "" {
@@ -129,6 +129,17 @@ func TestSystemModule_PeersTest(t *testing.T) {
 	require.Equal(t, SystemPeersResponse{}, sysPeerRes)
 }
 
+func TestSystemModule_ReservedPeers(t *testing.T) {
+	mockNetworkAPI := new(mocks.NetworkAPI)
+	mockNetworkAPI.On("ReservedPeers").Return([]string{"jimbo", "jimbo2"})
+	sm := NewSystemModule(mockNetworkAPI, nil, nil, nil, nil, nil, nil)
+
+	var res []string
+	err := sm.ReservedPeers(nil, &EmptyRequest{}, &res)
+	require.NoError(t, err)
+	require.Equal(t, []string{"jimbo", "jimbo2"}, res)
+}
+
 func TestSystemModule_NodeRolesTest(t *testing.T) {
 	mockNetworkAPI1 := new(mocks.NetworkAPI)
 	mockNetworkAPI1.On("NodeRoles").Return(byte(1), nil)
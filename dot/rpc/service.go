@@ -7,6 +7,7 @@
 	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -33,6 +34,8 @@ func (s *Service) Methods() []string {
 	// Precompute the reflect.Type of error and http.Request
 	typeOfError   = reflect.TypeOf((*error)(nil)).Elem()
 	typeOfRequest = reflect.TypeOf((*http.Request)(nil)).Elem()
+
+	versionedMethodRegexp = regexp.MustCompile(`^V[0-9]+[A-Z]`)
 )
 
 // BuildMethodNames takes receiver interface and populates rpcMethods array with available
@@ -75,11 +78,21 @@ func (s *Service) BuildMethodNames(rcvr interface{}, name string) {
 			continue
 		}
 
-		s.rpcMethods = append(s.rpcMethods,
-			fmt.Sprintf("%s_%s%s", name, strings.ToLower(string(method.Name[0])), method.Name[1:]))
+		s.rpcMethods = append(s.rpcMethods, fmt.Sprintf("%s_%s", name, methodName(method.Name)))
 	}
 }
 
+// methodName returns the RPC name of the method, the versioned methods like V1ChainName are
+// named v1_chainName
+func methodName(name string) string {
+	if loc := versionedMethodRegexp.FindStringIndex(name); loc != nil {
+		version, rest := name[:loc[1]-1], name[loc[1]-1:]
+		return strings.ToLower(version) + "_" + strings.ToLower(rest[:1]) + rest[1:]
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
 // isExported returns true of a string is an exported (upper case) name.
 func isExported(name string) bool {
 	r, _ := utf8.DecodeRuneInString(name)
@@ -23,7 +23,7 @@ func TestNewService(t *testing.T) {
 }
 
 func TestService_Methods(t *testing.T) {
-	qtySystemMethods := 15
+	qtySystemMethods := 16
 	qtyRPCMethods := 1
 	qtyAuthorMethods := 8
 
@@ -42,6 +42,11 @@ func TestService_Methods(t *testing.T) {
 	rpcService.BuildMethodNames(authMod, "author")
 	m = rpcService.Methods()
 	require.Equal(t, qtySystemMethods+qtyRPCMethods+qtyAuthorMethods, len(m))
+
+	chainSpecMod := modules.NewChainSpecModule(nil, nil)
+	rpcService.BuildMethodNames(chainSpecMod, "chainSpec")
+	m = rpcService.Methods()
+	require.Subset(t, m, []string{"chainSpec_v1_chainName", "chainSpec_v1_genesisHash", "chainSpec_v1_properties"})
 }
 
 type mockService struct{}
@@ -0,0 +1,1075 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+const (
+	chainHeadFollowEventMethod = "chainHead_v1_followEvent"
+
+	// maxPinnedBlocks is the maximum number of blocks pinned by a follow subscription, the
+	// subscription is stopped when the client doesn't unpin the blocks fast enough
+	maxPinnedBlocks = 512
+
+	operationStarted      = "started"
+	operationLimitReached = "limitReached"
+
+	storageValue             = "value"
+	storageHash              = "hash"
+	storageDescendantsValues = "descendantsValues"
+	storageDescendantsHashes = "descendantsHashes"
+)
+
+var (
+	errInvalidParams  = errors.New("invalid params")
+	errBlockNotPinned = errors.New("block is not pinned")
+	errNoRuntime      = errors.New("runtime is not available on this node")
+)
+
+// ChainHeadFollower is the listener of a chainHead_v1_follow subscription. It reports the new
+// and finalised blocks of the chain, and pins the reported blocks until they are unpinned by
+// the client.
+type ChainHeadFollower struct {
+	wsconn      *WSConn
+	subID       uint32
+	withRuntime bool
+
+	importedChan  chan *types.Block
+	finalizedChan chan *types.FinalisationInfo
+
+	mu      sync.Mutex
+	stopped bool
+	// blocks are the reported blocks which are descendants of the finalised block, and the
+	// finalised block itself
+	blocks          map[common.Hash]*followedBlock
+	finalized       common.Hash
+	best            common.Hash
+	pinned          map[common.Hash]struct{}
+	nextOperationID uint64
+
+	done          chan struct{}
+	cancel        chan struct{}
+	cancelTimeout time.Duration
+}
+
+type followedBlock struct {
+	parent common.Hash
+	number *big.Int
+	// runtime is the encoded runtime version of the block, it is only set with runtime updates
+	runtime []byte
+}
+
+type chainHeadParams struct {
+	Result         interface{} `json:"result"`
+	SubscriptionID string      `json:"subscription"`
+}
+
+type chainHeadResponseJSON struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  chainHeadParams `json:"params"`
+}
+
+type chainHeadRuntime struct {
+	Type  string                `json:"type"`
+	Spec  *chainHeadRuntimeSpec `json:"spec,omitempty"`
+	Error string                `json:"error,omitempty"`
+}
+
+type chainHeadRuntimeSpec struct {
+	SpecName           string            `json:"specName"`
+	ImplName           string            `json:"implName"`
+	SpecVersion        uint32            `json:"specVersion"`
+	ImplVersion        uint32            `json:"implVersion"`
+	TransactionVersion uint32            `json:"transactionVersion"`
+	APIs               map[string]uint32 `json:"apis"`
+}
+
+type chainHeadInitializedEvent struct {
+	Event                 string            `json:"event"`
+	FinalizedBlockHashes  []string          `json:"finalizedBlockHashes"`
+	FinalizedBlockRuntime *chainHeadRuntime `json:"finalizedBlockRuntime,omitempty"`
+}
+
+type chainHeadNewBlockEvent struct {
+	Event           string            `json:"event"`
+	BlockHash       string            `json:"blockHash"`
+	ParentBlockHash string            `json:"parentBlockHash"`
+	NewRuntime      *chainHeadRuntime `json:"newRuntime"`
+}
+
+type chainHeadBestBlockChangedEvent struct {
+	Event         string `json:"event"`
+	BestBlockHash string `json:"bestBlockHash"`
+}
+
+type chainHeadFinalizedEvent struct {
+	Event                string   `json:"event"`
+	FinalizedBlockHashes []string `json:"finalizedBlockHashes"`
+	PrunedBlockHashes    []string `json:"prunedBlockHashes"`
+}
+
+type chainHeadStopEvent struct {
+	Event string `json:"event"`
+}
+
+type chainHeadOperationEvent struct {
+	Event       string `json:"event"`
+	OperationID string `json:"operationId"`
+}
+
+type chainHeadOperationErrorEvent struct {
+	Event       string `json:"event"`
+	OperationID string `json:"operationId"`
+	Error       string `json:"error"`
+}
+
+type chainHeadBodyDoneEvent struct {
+	Event       string   `json:"event"`
+	OperationID string   `json:"operationId"`
+	Value       []string `json:"value"`
+}
+
+type chainHeadCallDoneEvent struct {
+	Event       string `json:"event"`
+	OperationID string `json:"operationId"`
+	Output      string `json:"output"`
+}
+
+type chainHeadStorageItemsEvent struct {
+	Event       string                 `json:"event"`
+	OperationID string                 `json:"operationId"`
+	Items       []chainHeadStorageItem `json:"items"`
+}
+
+type chainHeadStorageItem struct {
+	Key          string `json:"key"`
+	Value        string `json:"value,omitempty"`
+	Hash         string `json:"hash,omitempty"`
+	ChildTrieKey string `json:"childTrieKey,omitempty"`
+}
+
+type chainHeadStorageQuery struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+// chainHeadOperation is the result of the chainHead methods starting an operation
+type chainHeadOperation struct {
+	Result         string `json:"result"`
+	OperationID    string `json:"operationId,omitempty"`
+	DiscardedItems *int   `json:"discardedItems,omitempty"`
+}
+
+func newChainHeadFollower(conn *WSConn, withRuntime bool) *ChainHeadFollower {
+	return &ChainHeadFollower{
+		wsconn:        conn,
+		withRuntime:   withRuntime,
+		blocks:        make(map[common.Hash]*followedBlock),
+		pinned:        make(map[common.Hash]struct{}),
+		cancel:        make(chan struct{}, 1),
+		done:          make(chan struct{}, 1),
+		cancelTimeout: defaultCancelTimeout,
+	}
+}
+
+// Listen reports the finalised block and its descendants, then the imported and finalised blocks
+func (l *ChainHeadFollower) Listen() {
+	go func() {
+		defer func() {
+			l.wsconn.BlockAPI.FreeImportedBlockNotifierChannel(l.importedChan)
+			l.wsconn.BlockAPI.FreeFinalisedNotifierChannel(l.finalizedChan)
+			l.unpinAll()
+
+			close(l.done)
+		}()
+
+		l.initialize()
+
+		for {
+			select {
+			case <-l.cancel:
+				return
+			case block, ok := <-l.importedChan:
+				if !ok {
+					return
+				}
+
+				if block == nil {
+					continue
+				}
+
+				l.handleImported(block)
+			case info, ok := <-l.finalizedChan:
+				if !ok {
+					return
+				}
+
+				if info == nil {
+					continue
+				}
+
+				l.handleFinalized(info)
+			}
+		}
+	}()
+}
+
+// Stop stops the subscription and unpins its blocks
+func (l *ChainHeadFollower) Stop() error {
+	l.mu.Lock()
+	if l.stopped {
+		l.mu.Unlock()
+		return nil
+	}
+	l.stopped = true
+	l.mu.Unlock()
+
+	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
+}
+
+func (l *ChainHeadFollower) initialize() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return
+	}
+
+	hash, err := l.wsconn.BlockAPI.GetHighestFinalisedHash()
+	if err != nil {
+		logger.Warnf("failed to get highest finalised hash: %s", err)
+		l.stop()
+		return
+	}
+
+	header, err := l.wsconn.BlockAPI.GetHeader(hash)
+	if err != nil {
+		logger.Warnf("failed to get finalised header %s: %s", hash, err)
+		l.stop()
+		return
+	}
+
+	if !l.pin(hash) {
+		return
+	}
+
+	block := &followedBlock{
+		parent: header.ParentHash,
+		number: header.Number,
+	}
+	event := chainHeadInitializedEvent{
+		Event:                "initialized",
+		FinalizedBlockHashes: []string{hash.String()},
+	}
+	if l.withRuntime {
+		block.runtime, event.FinalizedBlockRuntime = l.runtime(hash)
+	}
+
+	l.blocks[hash] = block
+	l.finalized = hash
+	l.best = hash
+	l.send(event)
+
+	for _, hash := range l.wsconn.BlockAPI.GetNonFinalisedBlocks() {
+		header, err := l.wsconn.BlockAPI.GetHeader(hash)
+		if err != nil {
+			logger.Debugf("failed to get header of block %s: %s", hash, err)
+			continue
+		}
+
+		l.announce(header)
+		if l.stopped {
+			return
+		}
+	}
+
+	l.updateBest(true)
+}
+
+func (l *ChainHeadFollower) handleImported(block *types.Block) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return
+	}
+
+	l.announce(&block.Header)
+	l.updateBest(false)
+}
+
+func (l *ChainHeadFollower) handleFinalized(info *types.FinalisationInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return
+	}
+
+	hash := info.Header.Hash()
+	if hash == l.finalized || !l.announce(&info.Header) {
+		return
+	}
+
+	// the best block must be a descendant of the finalised block
+	if !l.isDescendant(l.best, hash) {
+		l.updateBest(false)
+	}
+	if !l.isDescendant(l.best, hash) {
+		l.best = hash
+		l.send(chainHeadBestBlockChangedEvent{
+			Event:         "bestBlockChanged",
+			BestBlockHash: hash.String(),
+		})
+	}
+
+	// the newly finalised blocks, from the oldest to the newest
+	var finalized []common.Hash
+	for h := hash; h != l.finalized; h = l.blocks[h].parent {
+		finalized = append(finalized, h)
+	}
+	for i, j := 0, len(finalized)-1; i < j; i, j = i+1, j-1 {
+		finalized[i], finalized[j] = finalized[j], finalized[i]
+	}
+
+	finalizedHashes := make([]string, len(finalized))
+	for i, h := range finalized {
+		finalizedHashes[i] = h.String()
+		if h != hash {
+			delete(l.blocks, h)
+		}
+	}
+
+	prunedHashes := []string{}
+	for h := range l.blocks {
+		if h == l.finalized || l.isDescendant(h, hash) {
+			continue
+		}
+
+		prunedHashes = append(prunedHashes, h.String())
+		delete(l.blocks, h)
+	}
+	sort.Strings(prunedHashes)
+
+	delete(l.blocks, l.finalized)
+	l.finalized = hash
+
+	l.send(chainHeadFinalizedEvent{
+		Event:                "finalized",
+		FinalizedBlockHashes: finalizedHashes,
+		PrunedBlockHashes:    prunedHashes,
+	})
+}
+
+// announce reports the block and its ancestors which aren't reported yet. It returns false if the
+// block isn't a descendant of the finalised block, or if the subscription is stopped.
+func (l *ChainHeadFollower) announce(header *types.Header) bool {
+	finalizedNumber := l.blocks[l.finalized].number
+
+	// the blocks to report, from the newest to the oldest
+	var headers []*types.Header
+	for {
+		if _, has := l.blocks[header.Hash()]; has {
+			break
+		}
+
+		if header.Number.Cmp(finalizedNumber) <= 0 {
+			return false
+		}
+
+		headers = append(headers, header)
+		if _, has := l.blocks[header.ParentHash]; has {
+			break
+		}
+
+		parent, err := l.wsconn.BlockAPI.GetHeader(header.ParentHash)
+		if err != nil {
+			logger.Debugf("failed to get header of block %s: %s", header.ParentHash, err)
+			return false
+		}
+		header = parent
+	}
+
+	for i := len(headers) - 1; i >= 0; i-- {
+		if !l.newBlock(headers[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *ChainHeadFollower) newBlock(header *types.Header) bool {
+	hash := header.Hash()
+	if !l.pin(hash) {
+		return false
+	}
+
+	block := &followedBlock{
+		parent: header.ParentHash,
+		number: header.Number,
+	}
+	event := chainHeadNewBlockEvent{
+		Event:           "newBlock",
+		BlockHash:       hash.String(),
+		ParentBlockHash: header.ParentHash.String(),
+	}
+	if l.withRuntime {
+		block.runtime, event.NewRuntime = l.runtime(hash)
+		if bytes.Equal(block.runtime, l.blocks[header.ParentHash].runtime) {
+			event.NewRuntime = nil
+		}
+	}
+
+	l.blocks[hash] = block
+	l.send(event)
+	return true
+}
+
+// updateBest reports the best block if it changed, or if force is true
+func (l *ChainHeadFollower) updateBest(force bool) {
+	changed := false
+
+	best := l.wsconn.BlockAPI.BestBlockHash()
+	if best != l.best {
+		header, err := l.wsconn.BlockAPI.GetHeader(best)
+		if err != nil {
+			logger.Debugf("failed to get header of best block %s: %s", best, err)
+		} else if l.announce(header) {
+			l.best = best
+			changed = true
+		}
+	}
+
+	if l.stopped || !changed && !force {
+		return
+	}
+
+	l.send(chainHeadBestBlockChangedEvent{
+		Event:         "bestBlockChanged",
+		BestBlockHash: l.best.String(),
+	})
+}
+
+// isDescendant returns true if the reported block is the ancestor or a descendant of it
+func (l *ChainHeadFollower) isDescendant(hash, ancestor common.Hash) bool {
+	for hash != ancestor {
+		block, has := l.blocks[hash]
+		if !has || hash == l.finalized {
+			return false
+		}
+		hash = block.parent
+	}
+
+	return true
+}
+
+func (l *ChainHeadFollower) pin(hash common.Hash) bool {
+	if len(l.pinned) >= maxPinnedBlocks {
+		logger.Debugf("follow subscription %d reached the limit of pinned blocks", l.subID)
+		l.stop()
+		return false
+	}
+
+	err := l.wsconn.BlockAPI.PinBlock(hash)
+	if err != nil {
+		logger.Debugf("failed to pin block %s: %s", hash, err)
+		l.stop()
+		return false
+	}
+
+	l.pinned[hash] = struct{}{}
+	return true
+}
+
+func (l *ChainHeadFollower) unpin(hashes []common.Hash) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return nil
+	}
+
+	unique := make(map[common.Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		_, pinned := l.pinned[hash]
+		_, duplicate := unique[hash]
+		if !pinned || duplicate {
+			return fmt.Errorf("cannot unpin block %s: %w", hash, errBlockNotPinned)
+		}
+		unique[hash] = struct{}{}
+	}
+
+	for hash := range unique {
+		delete(l.pinned, hash)
+
+		err := l.wsconn.BlockAPI.UnpinBlock(hash)
+		if err != nil {
+			logger.Warnf("failed to unpin block %s: %s", hash, err)
+		}
+	}
+
+	return nil
+}
+
+func (l *ChainHeadFollower) unpinAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for hash := range l.pinned {
+		err := l.wsconn.BlockAPI.UnpinBlock(hash)
+		if err != nil {
+			logger.Warnf("failed to unpin block %s: %s", hash, err)
+		}
+	}
+
+	l.pinned = make(map[common.Hash]struct{})
+}
+
+func (l *ChainHeadFollower) isPinned(hash common.Hash) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, has := l.pinned[hash]
+	return has && !l.stopped
+}
+
+// startOperation returns the ID of a new operation on the pinned block, it returns false if the
+// subscription is stopped
+func (l *ChainHeadFollower) startOperation(hash common.Hash) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return "", false, nil
+	}
+
+	if _, has := l.pinned[hash]; !has {
+		return "", true, fmt.Errorf("cannot start operation on block %s: %w", hash, errBlockNotPinned)
+	}
+
+	l.nextOperationID++
+	return strconv.FormatUint(l.nextOperationID, 10), true, nil
+}
+
+// stop stops the subscription from the listening goroutine, it must be called with the lock held
+func (l *ChainHeadFollower) stop() {
+	if l.stopped {
+		return
+	}
+
+	l.stopped = true
+	l.send(chainHeadStopEvent{Event: "stop"})
+	close(l.cancel)
+
+	l.wsconn.mu.Lock()
+	delete(l.wsconn.Subscriptions, l.subID)
+	l.wsconn.mu.Unlock()
+}
+
+// send sends the event to the client, it must be called with the lock held
+func (l *ChainHeadFollower) send(event interface{}) {
+	l.wsconn.safeSend(chainHeadResponseJSON{
+		Jsonrpc: "2.0",
+		Method:  chainHeadFollowEventMethod,
+		Params: chainHeadParams{
+			Result:         event,
+			SubscriptionID: strconv.FormatUint(uint64(l.subID), 10),
+		},
+	})
+}
+
+// sendOperationEvents sends the events of an operation, unless the subscription is stopped
+func (l *ChainHeadFollower) sendOperationEvents(events ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return
+	}
+
+	for _, event := range events {
+		l.send(event)
+	}
+}
+
+// runtime returns the encoded runtime version of the block and its description
+func (l *ChainHeadFollower) runtime(hash common.Hash) ([]byte, *chainHeadRuntime) {
+	if l.wsconn.CoreAPI == nil {
+		return nil, &chainHeadRuntime{Type: "invalid", Error: errNoRuntime.Error()}
+	}
+
+	version, err := l.wsconn.CoreAPI.GetRuntimeVersion(&hash)
+	if err != nil {
+		return nil, &chainHeadRuntime{Type: "invalid", Error: err.Error()}
+	}
+
+	enc, err := version.Encode()
+	if err != nil {
+		return nil, &chainHeadRuntime{Type: "invalid", Error: err.Error()}
+	}
+
+	apis := make(map[string]uint32, len(version.APIItems()))
+	for _, api := range version.APIItems() {
+		apis[common.BytesToHex(api.Name[:])] = api.Ver
+	}
+
+	return enc, &chainHeadRuntime{
+		Type: "valid",
+		Spec: &chainHeadRuntimeSpec{
+			SpecName:           string(version.SpecName()),
+			ImplName:           string(version.ImplName()),
+			SpecVersion:        version.SpecVersion(),
+			ImplVersion:        version.ImplVersion(),
+			TransactionVersion: version.TransactionVersion(),
+			APIs:               apis,
+		},
+	}
+}
+
+// isChainHeadCall returns true for the chainHead methods called with a follow subscription
+func isChainHeadCall(method string) bool {
+	switch method {
+	case chainHeadV1Unfollow, chainHeadV1Header, chainHeadV1Body, chainHeadV1Call, chainHeadV1Storage,
+		chainHeadV1Unpin, chainHeadV1Continue, chainHeadV1StopOperation:
+		return true
+	default:
+		return false
+	}
+}
+
+// handleChainHeadCall handles the calls of the chainHead methods, their first parameter is the ID
+// of the follow subscription
+func (c *WSConn) handleChainHeadCall(reqID float64, method string, params interface{}) {
+	args, ok := params.([]interface{})
+	if !ok || len(args) == 0 {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return
+	}
+
+	subID, ok := args[0].(string)
+	if !ok {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return
+	}
+
+	follower := c.getChainHeadFollower(subID)
+
+	switch method {
+	case chainHeadV1Header:
+		c.chainHeadHeader(reqID, follower, params)
+	case chainHeadV1Body:
+		c.chainHeadBody(reqID, follower, params)
+	case chainHeadV1Call:
+		c.chainHeadCall(reqID, follower, params)
+	case chainHeadV1Storage:
+		c.chainHeadStorage(reqID, follower, params)
+	case chainHeadV1Unpin:
+		c.chainHeadUnpin(reqID, follower, params)
+	case chainHeadV1Unfollow:
+		if follower != nil {
+			c.mu.Lock()
+			delete(c.Subscriptions, follower.subID)
+			c.mu.Unlock()
+
+			err := follower.Stop()
+			if err != nil {
+				logger.Warnf("failed to stop follow subscription %s: %s", subID, err)
+			}
+		}
+		c.safeSend(newResultResponseJSON(nil, reqID))
+	default:
+		// the operations are done at once, there is nothing to continue or to stop
+		c.safeSend(newResultResponseJSON(nil, reqID))
+	}
+}
+
+func (c *WSConn) chainHeadHeader(reqID float64, follower *ChainHeadFollower, params interface{}) {
+	var (
+		subID string
+		hash  common.Hash
+	)
+	err := decodeParams(params, 2, &subID, &hash)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return
+	}
+
+	if follower == nil {
+		c.safeSend(newResultResponseJSON(nil, reqID))
+		return
+	}
+
+	if !follower.isPinned(hash) {
+		c.safeSendError(reqID, big.NewInt(InvalidBlockHashCode), InvalidBlockHashMessage)
+		return
+	}
+
+	header, err := c.BlockAPI.GetHeader(hash)
+	if err != nil {
+		c.safeSendError(reqID, nil, err.Error())
+		return
+	}
+
+	enc, err := scale.Marshal(*header)
+	if err != nil {
+		c.safeSendError(reqID, nil, err.Error())
+		return
+	}
+
+	c.safeSend(newResultResponseJSON(common.BytesToHex(enc), reqID))
+}
+
+func (c *WSConn) chainHeadBody(reqID float64, follower *ChainHeadFollower, params interface{}) {
+	var (
+		subID string
+		hash  common.Hash
+	)
+	err := decodeParams(params, 2, &subID, &hash)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return
+	}
+
+	c.startChainHeadOperation(reqID, follower, hash, false, func(operationID string) []interface{} {
+		block, err := c.BlockAPI.GetBlockByHash(hash)
+		if err != nil {
+			return operationError(operationID, err)
+		}
+
+		value := make([]string, len(block.Body))
+		for i, ext := range block.Body {
+			value[i] = common.BytesToHex(ext)
+		}
+
+		return []interface{}{chainHeadBodyDoneEvent{
+			Event:       "operationBodyDone",
+			OperationID: operationID,
+			Value:       value,
+		}}
+	})
+}
+
+func (c *WSConn) chainHeadCall(reqID float64, follower *ChainHeadFollower, params interface{}) {
+	var (
+		subID, function, callParameters string
+		hash                            common.Hash
+	)
+	err := decodeParams(params, 4, &subID, &hash, &function, &callParameters)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return
+	}
+
+	data, err := common.HexToBytes(callParameters)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return
+	}
+
+	c.startChainHeadOperation(reqID, follower, hash, false, func(operationID string) []interface{} {
+		if c.CoreAPI == nil {
+			return operationError(operationID, errNoRuntime)
+		}
+
+		output, err := c.CoreAPI.CallAt(&hash, function, data)
+		if err != nil {
+			return operationError(operationID, err)
+		}
+
+		return []interface{}{chainHeadCallDoneEvent{
+			Event:       "operationCallDone",
+			OperationID: operationID,
+			Output:      common.BytesToHex(output),
+		}}
+	})
+}
+
+func (c *WSConn) chainHeadStorage(reqID float64, follower *ChainHeadFollower, params interface{}) {
+	var (
+		subID     string
+		hash      common.Hash
+		queries   []chainHeadStorageQuery
+		childTrie *string
+	)
+	err := decodeParams(params, 3, &subID, &hash, &queries, &childTrie)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return
+	}
+
+	keys := make([][]byte, len(queries))
+	for i, query := range queries {
+		switch query.Type {
+		case storageValue, storageHash, storageDescendantsValues, storageDescendantsHashes:
+		default:
+			c.safeSendError(reqID, big.NewInt(InvalidParamsCode),
+				fmt.Sprintf("%s: unsupported storage query type %q", InvalidParamsMessage, query.Type))
+			return
+		}
+
+		keys[i], err = common.HexToBytes(query.Key)
+		if err != nil {
+			c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+			return
+		}
+	}
+
+	var childKey []byte
+	if childTrie != nil {
+		childKey, err = common.HexToBytes(*childTrie)
+		if err != nil {
+			c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+			return
+		}
+	}
+
+	c.startChainHeadOperation(reqID, follower, hash, true, func(operationID string) []interface{} {
+		items, err := c.queryStorage(hash, queries, keys, childKey)
+		if err != nil {
+			return operationError(operationID, err)
+		}
+
+		var events []interface{}
+		if len(items) != 0 {
+			events = append(events, chainHeadStorageItemsEvent{
+				Event:       "operationStorageItems",
+				OperationID: operationID,
+				Items:       items,
+			})
+		}
+
+		return append(events, chainHeadOperationEvent{
+			Event:       "operationStorageDone",
+			OperationID: operationID,
+		})
+	})
+}
+
+// queryStorage returns the storage items of the block matching the queries
+func (c *WSConn) queryStorage(hash common.Hash, queries []chainHeadStorageQuery, keys [][]byte,
+	childKey []byte) ([]chainHeadStorageItem, error) {
+	root, err := c.StorageAPI.GetStateRootFromBlock(&hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var child *trie.Trie
+	if childKey != nil {
+		child, err = c.StorageAPI.GetStorageChild(root, childKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	get := func(key []byte) ([]byte, error) {
+		if child != nil {
+			return child.Get(key), nil
+		}
+		return c.StorageAPI.GetStorage(root, key)
+	}
+
+	items := []chainHeadStorageItem{}
+	for i, query := range queries {
+		matching := [][]byte{keys[i]}
+		if query.Type == storageDescendantsValues || query.Type == storageDescendantsHashes {
+			if child != nil {
+				matching = child.GetKeysWithPrefix(keys[i])
+			} else {
+				matching, err = c.StorageAPI.GetKeysWithPrefix(root, keys[i])
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for _, key := range matching {
+			value, err := get(key)
+			if err != nil {
+				return nil, err
+			}
+
+			if value == nil {
+				continue
+			}
+
+			item := chainHeadStorageItem{
+				Key: common.BytesToHex(key),
+			}
+			if childKey != nil {
+				item.ChildTrieKey = common.BytesToHex(childKey)
+			}
+
+			if query.Type == storageHash || query.Type == storageDescendantsHashes {
+				valueHash, err := common.Blake2bHash(value)
+				if err != nil {
+					return nil, err
+				}
+				item.Hash = valueHash.String()
+			} else {
+				item.Value = common.BytesToHex(value)
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// startChainHeadOperation responds with the ID of the operation, then runs the operation and sends
+// its events
+func (c *WSConn) startChainHeadOperation(reqID float64, follower *ChainHeadFollower, hash common.Hash,
+	storage bool, operation func(operationID string) []interface{}) {
+	if follower == nil {
+		c.safeSend(newResultResponseJSON(chainHeadOperation{Result: operationLimitReached}, reqID))
+		return
+	}
+
+	operationID, ok, err := follower.startOperation(hash)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidBlockHashCode), InvalidBlockHashMessage)
+		return
+	}
+
+	if !ok {
+		c.safeSend(newResultResponseJSON(chainHeadOperation{Result: operationLimitReached}, reqID))
+		return
+	}
+
+	res := chainHeadOperation{
+		Result:      operationStarted,
+		OperationID: operationID,
+	}
+	if storage {
+		// every storage query is processed by the operation
+		discardedItems := 0
+		res.DiscardedItems = &discardedItems
+	}
+	c.safeSend(newResultResponseJSON(res, reqID))
+
+	go func() {
+		follower.sendOperationEvents(operation(operationID)...)
+	}()
+}
+
+func (c *WSConn) chainHeadUnpin(reqID float64, follower *ChainHeadFollower, params interface{}) {
+	var (
+		subID  string
+		hashes json.RawMessage
+	)
+	err := decodeParams(params, 2, &subID, &hashes)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return
+	}
+
+	// the blocks are unpinned by hash, or by list of hashes
+	var list []common.Hash
+	if err = json.Unmarshal(hashes, &list); err != nil {
+		var hash common.Hash
+		if err = json.Unmarshal(hashes, &hash); err != nil {
+			c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+			return
+		}
+		list = []common.Hash{hash}
+	}
+
+	if follower != nil {
+		err = follower.unpin(list)
+		if err != nil {
+			c.safeSendError(reqID, big.NewInt(InvalidBlockHashCode), InvalidBlockHashMessage)
+			return
+		}
+	}
+
+	c.safeSend(newResultResponseJSON(nil, reqID))
+}
+
+// getChainHeadFollower returns the follow subscription with the given ID, or nil if there is none
+func (c *WSConn) getChainHeadFollower(subID string) *ChainHeadFollower {
+	id, err := strconv.ParseUint(subID, 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	follower, _ := c.Subscriptions[uint32(id)].(*ChainHeadFollower)
+	return follower
+}
+
+// stopChainHeadFollowers stops the follow subscriptions of the connection, so their blocks are unpinned
+func (c *WSConn) stopChainHeadFollowers() {
+	var followers []*ChainHeadFollower
+
+	c.mu.Lock()
+	for id, listener := range c.Subscriptions {
+		if follower, ok := listener.(*ChainHeadFollower); ok {
+			followers = append(followers, follower)
+			delete(c.Subscriptions, id)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, follower := range followers {
+		err := follower.Stop()
+		if err != nil {
+			logger.Warnf("failed to stop follow subscription %d: %s", follower.subID, err)
+		}
+	}
+}
+
+func operationError(operationID string, err error) []interface{} {
+	return []interface{}{chainHeadOperationErrorEvent{
+		Event:       "operationError",
+		OperationID: operationID,
+		Error:       err.Error(),
+	}}
+}
+
+// decodeParams decodes the positional parameters into dst, the parameters after the required
+// ones are optional
+func decodeParams(params interface{}, required int, dst ...interface{}) error {
+	enc, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	var args []json.RawMessage
+	err = json.Unmarshal(enc, &args)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidParams, err)
+	}
+
+	if len(args) < required || len(args) > len(dst) {
+		return fmt.Errorf("%w: expected %d parameters, got %d", errInvalidParams, required, len(args))
+	}
+
+	for i := range args {
+		err = json.Unmarshal(args[i], dst[i])
+		if err != nil {
+			return fmt.Errorf("%w: %s", errInvalidParams, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainHeadFollower(t *testing.T) {
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	defer cancel()
+
+	genesis := &types.Header{Number: big.NewInt(0), Digest: types.NewDigest()}
+	a1 := &types.Header{ParentHash: genesis.Hash(), Number: big.NewInt(1), StateRoot: common.Hash{1},
+		Digest: types.NewDigest()}
+	b1 := &types.Header{ParentHash: genesis.Hash(), Number: big.NewInt(1), StateRoot: common.Hash{2},
+		Digest: types.NewDigest()}
+	a2 := &types.Header{ParentHash: a1.Hash(), Number: big.NewInt(2), Digest: types.NewDigest()}
+
+	headers := map[common.Hash]*types.Header{}
+	for _, header := range []*types.Header{genesis, a1, b1, a2} {
+		headers[header.Hash()] = header
+	}
+
+	var best atomic.Value
+	best.Store(a1.Hash())
+
+	iCh := make(chan *types.Block)
+	fCh := make(chan *types.FinalisationInfo)
+
+	mockBlockAPI := new(mocks.BlockAPI)
+	mockBlockAPI.On("GetImportedBlockNotifierChannel").Return(iCh)
+	mockBlockAPI.On("GetFinalisedNotifierChannel").Return(fCh)
+	mockBlockAPI.On("FreeImportedBlockNotifierChannel", mock.AnythingOfType("chan *types.Block"))
+	mockBlockAPI.On("FreeFinalisedNotifierChannel", mock.AnythingOfType("chan *types.FinalisationInfo"))
+	mockBlockAPI.On("GetHighestFinalisedHash").Return(genesis.Hash(), nil)
+	mockBlockAPI.On("GetNonFinalisedBlocks").Return([]common.Hash{genesis.Hash(), a1.Hash(), b1.Hash()})
+	mockBlockAPI.On("BestBlockHash").Return(func() common.Hash { return best.Load().(common.Hash) })
+	mockBlockAPI.On("GetHeader", mock.AnythingOfType("common.Hash")).Return(
+		func(hash common.Hash) *types.Header { return headers[hash] },
+		func(hash common.Hash) error {
+			if headers[hash] == nil {
+				return errors.New("not found")
+			}
+			return nil
+		})
+	mockBlockAPI.On("GetBlockByHash", a2.Hash()).Return(&types.Block{
+		Header: *a2,
+		Body:   types.Body{{1, 2}, {3}},
+	}, nil)
+	mockBlockAPI.On("PinBlock", mock.AnythingOfType("common.Hash")).Return(nil)
+	mockBlockAPI.On("UnpinBlock", mock.AnythingOfType("common.Hash")).Return(nil)
+	wsconn.BlockAPI = mockBlockAPI
+
+	go wsconn.HandleComm()
+	time.Sleep(time.Second * 2)
+
+	send := func(method, params string, id int) {
+		err := c.WriteMessage(websocket.TextMessage,
+			[]byte(fmt.Sprintf(`{"jsonrpc":"2.0","method":"%s","params":%s,"id":%d}`, method, params, id)))
+		require.NoError(t, err)
+	}
+	expect := func(expected string) {
+		_, msg, err := c.ReadMessage()
+		require.NoError(t, err)
+		require.JSONEq(t, expected, string(msg))
+	}
+	expectEvent := func(event string) {
+		expect(`{"jsonrpc":"2.0","method":"chainHead_v1_followEvent",` +
+			`"params":{"subscription":"1","result":` + event + `}}`)
+	}
+
+	send(chainHeadV1Follow, `[false]`, 1)
+	expect(`{"jsonrpc":"2.0","result":"1","id":1}`)
+	expectEvent(fmt.Sprintf(`{"event":"initialized","finalizedBlockHashes":["%s"]}`, genesis.Hash()))
+	expectEvent(fmt.Sprintf(`{"event":"newBlock","blockHash":"%s","parentBlockHash":"%s","newRuntime":null}`,
+		a1.Hash(), genesis.Hash()))
+	expectEvent(fmt.Sprintf(`{"event":"newBlock","blockHash":"%s","parentBlockHash":"%s","newRuntime":null}`,
+		b1.Hash(), genesis.Hash()))
+	expectEvent(fmt.Sprintf(`{"event":"bestBlockChanged","bestBlockHash":"%s"}`, a1.Hash()))
+
+	best.Store(a2.Hash())
+	iCh <- &types.Block{Header: *a2}
+	expectEvent(fmt.Sprintf(`{"event":"newBlock","blockHash":"%s","parentBlockHash":"%s","newRuntime":null}`,
+		a2.Hash(), a1.Hash()))
+	expectEvent(fmt.Sprintf(`{"event":"bestBlockChanged","bestBlockHash":"%s"}`, a2.Hash()))
+
+	fCh <- &types.FinalisationInfo{Header: *a1}
+	expectEvent(fmt.Sprintf(`{"event":"finalized","finalizedBlockHashes":["%s"],"prunedBlockHashes":["%s"]}`,
+		a1.Hash(), b1.Hash()))
+
+	enc, err := scale.Marshal(*a2)
+	require.NoError(t, err)
+	send(chainHeadV1Header, fmt.Sprintf(`["1","%s"]`, a2.Hash()), 2)
+	expect(fmt.Sprintf(`{"jsonrpc":"2.0","result":"%s","id":2}`, common.BytesToHex(enc)))
+
+	send(chainHeadV1Header, fmt.Sprintf(`["1","%s"]`, common.Hash{9}), 3)
+	expect(`{"jsonrpc":"2.0","error":{"code":-32801,"message":"Invalid block hash"},"id":3}`)
+
+	send(chainHeadV1Header, fmt.Sprintf(`["9","%s"]`, a2.Hash()), 4)
+	expect(`{"jsonrpc":"2.0","result":null,"id":4}`)
+
+	send(chainHeadV1Body, fmt.Sprintf(`["1","%s"]`, a2.Hash()), 5)
+	expect(`{"jsonrpc":"2.0","result":{"result":"started","operationId":"1"},"id":5}`)
+	expectEvent(`{"event":"operationBodyDone","operationId":"1","value":["0x0102","0x03"]}`)
+
+	send(chainHeadV1Body, fmt.Sprintf(`["9","%s"]`, a2.Hash()), 6)
+	expect(`{"jsonrpc":"2.0","result":{"result":"limitReached"},"id":6}`)
+
+	send(chainHeadV1Body, `["1"]`, 7)
+	expect(`{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params"},"id":7}`)
+
+	// the pruned blocks stay pinned until they are unpinned
+	send(chainHeadV1Unpin, fmt.Sprintf(`["1","%s"]`, b1.Hash()), 8)
+	expect(`{"jsonrpc":"2.0","result":null,"id":8}`)
+	mockBlockAPI.AssertCalled(t, "UnpinBlock", b1.Hash())
+
+	send(chainHeadV1Unpin, fmt.Sprintf(`["1",["%s","%s"]]`, genesis.Hash(), b1.Hash()), 9)
+	expect(`{"jsonrpc":"2.0","error":{"code":-32801,"message":"Invalid block hash"},"id":9}`)
+	mockBlockAPI.AssertNotCalled(t, "UnpinBlock", genesis.Hash())
+
+	send(chainHeadV1Unfollow, `["1"]`, 10)
+	expect(`{"jsonrpc":"2.0","result":null,"id":10}`)
+	require.Empty(t, wsconn.Subscriptions)
+
+	for _, header := range []*types.Header{genesis, a1, a2} {
+		mockBlockAPI.AssertCalled(t, "UnpinBlock", header.Hash())
+	}
+	mockBlockAPI.AssertNumberOfCalls(t, "UnpinBlock", 4)
+}
+
+func TestChainHeadFollower_PinnedBlocksLimit(t *testing.T) {
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	defer cancel()
+
+	mockBlockAPI := new(mocks.BlockAPI)
+	mockBlockAPI.On("FreeImportedBlockNotifierChannel", mock.AnythingOfType("chan *types.Block"))
+	mockBlockAPI.On("FreeFinalisedNotifierChannel", mock.AnythingOfType("chan *types.FinalisationInfo"))
+	mockBlockAPI.On("PinBlock", mock.AnythingOfType("common.Hash")).Return(nil)
+	mockBlockAPI.On("UnpinBlock", mock.AnythingOfType("common.Hash")).Return(nil)
+	wsconn.BlockAPI = mockBlockAPI
+
+	follower := newChainHeadFollower(wsconn, false)
+	follower.subID = 1
+	wsconn.Subscriptions[follower.subID] = follower
+
+	follower.blocks[common.Hash{}] = &followedBlock{number: big.NewInt(0)}
+	for i := 0; i < maxPinnedBlocks; i++ {
+		follower.pinned[common.Hash{byte(i), byte(i >> 8)}] = struct{}{}
+	}
+
+	follower.mu.Lock()
+	ok := follower.announce(&types.Header{Number: big.NewInt(1), Digest: types.NewDigest()})
+	follower.mu.Unlock()
+	require.False(t, ok)
+
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"jsonrpc":"2.0","method":"chainHead_v1_followEvent",`+
+		`"params":{"subscription":"1","result":{"event":"stop"}}}`, string(msg))
+	require.Empty(t, wsconn.Subscriptions)
+	mockBlockAPI.AssertNotCalled(t, "PinBlock", mock.Anything)
+
+	// the listening goroutine unpins the blocks once it is stopped
+	follower.importedChan = make(chan *types.Block)
+	follower.finalizedChan = make(chan *types.FinalisationInfo)
+	follower.Listen()
+	<-follower.done
+	mockBlockAPI.AssertNumberOfCalls(t, "UnpinBlock", maxPinnedBlocks)
+	require.NoError(t, follower.Stop())
+}
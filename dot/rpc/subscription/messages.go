@@ -22,6 +22,33 @@ type Params struct {
 // InvalidRequestMessage error message for invalid request parameters
 const InvalidRequestMessage = "Invalid request"
 
+// RateLimitedCode error code returned when a client exceeds a rate limit of the RPC server
+const RateLimitedCode = -32005
+
+// RateLimitedMessage error message returned when a client exceeds a rate limit of the RPC server
+const RateLimitedMessage = "Rate limit exceeded"
+
+// ResponseTooBigCode error code returned when a response exceeds the maximum response size, value derived
+// from Substrate node output
+const ResponseTooBigCode = -32008
+
+// ResponseTooBigMessage error message returned when a response exceeds the maximum response size
+const ResponseTooBigMessage = "Response is too big"
+
+// InvalidParamsCode error code returned for invalid method parameters
+const InvalidParamsCode = -32602
+
+// InvalidParamsMessage error message returned for invalid method parameters
+const InvalidParamsMessage = "Invalid params"
+
+// InvalidBlockHashCode error code returned when a chainHead method is called with a block hash which is
+// not pinned by the follow subscription, value derived from the JSON-RPC interface specification
+const InvalidBlockHashCode = -32801
+
+// InvalidBlockHashMessage error message returned when a chainHead method is called with a block hash which
+// is not pinned by the follow subscription
+const InvalidBlockHashMessage = "Invalid block hash"
+
 func newSubcriptionBaseResponseJSON() BaseResponseJSON {
 	return BaseResponseJSON{
 		Jsonrpc: "2.0",
@@ -69,3 +96,18 @@ func newBooleanResponseJSON(value bool, reqID float64) BooleanResponse {
 		ID:      reqID,
 	}
 }
+
+// ResultResponse for responses of any result type
+type ResultResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Result  interface{} `json:"result"`
+	ID      float64     `json:"id"`
+}
+
+func newResultResponseJSON(result interface{}, reqID float64) ResultResponse {
+	return ResultResponse{
+		JSONRPC: "2.0",
+		Result:  result,
+		ID:      reqID,
+	}
+}
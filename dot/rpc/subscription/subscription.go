@@ -19,6 +19,17 @@
 	stateSubscribeStorage          string = "state_subscribeStorage"
 	stateSubscribeRuntimeVersion   string = "state_subscribeRuntimeVersion"
 	grandpaSubscribeJustifications string = "grandpa_subscribeJustifications"
+
+	chainHeadV1Prefix        string = "chainHead_v1_"
+	chainHeadV1Follow        string = "chainHead_v1_follow"
+	chainHeadV1Unfollow      string = "chainHead_v1_unfollow"
+	chainHeadV1Header        string = "chainHead_v1_header"
+	chainHeadV1Body          string = "chainHead_v1_body"
+	chainHeadV1Call          string = "chainHead_v1_call"
+	chainHeadV1Storage       string = "chainHead_v1_storage"
+	chainHeadV1Unpin         string = "chainHead_v1_unpin"
+	chainHeadV1Continue      string = "chainHead_v1_continue"
+	chainHeadV1StopOperation string = "chainHead_v1_stopOperation"
 )
 
 type setupListener func(reqid float64, params interface{}) (Listener, error)
@@ -46,6 +57,8 @@ func (c *WSConn) getSetupListener(method string) setupListener {
 		return c.initRuntimeVersionListener
 	case grandpaSubscribeJustifications:
 		return c.initGrandpaJustificationListener
+	case chainHeadV1Follow:
+		return c.initChainHeadFollower
 	default:
 		return nil
 	}
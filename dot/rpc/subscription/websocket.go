@@ -11,6 +11,7 @@
 	"io"
 	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -26,6 +27,11 @@ type httpclient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// RateLimiter limits the calls of the clients of the RPC servers
+type RateLimiter interface {
+	Allow(ip, method string) bool
+}
+
 var errCannotReadFromWebsocket = errors.New("cannot read message from websocket")
 var errCannotUnmarshalMessage = errors.New("cannot unmarshal webasocket message data")
 var logger = log.NewFromGlobal(log.AddContext("pkg", "rpc/subscription"))
@@ -43,6 +49,8 @@ type WSConn struct {
 	TxStateAPI    modules.TransactionStateAPI
 	RPCHost       string
 	HTTP          httpclient
+	ClientIP      string
+	RateLimiter   RateLimiter
 }
 
 // readWebsocketMessage will read and parse the message data to a string->interface{} data
@@ -55,6 +63,11 @@ func (c *WSConn) readWebsocketMessage() ([]byte, map[string]interface{}, error)
 
 	logger.Tracef("websocket message received: %s", string(mbytes))
 
+	if isBatchMessage(mbytes) {
+		// the calls of a batch are parsed by handleBatch
+		return mbytes, nil, nil
+	}
+
 	// determine if request is for subscribe method type
 	var msg map[string]interface{}
 	err = json.Unmarshal(mbytes, &msg)
@@ -72,6 +85,8 @@ func (c *WSConn) HandleComm() {
 	for {
 		mbytes, msg, err := c.readWebsocketMessage()
 		if errors.Is(err, errCannotReadFromWebsocket) {
+			// the blocks pinned by the follow subscriptions of the closed connection are unpinned
+			c.stopChainHeadFollowers()
 			return
 		}
 
@@ -80,12 +95,27 @@ func (c *WSConn) HandleComm() {
 			continue
 		}
 
+		if msg == nil {
+			c.handleBatch(mbytes)
+			continue
+		}
+
 		params := msg["params"]
 		reqid := msg["id"].(float64)
 		method := msg["method"].(string)
 
 		logger.Debugf("ws method %s called with params %v", method, params)
 
+		if isChainHeadCall(method) {
+			if !c.allow(method) {
+				c.safeSendError(reqid, big.NewInt(RateLimitedCode), RateLimitedMessage)
+				continue
+			}
+
+			c.handleChainHeadCall(reqid, method, params)
+			continue
+		}
+
 		if !strings.Contains(method, "_unsubscribe") && !strings.Contains(method, "_unwatch") {
 			setupListener := c.getSetupListener(method)
 
@@ -94,6 +124,11 @@ func (c *WSConn) HandleComm() {
 				continue
 			}
 
+			if !c.allow(method) {
+				c.safeSendError(reqid, big.NewInt(RateLimitedCode), RateLimitedMessage)
+				continue
+			}
+
 			listener, err := setupListener(reqid, params)
 			if err != nil {
 				logger.Warnf("failed to create listener (method=%s): %s", method, err)
@@ -104,6 +139,11 @@ func (c *WSConn) HandleComm() {
 			continue
 		}
 
+		if !c.allow(method) {
+			c.safeSendError(reqid, big.NewInt(RateLimitedCode), RateLimitedMessage)
+			continue
+		}
+
 		listener, err := c.getUnsubListener(params)
 
 		if err != nil {
@@ -145,9 +185,117 @@ func (c *WSConn) executeRPCCall(data []byte) {
 		return
 	}
 
+	if wsresponse == nil {
+		// no response for notifications
+		return
+	}
+
 	c.safeSend(wsresponse)
 }
 
+// handleBatch handles a batch of calls. The subscriptions cannot be part of a batch, since their
+// notifications are sent apart, the other calls are forwarded as a batch to the RPC server.
+func (c *WSConn) handleBatch(mbytes []byte) {
+	var calls []json.RawMessage
+	err := json.Unmarshal(mbytes, &calls)
+	if err != nil || len(calls) == 0 {
+		logger.Debugf("websocket failed to unmarshal batch message: %v", err)
+		c.safeSendError(0, big.NewInt(InvalidRequestCode), InvalidRequestMessage)
+		return
+	}
+
+	responses := make([]json.RawMessage, 0, len(calls))
+	forwarded := make([]json.RawMessage, 0, len(calls))
+	for _, call := range calls {
+		var req struct {
+			Method string  `json:"method"`
+			ID     float64 `json:"id"`
+		}
+
+		err = json.Unmarshal(call, &req)
+		if err != nil || !c.isSubscriptionMethod(req.Method) {
+			// the invalid calls are refused by the RPC server
+			forwarded = append(forwarded, call)
+			continue
+		}
+
+		res, err := json.Marshal(newErrorResponseJSON(req.ID, big.NewInt(InvalidRequestCode), InvalidRequestMessage))
+		if err != nil {
+			logger.Warnf("failed to encode error response: %s", err)
+			continue
+		}
+
+		responses = append(responses, res)
+	}
+
+	if len(forwarded) != 0 {
+		results, err := c.executeBatch(forwarded)
+		if err != nil {
+			logger.Warnf("problems while executing the batch: %s", err)
+		}
+
+		responses = append(responses, results...)
+	}
+
+	if len(responses) == 0 {
+		// no response for a batch of notifications
+		return
+	}
+
+	c.safeSend(responses)
+}
+
+// executeBatch forwards the batch of calls to the RPC server, and returns the responses
+func (c *WSConn) executeBatch(calls []json.RawMessage) ([]json.RawMessage, error) {
+	enc, err := json.Marshal(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := c.prepareRequest(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	var res json.RawMessage
+	err = c.executeRequest(request, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isBatchMessage(res) {
+		// the batch was refused as a whole, or every call was a notification
+		if len(res) == 0 {
+			return nil, nil
+		}
+		return []json.RawMessage{res}, nil
+	}
+
+	var results []json.RawMessage
+	err = json.Unmarshal(res, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (c *WSConn) isSubscriptionMethod(method string) bool {
+	return c.getSetupListener(method) != nil || isChainHeadCall(method) ||
+		strings.Contains(method, "_unsubscribe") || strings.Contains(method, "_unwatch")
+}
+
+// allow returns true if the client is allowed to call the subscription method, the other methods are
+// limited by the RPC server
+func (c *WSConn) allow(method string) bool {
+	return c.RateLimiter == nil || c.RateLimiter.Allow(c.ClientIP, method)
+}
+
+func isBatchMessage(mbytes []byte) bool {
+	trimmed := bytes.TrimSpace(mbytes)
+	return len(trimmed) != 0 && trimmed[0] == '['
+}
+
 func (c *WSConn) initStorageChangeListener(reqID float64, params interface{}) (Listener, error) {
 	if c.StorageAPI == nil {
 		c.safeSendError(reqID, nil, "error StorageAPI not set")
@@ -281,6 +429,10 @@ func (c *WSConn) initExtrinsicWatch(reqID float64, params interface{}) (Listener
 		return nil, fmt.Errorf("error BlockAPI not set")
 	}
 
+	if c.CoreAPI == nil {
+		return nil, fmt.Errorf("error CoreAPI not set")
+	}
+
 	txStatusChan := c.TxStateAPI.GetStatusNotifierChannel(extBytes)
 	importedChan := c.BlockAPI.GetImportedBlockNotifierChannel()
 	finalizedChan := c.BlockAPI.GetFinalisedNotifierChannel()
@@ -371,6 +523,34 @@ func (c *WSConn) initGrandpaJustificationListener(reqID float64, _ interface{})
 	return jl, nil
 }
 
+func (c *WSConn) initChainHeadFollower(reqID float64, params interface{}) (Listener, error) {
+	if c.BlockAPI == nil {
+		c.safeSendError(reqID, nil, "error BlockAPI not set")
+		return nil, fmt.Errorf("error BlockAPI not set")
+	}
+
+	var withRuntime bool
+	err := decodeParams(params, 1, &withRuntime)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), InvalidParamsMessage)
+		return nil, err
+	}
+
+	follower := newChainHeadFollower(c, withRuntime)
+	follower.importedChan = c.BlockAPI.GetImportedBlockNotifierChannel()
+	follower.finalizedChan = c.BlockAPI.GetFinalisedNotifierChannel()
+
+	c.mu.Lock()
+	follower.subID = atomic.AddUint32(&c.qtyListeners, 1)
+	c.Subscriptions[follower.subID] = follower
+	c.mu.Unlock()
+
+	// the IDs of the follow subscriptions are strings
+	c.safeSend(newResultResponseJSON(strconv.FormatUint(uint64(follower.subID), 10), reqID))
+
+	return follower, nil
+}
+
 func (c *WSConn) safeSend(msg interface{}) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -381,14 +561,7 @@ func (c *WSConn) safeSend(msg interface{}) {
 }
 
 func (c *WSConn) safeSendError(reqID float64, errorCode *big.Int, message string) {
-	res := &ErrorResponseJSON{
-		Jsonrpc: "2.0",
-		Error: &ErrorMessageJSON{
-			Code:    errorCode,
-			Message: message,
-		},
-		ID: reqID,
-	}
+	res := newErrorResponseJSON(reqID, errorCode, message)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	err := c.Wsconn.WriteJSON(res)
@@ -411,6 +584,10 @@ func (c *WSConn) prepareRequest(b []byte) (*http.Request, error) {
 	}
 
 	req.Header.Set("Content-Type", "application/json;")
+	if c.ClientIP != "" {
+		// the calls are rate limited by the RPC server with the address of the websocket client
+		req.Header.Set("X-Forwarded-For", c.ClientIP)
+	}
 	return req, nil
 }
 
@@ -433,6 +610,11 @@ func (c *WSConn) executeRequest(r *http.Request, d interface{}) error {
 		return err
 	}
 
+	if len(bytes.TrimSpace(body)) == 0 {
+		// no response for notifications
+		return nil
+	}
+
 	err = json.Unmarshal(body, d)
 
 	if err != nil {
@@ -450,6 +632,17 @@ type ErrorResponseJSON struct {
 	ID      float64           `json:"id"`
 }
 
+func newErrorResponseJSON(reqID float64, errorCode *big.Int, message string) *ErrorResponseJSON {
+	return &ErrorResponseJSON{
+		Jsonrpc: "2.0",
+		Error: &ErrorMessageJSON{
+			Code:    errorCode,
+			Message: message,
+		},
+		ID: reqID,
+	}
+}
+
 // ErrorMessageJSON json for error messages
 type ErrorMessageJSON struct {
 	Code    *big.Int `json:"code"`
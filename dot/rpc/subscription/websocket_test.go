@@ -5,7 +5,10 @@
 
 import (
 	"fmt"
+	"io"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -373,3 +376,58 @@ func TestSubscribeAllHeads(t *testing.T) {
 	require.NoError(t, l.Stop())
 	mockBlockAPI.On("FreeImportedBlockNotifierChannel", mock.AnythingOfType("chan *types.Block"))
 }
+
+type denyRateLimiter struct{}
+
+func (denyRateLimiter) Allow(_, _ string) bool {
+	return false
+}
+
+func TestWSConn_HandleBatch(t *testing.T) {
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	defer cancel()
+
+	rpcServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, `[{"jsonrpc":"2.0","method":"chain_getHeader","params":[],"id":2}]`, string(body))
+		require.Equal(t, "192.0.2.1", r.Header.Get("X-Forwarded-For"))
+
+		_, err = w.Write([]byte(`[{"jsonrpc":"2.0","result":null,"id":2}]`))
+		require.NoError(t, err)
+	}))
+	defer rpcServer.Close()
+
+	wsconn.RPCHost = rpcServer.URL
+	wsconn.HTTP = rpcServer.Client()
+	wsconn.ClientIP = "192.0.2.1"
+	wsconn.RateLimiter = denyRateLimiter{}
+
+	go wsconn.HandleComm()
+	time.Sleep(time.Second * 2)
+
+	// subscriptions are not allowed in a batch
+	err := c.WriteMessage(websocket.TextMessage, []byte(`[`+
+		`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":1},`+
+		`{"jsonrpc":"2.0","method":"chain_getHeader","params":[],"id":2}]`))
+	require.NoError(t, err)
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`[{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request"},"id":1},`+
+		`{"jsonrpc":"2.0","result":null,"id":2}]`+"\n"), msg)
+
+	err = c.WriteMessage(websocket.TextMessage, []byte(`[]`))
+	require.NoError(t, err)
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request"},"id":0}`+"\n"), msg)
+
+	// subscriptions are rate limited by the websocket connection
+	err = c.WriteMessage(websocket.TextMessage, []byte(
+		`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":3}`))
+	require.NoError(t, err)
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"jsonrpc":"2.0","error":{"code":-32005,"message":"Rate limit exceeded"},"id":3}`+"\n"), msg)
+}
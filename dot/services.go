@@ -13,6 +13,7 @@
 
 	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/digest"
+	"github.com/ChainSafe/gossamer/dot/light"
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/rpc"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
@@ -34,6 +35,7 @@
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/life"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/signer"
 	"github.com/ChainSafe/gossamer/lib/utils"
 )
 
@@ -58,9 +60,10 @@ func createStateService(cfg *Config) (*state.Service, error) {
 	logger.Debug("creating state service...")
 
 	config := state.Config{
-		Path:     cfg.Global.BasePath,
-		LogLevel: cfg.Log.StateLvl,
-		Metrics:  metrics.NewIntervalConfig(cfg.Global.PublishMetrics),
+		Path:                      cfg.Global.BasePath,
+		LogLevel:                  cfg.Log.StateLvl,
+		Metrics:                   metrics.NewIntervalConfig(cfg.Global.PublishMetrics),
+		TransactionIndexRetention: cfg.State.TransactionIndexRetention,
 	}
 
 	stateSrvc := state.NewService(config)
@@ -105,6 +108,27 @@ func createRuntimeStorage(st *state.Service) (*runtime.NodeStorage, error) {
 	}, nil
 }
 
+// setupModuleCache sets the cache of the runtimes compiled by wasmer, which is stored in the base path
+func setupModuleCache(cfg *Config) error {
+	size := cfg.Core.RuntimeCacheSize
+	if size < 0 {
+		wasmer.SetModuleCache(nil)
+		return nil
+	}
+
+	if size == 0 {
+		size = wasmer.DefaultModuleCacheSize
+	}
+
+	cache, err := wasmer.NewModuleCache(filepath.Join(cfg.Global.BasePath, "runtime-cache"), size)
+	if err != nil {
+		return err
+	}
+
+	wasmer.SetModuleCache(cache)
+	return nil
+}
+
 func createRuntime(cfg *Config, ns runtime.NodeStorage, st *state.Service,
 	ks *keystore.GlobalKeystore, net *network.Service, code []byte) (
 	runtime.Instance, error) {
@@ -172,6 +196,10 @@ func createRuntime(cfg *Config, ns runtime.NodeStorage, st *state.Service,
 		}
 	}
 
+	if cfg.Core.RuntimePoolSize > 0 {
+		st.Block.SetRuntimePoolSize(cfg.Core.RuntimePoolSize)
+	}
+
 	st.Block.StoreRuntime(st.Block.BestBlockHash(), rt)
 	return rt, nil
 }
@@ -194,7 +222,7 @@ func createBABEService(cfg *Config, st *state.Service, ks keystore.Keystore,
 
 	kps := ks.Keypairs()
 	logger.Infof("keystore with keys %v", kps)
-	if len(kps) == 0 && cfg.Core.BabeAuthority {
+	if len(kps) == 0 && cfg.Core.BabeAuthority && cfg.Account.Signer == "" {
 		return nil, ErrNoKeysProvided
 	}
 
@@ -205,13 +233,21 @@ func createBABEService(cfg *Config, st *state.Service, ks keystore.Keystore,
 		TransactionState:   st.Transaction,
 		EpochState:         st.Epoch,
 		BlockImportHandler: cs,
+		SlashingProtection: st.SlashingProtection,
 		Authority:          cfg.Core.BabeAuthority,
 		IsDev:              cfg.Global.ID == "dev",
 		Lead:               cfg.Core.BABELead,
 		Telemetry:          telemetryMailer,
 	}
 
-	if cfg.Core.BabeAuthority {
+	switch {
+	case cfg.Core.BabeAuthority && cfg.Account.Signer != "":
+		s, err := createRemoteSigner(cfg.Account.Signer, keystore.BabeName)
+		if err != nil {
+			return nil, err
+		}
+		bcfg.Signer = s
+	case cfg.Core.BabeAuthority:
 		bcfg.Keypair = kps[0].(*sr25519.Keypair)
 	}
 
@@ -255,6 +291,7 @@ func createCoreService(cfg *Config, ks *keystore.GlobalKeystore,
 		Keystore:             ks,
 		Network:              net,
 		DigestHandler:        dh,
+		Role:                 cfg.Core.Roles,
 		CodeSubstitutes:      codeSubs,
 		CodeSubstitutedState: st.Base,
 	}
@@ -298,6 +335,7 @@ func createNetworkService(cfg *Config, stateSrvc *state.Service,
 		MinPeers:          cfg.Network.MinPeers,
 		MaxPeers:          cfg.Network.MaxPeers,
 		PersistentPeers:   cfg.Network.PersistentPeers,
+		ReservedOnly:      cfg.Network.ReservedOnly,
 		DiscoveryInterval: cfg.Network.DiscoveryInterval,
 		SlotDuration:      slotDuration,
 		PublicIP:          cfg.Network.PublicIP,
@@ -306,6 +344,11 @@ func createNetworkService(cfg *Config, stateSrvc *state.Service,
 		Metrics:           metrics.NewIntervalConfig(cfg.Global.PublishMetrics),
 	}
 
+	// light clients do not store the chain state, so they cannot answer light requests
+	if cfg.Core.Roles != types.LightClientRole {
+		networkConfig.StorageState = stateSrvc.Storage
+	}
+
 	networkSrvc, err := network.NewService(&networkConfig)
 	if err != nil {
 		logger.Errorf("failed to create network service: %s", err)
@@ -341,12 +384,29 @@ func createRPCService(params rpcServiceSettings) (*rpc.HTTPServer, error) {
 		return nil, fmt.Errorf("failed to create sync state service: %s", err)
 	}
 
+	var (
+		storageAPI modules.StorageAPI = params.state.Storage
+		coreAPI    modules.CoreAPI
+		rpcModules = params.config.RPC.Modules
+	)
+
+	if params.core != nil {
+		coreAPI = params.core
+	}
+
+	// light clients do not store the chain state, so storage is requested
+	// from peers and only the modules that do not need the state are enabled
+	if params.config.Core.Roles == types.LightClientRole {
+		storageAPI = light.NewStorage(params.state.Block, params.network)
+		rpcModules = lightClientRPCModules(rpcModules)
+	}
+
 	rpcConfig := &rpc.HTTPServerConfig{
 		LogLvl:              params.config.Log.RPCLvl,
 		BlockAPI:            params.state.Block,
-		StorageAPI:          params.state.Storage,
+		StorageAPI:          storageAPI,
 		NetworkAPI:          params.network,
-		CoreAPI:             params.core,
+		CoreAPI:             coreAPI,
 		NodeStorage:         params.nodeStorage,
 		BlockProducerAPI:    params.blockProducer,
 		BlockFinalityAPI:    params.blockFinality,
@@ -366,12 +426,32 @@ func createRPCService(params rpcServiceSettings) (*rpc.HTTPServer, error) {
 		WSUnsafe:            params.config.RPC.WSUnsafe,
 		WSUnsafeExternal:    params.config.RPC.WSUnsafeExternal,
 		WSPort:              params.config.RPC.WSPort,
-		Modules:             params.config.RPC.Modules,
+		Modules:             rpcModules,
+
+		MaxQueryStorageRange: params.config.RPC.MaxQueryStorageRange,
+		CORS:                 params.config.RPC.CORS,
+		RateLimit:            params.config.RPC.RateLimit,
+		MethodRateLimits:     params.config.RPC.MethodRateLimits,
+		MaxResponseSize:      uint64(params.config.RPC.MaxResponseSize) << 20,
 	}
 
 	return rpc.NewHTTPServer(rpcConfig), nil
 }
 
+// lightClientRPCModules returns the given RPC modules that are supported by light clients
+func lightClientRPCModules(mods []string) []string {
+	supported := make([]string, 0, len(mods))
+	for _, mod := range mods {
+		switch mod {
+		case "system", "chain", "state", "rpc", "grandpa":
+			supported = append(supported, mod)
+		default:
+			logger.Warnf("rpc module %s is not supported in light client mode", mod)
+		}
+	}
+	return supported
+}
+
 // createSystemService creates a systemService for providing system related information
 func createSystemService(cfg *types.SystemInfo, stateSrvc *state.Service) (*system.Service, error) {
 	genesisData, err := stateSrvc.Base.LoadGenesisData()
@@ -385,6 +465,63 @@ func createSystemService(cfg *types.SystemInfo, stateSrvc *state.Service) (*syst
 // createGRANDPAService creates a new GRANDPA service
 func createGRANDPAService(cfg *Config, st *state.Service, dh *digest.Handler,
 	ks keystore.Keystore, net *network.Service, telemetryMailer telemetry.Client) (*grandpa.Service, error) {
+	if ks.Name() != "gran" || ks.Type() != crypto.Ed25519Type {
+		return nil, ErrInvalidKeystoreType
+	}
+
+	// light clients only use the GRANDPA service to verify justifications, they never vote
+	isLightClient := cfg.Core.Roles == types.LightClientRole
+	authority := cfg.Core.GrandpaAuthority && !isLightClient
+
+	voters, err := grandpaVoters(st, isLightClient)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := ks.Keypairs()
+	if len(keys) == 0 && authority && cfg.Account.Signer == "" {
+		return nil, errors.New("no ed25519 keys provided for GRANDPA")
+	}
+
+	gsCfg := &grandpa.Config{
+		LogLvl:             cfg.Log.FinalityGadgetLvl,
+		BlockState:         st.Block,
+		GrandpaState:       st.Grandpa,
+		DigestHandler:      dh,
+		Voters:             voters,
+		Authority:          authority,
+		Network:            net,
+		Interval:           cfg.Core.GrandpaInterval,
+		Telemetry:          telemetryMailer,
+		SlashingProtection: st.SlashingProtection,
+	}
+
+	switch {
+	case authority && cfg.Account.Signer != "":
+		s, err := createRemoteSigner(cfg.Account.Signer, keystore.GranName)
+		if err != nil {
+			return nil, err
+		}
+		gsCfg.Signer = s
+	case authority:
+		gsCfg.Keypair = keys[0].(*ed25519.Keypair)
+	}
+
+	return grandpa.NewService(gsCfg)
+}
+
+// grandpaVoters returns the current GRANDPA voters. Light clients do not hold the state of the
+// best block, so the voters are read from the GRANDPA state kept up to date by the digest handler.
+func grandpaVoters(st *state.Service, isLightClient bool) ([]types.GrandpaVoter, error) {
+	if isLightClient {
+		setID, err := st.Grandpa.GetCurrentSetID()
+		if err != nil {
+			return nil, err
+		}
+
+		return st.Grandpa.GetAuthorities(setID)
+	}
+
 	rt, err := st.Block.GetRuntime(nil)
 	if err != nil {
 		return nil, err
@@ -395,34 +532,27 @@ func createGRANDPAService(cfg *Config, st *state.Service, dh *digest.Handler,
 		return nil, err
 	}
 
-	if ks.Name() != "gran" || ks.Type() != crypto.Ed25519Type {
-		return nil, ErrInvalidKeystoreType
-	}
+	return types.NewGrandpaVotersFromAuthorities(ad), nil
+}
 
-	voters := types.NewGrandpaVotersFromAuthorities(ad)
+// createRemoteSigner returns a signer of the first key of the given type held by the remote signer
+func createRemoteSigner(endpoint string, keyType keystore.Name) (*signer.Remote, error) {
+	client, err := signer.NewClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
 
-	keys := ks.Keypairs()
-	if len(keys) == 0 && cfg.Core.GrandpaAuthority {
-		return nil, errors.New("no ed25519 keys provided for GRANDPA")
+	pubs, err := client.PublicKeys(keyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s keys from remote signer: %w", keyType, err)
 	}
 
-	gsCfg := &grandpa.Config{
-		LogLvl:        cfg.Log.FinalityGadgetLvl,
-		BlockState:    st.Block,
-		GrandpaState:  st.Grandpa,
-		DigestHandler: dh,
-		Voters:        voters,
-		Authority:     cfg.Core.GrandpaAuthority,
-		Network:       net,
-		Interval:      cfg.Core.GrandpaInterval,
-		Telemetry:     telemetryMailer,
-	}
-
-	if cfg.Core.GrandpaAuthority {
-		gsCfg.Keypair = keys[0].(*ed25519.Keypair)
+	if len(pubs) == 0 {
+		return nil, fmt.Errorf("%w: no %s keys in remote signer %s", ErrNoKeysProvided, keyType, endpoint)
 	}
 
-	return grandpa.NewService(gsCfg)
+	logger.Infof("using remote signer %s with %s key %s", endpoint, keyType, pubs[0].Hex())
+	return client.Signer(keyType, pubs[0]), nil
 }
 
 func createBlockVerifier(st *state.Service) (*babe.VerificationManager, error) {
@@ -435,8 +565,8 @@ func createBlockVerifier(st *state.Service) (*babe.VerificationManager, error) {
 }
 
 func newSyncService(cfg *Config, st *state.Service, fg sync.FinalityGadget,
-	verifier *babe.VerificationManager, cs *core.Service, net *network.Service, telemetryMailer telemetry.Client) (
-	*sync.Service, error) {
+	verifier *babe.VerificationManager, cs *core.Service, dh *digest.Handler, net *network.Service,
+	telemetryMailer telemetry.Client) (*sync.Service, error) {
 	slotDuration, err := st.Epoch.GetSlotDuration()
 	if err != nil {
 		return nil, err
@@ -457,6 +587,15 @@ func newSyncService(cfg *Config, st *state.Service, fg sync.FinalityGadget,
 		Telemetry:          telemetryMailer,
 	}
 
+	if cfg.Core.Roles == types.LightClientRole {
+		syncCfg.LightClient = true
+		syncCfg.DigestHandler = dh
+	} else if cfg.Core.WarpSync {
+		// light clients don't download the state, so they cannot warp sync
+		syncCfg.WarpSync = true
+		syncCfg.EpochState = st.Epoch
+	}
+
 	return sync.NewService(syncCfg)
 }
 
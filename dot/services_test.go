@@ -6,6 +6,7 @@
 import (
 	"context"
 	"flag"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -16,6 +17,7 @@
 	"github.com/ChainSafe/gossamer/internal/pprof"
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/signer"
 
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/require"
@@ -134,7 +136,7 @@ func TestCreateSyncService(t *testing.T) {
 	coreSrvc, err := createCoreService(cfg, ks, stateSrvc, &network.Service{}, dh)
 	require.NoError(t, err)
 
-	_, err = newSyncService(cfg, stateSrvc, &grandpa.Service{}, ver, coreSrvc, &network.Service{}, nil)
+	_, err = newSyncService(cfg, stateSrvc, &grandpa.Service{}, ver, coreSrvc, nil, &network.Service{}, nil)
 	require.NoError(t, err)
 }
 
@@ -294,6 +296,27 @@ func TestCreateGrandpaService(t *testing.T) {
 	require.NotNil(t, gs)
 }
 
+func Test_createRemoteSigner(t *testing.T) {
+	ks := keystore.NewGlobalKeystore()
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	err = ks.Babe.Insert(kr.Alice())
+	require.NoError(t, err)
+
+	server := httptest.NewServer(signer.NewServer(ks))
+	t.Cleanup(server.Close)
+
+	s, err := createRemoteSigner(server.URL, keystore.BabeName)
+	require.NoError(t, err)
+	require.Equal(t, kr.Alice().Public().Encode(), s.Public().Encode())
+
+	_, err = createRemoteSigner(server.URL, keystore.GranName)
+	require.ErrorIs(t, err, ErrNoKeysProvided)
+
+	_, err = createRemoteSigner("ftp://localhost", keystore.BabeName)
+	require.Error(t, err)
+}
+
 var addr = flag.String("addr", "localhost:8546", "http service address")
 var testCalls = []struct {
 	call     []byte
@@ -405,3 +428,12 @@ func Test_createPprofService(t *testing.T) {
 
 	require.NotNil(t, service)
 }
+
+func Test_lightClientRPCModules(t *testing.T) {
+	t.Parallel()
+
+	mods := []string{"system", "author", "chain", "state", "rpc", "grandpa", "offchain", "childstate", "syncstate"}
+	expected := []string{"system", "chain", "state", "rpc", "grandpa"}
+
+	require.Equal(t, expected, lightClientRPCModules(mods))
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+)
+
+// ExportSlashingProtection returns the blocks authored and the votes signed by the authorities of
+// the node with the given configuration, to move them to another machine.
+func ExportSlashingProtection(cfg *Config) (*state.SlashingProtectionData, error) {
+	stateSrvc, err := createStateService(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state service: %w", err)
+	}
+
+	defer func() {
+		if err := stateSrvc.DB().Close(); err != nil {
+			logger.Errorf("failed to close database: %s", err)
+		}
+	}()
+
+	return state.NewSlashingProtection(stateSrvc.DB()).Export()
+}
+
+// ImportSlashingProtection imports the blocks and votes in the given file, exported from another
+// machine, to the slashing protection database of the node with the given configuration.
+func ImportSlashingProtection(cfg *Config, fp string) error {
+	enc, err := os.ReadFile(filepath.Clean(fp))
+	if err != nil {
+		return err
+	}
+
+	data := new(state.SlashingProtectionData)
+	err = json.Unmarshal(enc, data)
+	if err != nil {
+		return fmt.Errorf("invalid slashing protection file: %w", err)
+	}
+
+	stateSrvc, err := createStateService(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create state service: %w", err)
+	}
+
+	defer func() {
+		if err := stateSrvc.DB().Close(); err != nil {
+			logger.Errorf("failed to close database: %s", err)
+		}
+	}()
+
+	err = state.NewSlashingProtection(stateSrvc.DB()).Import(data)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("imported %d blocks and %d votes to the slashing protection database",
+		len(data.Blocks), len(data.Votes))
+	return nil
+}
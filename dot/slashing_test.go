@@ -0,0 +1,58 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportExportSlashingProtection(t *testing.T) {
+	cfg := NewTestConfig(t)
+
+	data, err := ExportSlashingProtection(cfg)
+	require.NoError(t, err)
+	require.Empty(t, data.Blocks)
+	require.Empty(t, data.Votes)
+
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	expected := &state.SlashingProtectionData{
+		Blocks: []state.SignedBlock{
+			{Authority: kr.Alice().Public().Hex(), Slot: 1, Hash: common.Hash{1}},
+		},
+		Votes: []state.SignedVote{
+			{Authority: kr.Bob().Public().Hex(), SetID: 1, Round: 2, Subround: 1, Hash: common.Hash{2}, Number: 3},
+		},
+	}
+
+	enc, err := json.Marshal(expected)
+	require.NoError(t, err)
+
+	fp := filepath.Join(t.TempDir(), "slashing.json")
+	err = os.WriteFile(fp, enc, 0600)
+	require.NoError(t, err)
+
+	err = ImportSlashingProtection(cfg, fp)
+	require.NoError(t, err)
+
+	data, err = ExportSlashingProtection(cfg)
+	require.NoError(t, err)
+	require.Equal(t, expected, data)
+
+	err = os.WriteFile(fp, []byte("noot"), 0600)
+	require.NoError(t, err)
+
+	err = ImportSlashingProtection(cfg, fp)
+	require.Error(t, err)
+}
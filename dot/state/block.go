@@ -13,6 +13,7 @@
 	"time"
 
 	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/telemetry"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/blocktree"
@@ -61,6 +62,17 @@ type BlockState struct {
 	lastFinalised     common.Hash
 	unfinalisedBlocks *sync.Map // map[common.Hash]*types.Block
 
+	// transactionIndexDB holds the transactions indexed by the finalised blocks, it is nested
+	// in the block table so the indexed transactions are written in the finalisation batch
+	transactionIndexDB         chaindb.Database
+	unfinalisedIndexOperations *sync.Map // map[common.Hash][]types.IndexOperation
+	// transactionIndexRetention is the number of finalised blocks for which the indexed
+	// transactions are kept, they are kept forever if it is zero.
+	transactionIndexRetention uint32
+
+	// runtimePoolSize is the number of instances of the runtime instance pools
+	runtimePoolSize int
+
 	// block notifiers
 	imported                       map[chan *types.Block]struct{}
 	finalised                      map[chan *types.FinalisationInfo]struct{}
@@ -71,6 +83,11 @@ type BlockState struct {
 
 	pruneKeyCh chan *types.Header
 
+	// pinned holds the blocks pinned by the RPC clients, they are not pruned until they are unpinned
+	pinned      map[common.Hash]*pinnedBlock
+	pinnedLock  sync.Mutex
+	statePruner pruner.Pruner
+
 	telemetry telemetry.Client
 }
 
@@ -81,10 +98,14 @@ func NewBlockState(db chaindb.Database, telemetry telemetry.Client) (*BlockState
 		baseState:                  NewBaseState(db),
 		db:                         chaindb.NewTable(db, blockPrefix),
 		unfinalisedBlocks:          new(sync.Map),
+		transactionIndexDB:         chaindb.NewTable(db, blockPrefix+transactionIndexPrefix),
+		unfinalisedIndexOperations: new(sync.Map),
 		imported:                   make(map[chan *types.Block]struct{}),
 		finalised:                  make(map[chan *types.FinalisationInfo]struct{}),
 		pruneKeyCh:                 make(chan *types.Header, pruneKeyBufferSize),
+		pinned:                     make(map[common.Hash]*pinnedBlock),
 		runtimeUpdateSubscriptions: make(map[uint32]chan<- runtime.Version),
+		runtimePoolSize:            runtime.DefaultInstancePoolSize,
 		telemetry:                  telemetry,
 	}
 
@@ -114,12 +135,16 @@ func NewBlockStateFromGenesis(db chaindb.Database, header *types.Header,
 		baseState:                  NewBaseState(db),
 		db:                         chaindb.NewTable(db, blockPrefix),
 		unfinalisedBlocks:          new(sync.Map),
+		transactionIndexDB:         chaindb.NewTable(db, blockPrefix+transactionIndexPrefix),
+		unfinalisedIndexOperations: new(sync.Map),
 		imported:                   make(map[chan *types.Block]struct{}),
 		finalised:                  make(map[chan *types.FinalisationInfo]struct{}),
 		pruneKeyCh:                 make(chan *types.Header, pruneKeyBufferSize),
+		pinned:                     make(map[common.Hash]*pinnedBlock),
 		runtimeUpdateSubscriptions: make(map[uint32]chan<- runtime.Version),
 		genesisHash:                header.Hash(),
 		lastFinalised:              header.Hash(),
+		runtimePoolSize:            runtime.DefaultInstancePoolSize,
 		telemetry:                  telemetryMailer,
 	}
 
@@ -229,6 +254,10 @@ func (bs *BlockState) HasHeader(hash common.Hash) (bool, error) {
 		return true, nil
 	}
 
+	if _, has := bs.getPinnedBlock(hash); has {
+		return true, nil
+	}
+
 	return bs.db.Has(headerKey(hash))
 }
 
@@ -239,6 +268,10 @@ func (bs *BlockState) GetHeader(hash common.Hash) (*types.Header, error) {
 		return header, nil
 	}
 
+	if block, has := bs.getPinnedBlock(hash); has {
+		return &block.Header, nil
+	}
+
 	result := types.NewEmptyHeader()
 
 	if bs.db == nil {
@@ -320,6 +353,11 @@ func (bs *BlockState) GetBlockByHash(hash common.Hash) (*types.Block, error) {
 		return block, nil
 	}
 
+	block, has = bs.getPinnedBlock(hash)
+	if has {
+		return block, nil
+	}
+
 	header, err := bs.GetHeader(hash)
 	if err != nil {
 		return nil, err
@@ -352,6 +390,10 @@ func (bs *BlockState) HasBlockBody(hash common.Hash) (bool, error) {
 		return true, nil
 	}
 
+	if _, has := bs.getPinnedBlock(hash); has {
+		return true, nil
+	}
+
 	return bs.db.Has(blockBodyKey(hash))
 }
 
@@ -362,6 +404,11 @@ func (bs *BlockState) GetBlockBody(hash common.Hash) (*types.Body, error) {
 		return &block.Body, nil
 	}
 
+	block, has = bs.getPinnedBlock(hash)
+	if has {
+		return &block.Body, nil
+	}
+
 	data, err := bs.db.Get(blockBodyKey(hash))
 	if err != nil {
 		return nil, err
@@ -424,6 +471,25 @@ func (bs *BlockState) AddBlockWithArrivalTime(block *types.Block, arrivalTime ti
 	return nil
 }
 
+// AddHeader adds a header to the blocktree without its block body. It is used by light clients,
+// which only import and verify block headers. The header is written to the database upon finalisation.
+func (bs *BlockState) AddHeader(header *types.Header) error {
+	bs.Lock()
+	defer bs.Unlock()
+
+	if err := bs.bt.AddBlock(header, time.Now()); err != nil {
+		return err
+	}
+
+	block := &types.Block{
+		Header: *header,
+	}
+
+	bs.storeUnfinalisedBlock(block)
+	go bs.notifyImported(block)
+	return nil
+}
+
 // AddBlockToBlockTree adds the given block to the blocktree. It does not write it to the database.
 // TODO: remove this func and usage from sync (after sync refactor?)
 func (bs *BlockState) AddBlockToBlockTree(block *types.Block) error {
@@ -696,6 +762,21 @@ func (bs *BlockState) StoreRuntime(hash common.Hash, rt runtime.Instance) {
 	bs.bt.StoreRuntime(hash, rt)
 }
 
+// GetRuntimePool gets the instance pool of the runtime for the corresponding block hash.
+// Concurrent runtime calls must go through the pool, rather than the instance returned by GetRuntime.
+func (bs *BlockState) GetRuntimePool(hash *common.Hash) (*runtime.InstancePool, error) {
+	if hash == nil {
+		return bs.bt.GetBlockRuntimePool(bs.BestBlockHash(), bs.runtimePoolSize)
+	}
+
+	return bs.bt.GetBlockRuntimePool(*hash, bs.runtimePoolSize)
+}
+
+// SetRuntimePoolSize sets the number of instances of the runtime instance pools created afterwards.
+func (bs *BlockState) SetRuntimePoolSize(size int) {
+	bs.runtimePoolSize = size
+}
+
 // GetNonFinalisedBlocks get all the blocks in the blocktree
 func (bs *BlockState) GetNonFinalisedBlocks() []common.Hash {
 	return bs.bt.GetAllBlocks()
@@ -7,9 +7,11 @@
 	"encoding/binary"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/telemetry"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/blocktree"
 	"github.com/ChainSafe/gossamer/lib/common"
 )
 
@@ -146,11 +148,18 @@ func (bs *BlockState) SetFinalisedHash(hash common.Hash, round, setID uint64) er
 
 	pruned := bs.bt.Prune(hash)
 	for _, hash := range pruned {
+		bs.unfinalisedIndexOperations.Delete(hash)
 		block, has := bs.getAndDeleteUnfinalisedBlock(hash)
 		if !has {
 			continue
 		}
 
+		// pinned blocks and their state are kept until they are unpinned
+		if bs.keepPinnedBlock(block) {
+			logger.Tracef("kept pinned block number %s with hash %s", block.Header.Number, hash)
+			continue
+		}
+
 		logger.Tracef("pruned block number %s with hash %s", block.Header.Number, hash)
 		bs.pruneKeyCh <- &block.Header
 	}
@@ -179,6 +188,47 @@ func (bs *BlockState) SetFinalisedHash(hash common.Hash, round, setID uint64) er
 	return nil
 }
 
+// ImportFinalisedHeader sets a header whose ancestors are unknown, such as the target of a warp
+// sync, as the latest finalised block. The block tree is reset to have the header as its root.
+func (bs *BlockState) ImportFinalisedHeader(header *types.Header, round, setID uint64) error {
+	bs.Lock()
+	defer bs.Unlock()
+
+	hash := header.Hash()
+	if err := bs.SetHeader(header); err != nil {
+		return fmt.Errorf("failed to set header: %w", err)
+	}
+
+	if err := bs.setArrivalTime(hash, time.Now()); err != nil {
+		return fmt.Errorf("failed to set arrival time: %w", err)
+	}
+
+	if err := bs.db.Put(headerHashKey(header.Number.Uint64()), hash.ToBytes()); err != nil {
+		return fmt.Errorf("failed to set number->hash mapping: %w", err)
+	}
+
+	if err := bs.db.Put(finalisedHashKey(round, setID), hash[:]); err != nil {
+		return fmt.Errorf("failed to set finalised hash key: %w", err)
+	}
+
+	if err := bs.setHighestRoundAndSetID(round, setID); err != nil {
+		return fmt.Errorf("failed to set highest round and set ID: %w", err)
+	}
+
+	bs.bt = blocktree.NewBlockTreeFromRoot(header)
+	bs.lastFinalised = hash
+	bs.notifyFinalized(hash, round, setID)
+
+	bs.telemetry.SendMessage(
+		telemetry.NewNotifyFinalized(
+			hash,
+			header.Number.String(),
+		),
+	)
+
+	return nil
+}
+
 func (bs *BlockState) handleFinalisedBlock(curr common.Hash) error {
 	if curr.Equal(bs.lastFinalised) {
 		return nil
@@ -198,7 +248,14 @@ func (bs *BlockState) handleFinalisedBlock(curr common.Hash) error {
 		return err
 	}
 
+	previous, err := bs.GetHeader(prev)
+	if err != nil {
+		return fmt.Errorf("failed to get highest finalised header: %w", err)
+	}
+
 	batch := bs.db.NewBatch()
+	indexBatch := bs.newTransactionIndexBatch(batch)
+	finalised := previous.Number.Uint64()
 
 	// root of subchain is previously finalised block, which has already been stored in the db
 	for _, hash := range subchain[1:] {
@@ -215,8 +272,16 @@ func (bs *BlockState) handleFinalisedBlock(curr common.Hash) error {
 			return err
 		}
 
-		if err = bs.SetBlockBody(hash, &block.Body); err != nil {
-			return err
+		// blocks imported by light clients do not have a body
+		if block.Body != nil {
+			if err = bs.SetBlockBody(hash, &block.Body); err != nil {
+				return err
+			}
+
+			err = indexBatch.store(hash, block.Header.Number.Uint64(), &block.Body)
+			if err != nil {
+				return fmt.Errorf("failed to store indexed transactions: %w", err)
+			}
 		}
 
 		arrivalTime, err := bs.bt.GetArrivalTime(hash)
@@ -231,6 +296,7 @@ func (bs *BlockState) handleFinalisedBlock(curr common.Hash) error {
 		if err = batch.Put(headerHashKey(block.Header.Number.Uint64()), hash.ToBytes()); err != nil {
 			return err
 		}
+		finalised = block.Header.Number.Uint64()
 
 		// delete from the unfinalisedBlockMap and delete reference to in-memory trie
 		block, has = bs.getAndDeleteUnfinalisedBlock(hash)
@@ -242,6 +308,10 @@ func (bs *BlockState) handleFinalisedBlock(curr common.Hash) error {
 		bs.pruneKeyCh <- &block.Header
 	}
 
+	if err = indexBatch.prune(previous.Number.Uint64(), finalised); err != nil {
+		return fmt.Errorf("failed to prune indexed transactions: %w", err)
+	}
+
 	return batch.Flush()
 }
 
@@ -8,6 +8,7 @@
 	"testing"
 
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
 
 	"github.com/stretchr/testify/require"
 )
@@ -145,3 +146,47 @@ func TestSetFinalisedHash_setFirstSlotOnFinalisation(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, firstSlot, res)
 }
+
+func TestBlockState_ImportFinalisedHeader(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	digest := types.NewDigest()
+	di, err := types.NewBabeSecondaryPlainPreDigest(0, 100).ToPreRuntimeDigest()
+	require.NoError(t, err)
+	err = digest.Add(*di)
+	require.NoError(t, err)
+
+	// the parent of the header is unknown
+	header := &types.Header{
+		ParentHash: common.Hash{1},
+		Number:     big.NewInt(100),
+		Digest:     digest,
+	}
+
+	err = bs.ImportFinalisedHeader(header, 4, 2)
+	require.NoError(t, err)
+
+	round, setID, err := bs.GetHighestRoundAndSetID()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), round)
+	require.Equal(t, uint64(2), setID)
+
+	finalised, err := bs.GetHighestFinalisedHeader()
+	require.NoError(t, err)
+	require.Equal(t, header.Hash(), finalised.Hash())
+	require.Equal(t, header.Hash(), bs.BestBlockHash())
+
+	hash, err := bs.GetHashByNumber(big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, header.Hash(), hash)
+
+	// blocks can be added on top of the header
+	child := &types.Header{
+		ParentHash: header.Hash(),
+		Number:     big.NewInt(101),
+		Digest:     digest,
+	}
+	err = bs.AddBlock(&types.Block{Header: *child, Body: types.Body{}})
+	require.NoError(t, err)
+	require.Equal(t, child.Hash(), bs.BestBlockHash())
+}
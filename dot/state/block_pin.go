@@ -0,0 +1,118 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// ErrBlockNotPinned is returned when unpinning a block which is not pinned
+var ErrBlockNotPinned = errors.New("block is not pinned")
+
+// pinnedBlock is a block pinned by the RPC clients
+type pinnedBlock struct {
+	count  uint32
+	number int64
+	// block is set once the block is pruned from the block tree, it is kept in memory until it is unpinned
+	block *types.Block
+}
+
+// PinBlock pins the block with the given hash: neither the block nor its state are pruned until
+// the block is unpinned. A block can be pinned several times, it is unpinned once UnpinBlock is
+// called as many times.
+func (bs *BlockState) PinBlock(hash common.Hash) error {
+	// the block tree is not pruned while the block is being pinned
+	bs.RLock()
+	defer bs.RUnlock()
+
+	header, err := bs.GetHeader(hash)
+	if err != nil {
+		return fmt.Errorf("cannot get header of block %s: %w", hash, err)
+	}
+
+	bs.pinnedLock.Lock()
+	defer bs.pinnedLock.Unlock()
+
+	pinned, has := bs.pinned[hash]
+	if has {
+		pinned.count++
+		return nil
+	}
+
+	number := header.Number.Int64()
+	if bs.statePruner != nil {
+		err = bs.statePruner.Pin(number)
+		if err != nil {
+			return err
+		}
+	}
+
+	bs.pinned[hash] = &pinnedBlock{
+		count:  1,
+		number: number,
+	}
+	return nil
+}
+
+// UnpinBlock unpins the block with the given hash. If the block is not pinned anymore and it was
+// pruned from the block tree, it is removed from memory.
+func (bs *BlockState) UnpinBlock(hash common.Hash) error {
+	bs.pinnedLock.Lock()
+	defer bs.pinnedLock.Unlock()
+
+	pinned, has := bs.pinned[hash]
+	if !has {
+		return fmt.Errorf("cannot unpin block %s: %w", hash, ErrBlockNotPinned)
+	}
+
+	pinned.count--
+	if pinned.count > 0 {
+		return nil
+	}
+
+	delete(bs.pinned, hash)
+
+	if bs.statePruner != nil {
+		bs.statePruner.Unpin(pinned.number)
+	}
+
+	if pinned.block != nil {
+		logger.Tracef("pruned unpinned block number %s with hash %s", pinned.block.Header.Number, hash)
+		bs.pruneKeyCh <- &pinned.block.Header
+	}
+
+	return nil
+}
+
+// keepPinnedBlock keeps the block pruned from the block tree in memory if it is pinned. It returns
+// false if the block is not pinned.
+func (bs *BlockState) keepPinnedBlock(block *types.Block) bool {
+	bs.pinnedLock.Lock()
+	defer bs.pinnedLock.Unlock()
+
+	pinned, has := bs.pinned[block.Header.Hash()]
+	if !has {
+		return false
+	}
+
+	pinned.block = block
+	return true
+}
+
+// getPinnedBlock returns the pinned block with the given hash if it was pruned from the block tree
+func (bs *BlockState) getPinnedBlock(hash common.Hash) (*types.Block, bool) {
+	bs.pinnedLock.Lock()
+	defer bs.pinnedLock.Unlock()
+
+	pinned, has := bs.pinned[hash]
+	if !has || pinned.block == nil {
+		return nil, false
+	}
+
+	return pinned.block, true
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/stretchr/testify/require"
+)
+
+func drainPrunedKeys(bs *BlockState) []common.Hash {
+	var pruned []common.Hash
+	for {
+		select {
+		case header := <-bs.pruneKeyCh:
+			pruned = append(pruned, header.Hash())
+		default:
+			return pruned
+		}
+	}
+}
+
+func TestBlockState_PinBlock(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	chain, _ := AddBlocksToState(t, bs, 3, false)
+
+	fork := &types.Block{
+		Header: types.Header{
+			ParentHash: testGenesisHeader.Hash(),
+			Number:     big.NewInt(1),
+			StateRoot:  trie.EmptyHash,
+			Digest:     types.NewDigest(),
+		},
+		Body: sampleBlockBody,
+	}
+	forkHash := fork.Header.Hash()
+	err := bs.AddBlock(fork)
+	require.NoError(t, err)
+
+	err = bs.PinBlock(common.Hash{1})
+	require.Error(t, err)
+
+	err = bs.UnpinBlock(forkHash)
+	require.True(t, errors.Is(err, ErrBlockNotPinned))
+
+	// the block is pinned twice
+	err = bs.PinBlock(forkHash)
+	require.NoError(t, err)
+	err = bs.PinBlock(forkHash)
+	require.NoError(t, err)
+
+	err = bs.SetFinalisedHash(chain[1].Hash(), 1, 1)
+	require.NoError(t, err)
+	require.NotContains(t, drainPrunedKeys(bs), forkHash)
+
+	// the pruned block is still available while it is pinned
+	header, err := bs.GetHeader(forkHash)
+	require.NoError(t, err)
+	require.Equal(t, &fork.Header, header)
+
+	body, err := bs.GetBlockBody(forkHash)
+	require.NoError(t, err)
+	require.Equal(t, &sampleBlockBody, body)
+
+	has, err := bs.HasBlockBody(forkHash)
+	require.NoError(t, err)
+	require.True(t, has)
+
+	err = bs.UnpinBlock(forkHash)
+	require.NoError(t, err)
+	require.Empty(t, drainPrunedKeys(bs))
+
+	has, err = bs.HasHeader(forkHash)
+	require.NoError(t, err)
+	require.True(t, has)
+
+	err = bs.UnpinBlock(forkHash)
+	require.NoError(t, err)
+	require.Equal(t, []common.Hash{forkHash}, drainPrunedKeys(bs))
+
+	has, err = bs.HasHeader(forkHash)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	_, err = bs.GetHeader(forkHash)
+	require.Error(t, err)
+}
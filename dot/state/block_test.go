@@ -471,6 +471,41 @@ func TestAddBlockToBlockTree(t *testing.T) {
 	require.Equal(t, bs.BestBlockHash(), header.Hash())
 }
 
+func TestAddHeader(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	digest := types.NewDigest()
+	prd, err := types.NewBabeSecondaryPlainPreDigest(0, 1).ToPreRuntimeDigest()
+	require.NoError(t, err)
+	err = digest.Add(*prd)
+	require.NoError(t, err)
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Digest:     digest,
+		ParentHash: testGenesisHeader.Hash(),
+	}
+
+	err = bs.AddHeader(header)
+	require.NoError(t, err)
+	require.Equal(t, header.Hash(), bs.BestBlockHash())
+
+	has, err := bs.HasHeader(header.Hash())
+	require.NoError(t, err)
+	require.True(t, has)
+
+	err = bs.SetFinalisedHash(header.Hash(), 1, 1)
+	require.NoError(t, err)
+
+	stored, err := bs.GetHeader(header.Hash())
+	require.NoError(t, err)
+	require.Equal(t, header.Hash(), stored.Hash())
+
+	has, err = bs.HasBlockBody(header.Hash())
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
 func TestNumberIsFinalised(t *testing.T) {
 	bs := newTestBlockState(t, testGenesisHeader)
 	fin, err := bs.NumberIsFinalised(big.NewInt(0))
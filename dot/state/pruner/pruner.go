@@ -4,6 +4,7 @@
 package pruner
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -27,6 +28,9 @@
 	Archive = Mode("archive")
 )
 
+// ErrStatePruned is returned when pinning the state of a block which is already pruned
+var ErrStatePruned = errors.New("state of block is pruned")
+
 // Mode online pruning mode of historical state tries
 type Mode string
 
@@ -51,7 +55,10 @@ type Config struct {
 // Pruner is implemented by FullNode and ArchiveNode.
 type Pruner interface {
 	StoreJournalRecord(deletedHashesSet, insertedHashesSet map[common.Hash]struct{},
+		deletedValues, insertedValues map[common.Hash]uint32,
 		blockHash common.Hash, blockNum int64) error
+	Pin(blockNum int64) error
+	Unpin(blockNum int64)
 }
 
 // ArchiveNode is a no-op since we don't prune nodes in archive mode.
@@ -59,13 +66,22 @@ type ArchiveNode struct{
 
 // StoreJournalRecord for archive node doesn't do anything.
 func (a *ArchiveNode) StoreJournalRecord(_, _ map[common.Hash]struct{},
-	_ common.Hash, _ int64) error {
+	_, _ map[common.Hash]uint32, _ common.Hash, _ int64) error {
+	return nil
+}
+
+// Pin for archive node doesn't do anything, the states are never pruned.
+func (*ArchiveNode) Pin(_ int64) error {
 	return nil
 }
 
+// Unpin for archive node doesn't do anything.
+func (*ArchiveNode) Unpin(_ int64) {}
+
 type deathRecord struct {
-	blockHash   common.Hash
-	deletedKeys map[common.Hash]int64 // Mapping from deleted key hash to block number.
+	blockHash     common.Hash
+	deletedKeys   map[common.Hash]int64  // Mapping from deleted key hash to block number.
+	deletedValues map[common.Hash]uint32 // Mapping from deleted value hash to deletion count.
 }
 
 type deathRow []*deathRecord
@@ -77,10 +93,16 @@ type FullNode struct {
 	storageDB  chaindb.Database
 	journalDB  chaindb.Database
 	deathIndex map[common.Hash]int64 // Mapping from deleted key hash to block number.
+	// valueRefs is the number of nodes holding each value stored apart from its node,
+	// a value is deleted once it is no longer held by any node of the retained states
+	valueRefs map[common.Hash]uint32
 	// pendingNumber is the block number to be pruned.
 	// Initial value is set to 1 and is incremented after every block pruning.
 	pendingNumber int64
 	retainBlocks  int64
+	// pinned is the number of times each block number is pinned, the states of the
+	// pinned blocks are not pruned
+	pinned map[int64]uint32
 	sync.RWMutex
 }
 
@@ -91,6 +113,12 @@ type journalRecord struct {
 	insertedHashesSet map[common.Hash]struct{}
 	// Hash of keys that are deleted from state trie of the block
 	deletedHashesSet map[common.Hash]struct{}
+	// Hash of values stored apart from their nodes that are inserted into
+	// the state trie of the block, with the number of nodes holding them
+	insertedValues map[common.Hash]uint32
+	// Hash of values stored apart from their nodes that are deleted from
+	// the state trie of the block, with the number of times they are deleted
+	deletedValues map[common.Hash]uint32
 }
 
 type journalKey struct {
@@ -99,11 +127,14 @@ type journalKey struct {
 }
 
 func newJournalRecord(hash common.Hash, insertedHashesSet,
-	deletedHashesSet map[common.Hash]struct{}) *journalRecord {
+	deletedHashesSet map[common.Hash]struct{}, insertedValues,
+	deletedValues map[common.Hash]uint32) *journalRecord {
 	return &journalRecord{
 		blockHash:         hash,
 		insertedHashesSet: insertedHashesSet,
 		deletedHashesSet:  deletedHashesSet,
+		insertedValues:    insertedValues,
+		deletedValues:     deletedValues,
 	}
 }
 
@@ -112,9 +143,11 @@ func NewFullNode(db, storageDB chaindb.Database, retainBlocks int64, l log.Level
 	p := &FullNode{
 		deathList:    make([]deathRow, 0),
 		deathIndex:   make(map[common.Hash]int64),
+		valueRefs:    make(map[common.Hash]uint32),
 		storageDB:    storageDB,
 		journalDB:    chaindb.NewTable(db, journalPrefix),
 		retainBlocks: retainBlocks,
+		pinned:       make(map[int64]uint32),
 		logger:       l,
 	}
 
@@ -140,8 +173,9 @@ func NewFullNode(db, storageDB chaindb.Database, retainBlocks int64, l log.Level
 
 // StoreJournalRecord stores journal record into DB and add deathRow into deathList
 func (p *FullNode) StoreJournalRecord(deletedHashesSet, insertedHashesSet map[common.Hash]struct{},
+	deletedValues, insertedValues map[common.Hash]uint32,
 	blockHash common.Hash, blockNum int64) error {
-	jr := newJournalRecord(blockHash, insertedHashesSet, deletedHashesSet)
+	jr := newJournalRecord(blockHash, insertedHashesSet, deletedHashesSet, insertedValues, deletedValues)
 
 	key := &journalKey{blockNum, blockHash}
 	err := p.storeJournal(key, jr)
@@ -154,6 +188,46 @@ func (p *FullNode) StoreJournalRecord(deletedHashesSet, insertedHashesSet map[co
 	return nil
 }
 
+// Pin prevents the pruning of the state of the blocks with the given number, until Unpin is
+// called. It returns ErrStatePruned if the state is already pruned.
+func (p *FullNode) Pin(blockNum int64) error {
+	p.Lock()
+	defer p.Unlock()
+
+	// the death row of a block deletes the keys of the state of its parent
+	if blockNum+1 < p.pendingNumber {
+		return fmt.Errorf("cannot pin block number %d: %w", blockNum, ErrStatePruned)
+	}
+
+	p.pinned[blockNum]++
+	return nil
+}
+
+// Unpin allows the pruning of the state of the blocks with the given number again
+func (p *FullNode) Unpin(blockNum int64) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.pinned[blockNum] <= 1 {
+		delete(p.pinned, blockNum)
+		return
+	}
+
+	p.pinned[blockNum]--
+}
+
+// isPinned returns true if the death row of the given block number deletes keys of the state
+// of a pinned block, it must be called with the lock held
+func (p *FullNode) isPinned(blockNum int64) bool {
+	for num := range p.pinned {
+		if num < blockNum {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p *FullNode) addDeathRow(jr *journalRecord, blockNum int64) {
 	if blockNum == 0 {
 		return
@@ -169,6 +243,10 @@ func (p *FullNode) addDeathRow(jr *journalRecord, blockNum int64) {
 
 	p.processInsertedKeys(jr.insertedHashesSet, jr.blockHash)
 
+	for k, count := range jr.insertedValues {
+		p.valueRefs[k] += count
+	}
+
 	// add deleted keys from journal to death index
 	deletedKeys := make(map[common.Hash]int64, len(jr.deletedHashesSet))
 	for k := range jr.deletedHashesSet {
@@ -182,8 +260,9 @@ func (p *FullNode) addDeathRow(jr *journalRecord, blockNum int64) {
 	}
 
 	record := &deathRecord{
-		blockHash:   jr.blockHash,
-		deletedKeys: deletedKeys,
+		blockHash:     jr.blockHash,
+		deletedKeys:   deletedKeys,
+		deletedValues: jr.deletedValues,
 	}
 
 	// add deathRow to deathList
@@ -218,15 +297,21 @@ func (p *FullNode) start() {
 			canPrune = false
 			return
 		}
-		canPrune = true
 
 		// pop first element from death list
 		row := p.deathList[0]
 		blockNum := p.pendingNumber
 
+		if p.isPinned(blockNum) {
+			canPrune = false
+			return
+		}
+		canPrune = true
+
 		p.logger.Debugf("pruning block number %d", blockNum)
 
 		sdbBatch := p.storageDB.NewBatch()
+		valueRefs := make(map[common.Hash]uint32)
 		for _, record := range row {
 			err := p.deleteKeys(sdbBatch, record.deletedKeys)
 			if err != nil {
@@ -238,6 +323,13 @@ func (p *FullNode) start() {
 			for k := range record.deletedKeys {
 				delete(p.deathIndex, k)
 			}
+
+			err = p.deleteValues(sdbBatch, record.deletedValues, valueRefs)
+			if err != nil {
+				p.logger.Warnf("failed to prune values for block number %d: %s", blockNum, err)
+				sdbBatch.Reset()
+				return
+			}
 		}
 
 		if err := sdbBatch.Flush(); err != nil {
@@ -245,6 +337,14 @@ func (p *FullNode) start() {
 			return
 		}
 
+		for k, refs := range valueRefs {
+			if refs == 0 {
+				delete(p.valueRefs, k)
+				continue
+			}
+			p.valueRefs[k] = refs
+		}
+
 		err := p.storeLastPrunedIndex(blockNum)
 		if err != nil {
 			p.logger.Warnf("failed to store last pruned index for block number %d: %s", blockNum, err)
@@ -382,3 +482,33 @@ func (p *FullNode) deleteKeys(b chaindb.Batch, nodesHash map[common.Hash]int64)
 
 	return nil
 }
+
+// deleteValues releases the references of the deleted values, and deletes the values which
+// are no longer referenced. The updated reference counts are set in valueRefs, they must
+// only be applied once the batch is written. The values stored before they were tracked
+// by the journal are kept, since they may still be referenced.
+func (p *FullNode) deleteValues(b chaindb.Batch, deletedValues, valueRefs map[common.Hash]uint32) error {
+	for k, count := range deletedValues {
+		refs, ok := valueRefs[k]
+		if !ok {
+			refs, ok = p.valueRefs[k]
+		}
+
+		if !ok {
+			continue
+		}
+
+		if count < refs {
+			valueRefs[k] = refs - count
+			continue
+		}
+
+		valueRefs[k] = 0
+		err := b.Del(k.ToBytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -38,9 +38,15 @@ type Service struct {
 	Grandpa     *GrandpaState
 	closeCh     chan interface{}
 
+	SlashingProtection *SlashingProtection
+
 	PrunerCfg pruner.Config
 	Telemetry telemetry.Client
 
+	// TransactionIndexRetention is the number of finalised blocks for which the
+	// indexed transactions are kept, they are kept forever if it is zero.
+	TransactionIndexRetention uint32
+
 	// Below are for testing only.
 	BabeThresholdNumerator   uint64
 	BabeThresholdDenominator uint64
@@ -48,11 +54,12 @@ type Service struct {
 
 // Config is the default configuration used by state service.
 type Config struct {
-	Path      string
-	LogLevel  log.Level
-	PrunerCfg pruner.Config
-	Telemetry telemetry.Client
-	Metrics   metrics.IntervalConfig
+	Path                      string
+	LogLevel                  log.Level
+	PrunerCfg                 pruner.Config
+	Telemetry                 telemetry.Client
+	Metrics                   metrics.IntervalConfig
+	TransactionIndexRetention uint32
 }
 
 // NewService create a new instance of Service
@@ -69,6 +76,8 @@ func NewService(config Config) *Service {
 		closeCh:   make(chan interface{}),
 		PrunerCfg: config.PrunerCfg,
 		Telemetry: config.Telemetry,
+
+		TransactionIndexRetention: config.TransactionIndexRetention,
 	}
 }
 
@@ -120,6 +129,7 @@ func (s *Service) Start() error {
 	if err != nil {
 		return fmt.Errorf("failed to create block state: %w", err)
 	}
+	s.Block.transactionIndexRetention = s.TransactionIndexRetention
 
 	// retrieve latest header
 	bestHeader, err := s.Block.GetHighestFinalisedHeader()
@@ -161,6 +171,8 @@ func (s *Service) Start() error {
 		return fmt.Errorf("failed to create grandpa state: %w", err)
 	}
 
+	s.SlashingProtection = NewSlashingProtection(s.db)
+
 	num, _ := s.Block.BestBlockNumber()
 	logger.Info("created state service with head " +
 		s.Block.BestBlockHash().String() +
@@ -0,0 +1,270 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+)
+
+const (
+	slashingProtectionPrefix = "slashing"
+
+	// authorityKeyLength is the length of the encoded sr25519 and ed25519 public keys of the authorities
+	authorityKeyLength = 32
+)
+
+var (
+	signedBlockPrefix = []byte("blk")  // signedBlockPrefix + authority + slot -> header hash
+	signedVotePrefix  = []byte("vote") // signedVotePrefix + authority + set ID + round + subround -> vote
+
+	// ErrSlashable is returned when a signature would conflict with a previous signature of the authority
+	ErrSlashable = errors.New("signature conflicts with a previous signature of the authority")
+)
+
+// SignedBlock is a block header signed by a BABE authority
+type SignedBlock struct {
+	Authority string      `json:"authority"`
+	Slot      uint64      `json:"slot"`
+	Hash      common.Hash `json:"hash"`
+}
+
+// SignedVote is a vote signed by a GRANDPA authority
+type SignedVote struct {
+	Authority string      `json:"authority"`
+	SetID     uint64      `json:"setId"`
+	Round     uint64      `json:"round"`
+	Subround  uint8       `json:"subround"`
+	Hash      common.Hash `json:"hash"`
+	Number    uint32      `json:"number"`
+}
+
+// SlashingProtectionData is the interchange format of the slashing protection database,
+// used to move an authority from a machine to another
+type SlashingProtectionData struct {
+	Blocks []SignedBlock `json:"blocks"`
+	Votes  []SignedVote  `json:"votes"`
+}
+
+// SlashingProtection records every block authored and every vote signed by the local authorities,
+// and refuses to sign a block or a vote conflicting with them, so that a restarted or duplicated
+// authority does not equivocate. A record is flushed to disk before the signature is allowed.
+type SlashingProtection struct {
+	sync.Mutex
+	baseDB chaindb.Database
+	db     chaindb.Database
+}
+
+// NewSlashingProtection returns a new SlashingProtection
+func NewSlashingProtection(db chaindb.Database) *SlashingProtection {
+	return &SlashingProtection{
+		baseDB: db,
+		db:     chaindb.NewTable(db, slashingProtectionPrefix),
+	}
+}
+
+// signedBlockKey = signedBlockPrefix + authority + slot
+func signedBlockKey(authority []byte, slot uint64) []byte {
+	key := append(append([]byte{}, signedBlockPrefix...), authority...)
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, slot)
+	return append(key, buf...)
+}
+
+// signedVoteKey = signedVotePrefix + authority + set ID + round + subround
+func signedVoteKey(authority []byte, setID, round uint64, subround uint8) []byte {
+	key := append(append([]byte{}, signedVotePrefix...), authority...)
+	buf := make([]byte, 17)
+	binary.BigEndian.PutUint64(buf[:8], setID)
+	binary.BigEndian.PutUint64(buf[8:16], round)
+	buf[16] = subround
+	return append(key, buf...)
+}
+
+func encodeSignedVote(hash common.Hash, number uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, number)
+	return append(hash.ToBytes(), buf...)
+}
+
+// CheckAndRecordBlock records that the authority signs the block header with the given hash for
+// the given slot. It returns ErrSlashable if the authority already signed another header for the slot.
+func (s *SlashingProtection) CheckAndRecordBlock(authority crypto.PublicKey, slot uint64, hash common.Hash) error {
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.checkAndRecord(signedBlockKey(authority.Encode(), slot), hash.ToBytes())
+	if err != nil {
+		return fmt.Errorf("cannot sign block %s for slot %d: %w", hash, slot, err)
+	}
+
+	return nil
+}
+
+// CheckAndRecordVote records that the authority signs the vote for the given block in the given
+// set ID, round and subround. It returns ErrSlashable if the authority already signed another vote
+// in the subround.
+func (s *SlashingProtection) CheckAndRecordVote(authority crypto.PublicKey, setID, round uint64,
+	subround uint8, hash common.Hash, number uint32) error {
+	s.Lock()
+	defer s.Unlock()
+
+	key := signedVoteKey(authority.Encode(), setID, round, subround)
+	err := s.checkAndRecord(key, encodeSignedVote(hash, number))
+	if err != nil {
+		return fmt.Errorf("cannot sign vote for block %s in set ID %d, round %d and subround %d: %w",
+			hash, setID, round, subround, err)
+	}
+
+	return nil
+}
+
+// checkAndRecord records the value if there is no value for the key, it must be called with the lock held
+func (s *SlashingProtection) checkAndRecord(key, value []byte) error {
+	prev, err := s.db.Get(key)
+	switch {
+	case errors.Is(err, chaindb.ErrKeyNotFound):
+	case err != nil:
+		return err
+	case bytes.Equal(prev, value):
+		// signing the same block or vote again is not an equivocation
+		return nil
+	default:
+		return ErrSlashable
+	}
+
+	err = s.db.Put(key, value)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Flush()
+}
+
+// Export returns every block and vote recorded in the database
+func (s *SlashingProtection) Export() (*SlashingProtectionData, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	data := &SlashingProtectionData{
+		Blocks: []SignedBlock{},
+		Votes:  []SignedVote{},
+	}
+
+	// the iterator of the base database is used, since the table iterator does not support empty databases
+	iter := s.baseDB.NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, []byte(slashingProtectionPrefix)) {
+			continue
+		}
+
+		key, value := key[len(slashingProtectionPrefix):], iter.Value()
+
+		switch {
+		case bytes.HasPrefix(key, signedBlockPrefix):
+			key = key[len(signedBlockPrefix):]
+			if len(key) != authorityKeyLength+8 || len(value) != common.HashLength {
+				return nil, fmt.Errorf("invalid signed block record 0x%x", key)
+			}
+
+			data.Blocks = append(data.Blocks, SignedBlock{
+				Authority: common.BytesToHex(key[:authorityKeyLength]),
+				Slot:      binary.BigEndian.Uint64(key[authorityKeyLength:]),
+				Hash:      common.BytesToHash(value),
+			})
+		case bytes.HasPrefix(key, signedVotePrefix):
+			key = key[len(signedVotePrefix):]
+			if len(key) != authorityKeyLength+17 || len(value) != common.HashLength+4 {
+				return nil, fmt.Errorf("invalid signed vote record 0x%x", key)
+			}
+
+			data.Votes = append(data.Votes, SignedVote{
+				Authority: common.BytesToHex(key[:authorityKeyLength]),
+				SetID:     binary.BigEndian.Uint64(key[authorityKeyLength:]),
+				Round:     binary.BigEndian.Uint64(key[authorityKeyLength+8:]),
+				Subround:  key[authorityKeyLength+16],
+				Hash:      common.BytesToHash(value[:common.HashLength]),
+				Number:    binary.LittleEndian.Uint32(value[common.HashLength:]),
+			})
+		}
+	}
+
+	return data, nil
+}
+
+// Import records the blocks and votes exported from the database of another machine. A record
+// conflicting with a record of the database is skipped, the authority already equivocated.
+func (s *SlashingProtection) Import(data *SlashingProtectionData) error {
+	s.Lock()
+	defer s.Unlock()
+
+	batch := s.db.NewBatch()
+	records := make(map[string][]byte)
+
+	for _, block := range data.Blocks {
+		authority, err := decodeAuthority(block.Authority)
+		if err != nil {
+			return err
+		}
+
+		records[string(signedBlockKey(authority, block.Slot))] = block.Hash.ToBytes()
+	}
+
+	for _, vote := range data.Votes {
+		authority, err := decodeAuthority(vote.Authority)
+		if err != nil {
+			return err
+		}
+
+		key := signedVoteKey(authority, vote.SetID, vote.Round, vote.Subround)
+		records[string(key)] = encodeSignedVote(vote.Hash, vote.Number)
+	}
+
+	for key, value := range records {
+		prev, err := s.db.Get([]byte(key))
+		switch {
+		case errors.Is(err, chaindb.ErrKeyNotFound):
+		case err != nil:
+			return err
+		case !bytes.Equal(prev, value):
+			logger.Warnf("skipping imported record 0x%x conflicting with record in database", key)
+			continue
+		}
+
+		err = batch.Put([]byte(key), value)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := batch.Flush()
+	if err != nil {
+		return err
+	}
+
+	return s.db.Flush()
+}
+
+func decodeAuthority(authority string) ([]byte, error) {
+	enc, err := common.HexToBytes(authority)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authority %s: %w", authority, err)
+	}
+
+	if len(enc) != authorityKeyLength {
+		return nil, fmt.Errorf("invalid authority %s: length %d, expected %d", authority, len(enc), authorityKeyLength)
+	}
+
+	return enc, nil
+}
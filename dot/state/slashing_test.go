@@ -0,0 +1,139 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlashingProtection_CheckAndRecordBlock(t *testing.T) {
+	sp := NewSlashingProtection(NewInMemoryDB(t))
+
+	srKr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	alice, bob := srKr.Alice().Public(), srKr.Bob().Public()
+
+	hash := common.Hash{1}
+	err = sp.CheckAndRecordBlock(alice, 1, hash)
+	require.NoError(t, err)
+
+	// signing the same block again is allowed
+	err = sp.CheckAndRecordBlock(alice, 1, hash)
+	require.NoError(t, err)
+
+	err = sp.CheckAndRecordBlock(alice, 1, common.Hash{2})
+	require.ErrorIs(t, err, ErrSlashable)
+
+	err = sp.CheckAndRecordBlock(alice, 2, common.Hash{2})
+	require.NoError(t, err)
+
+	err = sp.CheckAndRecordBlock(bob, 1, common.Hash{2})
+	require.NoError(t, err)
+}
+
+func TestSlashingProtection_CheckAndRecordVote(t *testing.T) {
+	sp := NewSlashingProtection(NewInMemoryDB(t))
+	alice := kr.Alice().Public()
+
+	hash := common.Hash{1}
+	err := sp.CheckAndRecordVote(alice, 0, 1, 0, hash, 1)
+	require.NoError(t, err)
+
+	err = sp.CheckAndRecordVote(alice, 0, 1, 0, hash, 1)
+	require.NoError(t, err)
+
+	err = sp.CheckAndRecordVote(alice, 0, 1, 0, common.Hash{2}, 2)
+	require.ErrorIs(t, err, ErrSlashable)
+
+	err = sp.CheckAndRecordVote(alice, 0, 1, 0, hash, 2)
+	require.ErrorIs(t, err, ErrSlashable)
+
+	// a precommit, a vote in the next round and a vote in the next set are allowed
+	err = sp.CheckAndRecordVote(alice, 0, 1, 1, common.Hash{2}, 2)
+	require.NoError(t, err)
+
+	err = sp.CheckAndRecordVote(alice, 0, 2, 0, common.Hash{2}, 2)
+	require.NoError(t, err)
+
+	err = sp.CheckAndRecordVote(alice, 1, 1, 0, common.Hash{2}, 2)
+	require.NoError(t, err)
+}
+
+func TestSlashingProtection_Restart(t *testing.T) {
+	basepath := t.TempDir()
+	alice := kr.Alice().Public()
+
+	db, err := utils.SetupDatabase(basepath, false)
+	require.NoError(t, err)
+
+	err = NewSlashingProtection(db).CheckAndRecordVote(alice, 0, 1, 0, common.Hash{1}, 1)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db, err = utils.SetupDatabase(basepath, false)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	err = NewSlashingProtection(db).CheckAndRecordVote(alice, 0, 1, 0, common.Hash{2}, 2)
+	require.ErrorIs(t, err, ErrSlashable)
+}
+
+func TestSlashingProtection_ExportImport(t *testing.T) {
+	srKr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	babeKey, granKey := srKr.Alice().Public(), kr.Alice().Public()
+
+	sp := NewSlashingProtection(NewInMemoryDB(t))
+	err = sp.CheckAndRecordBlock(babeKey, 1, common.Hash{1})
+	require.NoError(t, err)
+	err = sp.CheckAndRecordBlock(babeKey, 2, common.Hash{2})
+	require.NoError(t, err)
+	err = sp.CheckAndRecordVote(granKey, 0, 1, 0, common.Hash{1}, 1)
+	require.NoError(t, err)
+	err = sp.CheckAndRecordVote(granKey, 0, 1, 1, common.Hash{1}, 1)
+	require.NoError(t, err)
+
+	data, err := sp.Export()
+	require.NoError(t, err)
+
+	expected := &SlashingProtectionData{
+		Blocks: []SignedBlock{
+			{Authority: babeKey.Hex(), Slot: 1, Hash: common.Hash{1}},
+			{Authority: babeKey.Hex(), Slot: 2, Hash: common.Hash{2}},
+		},
+		Votes: []SignedVote{
+			{Authority: granKey.Hex(), SetID: 0, Round: 1, Subround: 0, Hash: common.Hash{1}, Number: 1},
+			{Authority: granKey.Hex(), SetID: 0, Round: 1, Subround: 1, Hash: common.Hash{1}, Number: 1},
+		},
+	}
+	require.Equal(t, expected, data)
+
+	// the conflicting record of the other machine is kept
+	other := NewSlashingProtection(NewInMemoryDB(t))
+	err = other.CheckAndRecordBlock(babeKey, 2, common.Hash{3})
+	require.NoError(t, err)
+
+	err = other.Import(data)
+	require.NoError(t, err)
+
+	err = other.CheckAndRecordBlock(babeKey, 1, common.Hash{2})
+	require.ErrorIs(t, err, ErrSlashable)
+	err = other.CheckAndRecordBlock(babeKey, 2, common.Hash{3})
+	require.NoError(t, err)
+	err = other.CheckAndRecordVote(granKey, 0, 1, 1, common.Hash{2}, 2)
+	require.ErrorIs(t, err, ErrSlashable)
+
+	err = other.Import(&SlashingProtectionData{
+		Blocks: []SignedBlock{{Authority: "0x01", Slot: 1}},
+	})
+	require.Error(t, err)
+}
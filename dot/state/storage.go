@@ -67,6 +67,11 @@ func NewStorageState(db chaindb.Database, blockState *BlockState,
 		p = &pruner.ArchiveNode{}
 	}
 
+	if blockState != nil {
+		// the states of the blocks pinned in the block state are not pruned
+		blockState.statePruner = p
+	}
+
 	return &StorageState{
 		blockState:   blockState,
 		tries:        tries,
@@ -97,8 +102,15 @@ func (s *StorageState) StoreTrie(ts *rtstorage.TrieState, header *types.Header)
 			return fmt.Errorf("failed to get state trie inserted keys: block %s %w", header.Hash(), err)
 		}
 
+		insertedValueHashes, err := ts.GetInsertedValueHashes()
+		if err != nil {
+			return fmt.Errorf("failed to get state trie inserted values: block %s %w", header.Hash(), err)
+		}
+
 		deletedNodeHashes := ts.GetDeletedNodeHashes()
-		err = s.pruner.StoreJournalRecord(deletedNodeHashes, insertedNodeHashes, header.Hash(), header.Number.Int64())
+		deletedValueHashes := ts.GetDeletedValueHashes()
+		err = s.pruner.StoreJournalRecord(deletedNodeHashes, insertedNodeHashes,
+			deletedValueHashes, insertedValueHashes, header.Hash(), header.Number.Int64())
 		if err != nil {
 			return err
 		}
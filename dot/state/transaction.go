@@ -15,8 +15,9 @@
 
 // TransactionState represents the queue of transactions
 type TransactionState struct {
-	queue *transaction.PriorityQueue
-	pool  *transaction.Pool
+	queue  *transaction.PriorityQueue
+	pool   *transaction.Pool
+	banned *transaction.BanList
 
 	// notifierChannels are used to notify transaction status. It maps a channel to
 	// hex string of the extrinsic it is supposed to notify about.
@@ -31,6 +32,7 @@ func NewTransactionState(telemetry telemetry.Client) *TransactionState {
 	return &TransactionState{
 		queue:            transaction.NewPriorityQueue(),
 		pool:             transaction.NewPool(),
+		banned:           transaction.NewBanList(transaction.DefaultBanDuration),
 		notifierChannels: make(map[chan transaction.Status]string),
 		telemetry:        telemetry,
 	}
@@ -73,6 +75,35 @@ func (s *TransactionState) RemoveExtrinsicFromPool(ext types.Extrinsic) {
 	s.pool.Remove(ext.Hash())
 }
 
+// RemoveAndBan removes the transactions with the given hashes from the queue and pool
+// and temporarily bans them. It returns the hashes of the transactions that were removed.
+func (s *TransactionState) RemoveAndBan(hashes ...common.Hash) []common.Hash {
+	s.banned.Ban(hashes...)
+
+	removed := make([]common.Hash, 0, len(hashes))
+	for _, hash := range hashes {
+		vt := s.queue.RemoveByHash(hash)
+		if vt == nil {
+			vt = s.pool.Get(hash)
+		}
+		s.pool.Remove(hash)
+
+		if vt == nil {
+			continue
+		}
+
+		s.notifyStatus(vt.Extrinsic, transaction.Dropped)
+		removed = append(removed, hash)
+	}
+
+	return removed
+}
+
+// IsBanned returns true if the transaction hash is temporarily banned
+func (s *TransactionState) IsBanned(hash common.Hash) bool {
+	return s.banned.IsBanned(hash)
+}
+
 // AddToPool adds a transaction to the pool
 func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) common.Hash {
 	s.notifyStatus(vt.Extrinsic, transaction.Future)
@@ -0,0 +1,428 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+const transactionIndexPrefix = "txindex"
+
+var (
+	indexedTransactionPrefix       = []byte("itx") // indexedTransactionPrefix + hash -> indexed data
+	indexedTransactionRefsPrefix   = []byte("itr") // indexedTransactionRefsPrefix + hash -> number of referencing blocks
+	blockIndexedTransactionsPrefix = []byte("bit") // blockIndexedTransactionsPrefix + block hash -> indexed transactions
+	lastPrunedIndexedNumberKey     = []byte("lpi") // number of the last block whose indexed transactions were pruned
+
+	errIndexedTransactionNotFound = errors.New("indexed transaction not found")
+)
+
+// indexedTransactionKey = indexedTransactionPrefix + hash
+func indexedTransactionKey(hash common.Hash) []byte {
+	return append(indexedTransactionPrefix, hash.ToBytes()...)
+}
+
+// indexedTransactionRefsKey = indexedTransactionRefsPrefix + hash
+func indexedTransactionRefsKey(hash common.Hash) []byte {
+	return append(indexedTransactionRefsPrefix, hash.ToBytes()...)
+}
+
+// blockIndexedTransactionsKey = blockIndexedTransactionsPrefix + block hash
+func blockIndexedTransactionsKey(hash common.Hash) []byte {
+	return append(blockIndexedTransactionsPrefix, hash.ToBytes()...)
+}
+
+// SetIndexOperations sets the transaction index operations requested by the runtime
+// while executing the block with the given hash. The indexed transactions are written
+// to the database when the block is finalised.
+func (bs *BlockState) SetIndexOperations(hash common.Hash, ops []types.IndexOperation) {
+	if len(ops) == 0 {
+		return
+	}
+
+	bs.unfinalisedIndexOperations.Store(hash, ops)
+}
+
+func (bs *BlockState) getUnfinalisedIndexOperations(hash common.Hash) []types.IndexOperation {
+	ops, has := bs.unfinalisedIndexOperations.Load(hash)
+	if !has {
+		return nil
+	}
+
+	return ops.([]types.IndexOperation)
+}
+
+// GetIndexedBody returns the data indexed by the extrinsics of the block with the given hash,
+// in the order of the extrinsics. It returns nil if the block did not index any data, or if
+// its indexed data was pruned.
+func (bs *BlockState) GetIndexedBody(hash common.Hash) ([][]byte, error) {
+	block, has := bs.getUnfinalisedBlock(hash)
+	if has {
+		ops := sortIndexOperations(&block.Body, bs.getUnfinalisedIndexOperations(hash))
+		transactions := make([]types.IndexedTransaction, len(ops))
+		for i, op := range ops {
+			transactions[i] = types.IndexedTransaction{Extrinsic: op.Extrinsic, Hash: op.Hash}
+		}
+		return bs.getIndexedBody(transactions, true)
+	}
+
+	transactions, err := bs.getBlockIndexedTransactions(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return bs.getIndexedBody(transactions, false)
+}
+
+func (bs *BlockState) getIndexedBody(transactions []types.IndexedTransaction,
+	unfinalised bool) ([][]byte, error) {
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+
+	indexedBody := make([][]byte, len(transactions))
+	for i, transaction := range transactions {
+		data, err := bs.transactionIndexDB.Get(indexedTransactionKey(transaction.Hash))
+		if errors.Is(err, chaindb.ErrKeyNotFound) && unfinalised {
+			// the data indexed by an unfinalised block is only written upon finalisation
+			data, err = bs.findUnfinalisedIndexedData(transaction)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", errIndexedTransactionNotFound, transaction.Hash, err)
+		}
+
+		indexedBody[i] = data
+	}
+
+	return indexedBody, nil
+}
+
+// findUnfinalisedIndexedData finds the data of the given transaction among the data indexed
+// by the unfinalised blocks.
+func (bs *BlockState) findUnfinalisedIndexedData(transaction types.IndexedTransaction) (data []byte, err error) {
+	bs.unfinalisedIndexOperations.Range(func(key, value interface{}) bool {
+		block, has := bs.getUnfinalisedBlock(key.(common.Hash))
+		if !has {
+			return true
+		}
+
+		for _, op := range value.([]types.IndexOperation) {
+			if op.Renew || op.Hash != transaction.Hash {
+				continue
+			}
+
+			data = indexedData(&block.Body, op)
+			if data != nil {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	if data == nil {
+		return nil, chaindb.ErrKeyNotFound
+	}
+
+	return data, nil
+}
+
+func (bs *BlockState) getBlockIndexedTransactions(hash common.Hash) ([]types.IndexedTransaction, error) {
+	enc, err := bs.transactionIndexDB.Get(blockIndexedTransactionsKey(hash))
+	if errors.Is(err, chaindb.ErrKeyNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var transactions []types.IndexedTransaction
+	err = scale.Unmarshal(enc, &transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// sortIndexOperations returns the valid index operations ordered by extrinsic. An extrinsic
+// indexes at most one transaction, so the last operation of an extrinsic takes precedence.
+func sortIndexOperations(body *types.Body, ops []types.IndexOperation) []types.IndexOperation {
+	if len(ops) == 0 {
+		return nil
+	}
+
+	byExtrinsic := make(map[uint32]types.IndexOperation, len(ops))
+	for _, op := range ops {
+		byExtrinsic[op.Extrinsic] = op
+	}
+
+	var sorted []types.IndexOperation
+	for i := range *body {
+		op, has := byExtrinsic[uint32(i)]
+		if !has {
+			continue
+		}
+
+		if !op.Renew && indexedData(body, op) == nil {
+			logger.Warnf("invalid indexed transaction %s of size %d for extrinsic %d", op.Hash, op.Size, op.Extrinsic)
+			continue
+		}
+
+		sorted = append(sorted, op)
+	}
+
+	return sorted
+}
+
+// indexedData returns the data indexed by the given operation, which is the end of its extrinsic.
+// It returns nil if the size of the indexed data exceeds the size of the extrinsic.
+func indexedData(body *types.Body, op types.IndexOperation) []byte {
+	if int(op.Extrinsic) >= len(*body) {
+		return nil
+	}
+
+	extrinsic := (*body)[op.Extrinsic]
+	if int(op.Size) > len(extrinsic) {
+		return nil
+	}
+
+	return extrinsic[len(extrinsic)-int(op.Size):]
+}
+
+// transactionIndexBatch writes the transactions indexed by the finalised blocks through the
+// finalisation batch. The reference counts and the indexed transactions of the blocks written
+// to the batch are kept in memory, since they are not in the database until the batch is flushed.
+type transactionIndexBatch struct {
+	bs     *BlockState
+	batch  chaindb.Batch
+	refs   map[common.Hash]uint32
+	blocks map[common.Hash][]types.IndexedTransaction
+	hashes map[uint64]common.Hash
+}
+
+// newTransactionIndexBatch returns a transactionIndexBatch writing to the given batch of the block database
+func (bs *BlockState) newTransactionIndexBatch(batch chaindb.Batch) *transactionIndexBatch {
+	return &transactionIndexBatch{
+		bs:     bs,
+		batch:  batch,
+		refs:   make(map[common.Hash]uint32),
+		blocks: make(map[common.Hash][]types.IndexedTransaction),
+		hashes: make(map[uint64]common.Hash),
+	}
+}
+
+// the transaction index table is nested in the block table, so it can be written by its batch
+func (b *transactionIndexBatch) put(key, value []byte) error {
+	return b.batch.Put(append([]byte(transactionIndexPrefix), key...), value)
+}
+
+func (b *transactionIndexBatch) del(key []byte) error {
+	return b.batch.Del(append([]byte(transactionIndexPrefix), key...))
+}
+
+// store writes the transactions indexed by the finalised block with the given hash and number.
+// The data of a transaction is stored once, along with the number of blocks referencing it.
+func (b *transactionIndexBatch) store(hash common.Hash, number uint64, body *types.Body) error {
+	b.hashes[number] = hash
+
+	ops, has := b.bs.unfinalisedIndexOperations.LoadAndDelete(hash)
+	if !has {
+		return nil
+	}
+
+	var transactions []types.IndexedTransaction
+	for _, op := range sortIndexOperations(body, ops.([]types.IndexOperation)) {
+		var data []byte
+		if !op.Renew {
+			data = indexedData(body, op)
+		}
+
+		referenced, err := b.reference(op.Hash, data)
+		if err != nil {
+			return err
+		}
+
+		if !referenced {
+			logger.Warnf("cannot renew unknown indexed transaction %s for extrinsic %d", op.Hash, op.Extrinsic)
+			continue
+		}
+
+		transactions = append(transactions, types.IndexedTransaction{
+			Extrinsic: op.Extrinsic,
+			Hash:      op.Hash,
+		})
+	}
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	enc, err := scale.Marshal(transactions)
+	if err != nil {
+		return err
+	}
+
+	b.blocks[hash] = transactions
+	return b.put(blockIndexedTransactionsKey(hash), enc)
+}
+
+// reference increments the number of blocks referencing the indexed transaction with the
+// given hash, and stores its data if it is not stored yet. The data is nil when renewing
+// a transaction, in which case it returns false if the transaction is not stored.
+func (b *transactionIndexBatch) reference(hash common.Hash, data []byte) (referenced bool, err error) {
+	refs, err := b.getRefs(hash)
+	if err != nil {
+		return false, err
+	}
+
+	if refs == 0 {
+		if data == nil {
+			return false, nil
+		}
+
+		err = b.put(indexedTransactionKey(hash), data)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, b.setRefs(hash, refs+1)
+}
+
+func (b *transactionIndexBatch) getRefs(hash common.Hash) (uint32, error) {
+	refs, has := b.refs[hash]
+	if has {
+		return refs, nil
+	}
+
+	return b.bs.getIndexedTransactionRefs(hash)
+}
+
+func (b *transactionIndexBatch) setRefs(hash common.Hash, refs uint32) error {
+	b.refs[hash] = refs
+
+	if refs == 0 {
+		err := b.del(indexedTransactionKey(hash))
+		if err != nil {
+			return err
+		}
+
+		return b.del(indexedTransactionRefsKey(hash))
+	}
+
+	enc := make([]byte, 4)
+	binary.LittleEndian.PutUint32(enc, refs)
+	return b.put(indexedTransactionRefsKey(hash), enc)
+}
+
+// prune removes the references to the transactions indexed by the blocks which are more than
+// transactionIndexRetention blocks older than the finalised block with the given number, and
+// which were not pruned yet. The previous number is the number of the previously finalised
+// block, it bounds the blocks to prune if none were pruned before. The data of a transaction
+// is deleted once it is not referenced anymore.
+func (b *transactionIndexBatch) prune(previous, finalised uint64) error {
+	retention := uint64(b.bs.transactionIndexRetention)
+	if retention == 0 || finalised <= retention {
+		return nil
+	}
+
+	end := finalised - retention
+	start, err := b.bs.getLastPrunedIndexedNumber()
+	if errors.Is(err, chaindb.ErrKeyNotFound) {
+		start = 0
+		if previous > retention {
+			start = previous - retention
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if start >= end {
+		return nil
+	}
+
+	for number := start + 1; number <= end; number++ {
+		if err = b.pruneBlock(number); err != nil {
+			return fmt.Errorf("cannot prune block number %d: %w", number, err)
+		}
+	}
+
+	enc := make([]byte, 8)
+	binary.LittleEndian.PutUint64(enc, end)
+	return b.put(lastPrunedIndexedNumberKey, enc)
+}
+
+// pruneBlock removes the references to the transactions indexed by the finalised block with the given number
+func (b *transactionIndexBatch) pruneBlock(number uint64) error {
+	hash, has := b.hashes[number]
+	if !has {
+		enc, err := b.bs.db.Get(headerHashKey(number))
+		if errors.Is(err, chaindb.ErrKeyNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		hash = common.NewHash(enc)
+	}
+
+	transactions, has := b.blocks[hash]
+	if !has {
+		var err error
+		transactions, err = b.bs.getBlockIndexedTransactions(hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	for _, transaction := range transactions {
+		refs, err := b.getRefs(transaction.Hash)
+		if err != nil {
+			return err
+		}
+
+		if refs == 0 {
+			continue
+		}
+
+		err = b.setRefs(transaction.Hash, refs-1)
+		if err != nil {
+			return err
+		}
+	}
+
+	delete(b.blocks, hash)
+	return b.del(blockIndexedTransactionsKey(hash))
+}
+
+func (bs *BlockState) getIndexedTransactionRefs(hash common.Hash) (uint32, error) {
+	enc, err := bs.transactionIndexDB.Get(indexedTransactionRefsKey(hash))
+	if errors.Is(err, chaindb.ErrKeyNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(enc), nil
+}
+
+func (bs *BlockState) getLastPrunedIndexedNumber() (uint64, error) {
+	enc, err := bs.transactionIndexDB.Get(lastPrunedIndexedNumberKey)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(enc), nil
+}
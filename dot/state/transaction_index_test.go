@@ -0,0 +1,176 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIndexingBlock(t *testing.T, parent *types.Header, body types.Body) *types.Block {
+	t.Helper()
+
+	digest := types.NewDigest()
+	prd, err := types.NewBabeSecondaryPlainPreDigest(0, parent.Number.Uint64()+1).ToPreRuntimeDigest()
+	require.NoError(t, err)
+	err = digest.Add(*prd)
+	require.NoError(t, err)
+
+	return &types.Block{
+		Header: types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(0).Add(parent.Number, big.NewInt(1)),
+			Digest:     digest,
+		},
+		Body: body,
+	}
+}
+
+func TestBlockState_IndexedTransactions(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+	bs.transactionIndexRetention = 2
+
+	data := []byte("indexed data")
+	dataHash := common.MustBlake2bHash(data)
+
+	block1 := newTestIndexingBlock(t, testGenesisHeader, types.Body{
+		types.Extrinsic("inherent"),
+		types.Extrinsic(append([]byte("store:"), data...)),
+	})
+	bs.SetIndexOperations(block1.Header.Hash(), []types.IndexOperation{
+		{Extrinsic: 1, Hash: dataHash, Size: uint32(len(data))},
+	})
+	require.NoError(t, bs.AddBlock(block1))
+
+	block2 := newTestIndexingBlock(t, &block1.Header, types.Body{types.Extrinsic("renew")})
+	bs.SetIndexOperations(block2.Header.Hash(), []types.IndexOperation{
+		{Extrinsic: 0, Hash: dataHash, Renew: true},
+	})
+	require.NoError(t, bs.AddBlock(block2))
+
+	block3 := newTestIndexingBlock(t, &block2.Header, types.Body{})
+	require.NoError(t, bs.AddBlock(block3))
+	block4 := newTestIndexingBlock(t, &block3.Header, types.Body{})
+	require.NoError(t, bs.AddBlock(block4))
+
+	// the data indexed by unfinalised blocks is served from memory
+	for _, hash := range []common.Hash{block1.Header.Hash(), block2.Header.Hash()} {
+		indexedBody, err := bs.GetIndexedBody(hash)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{data}, indexedBody)
+	}
+
+	indexedBody, err := bs.GetIndexedBody(block3.Header.Hash())
+	require.NoError(t, err)
+	require.Nil(t, indexedBody)
+
+	err = bs.SetFinalisedHash(block2.Header.Hash(), 1, 0)
+	require.NoError(t, err)
+
+	refs, err := bs.getIndexedTransactionRefs(dataHash)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), refs)
+
+	for _, hash := range []common.Hash{block1.Header.Hash(), block2.Header.Hash()} {
+		indexedBody, err = bs.GetIndexedBody(hash)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{data}, indexedBody)
+	}
+
+	// finalising block 3 prunes the transactions indexed by block 1
+	err = bs.SetFinalisedHash(block3.Header.Hash(), 2, 0)
+	require.NoError(t, err)
+
+	indexedBody, err = bs.GetIndexedBody(block1.Header.Hash())
+	require.NoError(t, err)
+	require.Nil(t, indexedBody)
+
+	indexedBody, err = bs.GetIndexedBody(block2.Header.Hash())
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{data}, indexedBody)
+
+	// finalising block 4 prunes the transactions renewed by block 2
+	err = bs.SetFinalisedHash(block4.Header.Hash(), 3, 0)
+	require.NoError(t, err)
+
+	indexedBody, err = bs.GetIndexedBody(block2.Header.Hash())
+	require.NoError(t, err)
+	require.Nil(t, indexedBody)
+
+	has, err := bs.transactionIndexDB.Has(indexedTransactionKey(dataHash))
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
+func TestBlockState_IndexedTransactions_Invalid(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	block := newTestIndexingBlock(t, testGenesisHeader, types.Body{types.Extrinsic("short")})
+	bs.SetIndexOperations(block.Header.Hash(), []types.IndexOperation{
+		// the indexed data cannot be larger than the extrinsic
+		{Extrinsic: 0, Hash: common.Hash{1}, Size: 6},
+		// the extrinsic does not exist
+		{Extrinsic: 1, Hash: common.Hash{2}, Size: 1},
+	})
+	require.NoError(t, bs.AddBlock(block))
+
+	err := bs.SetFinalisedHash(block.Header.Hash(), 1, 0)
+	require.NoError(t, err)
+
+	indexedBody, err := bs.GetIndexedBody(block.Header.Hash())
+	require.NoError(t, err)
+	require.Nil(t, indexedBody)
+}
+
+func TestBlockState_IndexedTransactions_PruneSeveralBlocks(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+	bs.transactionIndexRetention = 1
+
+	data := []byte("indexed data")
+	dataHash := common.MustBlake2bHash(data)
+
+	block1 := newTestIndexingBlock(t, testGenesisHeader, types.Body{
+		types.Extrinsic(append([]byte("store:"), data...)),
+	})
+	bs.SetIndexOperations(block1.Header.Hash(), []types.IndexOperation{
+		{Extrinsic: 0, Hash: dataHash, Size: uint32(len(data))},
+	})
+	require.NoError(t, bs.AddBlock(block1))
+
+	block2 := newTestIndexingBlock(t, &block1.Header, types.Body{types.Extrinsic("renew")})
+	bs.SetIndexOperations(block2.Header.Hash(), []types.IndexOperation{
+		{Extrinsic: 0, Hash: dataHash, Renew: true},
+	})
+	require.NoError(t, bs.AddBlock(block2))
+
+	block3 := newTestIndexingBlock(t, &block2.Header, types.Body{})
+	require.NoError(t, bs.AddBlock(block3))
+
+	// finalising block 3 at once prunes the transactions indexed by blocks 1 and 2
+	err := bs.SetFinalisedHash(block3.Header.Hash(), 1, 0)
+	require.NoError(t, err)
+
+	for _, hash := range []common.Hash{block1.Header.Hash(), block2.Header.Hash()} {
+		indexedBody, err := bs.GetIndexedBody(hash)
+		require.NoError(t, err)
+		require.Nil(t, indexedBody)
+	}
+
+	refs, err := bs.getIndexedTransactionRefs(dataHash)
+	require.NoError(t, err)
+	require.Zero(t, refs)
+
+	has, err := bs.transactionIndexDB.Has(indexedTransactionKey(dataHash))
+	require.NoError(t, err)
+	require.False(t, has)
+
+	lastPruned, err := bs.getLastPrunedIndexedNumber()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), lastPruned)
+}
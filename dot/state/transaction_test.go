@@ -125,3 +125,40 @@ func TestTransactionState_NotifierChannels(t *testing.T) {
 	require.Equal(t, expectedFutureCount, futureCount)
 	require.Equal(t, expectedReadyCount, readyCount)
 }
+
+func TestTransactionState_RemoveAndBan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	ts := NewTransactionState(telemetryMock)
+
+	inQueue := &transaction.ValidTransaction{
+		Extrinsic: []byte("a"),
+		Validity:  &transaction.Validity{Priority: 1},
+	}
+	inPool := &transaction.ValidTransaction{
+		Extrinsic: []byte("b"),
+		Validity:  &transaction.Validity{Priority: 4},
+	}
+	kept := &transaction.ValidTransaction{
+		Extrinsic: []byte("c"),
+		Validity:  &transaction.Validity{Priority: 2},
+	}
+
+	queueHash, err := ts.Push(inQueue)
+	require.NoError(t, err)
+	poolHash := ts.AddToPool(inPool)
+	ts.AddToPool(kept)
+
+	unknownHash := common.Hash{1}
+	removed := ts.RemoveAndBan(queueHash, poolHash, unknownHash)
+	require.Equal(t, []common.Hash{queueHash, poolHash}, removed)
+
+	require.Equal(t, []*transaction.ValidTransaction{kept}, ts.Pending())
+
+	require.True(t, ts.IsBanned(queueHash))
+	require.True(t, ts.IsBanned(poolHash))
+	require.True(t, ts.IsBanned(unknownHash))
+	require.False(t, ts.IsBanned(kept.Extrinsic.Hash()))
+}
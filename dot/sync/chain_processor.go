@@ -8,10 +8,13 @@
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/telemetry"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/blocktree"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 )
 
 // ChainProcessor processes ready blocks.
@@ -40,12 +43,18 @@ type chainProcessor struct {
 	finalityGadget     FinalityGadget
 	blockImportHandler BlockImportHandler
 	telemetry          telemetry.Client
+
+	// digestHandler and headersOnly are used by light clients, which
+	// only import block headers without executing the blocks
+	digestHandler DigestHandler
+	headersOnly   bool
 }
 
 func newChainProcessor(readyBlocks *blockQueue, pendingBlocks DisjointBlockSet,
 	blockState BlockState, storageState StorageState,
 	transactionState TransactionState, babeVerifier BabeVerifier,
-	finalityGadget FinalityGadget, blockImportHandler BlockImportHandler, telemetry telemetry.Client) *chainProcessor {
+	finalityGadget FinalityGadget, blockImportHandler BlockImportHandler, telemetry telemetry.Client,
+	digestHandler DigestHandler, headersOnly bool) *chainProcessor {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &chainProcessor{
@@ -60,6 +69,8 @@ func newChainProcessor(readyBlocks *blockQueue, pendingBlocks DisjointBlockSet,
 		finalityGadget:     finalityGadget,
 		blockImportHandler: blockImportHandler,
 		telemetry:          telemetry,
+		digestHandler:      digestHandler,
+		headersOnly:        headersOnly,
 	}
 }
 
@@ -92,16 +103,25 @@ func (s *chainProcessor) processReadyBlocks() {
 			}
 
 			logger.Tracef("block data processing for block with hash %s failed: %s", bd.Hash, err)
-			if err := s.pendingBlocks.addBlock(&types.Block{
-				Header: *bd.Header,
-				Body:   *bd.Body,
-			}); err != nil {
+			if err := s.addPendingBlock(bd); err != nil {
 				logger.Debugf("failed to re-add block to pending blocks: %s", err)
 			}
 		}
 	}
 }
 
+// addPendingBlock adds the block data to the pending blocks set, without a body if it is unknown
+func (s *chainProcessor) addPendingBlock(bd *types.BlockData) error {
+	if bd.Body == nil {
+		return s.pendingBlocks.addHeader(bd.Header)
+	}
+
+	return s.pendingBlocks.addBlock(&types.Block{
+		Header: *bd.Header,
+		Body:   *bd.Body,
+	})
+}
+
 // processBlockData processes the BlockData from a BlockResponse and
 // eturns the index of the last BlockData it handled on success,
 // or the index of the block data that errored on failure.
@@ -110,6 +130,10 @@ func (s *chainProcessor) processBlockData(bd *types.BlockData) error {
 		return ErrNilBlockData
 	}
 
+	if s.headersOnly {
+		return s.processHeaderData(bd)
+	}
+
 	hasHeader, _ := s.blockState.HasHeader(bd.Hash)
 	hasBody, _ := s.blockState.HasBlockBody(bd.Hash)
 	if hasHeader && hasBody {
@@ -190,6 +214,59 @@ func (s *chainProcessor) processBlockData(bd *types.BlockData) error {
 	return nil
 }
 
+// processHeaderData processes the BlockData from a BlockResponse when only syncing headers.
+// The header is verified and imported without executing the block, and the
+// justification, if any, is verified and used to finalise the block.
+func (s *chainProcessor) processHeaderData(bd *types.BlockData) error {
+	if bd.Header == nil {
+		return nil
+	}
+
+	hasHeader, _ := s.blockState.HasHeader(bd.Hash)
+	if !hasHeader {
+		logger.Debugf("processing header with hash %s", bd.Hash)
+
+		if err := s.handleHeader(bd.Header); err != nil {
+			return err
+		}
+
+		if err := s.importHeader(bd.Header); err != nil {
+			logger.Debugf("failed to import header number %s: %s", bd.Header.Number, err)
+			return err
+		}
+	}
+
+	if bd.Justification != nil {
+		logger.Debugf("handling Justification for block number %s with hash %s...", bd.Number(), bd.Hash)
+		s.handleJustification(bd.Header, *bd.Justification)
+	}
+
+	return nil
+}
+
+// importHeader adds a verified header to the block state and handles its digests
+func (s *chainProcessor) importHeader(header *types.Header) error {
+	if _, err := s.blockState.GetHeader(header.ParentHash); err != nil {
+		return fmt.Errorf("%w: %s", errFailedToGetParent, err)
+	}
+
+	if err := s.blockState.AddHeader(header); err != nil {
+		return err
+	}
+
+	s.digestHandler.HandleDigests(header)
+
+	logger.Debugf("🔗 imported header number %s with hash %s", header.Number, header.Hash())
+
+	headerHash := header.Hash()
+	s.telemetry.SendMessage(telemetry.NewBlockImport(
+		&headerHash,
+		header.Number,
+		"NetworkInitialSync"))
+
+	return nil
+}
+
 // handleHeader handles headers included in BlockResponses
 func (s *chainProcessor) handleHeader(header *types.Header) error {
 	err := s.babeVerifier.VerifyBlock(header)
@@ -232,16 +309,26 @@ func (s *chainProcessor) handleBlock(block *types.Block) error {
 	}
 
 	hash := parent.Hash()
-	rt, err := s.blockState.GetRuntime(&hash)
+	pool, err := s.blockState.GetRuntimePool(&hash)
 	if err != nil {
 		return err
 	}
 
-	rt.SetContextStorage(ts)
+	err = pool.Call(ts, func(rt runtime.Instance) error {
+		err := checkInherents(rt, ts, block)
+		if err != nil {
+			return fmt.Errorf("failed to check inherents of block %d: %w", block.Header.Number, err)
+		}
+
+		_, err = rt.ExecuteBlock(block)
+		if err != nil {
+			return fmt.Errorf("failed to execute block %d: %w", block.Header.Number, err)
+		}
 
-	_, err = rt.ExecuteBlock(block)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to execute block %d: %w", block.Header.Number, err)
+		return err
 	}
 
 	if err = s.blockImportHandler.HandleBlockImport(block, ts); err != nil {
@@ -259,6 +346,51 @@ func (s *chainProcessor) handleBlock(block *types.Block) error {
 	return nil
 }
 
+// checkInherents checks the inherents of the given block against the current time and the slot of
+// the block. It returns an error if the runtime reports a fatal inherent error, ie. if the block is invalid.
+func checkInherents(rt runtime.Instance, ts *rtstorage.TrieState, block *types.Block) error {
+	slot, err := types.GetSlotFromHeader(&block.Header)
+	if err != nil {
+		return err
+	}
+
+	idata := types.NewInherentsData()
+	err = idata.SetInt64Inherent(types.Timstap0, uint64(time.Now().UnixMilli()))
+	if err != nil {
+		return err
+	}
+
+	err = idata.SetInt64Inherent(types.Babeslot, slot)
+	if err != nil {
+		return err
+	}
+
+	// the inherents are checked on a copy of the state, so that the check
+	// doesn't change the state the block is executed on
+	checkState, err := rtstorage.NewTrieState(ts.Snapshot())
+	if err != nil {
+		return err
+	}
+
+	rt.SetContextStorage(checkState)
+	defer rt.SetContextStorage(ts)
+
+	result, err := rt.CheckInherents(block, idata)
+	if err != nil {
+		return err
+	}
+
+	if result.FatalError {
+		return fmt.Errorf("%w: %s", runtime.ErrFatalInherentError, result)
+	}
+
+	if !result.Okay {
+		logger.Debugf("non fatal inherent errors in block %s: %s", block.Header.Hash(), result)
+	}
+
+	return nil
+}
+
 func (s *chainProcessor) handleJustification(header *types.Header, justification []byte) {
 	if len(justification) == 0 || header == nil {
 		return
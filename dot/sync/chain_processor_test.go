@@ -11,10 +11,17 @@
 
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/state"
+	syncmocks "github.com/ChainSafe/gossamer/dot/sync/mocks"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/common/variadic"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	runtimemocks "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -249,3 +256,166 @@ func TestChainProcessor_processReadyBlocks_errFailedToGetParent(t *testing.T) {
 	time.Sleep(time.Millisecond * 100)
 	require.True(t, processor.pendingBlocks.hasBlock(header.Hash()))
 }
+
+func TestChainProcessor_processBlockData_headersOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any())
+
+	parent := &types.Header{
+		Number: big.NewInt(0),
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(1),
+	}
+	just := []byte("testjustification")
+
+	blockState := new(syncmocks.BlockState)
+	blockState.On("HasHeader", header.Hash()).Return(false, nil)
+	blockState.On("GetHeader", parent.Hash()).Return(parent, nil)
+	blockState.On("AddHeader", header).Return(nil)
+	blockState.On("SetJustification", header.Hash(), just).Return(nil)
+
+	babeVerifier := new(syncmocks.BabeVerifier)
+	babeVerifier.On("VerifyBlock", header).Return(nil)
+
+	finalityGadget := new(syncmocks.FinalityGadget)
+	finalityGadget.On("VerifyBlockJustification", header.Hash(), just).Return(nil)
+
+	digestHandler := new(syncmocks.DigestHandler)
+	digestHandler.On("HandleDigests", header)
+
+	processor := newChainProcessor(newBlockQueue(1), newDisjointBlockSet(1),
+		blockState, nil, nil, babeVerifier, finalityGadget, nil, telemetryMock,
+		digestHandler, true)
+
+	err := processor.processBlockData(&types.BlockData{
+		Hash:          header.Hash(),
+		Header:        header,
+		Justification: &just,
+	})
+	require.NoError(t, err)
+
+	blockState.AssertExpectations(t)
+	babeVerifier.AssertExpectations(t)
+	finalityGadget.AssertExpectations(t)
+	digestHandler.AssertExpectations(t)
+}
+
+func TestChainProcessor_processBlockData_headersOnly_unknownParent(t *testing.T) {
+	header := &types.Header{
+		ParentHash: common.Hash{0xff},
+		Number:     big.NewInt(1),
+	}
+
+	blockState := new(syncmocks.BlockState)
+	blockState.On("HasHeader", header.Hash()).Return(false, nil)
+	blockState.On("GetHeader", header.ParentHash).Return(nil, errors.New("not found"))
+
+	babeVerifier := new(syncmocks.BabeVerifier)
+	babeVerifier.On("VerifyBlock", header).Return(nil)
+
+	processor := newChainProcessor(newBlockQueue(1), newDisjointBlockSet(1),
+		blockState, nil, nil, babeVerifier, nil, nil, nil,
+		new(syncmocks.DigestHandler), true)
+
+	err := processor.processBlockData(&types.BlockData{
+		Hash:   header.Hash(),
+		Header: header,
+	})
+	require.ErrorIs(t, err, errFailedToGetParent)
+}
+
+func TestChainProcessor_handleBlock_CheckInherents(t *testing.T) {
+	timestampErr := types.InherentError{Key: [8]byte{'t', 'i', 'm', 's', 't', 'a', 'p', '0'}, Error: []byte{1}}
+
+	testCases := map[string]struct {
+		result      *types.CheckInherentsResult
+		errSentinel error
+	}{
+		"okay": {
+			result: &types.CheckInherentsResult{Okay: true},
+		},
+		"non fatal error": {
+			result: &types.CheckInherentsResult{Errors: []types.InherentError{timestampErr}},
+		},
+		"fatal error": {
+			result: &types.CheckInherentsResult{
+				FatalError: true,
+				Errors:     []types.InherentError{timestampErr},
+			},
+			errSentinel: runtime.ErrFatalInherentError,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			ts, err := rtstorage.NewTrieState(nil)
+			require.NoError(t, err)
+			parent := &types.Header{
+				Number:    big.NewInt(0),
+				StateRoot: ts.MustRoot(),
+			}
+
+			preDigest, err := types.NewBabeSecondaryPlainPreDigest(0, 1).ToPreRuntimeDigest()
+			require.NoError(t, err)
+			digest := types.NewDigest()
+			require.NoError(t, digest.Add(*preDigest))
+
+			block := &types.Block{
+				Header: types.Header{
+					ParentHash: parent.Hash(),
+					Number:     big.NewInt(1),
+					Digest:     digest,
+				},
+				Body: types.Body{},
+			}
+
+			instance := new(runtimemocks.Instance)
+			instance.On("SetContextStorage", mock.Anything)
+			instance.On("CheckInherents", block, mock.Anything).Return(testCase.result, nil).Once()
+			pool, err := runtime.NewInstancePool(instance, 1)
+			require.NoError(t, err)
+
+			parentHash := parent.Hash()
+			blockState := new(syncmocks.BlockState)
+			blockState.On("GetHeader", parentHash).Return(parent, nil)
+			blockState.On("GetRuntimePool", &parentHash).Return(pool, nil)
+
+			storageState := new(syncmocks.StorageState)
+			storageState.On("Lock")
+			storageState.On("Unlock")
+			storageState.On("TrieState", &parent.StateRoot).Return(ts, nil)
+
+			blockImportHandler := new(syncmocks.BlockImportHandler)
+			if testCase.errSentinel == nil {
+				instance.On("ExecuteBlock", block).Return(nil, nil).Once()
+				blockImportHandler.On("HandleBlockImport", block, ts).Return(nil).Once()
+			}
+
+			telemetryMock := NewMockClient(ctrl)
+			if testCase.errSentinel == nil {
+				telemetryMock.EXPECT().SendMessage(gomock.Any())
+			}
+
+			processor := newChainProcessor(newBlockQueue(1), newDisjointBlockSet(1),
+				blockState, storageState, nil, nil, nil, blockImportHandler, telemetryMock,
+				nil, false)
+
+			err = processor.handleBlock(block)
+			if testCase.errSentinel != nil {
+				require.ErrorIs(t, err, testCase.errSentinel)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// the block is only executed and imported if its inherents have no fatal error
+			instance.AssertExpectations(t)
+			blockImportHandler.AssertExpectations(t)
+		})
+	}
+}
@@ -161,6 +161,9 @@ type chainSync struct {
 	minPeers         int
 	maxWorkerRetries uint16
 	slotDuration     time.Duration
+
+	// headersOnly is set when only block headers and justifications are requested from peers
+	headersOnly bool
 }
 
 type chainSyncConfig struct {
@@ -170,6 +173,7 @@ type chainSyncConfig struct {
 	pendingBlocks      DisjointBlockSet
 	minPeers, maxPeers int
 	slotDuration       time.Duration
+	headersOnly        bool
 }
 
 func newChainSync(cfg *chainSyncConfig) *chainSync {
@@ -194,6 +198,7 @@ func newChainSync(cfg *chainSyncConfig) *chainSync {
 		minPeers:         cfg.minPeers,
 		maxWorkerRetries: uint16(cfg.maxPeers),
 		slotDuration:     cfg.slotDuration,
+		headersOnly:      cfg.headersOnly,
 	}
 }
 
@@ -540,7 +545,7 @@ func (cs *chainSync) setMode(mode chainSyncState) {
 	case bootstrap:
 		cs.handler = newBootstrapSyncer(cs.blockState)
 	case tip:
-		cs.handler = newTipSyncer(cs.blockState, cs.pendingBlocks, cs.readyBlocks, cs.handleReadyBlock)
+		cs.handler = newTipSyncer(cs.blockState, cs.pendingBlocks, cs.readyBlocks, cs.handleReadyBlock, cs.headersOnly)
 	}
 
 	cs.state = mode
@@ -652,6 +657,12 @@ func (cs *chainSync) dispatchWorker(w *worker) {
 		return
 	}
 
+	if cs.headersOnly {
+		for _, req := range reqs {
+			req.RequestedData &^= network.RequestedDataBody
+		}
+	}
+
 	for _, req := range reqs {
 		// TODO: if we find a good peer, do sync with them, right now it re-selects a peer each time (#1399)
 		if err := cs.doSync(req, w.peersTried); err != nil {
@@ -865,10 +876,7 @@ func (cs *chainSync) validateResponse(req *network.BlockRequestMessage,
 			}
 
 			// parent unknown, add to pending blocks
-			if err := cs.pendingBlocks.addBlock(&types.Block{
-				Header: *curr,
-				Body:   *bd.Body,
-			}); err != nil {
+			if err := cs.addPendingBlock(curr, bd.Body); err != nil {
 				return err
 			}
 
@@ -886,10 +894,7 @@ func (cs *chainSync) validateResponse(req *network.BlockRequestMessage,
 		if !prev.Hash().Equal(curr.ParentHash) || curr.Number.Cmp(big.NewInt(0).Add(prev.Number, big.NewInt(1))) != 0 {
 			// the response is missing some blocks, place blocks from curr onwards into pending blocks set
 			for _, bd := range resp.BlockData[i:] {
-				if err := cs.pendingBlocks.addBlock(&types.Block{
-					Header: *curr,
-					Body:   *bd.Body,
-				}); err != nil {
+				if err := cs.addPendingBlock(curr, bd.Body); err != nil {
 					return err
 				}
 
@@ -908,6 +913,19 @@ func (cs *chainSync) validateResponse(req *network.BlockRequestMessage,
 	return nil
 }
 
+// addPendingBlock adds a block to the pending blocks set. Only its header is added
+// if the block body is unknown, which is always the case when only syncing headers.
+func (cs *chainSync) addPendingBlock(header *types.Header, body *types.Body) error {
+	if body == nil {
+		return cs.pendingBlocks.addHeader(header)
+	}
+
+	return cs.pendingBlocks.addBlock(&types.Block{
+		Header: *header,
+		Body:   *body,
+	})
+}
+
 // validateBlockData checks that the expected fields are in the block data
 func (cs *chainSync) validateBlockData(req *network.BlockRequestMessage, bd *types.BlockData, p peer.ID) error {
 	if bd == nil {
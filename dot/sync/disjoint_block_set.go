@@ -105,6 +105,10 @@ type disjointBlockSet struct {
 	parentToChildren map[common.Hash]map[common.Hash]struct{}
 
 	timeNow func() time.Time
+
+	// headersOnly is set when only block headers are synced, in which case
+	// a pending block is ready to be processed once its header is known
+	headersOnly bool
 }
 
 func newDisjointBlockSet(limit int) *disjointBlockSet {
@@ -307,7 +311,7 @@ func (s *disjointBlockSet) getReadyDescendants(curr common.Hash, ready []*types.
 
 	for c := range children {
 		b := s.getBlock(c)
-		if b == nil || b.header == nil || b.body == nil {
+		if b == nil || b.header == nil || (b.body == nil && !s.headersOnly) {
 			continue
 		}
 
@@ -225,3 +225,33 @@ func TestDisjointBlockSet_ClearBlocks(t *testing.T) {
 	_, has := s.blocks[testHashB]
 	require.True(t, has)
 }
+
+func TestDisjointBlockSet_getReadyDescendants_headersOnly(t *testing.T) {
+	s := newDisjointBlockSet(pendingBlocksLimit)
+	s.headersOnly = true
+
+	header1 := &types.Header{
+		Number: big.NewInt(1),
+	}
+
+	header2 := &types.Header{
+		ParentHash: header1.Hash(),
+		Number:     big.NewInt(2),
+	}
+	s.addHeader(header2)
+
+	header3 := &types.Header{
+		ParentHash: header2.Hash(),
+		Number:     big.NewInt(3),
+	}
+	s.addHeader(header3)
+
+	ready := []*types.BlockData{{
+		Hash:   header1.Hash(),
+		Header: header1,
+	}}
+	ready = s.getReadyDescendants(header1.Hash(), ready)
+	require.Equal(t, 3, len(ready))
+	require.Equal(t, header2.Hash(), ready[1].Hash)
+	require.Equal(t, header3.Hash(), ready[2].Hash)
+}
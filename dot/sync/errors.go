@@ -16,6 +16,8 @@
 	errNilNetwork            = errors.New("cannot have nil Network")
 	errNilFinalityGadget     = errors.New("cannot have nil FinalityGadget")
 	errNilTransactionState   = errors.New("cannot have nil TransactionState")
+	errNilDigestHandler      = errors.New("cannot have nil DigestHandler")
+	errNilEpochState         = errors.New("cannot have nil EpochState")
 
 	// ErrNilBlockData is returned when trying to process a BlockResponseMessage with nil BlockData
 	ErrNilBlockData = errors.New("got nil BlockData")
@@ -50,6 +52,10 @@
 	errNilDescendantNumber          = errors.New("descendant number is nil")
 	errStartAndEndMismatch          = errors.New("request start and end hash are not on the same chain")
 	errFailedToGetDescendant        = errors.New("failed to find descendant block")
+
+	// warpSyncer errors
+	errWarpSyncFailed          = errors.New("no peer answered warp sync requests")
+	errStateResponseNoProgress = errors.New("incomplete state response does not advance past the start key")
 )
 
 // ErrNilChannel is returned if a channel is nil
@@ -24,10 +24,12 @@ type BlockState interface {
 	BestBlockHeader() (*types.Header, error)
 	BestBlockNumber() (*big.Int, error)
 	AddBlock(*types.Block) error
+	AddHeader(*types.Header) error
 	CompareAndSetBlockData(bd *types.BlockData) error
 	GetBlockByNumber(*big.Int) (*types.Block, error)
 	HasBlockBody(hash common.Hash) (bool, error)
 	GetBlockBody(common.Hash) (*types.Body, error)
+	GetIndexedBody(common.Hash) ([][]byte, error)
 	SetHeader(*types.Header) error
 	GetHeader(common.Hash) (*types.Header, error)
 	HasHeader(hash common.Hash) (bool, error)
@@ -41,18 +43,24 @@ type BlockState interface {
 	GetHashByNumber(*big.Int) (common.Hash, error)
 	GetBlockByHash(common.Hash) (*types.Block, error)
 	GetRuntime(*common.Hash) (runtime.Instance, error)
+	GetRuntimePool(*common.Hash) (*runtime.InstancePool, error)
 	StoreRuntime(common.Hash, runtime.Instance)
 	GetHighestFinalisedHeader() (*types.Header, error)
 	GetFinalisedNotifierChannel() chan *types.FinalisationInfo
 	GetHeaderByNumber(num *big.Int) (*types.Header, error)
 	GetAllBlocksAtNumber(num *big.Int) ([]common.Hash, error)
 	IsDescendantOf(parent, child common.Hash) (bool, error)
+	ImportFinalisedHeader(header *types.Header, round, setID uint64) error
+	HandleRuntimeChanges(newState *rtstorage.TrieState, in runtime.Instance, bHash common.Hash) error
 }
 
+//go:generate mockery --name StorageState --structname StorageState --case underscore --keeptree
+
 // StorageState is the interface for the storage state
 type StorageState interface {
 	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
 	LoadCodeHash(*common.Hash) (common.Hash, error)
+	StoreTrie(*rtstorage.TrieState, *types.Header) error
 	sync.Locker
 }
 
@@ -79,6 +87,20 @@ type BabeVerifier interface {
 // FinalityGadget implements justification verification functionality
 type FinalityGadget interface {
 	VerifyBlockJustification(common.Hash, []byte) error
+
+	// VerifyWarpSyncProof verifies the warp sync proof and stores the authority set changes
+	// it proves. It returns the round and set ID of the justification of the last fragment.
+	VerifyWarpSyncProof(proof *network.WarpSyncProof) (round, setID uint64, err error)
+}
+
+//go:generate mockery --name EpochState --structname EpochState --case underscore --keeptree
+
+// EpochState is the interface for the epoch state, it is used to store the BABE
+// epoch data of the state downloaded when warp syncing
+type EpochState interface {
+	SetFirstSlot(slot uint64) error
+	SetEpochData(epoch uint64, info *types.EpochData) error
+	SetCurrentEpoch(epoch uint64) error
 }
 
 //go:generate mockery --name BlockImportHandler --structname BlockImportHandler --case underscore --keeptree
@@ -88,6 +110,14 @@ type BlockImportHandler interface {
 	HandleBlockImport(block *types.Block, state *rtstorage.TrieState) error
 }
 
+//go:generate mockery --name DigestHandler --structname DigestHandler --case underscore --keeptree
+
+// DigestHandler is the interface for the consensus digest handler, it is used
+// to handle the digests of imported headers when only syncing headers
+type DigestHandler interface {
+	HandleDigests(header *types.Header)
+}
+
 //go:generate mockery --name Network --structname Network --case underscore --keeptree
 
 // Network is the interface for the network
@@ -97,6 +127,12 @@ type Network interface {
 	// it is returned, otherwise an error is returned.
 	DoBlockRequest(to peer.ID, req *network.BlockRequestMessage) (*network.BlockResponseMessage, error)
 
+	// DoWarpSyncRequest sends a warp sync request to the given peer and returns its proof.
+	DoWarpSyncRequest(to peer.ID, req *network.WarpProofRequest) (*network.WarpSyncProof, error)
+
+	// DoStateRequest sends a state request to the given peer and returns its response.
+	DoStateRequest(to peer.ID, req *network.StateRequest) (*network.StateResponse, error)
+
 	// Peers returns a list of currently connected peers
 	Peers() []common.PeerInfo
 
@@ -420,5 +420,14 @@ func (s *Service) getBlockData(hash common.Hash, requestedData byte) (*types.Blo
 		}
 	}
 
+	if (requestedData&network.RequestedDataIndexedBody)>>5 == 1 {
+		retData, err := s.blockState.GetIndexedBody(hash)
+		if err != nil {
+			logger.Debugf("failed to get indexed body for block with hash %s: %s", hash, err)
+		} else if retData != nil {
+			blockData.IndexedBody = &retData
+		}
+	}
+
 	return blockData, nil
 }
@@ -10,6 +10,8 @@
 
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
 
+	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+
 	types "github.com/ChainSafe/gossamer/dot/types"
 )
 
@@ -32,6 +34,20 @@ func (_m *BlockState) AddBlock(_a0 *types.Block) error {
 	return r0
 }
 
+// AddHeader provides a mock function with given fields: _a0
+func (_m *BlockState) AddHeader(_a0 *types.Header) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Header) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddBlockToBlockTree provides a mock function with given fields: block
 func (_m *BlockState) AddBlockToBlockTree(block *types.Block) error {
 	ret := _m.Called(block)
@@ -322,6 +338,29 @@ func (_m *BlockState) GetHighestFinalisedHeader() (*types.Header, error) {
 	return r0, r1
 }
 
+// GetIndexedBody provides a mock function with given fields: _a0
+func (_m *BlockState) GetIndexedBody(_a0 common.Hash) ([][]byte, error) {
+	ret := _m.Called(_a0)
+
+	var r0 [][]byte
+	if rf, ok := ret.Get(0).(func(common.Hash) [][]byte); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetJustification provides a mock function with given fields: _a0
 func (_m *BlockState) GetJustification(_a0 common.Hash) ([]byte, error) {
 	ret := _m.Called(_a0)
@@ -414,6 +453,43 @@ func (_m *BlockState) GetRuntime(_a0 *common.Hash) (runtime.Instance, error) {
 	return r0, r1
 }
 
+// GetRuntimePool provides a mock function with given fields: _a0
+func (_m *BlockState) GetRuntimePool(_a0 *common.Hash) (*runtime.InstancePool, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *runtime.InstancePool
+	if rf, ok := ret.Get(0).(func(*common.Hash) *runtime.InstancePool); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*runtime.InstancePool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*common.Hash) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HandleRuntimeChanges provides a mock function with given fields: newState, in, bHash
+func (_m *BlockState) HandleRuntimeChanges(newState *storage.TrieState, in runtime.Instance, bHash common.Hash) error {
+	ret := _m.Called(newState, in, bHash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*storage.TrieState, runtime.Instance, common.Hash) error); ok {
+		r0 = rf(newState, in, bHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // HasBlockBody provides a mock function with given fields: hash
 func (_m *BlockState) HasBlockBody(hash common.Hash) (bool, error) {
 	ret := _m.Called(hash)
@@ -456,6 +532,20 @@ func (_m *BlockState) HasHeader(hash common.Hash) (bool, error) {
 	return r0, r1
 }
 
+// ImportFinalisedHeader provides a mock function with given fields: header, round, setID
+func (_m *BlockState) ImportFinalisedHeader(header *types.Header, round uint64, setID uint64) error {
+	ret := _m.Called(header, round, setID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Header, uint64, uint64) error); ok {
+		r0 = rf(header, round, setID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // IsDescendantOf provides a mock function with given fields: parent, child
 func (_m *BlockState) IsDescendantOf(parent common.Hash, child common.Hash) (bool, error) {
 	ret := _m.Called(parent, child)
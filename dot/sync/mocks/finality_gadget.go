@@ -5,6 +5,8 @@
 import (
 	common "github.com/ChainSafe/gossamer/lib/common"
 	mock "github.com/stretchr/testify/mock"
+
+	network "github.com/ChainSafe/gossamer/dot/network"
 )
 
 // FinalityGadget is an autogenerated mock type for the FinalityGadget type
@@ -25,3 +27,31 @@ func (_m *FinalityGadget) VerifyBlockJustification(_a0 common.Hash, _a1 []byte)
 
 	return r0
 }
+
+// VerifyWarpSyncProof provides a mock function with given fields: proof
+func (_m *FinalityGadget) VerifyWarpSyncProof(proof *network.WarpSyncProof) (uint64, uint64, error) {
+	ret := _m.Called(proof)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(*network.WarpSyncProof) uint64); ok {
+		r0 = rf(proof)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 uint64
+	if rf, ok := ret.Get(1).(func(*network.WarpSyncProof) uint64); ok {
+		r1 = rf(proof)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*network.WarpSyncProof) error); ok {
+		r2 = rf(proof)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
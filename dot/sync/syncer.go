@@ -4,6 +4,7 @@
 package sync
 
 import (
+	"errors"
 	"math/big"
 	"time"
 
@@ -23,6 +24,7 @@ type Service struct {
 	chainSync      ChainSync
 	chainProcessor ChainProcessor
 	network        Network
+	warpSyncer     *warpSyncer
 }
 
 // Config is the configuration for the sync Service.
@@ -38,6 +40,17 @@ type Config struct {
 	MinPeers, MaxPeers int
 	SlotDuration       time.Duration
 	Telemetry          telemetry.Client
+
+	// LightClient is set for light clients, which only sync and verify block headers and
+	// justifications. The DigestHandler is then used instead of the BlockImportHandler.
+	LightClient   bool
+	DigestHandler DigestHandler
+
+	// WarpSync is set to sync a node which only has the genesis block to the latest finalised
+	// block of the network, using GRANDPA warp sync proofs and downloading the state of the block.
+	// The EpochState is then used to store the BABE epoch data of the downloaded state.
+	WarpSync   bool
+	EpochState EpochState
 }
 
 // NewService returns a new *sync.Service
@@ -58,22 +71,33 @@ func NewService(cfg *Config) (*Service, error) {
 		return nil, errNilFinalityGadget
 	}
 
-	if cfg.TransactionState == nil {
-		return nil, errNilTransactionState
-	}
-
 	if cfg.BabeVerifier == nil {
 		return nil, errNilVerifier
 	}
 
-	if cfg.BlockImportHandler == nil {
-		return nil, errNilBlockImportHandler
+	if cfg.LightClient {
+		if cfg.DigestHandler == nil {
+			return nil, errNilDigestHandler
+		}
+	} else {
+		if cfg.TransactionState == nil {
+			return nil, errNilTransactionState
+		}
+
+		if cfg.BlockImportHandler == nil {
+			return nil, errNilBlockImportHandler
+		}
+	}
+
+	if cfg.WarpSync && cfg.EpochState == nil {
+		return nil, errNilEpochState
 	}
 
 	logger.Patch(log.SetLevel(cfg.LogLvl))
 
 	readyBlocks := newBlockQueue(maxResponseSize * 30)
 	pendingBlocks := newDisjointBlockSet(pendingBlocksLimit)
+	pendingBlocks.headersOnly = cfg.LightClient
 
 	csCfg := &chainSyncConfig{
 		bs:            cfg.BlockState,
@@ -83,30 +107,60 @@ func NewService(cfg *Config) (*Service, error) {
 		minPeers:      cfg.MinPeers,
 		maxPeers:      cfg.MaxPeers,
 		slotDuration:  cfg.SlotDuration,
+		headersOnly:   cfg.LightClient,
 	}
 
 	chainSync := newChainSync(csCfg)
 	chainProcessor := newChainProcessor(readyBlocks, pendingBlocks,
 		cfg.BlockState, cfg.StorageState, cfg.TransactionState,
-		cfg.BabeVerifier, cfg.FinalityGadget, cfg.BlockImportHandler, cfg.Telemetry)
+		cfg.BabeVerifier, cfg.FinalityGadget, cfg.BlockImportHandler, cfg.Telemetry,
+		cfg.DigestHandler, cfg.LightClient)
+
+	var ws *warpSyncer
+	if cfg.WarpSync {
+		ws = newWarpSyncer(cfg.BlockState, cfg.StorageState, cfg.EpochState, cfg.FinalityGadget, cfg.Network)
+	}
 
 	return &Service{
 		blockState:     cfg.BlockState,
 		chainSync:      chainSync,
 		chainProcessor: chainProcessor,
 		network:        cfg.Network,
+		warpSyncer:     ws,
 	}, nil
 }
 
-// Start begins the chainSync and chainProcessor modules. It begins syncing in bootstrap mode
+// Start begins the chainSync and chainProcessor modules. It begins syncing in bootstrap mode.
+// If warp sync is enabled, they are started once the node has been warp synced.
 func (s *Service) Start() error {
-	go s.chainSync.start()
-	go s.chainProcessor.start()
+	if s.warpSyncer == nil {
+		go s.chainSync.start()
+		go s.chainProcessor.start()
+		return nil
+	}
+
+	go func() {
+		err := s.warpSyncer.sync()
+		if errors.Is(err, ErrServiceStopped) {
+			return
+		}
+
+		if err != nil {
+			logger.Errorf("failed to warp sync, falling back to full sync: %s", err)
+		}
+
+		go s.chainSync.start()
+		go s.chainProcessor.start()
+	}()
 	return nil
 }
 
 // Stop stops the chainSync and chainProcessor modules
 func (s *Service) Stop() error {
+	if s.warpSyncer != nil {
+		s.warpSyncer.stop()
+	}
+
 	s.chainSync.stop()
 	s.chainProcessor.stop()
 	return nil
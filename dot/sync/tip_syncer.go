@@ -22,15 +22,20 @@ type tipSyncer struct {
 	pendingBlocks    DisjointBlockSet
 	readyBlocks      *blockQueue
 	handleReadyBlock handleReadyBlockFunc
+
+	// headersOnly is set when only block headers are synced, in which case
+	// block bodies are never requested
+	headersOnly bool
 }
 
 func newTipSyncer(blockState BlockState, pendingBlocks DisjointBlockSet, readyBlocks *blockQueue,
-	handleReadyBlock handleReadyBlockFunc) *tipSyncer {
+	handleReadyBlock handleReadyBlockFunc, headersOnly bool) *tipSyncer {
 	return &tipSyncer{
 		blockState:       blockState,
 		pendingBlocks:    pendingBlocks,
 		readyBlocks:      readyBlocks,
 		handleReadyBlock: handleReadyBlock,
+		headersOnly:      headersOnly,
 	}
 }
 
@@ -194,7 +199,7 @@ func (s *tipSyncer) handleTick() ([]*worker, error) {
 			continue
 		}
 
-		if block.body == nil {
+		if block.body == nil && !s.headersOnly {
 			// case 2
 			workers = append(workers, &worker{
 				startHash:    block.hash,
@@ -34,7 +34,7 @@ func newTestTipSyncer(t *testing.T) *tipSyncer {
 		pendingBlocks: pendingBlocks,
 	}
 
-	return newTipSyncer(bs, pendingBlocks, readyBlocks, cs.handleReadyBlock)
+	return newTipSyncer(bs, pendingBlocks, readyBlocks, cs.handleReadyBlock, false)
 }
 
 func TestTipSyncer_handleNewPeerState(t *testing.T) {
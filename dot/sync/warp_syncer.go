@@ -0,0 +1,464 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/peerset"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	warpSyncPeersInterval = time.Second
+	// maxWarpSyncAttempts is the number of times requests are sent to every peer
+	// before warp sync fails, after which the node falls back to full sync
+	maxWarpSyncAttempts = 10
+)
+
+// warpSyncer syncs a node which only has the genesis block to the latest finalised block
+// of the network. The authority set changes since genesis are proven with GRANDPA
+// justifications, then the state of the finalised block is downloaded from peers.
+type warpSyncer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	blockState     BlockState
+	storageState   StorageState
+	epochState     EpochState
+	finalityGadget FinalityGadget
+	network        Network
+}
+
+func newWarpSyncer(bs BlockState, ss StorageState, es EpochState,
+	fg FinalityGadget, net Network) *warpSyncer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &warpSyncer{
+		ctx:            ctx,
+		cancel:         cancel,
+		blockState:     bs,
+		storageState:   ss,
+		epochState:     es,
+		finalityGadget: fg,
+		network:        net,
+	}
+}
+
+func (w *warpSyncer) stop() {
+	w.cancel()
+}
+
+// warpSyncTarget is the latest finalised block proven by the warp sync proofs
+type warpSyncTarget struct {
+	header        *types.Header
+	justification []byte
+	round, setID  uint64
+}
+
+// sync warp syncs the node if it only has the genesis block. It returns once the state of
+// the latest finalised block of the network has been imported, or if there is nothing to sync.
+func (w *warpSyncer) sync() error {
+	best, err := w.blockState.BestBlockHeader()
+	if err != nil {
+		return err
+	}
+
+	if !best.Number.IsUint64() || best.Number.Uint64() != 0 {
+		logger.Infof("not warp syncing, already have blocks up to number %s", best.Number)
+		return nil
+	}
+
+	target, err := w.syncFinality(best.Hash())
+	if err != nil {
+		return err
+	}
+
+	if target == nil {
+		logger.Info("not warp syncing, no block has been finalised since genesis")
+		return nil
+	}
+
+	logger.Infof("warp synced finality to block %s with number %s, downloading its state...",
+		target.header.Hash(), target.header.Number)
+
+	ts, err := w.downloadState(target.header)
+	if err != nil {
+		return fmt.Errorf("cannot download state: %w", err)
+	}
+
+	return w.importState(target, ts)
+}
+
+// waitForPeers blocks until the node is connected to a peer, and returns the connected peers
+func (w *warpSyncer) waitForPeers() ([]peer.ID, error) {
+	ticker := time.NewTicker(warpSyncPeersInterval)
+	defer ticker.Stop()
+
+	for {
+		var peers []peer.ID
+		for _, info := range w.network.Peers() {
+			p, err := peer.Decode(info.PeerID)
+			if err != nil {
+				continue
+			}
+			peers = append(peers, p)
+		}
+
+		if len(peers) != 0 {
+			return peers, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-w.ctx.Done():
+			return nil, ErrServiceStopped
+		}
+	}
+}
+
+// syncFinality requests warp sync proofs starting at the given block until a peer
+// proves its latest finalised block, and returns that block. It returns nil if the
+// peers have not finalised any set change or block since the given block.
+func (w *warpSyncer) syncFinality(begin common.Hash) (*warpSyncTarget, error) {
+	var target *warpSyncTarget
+
+	for attempts := 0; attempts < maxWarpSyncAttempts; {
+		peers, err := w.waitForPeers()
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			proof *network.WarpSyncProof
+			who   peer.ID
+		)
+
+		for _, p := range peers {
+			proof, err = w.network.DoWarpSyncRequest(p, &network.WarpProofRequest{Begin: begin})
+			if err != nil {
+				logger.Debugf("warp sync request to peer %s failed: %s", p, err)
+				continue
+			}
+
+			if len(proof.Fragments) == 0 && !proof.IsFinished {
+				logger.Debugf("peer %s sent an empty warp sync proof", p)
+				continue
+			}
+
+			who = p
+			break
+		}
+
+		if who == "" {
+			attempts++
+			select {
+			case <-time.After(warpSyncPeersInterval):
+				continue
+			case <-w.ctx.Done():
+				return nil, ErrServiceStopped
+			}
+		}
+
+		if len(proof.Fragments) == 0 {
+			// the peer has nothing finalised after the begin block
+			return target, nil
+		}
+
+		round, setID, err := w.finalityGadget.VerifyWarpSyncProof(proof)
+		if err != nil {
+			w.network.ReportPeer(peerset.ReputationChange{
+				Value:  peerset.BadJustificationValue,
+				Reason: peerset.BadJustificationReason,
+			}, who)
+			logger.Debugf("invalid warp sync proof from peer %s: %s", who, err)
+			attempts++
+			continue
+		}
+
+		last := proof.Fragments[len(proof.Fragments)-1]
+		target = &warpSyncTarget{
+			header:        &last.Header,
+			justification: last.Justification,
+			round:         round,
+			setID:         setID,
+		}
+		begin = last.Header.Hash()
+
+		logger.Debugf("verified %d warp sync fragments from peer %s up to block number %s",
+			len(proof.Fragments), who, last.Header.Number)
+
+		if proof.IsFinished {
+			return target, nil
+		}
+	}
+
+	return nil, errWarpSyncFailed
+}
+
+// downloadState downloads the state of the given block, verifying each response with its proof
+func (w *warpSyncer) downloadState(header *types.Header) (*rtstorage.TrieState, error) {
+	t := trie.NewEmptyTrie()
+
+	err := w.downloadTrie(header.Hash(), nil, header.StateRoot.ToBytes(), t)
+	if err != nil {
+		return nil, err
+	}
+
+	// the child tries are downloaded once the top trie, which contains their roots, is verified
+	prefix := trie.ChildStorageKeyPrefix
+	for key := t.NextKey(prefix); bytes.HasPrefix(key, prefix); key = t.NextKey(key) {
+		child := trie.NewEmptyTrie()
+		if err = w.downloadTrie(header.Hash(), key, t.Get(key), child); err != nil {
+			return nil, fmt.Errorf("cannot download child trie %s: %w", key, err)
+		}
+
+		if err = t.PutChild(key[len(prefix):], child); err != nil {
+			return nil, err
+		}
+	}
+
+	root, err := t.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	if !root.Equal(header.StateRoot) {
+		return nil, fmt.Errorf("downloaded state root %s does not match block state root %s", root, header.StateRoot)
+	}
+
+	return rtstorage.NewTrieState(t)
+}
+
+// downloadTrie downloads the entries of the trie with the given root into t. If the child
+// key is set, the entries of the child trie stored at that key are downloaded.
+func (w *warpSyncer) downloadTrie(block common.Hash, childKey, root []byte, t *trie.Trie) error {
+	var last []byte
+
+	for attempts := 0; attempts < maxWarpSyncAttempts; {
+		peers, err := w.waitForPeers()
+		if err != nil {
+			return err
+		}
+
+		req := &network.StateRequest{Block: block, Start: [][]byte{last}}
+		if childKey != nil {
+			req.Start = [][]byte{childKey, last}
+		}
+
+		var complete, received bool
+		for _, p := range peers {
+			entries, done, err := w.requestState(p, req, root)
+			if err != nil {
+				logger.Debugf("state request to peer %s failed: %s", p, err)
+				continue
+			}
+
+			for _, entry := range entries {
+				t.Put(entry.Key, entry.Value)
+			}
+
+			if len(entries) != 0 {
+				last = entries[len(entries)-1].Key
+			}
+
+			complete, received = done, true
+			break
+		}
+
+		if complete {
+			return nil
+		}
+
+		if !received {
+			attempts++
+			select {
+			case <-time.After(warpSyncPeersInterval):
+			case <-w.ctx.Done():
+				return ErrServiceStopped
+			}
+		}
+	}
+
+	return errWarpSyncFailed
+}
+
+// requestState sends the state request to the peer and verifies the entries of the response
+// against the given root. It returns the entries and whether the trie has been fully downloaded.
+func (w *warpSyncer) requestState(p peer.ID, req *network.StateRequest,
+	root []byte) ([]network.StateEntry, bool, error) {
+	resp, err := w.network.DoStateRequest(p, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(resp.Entries) != 1 {
+		return nil, false, fmt.Errorf("expected 1 key-value state entry, got %d", len(resp.Entries))
+	}
+
+	kv := resp.Entries[0]
+	if len(kv.Entries) == 0 && kv.Complete {
+		return nil, true, nil
+	}
+
+	// an incomplete response must return entries after the start key, otherwise the
+	// download would request the same entries forever
+	start := req.Start[len(req.Start)-1]
+	if !kv.Complete && (len(kv.Entries) == 0 || bytes.Compare(kv.Entries[len(kv.Entries)-1].Key, start) <= 0) {
+		err = errStateResponseNoProgress
+	}
+
+	var proof [][]byte
+	if err == nil {
+		err = scale.Unmarshal(resp.Proof, &proof)
+	}
+	if err == nil {
+		pairs := make([]trie.Pair, len(kv.Entries))
+		for i, entry := range kv.Entries {
+			pairs[i] = trie.Pair{Key: entry.Key, Value: entry.Value}
+		}
+
+		var ok bool
+		ok, err = trie.VerifyProof(proof, root, pairs)
+		if err == nil && !ok {
+			err = fmt.Errorf("proof does not match state root 0x%x", root)
+		}
+	}
+
+	if err != nil {
+		w.network.ReportPeer(peerset.ReputationChange{
+			Value:  peerset.BadMessageValue,
+			Reason: peerset.BadMessageReason,
+		}, p)
+		return nil, false, fmt.Errorf("invalid state response: %w", err)
+	}
+
+	return kv.Entries, kv.Complete, nil
+}
+
+// importState stores the downloaded state and imports the target block as the latest
+// finalised block, along with the BABE epoch data of its state
+func (w *warpSyncer) importState(target *warpSyncTarget, ts *rtstorage.TrieState) error {
+	// the runtime of the genesis block is upgraded to the code of the downloaded state
+	rt, err := w.blockState.GetRuntime(nil)
+	if err != nil {
+		return err
+	}
+
+	// the first slot must be set while the genesis block is the latest finalised block
+	if err = w.importEpochData(ts); err != nil {
+		return fmt.Errorf("cannot import epoch data: %w", err)
+	}
+
+	if err = w.storageState.StoreTrie(ts, target.header); err != nil {
+		return err
+	}
+
+	err = w.blockState.ImportFinalisedHeader(target.header, target.round, target.setID)
+	if err != nil {
+		return err
+	}
+
+	hash := target.header.Hash()
+	if err = w.blockState.SetJustification(hash, target.justification); err != nil {
+		return err
+	}
+
+	if err = w.blockState.HandleRuntimeChanges(ts, rt, hash); err != nil {
+		return fmt.Errorf("cannot update runtime: %w", err)
+	}
+
+	logger.Infof("warp sync complete, imported state of block %s with number %s",
+		hash, target.header.Number)
+	return nil
+}
+
+// importEpochData stores the first slot and the data of the current
+// and next BABE epochs, as found in the storage of the BABE pallet
+func (w *warpSyncer) importEpochData(ts *rtstorage.TrieState) error {
+	var (
+		genesisSlot, epoch         uint64
+		auths, nextAuths           []types.AuthorityRaw
+		randomness, nextRandomness [types.RandomnessLength]byte
+	)
+
+	items := []struct {
+		name string
+		dst  interface{}
+	}{
+		{"GenesisSlot", &genesisSlot},
+		{"EpochIndex", &epoch},
+		{"Authorities", &auths},
+		{"Randomness", &randomness},
+		{"NextAuthorities", &nextAuths},
+		{"NextRandomness", &nextRandomness},
+	}
+
+	for _, item := range items {
+		key, err := babeStorageKey(item.name)
+		if err != nil {
+			return err
+		}
+
+		enc := ts.Get(key)
+		if enc == nil {
+			return fmt.Errorf("cannot find Babe %s in state", item.name)
+		}
+
+		if err = scale.Unmarshal(enc, item.dst); err != nil {
+			return fmt.Errorf("cannot decode Babe %s: %w", item.name, err)
+		}
+	}
+
+	if err := w.epochState.SetFirstSlot(genesisSlot); err != nil {
+		return err
+	}
+
+	for i, data := range []struct {
+		auths      []types.AuthorityRaw
+		randomness [types.RandomnessLength]byte
+	}{
+		{auths, randomness},
+		{nextAuths, nextRandomness},
+	} {
+		authorities, err := types.BABEAuthorityRawToAuthority(data.auths)
+		if err != nil {
+			return err
+		}
+
+		err = w.epochState.SetEpochData(epoch+uint64(i), &types.EpochData{
+			Authorities: authorities,
+			Randomness:  data.randomness,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.epochState.SetCurrentEpoch(epoch)
+}
+
+// babeStorageKey returns the storage key of the given item of the BABE pallet
+func babeStorageKey(item string) ([]byte, error) {
+	module, err := common.Twox128Hash([]byte("Babe"))
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := common.Twox128Hash([]byte(item))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(module, name...), nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/sync/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testWarpSyncPeer = "QmaCpDMGvV2BGHeYERUEnRQAwe3N8SzbUtfsmvsqQLuvuJ"
+
+func newWarpSyncTestTrie(t *testing.T) *trie.Trie {
+	t.Helper()
+
+	tr := trie.NewEmptyTrie()
+	items := map[string]interface{}{
+		"GenesisSlot":     uint64(100),
+		"EpochIndex":      uint64(7),
+		"Authorities":     []types.AuthorityRaw{{Key: [32]byte{1}, Weight: 1}},
+		"Randomness":      [types.RandomnessLength]byte{2},
+		"NextAuthorities": []types.AuthorityRaw{{Key: [32]byte{3}, Weight: 1}},
+		"NextRandomness":  [types.RandomnessLength]byte{4},
+	}
+
+	for name, value := range items {
+		key, err := babeStorageKey(name)
+		require.NoError(t, err)
+
+		enc, err := scale.Marshal(value)
+		require.NoError(t, err)
+		tr.Put(key, enc)
+	}
+
+	child := trie.NewEmptyTrie()
+	child.Put([]byte("childkey"), common.Hash{5}.ToBytes())
+	err := tr.PutChild([]byte("child"), child)
+	require.NoError(t, err)
+
+	return tr
+}
+
+// newTestStateResponse answers the state request with one entry of the trie, along with its proof
+func newTestStateResponse(t *testing.T, tr *trie.Trie, req *network.StateRequest) *network.StateResponse {
+	t.Helper()
+
+	kv := network.KeyValueStateEntry{}
+	start := req.Start[len(req.Start)-1]
+	if len(req.Start) == 2 {
+		var err error
+		kv.StateRoot = req.Start[0]
+		tr, err = tr.GetChild(req.Start[0][len(trie.ChildStorageKeyPrefix):])
+		require.NoError(t, err)
+	}
+
+	key := tr.NextKey(start)
+	kv.Entries = []network.StateEntry{{Key: key, Value: tr.Get(key)}}
+	kv.Complete = tr.NextKey(key) == nil
+
+	proof, err := tr.GenerateProof([][]byte{key})
+	require.NoError(t, err)
+
+	enc, err := scale.Marshal(proof)
+	require.NoError(t, err)
+
+	return &network.StateResponse{
+		Entries: []network.KeyValueStateEntry{kv},
+		Proof:   enc,
+	}
+}
+
+func TestWarpSyncer_sync(t *testing.T) {
+	t.Parallel()
+
+	tr := newWarpSyncTestTrie(t)
+	genesis, err := types.NewHeader(common.Hash{}, common.Hash{}, common.Hash{}, big.NewInt(0), types.NewDigest())
+	require.NoError(t, err)
+	target, err := types.NewHeader(common.Hash{1}, tr.MustHash(), common.Hash{}, big.NewInt(100), types.NewDigest())
+	require.NoError(t, err)
+
+	p, err := peer.Decode(testWarpSyncPeer)
+	require.NoError(t, err)
+
+	proof := &network.WarpSyncProof{
+		Fragments:  []network.WarpSyncFragment{{Header: *target, Justification: []byte{1, 2}}},
+		IsFinished: true,
+	}
+
+	net := new(mocks.Network)
+	net.On("Peers").Return([]common.PeerInfo{{PeerID: testWarpSyncPeer}})
+	net.On("DoWarpSyncRequest", p, &network.WarpProofRequest{Begin: genesis.Hash()}).Return(proof, nil)
+	net.On("DoStateRequest", p, mock.AnythingOfType("*network.StateRequest")).Return(
+		func(_ peer.ID, req *network.StateRequest) *network.StateResponse {
+			return newTestStateResponse(t, tr, req)
+		}, nil)
+
+	fg := new(mocks.FinalityGadget)
+	fg.On("VerifyWarpSyncProof", proof).Return(uint64(3), uint64(1), nil)
+
+	bs := new(mocks.BlockState)
+	bs.On("BestBlockHeader").Return(genesis, nil)
+	bs.On("GetRuntime", (*common.Hash)(nil)).Return(nil, nil)
+	bs.On("ImportFinalisedHeader", target, uint64(3), uint64(1)).Return(nil)
+	bs.On("SetJustification", target.Hash(), []byte{1, 2}).Return(nil)
+	bs.On("HandleRuntimeChanges", mock.AnythingOfType("*storage.TrieState"), nil, target.Hash()).Return(nil)
+
+	var stored *rtstorage.TrieState
+	ss := new(mocks.StorageState)
+	ss.On("StoreTrie", mock.AnythingOfType("*storage.TrieState"), target).Return(nil).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*rtstorage.TrieState)
+	})
+
+	es := new(mocks.EpochState)
+	es.On("SetFirstSlot", uint64(100)).Return(nil)
+	es.On("SetEpochData", uint64(7), mock.MatchedBy(func(data *types.EpochData) bool {
+		return data.Randomness == [types.RandomnessLength]byte{2} && len(data.Authorities) == 1
+	})).Return(nil)
+	es.On("SetEpochData", uint64(8), mock.MatchedBy(func(data *types.EpochData) bool {
+		return data.Randomness == [types.RandomnessLength]byte{4} && len(data.Authorities) == 1
+	})).Return(nil)
+	es.On("SetCurrentEpoch", uint64(7)).Return(nil)
+
+	w := newWarpSyncer(bs, ss, es, fg, net)
+	err = w.sync()
+	require.NoError(t, err)
+
+	require.Equal(t, tr.MustHash(), stored.MustRoot())
+	child, err := stored.Trie().GetChild([]byte("child"))
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{5}.ToBytes(), child.Get([]byte("childkey")))
+
+	bs.AssertExpectations(t)
+	ss.AssertExpectations(t)
+	es.AssertExpectations(t)
+	fg.AssertExpectations(t)
+}
+
+func TestWarpSyncer_sync_NotAtGenesis(t *testing.T) {
+	t.Parallel()
+
+	header, err := types.NewHeader(common.Hash{}, common.Hash{}, common.Hash{}, big.NewInt(1), types.NewDigest())
+	require.NoError(t, err)
+
+	bs := new(mocks.BlockState)
+	bs.On("BestBlockHeader").Return(header, nil)
+
+	w := newWarpSyncer(bs, nil, nil, nil, new(mocks.Network))
+	err = w.sync()
+	require.NoError(t, err)
+}
+
+func TestWarpSyncer_requestState_InvalidProof(t *testing.T) {
+	t.Parallel()
+
+	tr := newWarpSyncTestTrie(t)
+	p, err := peer.Decode(testWarpSyncPeer)
+	require.NoError(t, err)
+
+	req := &network.StateRequest{Start: [][]byte{nil}}
+	resp := newTestStateResponse(t, tr, req)
+	resp.Entries[0].Entries[0].Value = []byte{9}
+
+	net := new(mocks.Network)
+	net.On("DoStateRequest", p, req).Return(resp, nil)
+	net.On("ReportPeer", mock.Anything, p)
+
+	w := newWarpSyncer(nil, nil, nil, nil, net)
+	_, _, err = w.requestState(p, req, tr.MustHash().ToBytes())
+	require.Error(t, err)
+	net.AssertCalled(t, "ReportPeer", mock.Anything, p)
+
+	resp.Entries[0].Entries[0].Value = tr.Get(resp.Entries[0].Entries[0].Key)
+	entries, _, err := w.requestState(p, req, tr.MustHash().ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, resp.Entries[0].Entries, entries)
+}
+
+func TestWarpSyncer_requestState_NoProgress(t *testing.T) {
+	t.Parallel()
+
+	tr := newWarpSyncTestTrie(t)
+	p, err := peer.Decode(testWarpSyncPeer)
+	require.NoError(t, err)
+
+	req := &network.StateRequest{Start: [][]byte{nil}}
+	resp := &network.StateResponse{Entries: []network.KeyValueStateEntry{{}}}
+
+	net := new(mocks.Network)
+	net.On("DoStateRequest", p, req).Return(resp, nil)
+	net.On("ReportPeer", mock.Anything, p)
+
+	// an incomplete response without entries is rejected
+	w := newWarpSyncer(nil, nil, nil, nil, net)
+	_, _, err = w.requestState(p, req, tr.MustHash().ToBytes())
+	require.ErrorIs(t, err, errStateResponseNoProgress)
+	net.AssertCalled(t, "ReportPeer", mock.Anything, p)
+
+	// as is an incomplete response which doesn't advance past the start key
+	last := newTestStateResponse(t, tr, req).Entries[0].Entries[0].Key
+	againReq := &network.StateRequest{Start: [][]byte{last}}
+	againResp := newTestStateResponse(t, tr, req)
+	net.On("DoStateRequest", p, againReq).Return(againResp, nil)
+
+	_, _, err = w.requestState(p, againReq, tr.MustHash().ToBytes())
+	require.ErrorIs(t, err, errStateResponseNoProgress)
+
+	// an empty response is accepted once the trie is complete
+	resp.Entries[0].Complete = true
+	entries, complete, err := w.requestState(p, req, tr.MustHash().ToBytes())
+	require.NoError(t, err)
+	require.True(t, complete)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package telemetry
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+type afgEquivocationTM AfgEquivocation
+
+var _ Message = (*AfgEquivocation)(nil)
+
+// AfgEquivocation is a telemetry message of type `afg.equivocation` which is
+// meant to be sent when grandpa client detects an equivocation of a voter
+type AfgEquivocation struct {
+	Voter            string      `json:"voter"`
+	AuthoritySetID   string      `json:"authority_set_id"`
+	Round            string      `json:"round"`
+	Stage            string      `json:"stage"`
+	FirstTargetHash  common.Hash `json:"first_target_hash"`
+	SecondTargetHash common.Hash `json:"second_target_hash"`
+}
+
+// NewAfgEquivocation creates a new AfgEquivocation struct.
+func NewAfgEquivocation(voter, authoritySetID, round, stage string,
+	firstTargetHash, secondTargetHash common.Hash) *AfgEquivocation {
+	return &AfgEquivocation{
+		Voter:            voter,
+		AuthoritySetID:   authoritySetID,
+		Round:            round,
+		Stage:            stage,
+		FirstTargetHash:  firstTargetHash,
+		SecondTargetHash: secondTargetHash,
+	}
+}
+
+func (afg AfgEquivocation) MarshalJSON() ([]byte, error) {
+	telemetryData := struct {
+		afgEquivocationTM
+		MessageType string    `json:"msg"`
+		Timestamp   time.Time `json:"ts"`
+	}{
+		afgEquivocationTM: afgEquivocationTM(afg),
+		MessageType:       afgEquivocationMsg,
+		Timestamp:         time.Now(),
+	}
+
+	return json.Marshal(telemetryData)
+}
@@ -235,6 +235,20 @@ func TestTelemetryMarshalMessage(t *testing.T) {
 				`:"authorities","msg":"afg.authority_set","ts":"[0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}.` +
 				`[0-9]+Z|([+-][0-9]{2}:[0-9]{2})"}$`),
 		},
+		"AfgEquivocation_marshal": {
+			message: &AfgEquivocation{
+				Voter:            "0x0",
+				AuthoritySetID:   "0",
+				Round:            "1",
+				Stage:            "prevote",
+				FirstTargetHash:  common.Hash{},
+				SecondTargetHash: common.Hash{},
+			},
+			expected: regexp.MustCompile(`^{"voter":"0x0","authority_set_id":"0","round":"1","stage":"prevote",` +
+				`"first_target_hash":"0x[0]{64}","second_target_hash":"0x[0]{64}",` +
+				`"msg":"afg.equivocation","ts":"[0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:` +
+				`[0-9]{2}.[0-9]+Z|([+-][0-9]{2}:[0-9]{2})"}$`),
+		},
 		"AfgFinalizedBlocksUpTo_marshal": {
 			message: &AfgFinalizedBlocksUpTo{
 				Hash:   common.Hash{},
@@ -6,6 +6,7 @@
 // telemetry message types
 const (
 	afgAuthoritySetMsg        = "afg.authority_set"
+	afgEquivocationMsg        = "afg.equivocation"
 	afgFinalizedBlocksUpToMsg = "afg.finalized_blocks_up_to"
 	afgReceivedCommitMsg      = "afg.received_commit"
 	afgReceivedPrecommitMsg   = "afg.received_precommit"
@@ -0,0 +1,53 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package dot
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/core"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// TraceBlock re-executes the stored block with the given hash with the runtime
+// host function calls being recorded, using the node database at the configured base path.
+func TraceBlock(cfg *Config, hash common.Hash) (*core.BlockTrace, error) {
+	stateSrvc, err := createStateService(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state service: %w", err)
+	}
+
+	err = stateSrvc.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start state service: %w", err)
+	}
+
+	defer func() {
+		if err := stateSrvc.Stop(); err != nil {
+			logger.Errorf("failed to stop state service: %s", err)
+		}
+	}()
+
+	block, err := stateSrvc.Block.GetBlockByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block: %w", err)
+	}
+
+	parent, err := stateSrvc.Block.GetHeader(block.Header.ParentHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get parent header: %w", err)
+	}
+
+	parentState, err := stateSrvc.Storage.TrieState(&parent.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get parent state: %w", err)
+	}
+
+	ns, err := createRuntimeStorage(stateSrvc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime storage: %w", err)
+	}
+
+	return core.TraceBlock(block, parentState, *ns)
+}
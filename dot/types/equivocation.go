@@ -0,0 +1,62 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package types
+
+import (
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+)
+
+// OpaqueKeyOwnershipProof is the proof, generated by the runtime, that the key of
+// an authority belongs to a validator of the current or of a historical session
+type OpaqueKeyOwnershipProof []byte
+
+// BabeEquivocationProof is the proof that a BABE authority produced two different
+// headers for the same slot. It is the EquivocationProof of sp_consensus_slots.
+type BabeEquivocationProof struct {
+	Offender     [sr25519.PublicKeyLength]byte
+	Slot         uint64
+	FirstHeader  Header
+	SecondHeader Header
+}
+
+// GrandpaEquivocationStage is the stage of the votes of a GRANDPA equivocation
+type GrandpaEquivocationStage byte
+
+const (
+	// PrevoteEquivocation is an equivocation of prevotes
+	PrevoteEquivocation GrandpaEquivocationStage = iota
+	// PrecommitEquivocation is an equivocation of precommits
+	PrecommitEquivocation
+)
+
+func (s GrandpaEquivocationStage) String() string {
+	switch s {
+	case PrevoteEquivocation:
+		return "prevote"
+	case PrecommitEquivocation:
+		return "precommit"
+	}
+
+	return "unknown"
+}
+
+// GrandpaEquivocation is a GRANDPA authority casting two different votes
+// in the same stage of a round
+type GrandpaEquivocation struct {
+	RoundNumber     uint64
+	ID              ed25519.PublicKeyBytes
+	FirstVote       GrandpaVote
+	FirstSignature  [64]byte
+	SecondVote      GrandpaVote
+	SecondSignature [64]byte
+}
+
+// GrandpaEquivocationProof is the proof that a GRANDPA authority of the given set
+// equivocated. It is the EquivocationProof of sp_finality_grandpa.
+type GrandpaEquivocationProof struct {
+	SetID        uint64
+	Stage        GrandpaEquivocationStage
+	Equivocation GrandpaEquivocation
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrandpaEquivocationProof_Encode(t *testing.T) {
+	proof := GrandpaEquivocationProof{
+		SetID: 1,
+		Stage: PrecommitEquivocation,
+		Equivocation: GrandpaEquivocation{
+			RoundNumber:     2,
+			ID:              [32]byte{3},
+			FirstVote:       GrandpaVote{Hash: common.Hash{4}, Number: 5},
+			FirstSignature:  [64]byte{6},
+			SecondVote:      GrandpaVote{Hash: common.Hash{7}, Number: 8},
+			SecondSignature: [64]byte{9},
+		},
+	}
+
+	enc, err := scale.Marshal(proof)
+	require.NoError(t, err)
+
+	expected := common.MustHexToBytes("0x0100000000000000" + // set id
+		"01" + // precommit
+		"0200000000000000" + // round number
+		"03" + strings.Repeat("00", 31) + // offender
+		"04" + strings.Repeat("00", 31) + "05000000" + "06" + strings.Repeat("00", 63) + // first vote and signature
+		"07" + strings.Repeat("00", 31) + "08000000" + "09" + strings.Repeat("00", 63)) // second vote and signature
+	require.Equal(t, expected, enc)
+
+	var res GrandpaEquivocationProof
+	err = scale.Unmarshal(enc, &res)
+	require.NoError(t, err)
+	require.Equal(t, proof, res)
+}
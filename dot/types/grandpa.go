@@ -99,15 +99,16 @@ func NewGrandpaVotersFromAuthorities(ad []Authority) []GrandpaVoter {
 func NewGrandpaVotersFromAuthoritiesRaw(ad []GrandpaAuthoritiesRaw) ([]GrandpaVoter, error) {
 	v := make([]GrandpaVoter, len(ad))
 
-	for i, d := range ad {
-		key, err := ed25519.NewPublicKey(d.Key[:])
+	for i := range ad {
+		// the key must not point to the loop variable, which is reused for each authority
+		key, err := ed25519.NewPublicKey(ad[i].Key[:])
 		if err != nil {
 			return nil, err
 		}
 
 		v[i] = GrandpaVoter{
 			Key: *key,
-			ID:  d.ID,
+			ID:  ad[i].ID,
 		}
 	}
 
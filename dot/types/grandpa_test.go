@@ -82,3 +82,19 @@ func TestGrandpaAuthoritiesRawToAuthorities(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, a, authoritys[1])
 }
+
+func TestNewGrandpaVotersFromAuthoritiesRaw(t *testing.T) {
+	authA, _ := common.HexToHash("0xeea1eabcac7d2c8a6459b7322cf997874482bfc3d2ec7a80888a3a7d71410364")
+	authB, _ := common.HexToHash("0xb64994460e59b30364cad3c92e3df6052f9b0ebbb8f88460c194dc5794d6d717")
+
+	voters, err := NewGrandpaVotersFromAuthoritiesRaw([]GrandpaAuthoritiesRaw{
+		{Key: authA, ID: 0},
+		{Key: authB, ID: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, voters, 2)
+	require.Equal(t, authA[:], voters[0].Key.Encode())
+	require.Equal(t, uint64(0), voters[0].ID)
+	require.Equal(t, authB[:], voters[1].Key.Encode())
+	require.Equal(t, uint64(1), voters[1].ID)
+}
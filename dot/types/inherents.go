@@ -9,6 +9,7 @@
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/ChainSafe/gossamer/pkg/scale"
 )
@@ -91,3 +92,31 @@ func (d *InherentsData) Encode() ([]byte, error) {
 	}
 	return buffer.Bytes(), nil
 }
+
+// InherentError is an error, returned by the runtime, of the inherent with the given key
+type InherentError struct {
+	Key   [8]byte
+	Error []byte // SCALE encoded error, its type depends on the inherent
+}
+
+func (e InherentError) String() string {
+	return fmt.Sprintf("%s: 0x%x", e.Key[:], e.Error)
+}
+
+// CheckInherentsResult is the result of the check of the inherents of a block by the runtime
+type CheckInherentsResult struct {
+	// Okay is false if the check of any inherent failed
+	Okay bool
+	// FatalError is true if any of the errors is fatal, ie. the block is invalid
+	FatalError bool
+	Errors     []InherentError
+}
+
+// String returns the errors of the check as a string
+func (r *CheckInherentsResult) String() string {
+	errs := make([]string, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = e.String()
+	}
+	return strings.Join(errs, ", ")
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package types
+
+import (
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// IndexOperation is a transaction storage indexing operation requested by the runtime
+// while executing a block, with ext_transaction_index_index_version_1 or
+// ext_transaction_index_renew_version_1.
+type IndexOperation struct {
+	// Extrinsic is the index of the extrinsic in the block body.
+	Extrinsic uint32
+	// Hash is the blake2b hash of the indexed data.
+	Hash common.Hash
+	// Size is the size of the indexed data, which is the end of the extrinsic.
+	// It is unused when renewing data indexed by a previous block.
+	Size uint32
+	// Renew is true if the operation renews the data indexed by a previous block,
+	// instead of indexing the end of the extrinsic.
+	Renew bool
+}
+
+// IndexedTransaction references the data indexed by an extrinsic of a block.
+type IndexedTransaction struct {
+	Extrinsic uint32
+	Hash      common.Hash
+}
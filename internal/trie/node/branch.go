@@ -17,6 +17,10 @@ type Branch struct {
 	Key      []byte
 	Children [16]Node
 	Value    []byte
+	// HashedValue is true when the value is encoded as its hash,
+	// which is the case for values larger than 32 bytes with the
+	// state version 1.
+	HashedValue bool
 	// Dirty is true when the branch differs
 	// from the node stored in the database.
 	Dirty      bool
@@ -60,6 +64,9 @@ func (b *Branch) StringNode() (stringNode *gotree.Node) {
 	stringNode.Appendf("Dirty: %t", b.Dirty)
 	stringNode.Appendf("Key: " + bytesToString(b.Key))
 	stringNode.Appendf("Value: " + bytesToString(b.Value))
+	if b.HashedValue {
+		stringNode.Appendf("Hashed value: true")
+	}
 	stringNode.Appendf("Calculated encoding: " + bytesToString(b.Encoding))
 	stringNode.Appendf("Calculated digest: " + bytesToString(b.HashDigest))
 
@@ -102,7 +102,12 @@ func (b *Branch) Encode(buffer Buffer) (err error) {
 		return fmt.Errorf("cannot write children bitmap to buffer: %w", err)
 	}
 
-	if b.Value != nil {
+	if b.Value != nil && b.HashedValue {
+		err = encodeHashedValue(b.Value, buffer)
+		if err != nil {
+			return fmt.Errorf("cannot encode hashed value: %w", err)
+		}
+	} else if b.Value != nil {
 		bytes, err := scale.Marshal(b.Value)
 		if err != nil {
 			return fmt.Errorf("cannot scale encode value: %w", err)
@@ -11,8 +11,9 @@ func (b *Branch) Copy(copyChildren bool) Node {
 	defer b.RUnlock()
 
 	cpy := &Branch{
-		Dirty:      b.Dirty,
-		Generation: b.Generation,
+		HashedValue: b.HashedValue,
+		Dirty:       b.Dirty,
+		Generation:  b.Generation,
 	}
 
 	if copyChildren {
@@ -59,8 +60,9 @@ func (l *Leaf) Copy(_ bool) Node {
 	defer l.encodingMu.RUnlock()
 
 	cpy := &Leaf{
-		Dirty:      l.Dirty,
-		Generation: l.Generation,
+		HashedValue: l.HashedValue,
+		Dirty:       l.Dirty,
+		Generation:  l.Generation,
 	}
 
 	if l.Key != nil {
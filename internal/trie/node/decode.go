@@ -21,6 +21,7 @@
 	ErrDecodeValue          = errors.New("cannot decode value")
 	ErrReadChildrenBitmap   = errors.New("cannot read children bitmap")
 	ErrDecodeChildHash      = errors.New("cannot decode child hash")
+	ErrReadHashedValue      = errors.New("cannot read hashed value")
 )
 
 // Decode decodes a node from a reader.
@@ -36,42 +37,54 @@ func Decode(reader io.Reader) (n Node, err error) {
 	}
 	header := oneByteBuf[0]
 
-	nodeType := Type(header >> 6)
-	switch nodeType {
-	case LeafType:
+	variant, ok := decodeHeaderVariant(header)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownNodeType, Type(header>>6))
+	}
+
+	switch variant {
+	case leafVariant, leafWithHashedValueVariant:
 		n, err = decodeLeaf(reader, header)
 		if err != nil {
 			return nil, fmt.Errorf("cannot decode leaf: %w", err)
 		}
 		return n, nil
-	case BranchType, BranchWithValueType:
+	default:
 		n, err = decodeBranch(reader, header)
 		if err != nil {
 			return nil, fmt.Errorf("cannot decode branch: %w", err)
 		}
 		return n, nil
-	default:
-		return nil, fmt.Errorf("%w: %d", ErrUnknownNodeType, nodeType)
 	}
 }
 
+// decodeHashedValue reads the hash of a value stored by hash.
+func decodeHashedValue(reader io.Reader) (hash []byte, err error) {
+	hash = make([]byte, 32)
+	_, err = io.ReadFull(reader, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadHashedValue, err)
+	}
+	return hash, nil
+}
+
 // decodeBranch reads and decodes from a reader with the encoding specified in lib/trie/node/encode_doc.go.
 // Note that since the encoded branch stores the hash of the children nodes, we are not
 // reconstructing the child nodes from the encoding. This function instead stubs where the
 // children are known to be with an empty leaf. The children nodes hashes are then used to
 // find other values using the persistent database.
 func decodeBranch(reader io.Reader, header byte) (branch *Branch, err error) {
-	nodeType := Type(header >> 6)
-	switch nodeType {
-	case BranchType, BranchWithValueType:
+	variant, _ := decodeHeaderVariant(header)
+	switch variant {
+	case branchVariant, branchWithValueVariant, branchWithHashedValueVariant:
 	default:
-		return nil, fmt.Errorf("%w: %d", ErrNodeTypeIsNotABranch, nodeType)
+		return nil, fmt.Errorf("%w: %d", ErrNodeTypeIsNotABranch, Type(header>>6))
 	}
 
 	branch = new(Branch)
 
-	keyLen := header & keyLenOffset
-	branch.Key, err = decodeKey(reader, keyLen)
+	keyLenMask := variant.keyLenMask()
+	branch.Key, err = decodeKeyWithMask(reader, header&keyLenMask, keyLenMask)
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode key: %w", err)
 	}
@@ -84,7 +97,8 @@ func decodeBranch(reader io.Reader, header byte) (branch *Branch, err error) {
 
 	sd := scale.NewDecoder(reader)
 
-	if nodeType == BranchWithValueType {
+	switch variant {
+	case branchWithValueVariant:
 		var value []byte
 		// branch w/ value
 		err := sd.Decode(&value)
@@ -92,6 +106,13 @@ func decodeBranch(reader io.Reader, header byte) (branch *Branch, err error) {
 			return nil, fmt.Errorf("%w: %s", ErrDecodeValue, err)
 		}
 		branch.Value = value
+	case branchWithHashedValueVariant:
+		// the value is the hash of the value, which is stored separately
+		branch.Value, err = decodeHashedValue(reader)
+		if err != nil {
+			return nil, err
+		}
+		branch.HashedValue = true
 	}
 
 	for i := 0; i < 16; i++ {
@@ -117,21 +138,31 @@ func decodeBranch(reader io.Reader, header byte) (branch *Branch, err error) {
 
 // decodeLeaf reads and decodes from a reader with the encoding specified in lib/trie/node/encode_doc.go.
 func decodeLeaf(reader io.Reader, header byte) (leaf *Leaf, err error) {
-	nodeType := Type(header >> 6)
-	if nodeType != LeafType {
-		return nil, fmt.Errorf("%w: %d", ErrNodeTypeIsNotALeaf, nodeType)
+	variant, _ := decodeHeaderVariant(header)
+	if variant != leafVariant && variant != leafWithHashedValueVariant {
+		return nil, fmt.Errorf("%w: %d", ErrNodeTypeIsNotALeaf, Type(header>>6))
 	}
 
 	leaf = &Leaf{
 		Dirty: true,
 	}
 
-	keyLen := header & keyLenOffset
-	leaf.Key, err = decodeKey(reader, keyLen)
+	keyLenMask := variant.keyLenMask()
+	leaf.Key, err = decodeKeyWithMask(reader, header&keyLenMask, keyLenMask)
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode key: %w", err)
 	}
 
+	if variant == leafWithHashedValueVariant {
+		// the value is the hash of the value, which is stored separately
+		leaf.Value, err = decodeHashedValue(reader)
+		if err != nil {
+			return nil, err
+		}
+		leaf.HashedValue = true
+		return leaf, nil
+	}
+
 	sd := scale.NewDecoder(reader)
 	var value []byte
 	err = sd.Decode(&value)
@@ -7,6 +7,7 @@
 	"bytes"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -87,3 +88,70 @@ func Test_Branch_Encode_Decode(t *testing.T) {
 		})
 	}
 }
+
+func Test_Encode_Decode_HashedValue(t *testing.T) {
+	t.Parallel()
+
+	value := bytes.Repeat([]byte{1}, 33)
+	valueHash := common.MustBlake2bHash(value)
+
+	testCases := map[string]struct {
+		nodeToEncode Node
+		nodeDecoded  Node
+	}{
+		"leaf": {
+			nodeToEncode: &Leaf{
+				Key:         []byte{1, 2},
+				Value:       value,
+				HashedValue: true,
+			},
+			nodeDecoded: &Leaf{
+				Key:         []byte{1, 2},
+				Value:       valueHash[:],
+				HashedValue: true,
+				Dirty:       true,
+			},
+		},
+		"branch": {
+			nodeToEncode: &Branch{
+				Key:         []byte{1},
+				Value:       value,
+				HashedValue: true,
+				Children: [16]Node{
+					&Leaf{
+						Key:   []byte{9},
+						Value: []byte{10},
+					},
+				},
+			},
+			nodeDecoded: &Branch{
+				Key:         []byte{1},
+				Value:       valueHash[:],
+				HashedValue: true,
+				Children: [16]Node{
+					&Leaf{
+						HashDigest: []byte{0x41, 0x9, 0x4, 0xa},
+					},
+				},
+				Dirty: true,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+
+			err := testCase.nodeToEncode.Encode(buffer)
+			require.NoError(t, err)
+
+			resultNode, err := Decode(buffer)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.nodeDecoded, resultNode)
+		})
+	}
+}
@@ -26,3 +26,11 @@
 // `Extra partial key length` is included if len(key) > 63 and consists of the remaining key length
 // `Partial Key` is the leaf's key
 // `Value` is the leaf's SCALE encoded value
+//
+// Hashed value encoding (state version 1):
+// Values larger than 32 bytes are encoded as their raw 32 bytes blake2b hash instead of
+// their SCALE encoding, in which case `NodeHeader` is a byte such that:
+// most significant three bits of `NodeHeader`: 001 for a leaf,
+// least significant five bits of `NodeHeader`: if len(key) > 30, 0x1f, otherwise len(key)
+// most significant four bits of `NodeHeader`: 0001 for a branch w/ value,
+// least significant four bits of `NodeHeader`: if len(key) > 14, 0x0f, otherwise len(key)
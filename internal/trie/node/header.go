@@ -7,62 +7,89 @@
 	"io"
 )
 
-const (
-	keyLenOffset    = 0x3f
-	nodeHeaderShift = 6
+const keyLenOffset = 0x3f
+
+// headerVariant is a variant of node header, identified by the most
+// significant bits of the header byte. The remaining bits of the header
+// byte hold the partial key length.
+type headerVariant struct {
+	bits byte
+	mask byte
+}
+
+var (
+	leafVariant                  = headerVariant{bits: 0b0100_0000, mask: 0b1100_0000}
+	branchVariant                = headerVariant{bits: 0b1000_0000, mask: 0b1100_0000}
+	branchWithValueVariant       = headerVariant{bits: 0b1100_0000, mask: 0b1100_0000}
+	leafWithHashedValueVariant   = headerVariant{bits: 0b0010_0000, mask: 0b1110_0000}
+	branchWithHashedValueVariant = headerVariant{bits: 0b0001_0000, mask: 0b1111_0000}
 )
 
-// encodeHeader creates the encoded header for the branch.
-func (b *Branch) encodeHeader(writer io.Writer) (err error) {
-	var header byte
-	if b.Value == nil {
-		header = byte(BranchType) << nodeHeaderShift
-	} else {
-		header = byte(BranchWithValueType) << nodeHeaderShift
-	}
+// headerVariants is ordered by decreasing number of bits of the mask,
+// so that the first variant matching a header byte is the right one.
+var headerVariants = [...]headerVariant{
+	branchWithHashedValueVariant,
+	leafWithHashedValueVariant,
+	leafVariant,
+	branchVariant,
+	branchWithValueVariant,
+}
 
-	if len(b.Key) >= keyLenOffset {
-		header = header | keyLenOffset
-		_, err = writer.Write([]byte{header})
-		if err != nil {
-			return err
-		}
+// keyLenMask returns the mask of the partial key length bits of the header byte.
+func (v headerVariant) keyLenMask() byte {
+	return ^v.mask
+}
 
-		err = encodeKeyLength(len(b.Key), writer)
-		if err != nil {
-			return err
-		}
-	} else {
-		header = header | byte(len(b.Key))
-		_, err = writer.Write([]byte{header})
-		if err != nil {
-			return err
+// decodeHeaderVariant returns the variant of the header byte given,
+// and false if the header byte does not match any variant.
+func decodeHeaderVariant(header byte) (variant headerVariant, ok bool) {
+	for _, variant := range headerVariants {
+		if header&variant.mask == variant.bits {
+			return variant, true
 		}
 	}
+	return headerVariant{}, false
+}
 
-	return nil
+// encodeHeader creates the encoded header for the branch.
+func (b *Branch) encodeHeader(writer io.Writer) (err error) {
+	variant := branchVariant
+	switch {
+	case b.Value == nil:
+	case b.HashedValue:
+		variant = branchWithHashedValueVariant
+	default:
+		variant = branchWithValueVariant
+	}
+
+	return encodeHeader(variant, len(b.Key), writer)
 }
 
 // encodeHeader creates the encoded header for the leaf.
 func (l *Leaf) encodeHeader(writer io.Writer) (err error) {
-	header := byte(LeafType) << nodeHeaderShift
+	variant := leafVariant
+	if l.HashedValue {
+		variant = leafWithHashedValueVariant
+	}
+
+	return encodeHeader(variant, len(l.Key), writer)
+}
+
+func encodeHeader(variant headerVariant, keyLength int, writer io.Writer) (err error) {
+	keyLenMask := variant.keyLenMask()
+	header := variant.bits
 
-	if len(l.Key) < 63 {
-		header |= byte(len(l.Key))
+	if keyLength < int(keyLenMask) {
+		header |= byte(keyLength)
 		_, err = writer.Write([]byte{header})
 		return err
 	}
 
-	header |= keyLenOffset
+	header |= keyLenMask
 	_, err = writer.Write([]byte{header})
 	if err != nil {
 		return err
 	}
 
-	err = encodeKeyLength(len(l.Key), writer)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return encodeKeyLengthFrom(keyLength, int(keyLenMask), writer)
 }
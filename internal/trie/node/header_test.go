@@ -31,6 +31,27 @@ func Test_Branch_encodeHeader(t *testing.T) {
 				{written: []byte{0xc0}},
 			},
 		},
+		"with hashed value": {
+			branch: &Branch{
+				Key:         make([]byte, 3),
+				Value:       []byte{1},
+				HashedValue: true,
+			},
+			writes: []writeCall{
+				{written: []byte{0x13}},
+			},
+		},
+		"with hashed value and key of length 15": {
+			branch: &Branch{
+				Key:         make([]byte, 15),
+				Value:       []byte{1},
+				HashedValue: true,
+			},
+			writes: []writeCall{
+				{written: []byte{0x1f}},
+				{written: []byte{0x0}},
+			},
+		},
 		"key of length 30": {
 			branch: &Branch{
 				Key: make([]byte, 30),
@@ -151,6 +172,25 @@ func Test_Leaf_encodeHeader(t *testing.T) {
 				{written: []byte{0x5e}},
 			},
 		},
+		"hashed value with key of length 30": {
+			leaf: &Leaf{
+				Key:         make([]byte, 30),
+				HashedValue: true,
+			},
+			writes: []writeCall{
+				{written: []byte{0x3e}},
+			},
+		},
+		"hashed value with key of length 32": {
+			leaf: &Leaf{
+				Key:         make([]byte, 32),
+				HashedValue: true,
+			},
+			writes: []writeCall{
+				{written: []byte{0x3f}},
+				{written: []byte{0x1}},
+			},
+		},
 		"short key write error": {
 			leaf: &Leaf{
 				Key: make([]byte, 30),
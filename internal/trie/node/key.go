@@ -51,7 +51,14 @@ func (l *Leaf) SetKey(key []byte) {
 
 // encodeKeyLength encodes the key length.
 func encodeKeyLength(keyLength int, writer io.Writer) (err error) {
-	keyLength -= 63
+	return encodeKeyLengthFrom(keyLength, keyLenOffset, writer)
+}
+
+// encodeKeyLengthFrom encodes the part of the key length which
+// does not fit in the header byte, where the offset is the key
+// length held by the header byte.
+func encodeKeyLengthFrom(keyLength, offset int, writer io.Writer) (err error) {
+	keyLength -= offset
 
 	if keyLength >= int(maxPartialKeySize) {
 		return fmt.Errorf("%w: %d",
@@ -79,10 +86,16 @@ func encodeKeyLength(keyLength int, writer io.Writer) (err error) {
 
 // decodeKey decodes a key from a reader.
 func decodeKey(reader io.Reader, keyLengthByte byte) (b []byte, err error) {
+	return decodeKeyWithMask(reader, keyLengthByte, keyLenOffset)
+}
+
+// decodeKeyWithMask decodes a key from a reader, where keyLenMask
+// is the mask of the key length bits of the header byte.
+func decodeKeyWithMask(reader io.Reader, keyLengthByte, keyLenMask byte) (b []byte, err error) {
 	keyLength := int(keyLengthByte)
 
-	if keyLengthByte == keyLenOffset {
-		// partial key longer than 63, read next bytes for rest of pk len
+	if keyLengthByte == keyLenMask {
+		// partial key longer than the mask, read next bytes for rest of pk len
 		buffer := pools.SingleByteBuffers.Get().(*bytes.Buffer)
 		defer pools.SingleByteBuffers.Put(buffer)
 		oneByteBuf := buffer.Bytes()
@@ -17,6 +17,10 @@ type Leaf struct {
 	// Partial key bytes in nibbles (0 to f in hexadecimal)
 	Key   []byte
 	Value []byte
+	// HashedValue is true when the value is encoded as its hash,
+	// which is the case for values larger than 32 bytes with the
+	// state version 1.
+	HashedValue bool
 	// Dirty is true when the branch differs
 	// from the node stored in the database.
 	Dirty      bool
@@ -57,6 +61,9 @@ func (l *Leaf) StringNode() (stringNode *gotree.Node) {
 	stringNode.Appendf("Dirty: %t", l.Dirty)
 	stringNode.Appendf("Key: " + bytesToString(l.Key))
 	stringNode.Appendf("Value: " + bytesToString(l.Value))
+	if l.HashedValue {
+		stringNode.Appendf("Hashed value: true")
+	}
 	stringNode.Appendf("Calculated encoding: " + bytesToString(l.Encoding))
 	stringNode.Appendf("Calculated digest: " + bytesToString(l.HashDigest))
 	return stringNode
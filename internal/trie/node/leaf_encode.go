@@ -40,14 +40,21 @@ func (l *Leaf) Encode(buffer Buffer) (err error) {
 		return fmt.Errorf("cannot write LE key to buffer: %w", err)
 	}
 
-	encodedValue, err := scale.Marshal(l.Value) // TODO scale encoder to write to buffer
-	if err != nil {
-		return fmt.Errorf("cannot scale marshal value: %w", err)
-	}
+	if l.HashedValue {
+		err = encodeHashedValue(l.Value, buffer)
+		if err != nil {
+			return fmt.Errorf("cannot encode hashed value: %w", err)
+		}
+	} else {
+		encodedValue, err := scale.Marshal(l.Value) // TODO scale encoder to write to buffer
+		if err != nil {
+			return fmt.Errorf("cannot scale marshal value: %w", err)
+		}
 
-	_, err = buffer.Write(encodedValue)
-	if err != nil {
-		return fmt.Errorf("cannot write scale encoded value to buffer: %w", err)
+		_, err = buffer.Write(encodedValue)
+		if err != nil {
+			return fmt.Errorf("cannot write scale encoded value to buffer: %w", err)
+		}
 	}
 
 	// TODO remove this copying since it defeats the purpose of `buffer`
@@ -18,6 +18,9 @@ type Node interface {
 	GetHash() (hash []byte)
 	GetKey() (key []byte)
 	GetValue() (value []byte)
+	SetValue(value []byte)
+	IsHashedValue() bool
+	SetHashedValue(hashed bool)
 	GetGeneration() (generation uint64)
 	SetGeneration(generation uint64)
 	Copy(copyChildren bool) Node
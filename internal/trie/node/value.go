@@ -3,6 +3,13 @@
 
 package node
 
+import (
+	"fmt"
+	"io"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
 // GetValue returns the value of the branch.
 // Note it does not copy the byte slice so modifying the returned
 // byte slice will modify the byte slice of the branch.
@@ -16,3 +23,54 @@ func (b *Branch) GetValue() (value []byte) {
 func (l *Leaf) GetValue() (value []byte) {
 	return l.Value
 }
+
+// SetValue sets the value of the branch, for example to replace
+// the hash of a hashed value decoded by the value itself.
+// Note it does not copy it so modifying the passed value
+// will modify the value stored in the branch.
+func (b *Branch) SetValue(value []byte) {
+	b.Value = value
+}
+
+// SetValue sets the value of the leaf, for example to replace
+// the hash of a hashed value decoded by the value itself.
+// Note it does not copy it so modifying the passed value
+// will modify the value stored in the leaf.
+func (l *Leaf) SetValue(value []byte) {
+	l.Value = value
+}
+
+// IsHashedValue returns true if the value of the branch is encoded as its hash.
+func (b *Branch) IsHashedValue() bool {
+	return b.HashedValue
+}
+
+// SetHashedValue sets whether the value of the branch is encoded as its hash.
+func (b *Branch) SetHashedValue(hashed bool) {
+	b.HashedValue = hashed
+}
+
+// IsHashedValue returns true if the value of the leaf is encoded as its hash.
+func (l *Leaf) IsHashedValue() bool {
+	return l.HashedValue
+}
+
+// SetHashedValue sets whether the value of the leaf is encoded as its hash.
+func (l *Leaf) SetHashedValue(hashed bool) {
+	l.HashedValue = hashed
+}
+
+// encodeHashedValue writes the blake2b hash of the value to the writer.
+func encodeHashedValue(value []byte, writer io.Writer) (err error) {
+	hash, err := common.Blake2bHash(value)
+	if err != nil {
+		return fmt.Errorf("cannot hash value: %w", err)
+	}
+
+	_, err = writer.Write(hash[:])
+	if err != nil {
+		return fmt.Errorf("cannot write hashed value to buffer: %w", err)
+	}
+
+	return nil
+}
@@ -15,6 +15,8 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/signer"
 
 	ethmetrics "github.com/ethereum/go-ethereum/metrics"
 )
@@ -41,9 +43,10 @@ type Service struct {
 	epochState       EpochState
 
 	blockImportHandler BlockImportHandler
+	slashingProtection SlashingProtection
 
-	// BABE authority keypair
-	keypair *sr25519.Keypair // TODO: change to BABE keystore (#1864)
+	// signer of the BABE authority key
+	signer signer.VRFSigner
 
 	// State variables
 	sync.RWMutex
@@ -60,7 +63,9 @@ type ServiceConfig struct {
 	TransactionState   TransactionState
 	EpochState         EpochState
 	BlockImportHandler BlockImportHandler
+	SlashingProtection SlashingProtection // if set, checked before sealing a block
 	Keypair            *sr25519.Keypair
+	Signer             signer.VRFSigner // used instead of Keypair if set, eg. for a remote signer
 	AuthData           []types.Authority
 	IsDev              bool
 	Authority          bool
@@ -70,7 +75,12 @@ type ServiceConfig struct {
 
 // NewService returns a new Babe Service using the provided VRF keys and runtime
 func NewService(cfg *ServiceConfig) (*Service, error) {
-	if cfg.Keypair == nil && cfg.Authority {
+	vrfSigner := cfg.Signer
+	if vrfSigner == nil && cfg.Keypair != nil {
+		vrfSigner = signer.NewLocal(cfg.Keypair)
+	}
+
+	if vrfSigner == nil && cfg.Authority {
 		return nil, errors.New("cannot create BABE service as authority; no keypair provided")
 	}
 
@@ -106,12 +116,13 @@ func NewService(cfg *ServiceConfig) (*Service, error) {
 		blockState:         cfg.BlockState,
 		storageState:       cfg.StorageState,
 		epochState:         cfg.EpochState,
-		keypair:            cfg.Keypair,
+		signer:             vrfSigner,
 		transactionState:   cfg.TransactionState,
 		pause:              make(chan struct{}),
 		authority:          cfg.Authority,
 		dev:                cfg.IsDev,
 		blockImportHandler: cfg.BlockImportHandler,
+		slashingProtection: cfg.SlashingProtection,
 		lead:               cfg.Lead,
 		constants: constants{
 			slotDuration: slotDuration,
@@ -279,7 +290,7 @@ func (b *Service) getAuthorityIndex(Authorities []types.Authority) (uint32, erro
 		return 0, ErrNotAuthority
 	}
 
-	pub := b.keypair.Public()
+	pub := b.signer.Public()
 
 	for i, auth := range Authorities {
 		if bytes.Equal(pub.Encode(), auth.Key.Encode()) {
@@ -328,7 +339,7 @@ func (b *Service) initiateAndGetEpochHandler(epoch uint64) (*epochHandler, error
 		epochData,
 		b.constants,
 		b.handleSlot,
-		b.keypair,
+		b.signer,
 	)
 }
 
@@ -435,14 +446,16 @@ func (b *Service) handleSlot(epoch, slotNum uint64, authorityIndex uint32, proof
 	}
 
 	hash := parent.Hash()
-	rt, err := b.blockState.GetRuntime(&hash)
+	pool, err := b.blockState.GetRuntimePool(&hash)
 	if err != nil {
 		return err
 	}
 
-	rt.SetContextStorage(ts)
-
-	block, err := b.buildBlock(parent, currentSlot, rt, authorityIndex, proof)
+	var block *types.Block
+	err = pool.Call(ts, func(rt runtime.Instance) (err error) {
+		block, err = b.buildBlock(parent, currentSlot, rt, authorityIndex, proof)
+		return err
+	})
 	if err != nil {
 		return err
 	}
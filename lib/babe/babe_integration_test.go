@@ -23,6 +23,7 @@
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/signer"
 	"github.com/ChainSafe/gossamer/lib/trie"
 	"github.com/ChainSafe/gossamer/lib/utils"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -260,7 +261,7 @@ func TestService_GetAuthorityIndex(t *testing.T) {
 	}
 
 	bs := &Service{
-		keypair:   kpA,
+		signer:    signer.NewLocal(kpA),
 		authority: true,
 	}
 
@@ -269,7 +270,7 @@ func TestService_GetAuthorityIndex(t *testing.T) {
 	require.Equal(t, uint32(0), idx)
 
 	bs = &Service{
-		keypair:   kpB,
+		signer:    signer.NewLocal(kpB),
 		authority: true,
 	}
 
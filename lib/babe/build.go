@@ -12,8 +12,8 @@
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
-	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/signer"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	ethmetrics "github.com/ethereum/go-ethereum/metrics"
@@ -28,7 +28,7 @@
 func (b *Service) buildBlock(parent *types.Header, slot Slot, rt runtime.Instance,
 	authorityIndex uint32, proof *VrfOutputAndProof) (*types.Block, error) {
 	builder, err := NewBlockBuilder(
-		b.keypair,
+		b.signer,
 		b.transactionState,
 		b.blockState,
 		proof,
@@ -37,6 +37,7 @@ func (b *Service) buildBlock(parent *types.Header, slot Slot, rt runtime.Instanc
 	if err != nil {
 		return nil, fmt.Errorf("failed to create block builder: %w", err)
 	}
+	builder.slashingProtection = b.slashingProtection
 
 	// is necessary to enable ethmetrics to be possible register values
 	ethmetrics.Enabled = true
@@ -56,15 +57,16 @@ func (b *Service) buildBlock(parent *types.Header, slot Slot, rt runtime.Instanc
 
 // BlockBuilder builds blocks.
 type BlockBuilder struct {
-	keypair               *sr25519.Keypair
+	signer                signer.Signer
 	transactionState      TransactionState
 	blockState            BlockState
 	proof                 *VrfOutputAndProof
 	currentAuthorityIndex uint32
+	slashingProtection    SlashingProtection
 }
 
 // NewBlockBuilder creates a new block builder.
-func NewBlockBuilder(kp *sr25519.Keypair, ts TransactionState,
+func NewBlockBuilder(s signer.Signer, ts TransactionState,
 	bs BlockState, proof *VrfOutputAndProof,
 	authidx uint32) (*BlockBuilder, error) {
 	if ts == nil {
@@ -78,7 +80,7 @@ func NewBlockBuilder(kp *sr25519.Keypair, ts TransactionState,
 	}
 
 	bb := &BlockBuilder{
-		keypair:               kp,
+		signer:                s,
 		transactionState:      ts,
 		blockState:            bs,
 		proof:                 proof,
@@ -142,7 +144,7 @@ func (b *BlockBuilder) buildBlock(parent *types.Header, slot Slot, rt runtime.In
 	logger.Trace("finalised block")
 
 	// create seal and add to digest
-	seal, err := b.buildBlockSeal(header)
+	seal, err := b.buildBlockSeal(header, slot.number)
 	if err != nil {
 		return nil, err
 	}
@@ -169,7 +171,8 @@ func (b *BlockBuilder) buildBlock(parent *types.Header, slot Slot, rt runtime.In
 
 // buildBlockSeal creates the seal for the block header.
 // the seal consists of the ConsensusEngineID and a signature of the encoded block header.
-func (b *BlockBuilder) buildBlockSeal(header *types.Header) (*types.SealDigest, error) {
+// the header is recorded in the slashing protection database before it is signed.
+func (b *BlockBuilder) buildBlockSeal(header *types.Header, slot uint64) (*types.SealDigest, error) {
 	encHeader, err := scale.Marshal(*header)
 	if err != nil {
 		return nil, err
@@ -180,7 +183,14 @@ func (b *BlockBuilder) buildBlockSeal(header *types.Header) (*types.SealDigest,
 		return nil, err
 	}
 
-	sig, err := b.keypair.Sign(hash[:])
+	if b.slashingProtection != nil {
+		err = b.slashingProtection.CheckAndRecordBlock(b.signer.Public(), slot, hash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sig, err := b.signer.Sign(hash[:])
 	if err != nil {
 		return nil, err
 	}
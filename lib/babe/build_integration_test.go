@@ -45,7 +45,7 @@ func TestSeal(t *testing.T) {
 	require.NotEmpty(t, authoringSlots)
 
 	builder, _ := NewBlockBuilder(
-		babeService.keypair,
+		babeService.signer,
 		babeService.transactionState,
 		babeService.blockState,
 		babeService.epochHandler.slotToProof[authoringSlots[0]],
@@ -64,7 +64,7 @@ func TestSeal(t *testing.T) {
 	hash, err := common.Blake2bHash(encHeader)
 	require.NoError(t, err)
 
-	seal, err := builder.buildBlockSeal(header)
+	seal, err := builder.buildBlockSeal(header, 0)
 	require.NoError(t, err)
 
 	ok, err := kp.Public().Verify(hash[:], seal.Data)
@@ -155,7 +155,7 @@ func TestApplyExtrinsic(t *testing.T) {
 	const authorityIndex = 0
 
 	builder, _ := NewBlockBuilder(
-		babeService.keypair,
+		babeService.signer,
 		babeService.transactionState,
 		babeService.blockState,
 		&VrfOutputAndProof{},
@@ -0,0 +1,53 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package babe
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/signer"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockBuilder_buildBlockSeal_SlashingProtection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	header, err := types.NewHeader(common.Hash{1}, common.Hash{}, common.Hash{}, big.NewInt(1), types.NewDigest())
+	require.NoError(t, err)
+
+	encHeader, err := scale.Marshal(*header)
+	require.NoError(t, err)
+	hash, err := common.Blake2bHash(encHeader)
+	require.NoError(t, err)
+
+	errTest := errors.New("test error")
+	slashingProtection := NewMockSlashingProtection(ctrl)
+	slashingProtection.EXPECT().CheckAndRecordBlock(kp.Public(), uint64(1), hash).Return(nil)
+	slashingProtection.EXPECT().CheckAndRecordBlock(kp.Public(), uint64(2), hash).Return(errTest)
+
+	builder := &BlockBuilder{
+		signer:             signer.NewLocal(kp),
+		slashingProtection: slashingProtection,
+	}
+
+	seal, err := builder.buildBlockSeal(header, 1)
+	require.NoError(t, err)
+	ok, err := kp.Public().Verify(hash[:], seal.Data)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = builder.buildBlockSeal(header, 2)
+	require.ErrorIs(t, err, errTest)
+}
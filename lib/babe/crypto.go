@@ -9,8 +9,8 @@
 	"math"
 	"math/big"
 
-	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/signer"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/gtank/merlin"
 )
@@ -19,12 +19,16 @@
 // https://github.com/paritytech/substrate/blob/89275433863532d797318b75bb5321af098fea7c/primitives/consensus/babe/src/lib.rs#L93
 var babeVRFPrefix = []byte("substrate-babe-vrf")
 
+func makeTranscriptData(randomness Randomness, slot, epoch uint64) *signer.VRFTranscriptData {
+	data := signer.NewVRFTranscriptData("BABE") //string(types.BabeEngineID[:])
+	data.AppendUint64("slot number", slot)
+	data.AppendUint64("current epoch", epoch)
+	data.AppendMessage("chain randomness", randomness[:])
+	return data
+}
+
 func makeTranscript(randomness Randomness, slot, epoch uint64) *merlin.Transcript {
-	t := merlin.NewTranscript("BABE") //string(types.BabeEngineID[:])
-	crypto.AppendUint64(t, []byte("slot number"), slot)
-	crypto.AppendUint64(t, []byte("current epoch"), epoch)
-	t.AppendMessage([]byte("chain randomness"), randomness[:])
-	return t
+	return makeTranscriptData(randomness, slot, epoch).Transcript()
 }
 
 // claimPrimarySlot checks if a slot can be claimed.
@@ -34,19 +38,17 @@ func makeTranscript(randomness Randomness, slot, epoch uint64) *merlin.Transcrip
 func claimPrimarySlot(randomness Randomness,
 	slot, epoch uint64,
 	threshold *scale.Uint128,
-	keypair *sr25519.Keypair,
+	vrfSigner signer.VRFSigner,
 ) (*VrfOutputAndProof, error) {
-	transcript := makeTranscript(randomness, slot, epoch)
-
-	out, proof, err := keypair.VrfSign(transcript)
+	out, proof, err := vrfSigner.VrfSign(makeTranscriptData(randomness, slot, epoch))
 	if err != nil {
 		return nil, err
 	}
 
 	logger.Tracef("claimPrimarySlot pub=%s slot=%d epoch=%d output=0x%x proof=0x%x",
-		keypair.Public().Hex(), slot, epoch, out, proof)
+		vrfSigner.Public().Hex(), slot, epoch, out, proof)
 
-	ok, err := checkPrimaryThreshold(randomness, slot, epoch, out, threshold, keypair.Public().(*sr25519.PublicKey))
+	ok, err := checkPrimaryThreshold(randomness, slot, epoch, out, threshold, vrfSigner.Public().(*sr25519.PublicKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to compare with threshold, %w", err)
 	}
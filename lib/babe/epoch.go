@@ -227,6 +227,6 @@ func (b *Service) runLottery(slot, epoch uint64, epochData *epochData) (*VrfOutp
 		slot,
 		epoch,
 		epochData.threshold,
-		b.keypair,
+		b.signer,
 	)
 }
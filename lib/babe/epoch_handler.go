@@ -10,7 +10,7 @@
 	"sort"
 	"time"
 
-	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/signer"
 )
 
 type handleSlotFunc = func(epoch, slotNum uint64, authorityIndex uint32, proof *VrfOutputAndProof) error
@@ -33,7 +33,7 @@ type epochHandler struct {
 }
 
 func newEpochHandler(epochNumber, firstSlot uint64, epochData *epochData, constants constants,
-	handleSlot handleSlotFunc, keypair *sr25519.Keypair) (*epochHandler, error) {
+	handleSlot handleSlotFunc, vrfSigner signer.VRFSigner) (*epochHandler, error) {
 	// determine which slots we'll be authoring in by pre-calculating VRF output
 	slotToProof := make(map[uint64]*VrfOutputAndProof, constants.epochLength)
 	for i := firstSlot; i < firstSlot+constants.epochLength; i++ {
@@ -42,7 +42,7 @@ func newEpochHandler(epochNumber, firstSlot uint64, epochData *epochData, consta
 			i,
 			epochNumber,
 			epochData.threshold,
-			keypair,
+			vrfSigner,
 		)
 		if errors.Is(err, errOverPrimarySlotThreshold) {
 			continue
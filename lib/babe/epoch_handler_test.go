@@ -9,6 +9,7 @@
 	"time"
 
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/signer"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 
 	"github.com/stretchr/testify/require"
@@ -33,7 +34,7 @@ func TestNewEpochHandler(t *testing.T) {
 
 	keypair := keyring.Alice().(*sr25519.Keypair)
 
-	epochHandler, err := newEpochHandler(1, 9999, epochData, constants, testHandleSlotFunc, keypair)
+	epochHandler, err := newEpochHandler(1, 9999, epochData, constants, testHandleSlotFunc, signer.NewLocal(keypair))
 	require.NoError(t, err)
 	require.Equal(t, 200, len(epochHandler.slotToProof))
 	require.Equal(t, uint64(1), epochHandler.epochNumber)
@@ -76,7 +77,7 @@ func TestEpochHandler_run(t *testing.T) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	epochHandler, err := newEpochHandler(1, startSlot, epochData, constants, testHandleSlotFunc, keypair)
+	epochHandler, err := newEpochHandler(1, startSlot, epochData, constants, testHandleSlotFunc, signer.NewLocal(keypair))
 	require.NoError(t, err)
 	require.Equal(t, epochLength, uint64(len(epochHandler.slotToProof)))
 
@@ -39,7 +39,7 @@ func TestInitiateEpoch_Epoch1(t *testing.T) {
 
 	// epoch 1, check that genesis EpochData and ConfigData was properly set
 	auth := types.Authority{
-		Key:    bs.keypair.Public().(*sr25519.PublicKey),
+		Key:    bs.signer.Public().(*sr25519.PublicKey),
 		Weight: 1,
 	}
 
@@ -10,6 +10,7 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/signer"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -122,21 +123,21 @@ func TestBabeService_getEpochDataAndStartSlot(t *testing.T) {
 
 	bs0 := &Service{
 		authority:  true,
-		keypair:    kp,
+		signer:     signer.NewLocal(kp),
 		epochState: mockEpochState0,
 		blockState: mockBlockState,
 	}
 
 	bs1 := &Service{
 		authority:  true,
-		keypair:    kp,
+		signer:     signer.NewLocal(kp),
 		epochState: mockEpochState1,
 		blockState: mockBlockState,
 	}
 
 	bs2 := &Service{
 		authority:  true,
-		keypair:    kp,
+		signer:     signer.NewLocal(kp),
 		epochState: mockEpochState2,
 		blockState: mockBlockState,
 	}
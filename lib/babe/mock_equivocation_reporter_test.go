@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ChainSafe/gossamer/lib/babe (interfaces: EquivocationReporter)
+
+// Package babe is a generated GoMock package.
+package babe
+
+import (
+	reflect "reflect"
+
+	types "github.com/ChainSafe/gossamer/dot/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEquivocationReporter is a mock of EquivocationReporter interface.
+type MockEquivocationReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockEquivocationReporterMockRecorder
+}
+
+// MockEquivocationReporterMockRecorder is the mock recorder for MockEquivocationReporter.
+type MockEquivocationReporterMockRecorder struct {
+	mock *MockEquivocationReporter
+}
+
+// NewMockEquivocationReporter creates a new mock instance.
+func NewMockEquivocationReporter(ctrl *gomock.Controller) *MockEquivocationReporter {
+	mock := &MockEquivocationReporter{ctrl: ctrl}
+	mock.recorder = &MockEquivocationReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEquivocationReporter) EXPECT() *MockEquivocationReporterMockRecorder {
+	return m.recorder
+}
+
+// ReportBabeEquivocation mocks base method.
+func (m *MockEquivocationReporter) ReportBabeEquivocation(arg0 *types.BabeEquivocationProof) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportBabeEquivocation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportBabeEquivocation indicates an expected call of ReportBabeEquivocation.
+func (mr *MockEquivocationReporterMockRecorder) ReportBabeEquivocation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportBabeEquivocation", reflect.TypeOf((*MockEquivocationReporter)(nil).ReportBabeEquivocation), arg0)
+}
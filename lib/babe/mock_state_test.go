@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/ChainSafe/gossamer/lib/babe (interfaces: BlockState,ImportedBlockNotifierManager,StorageState,TransactionState,EpochState,DigestHandler,BlockImportHandler)
+// Source: github.com/ChainSafe/gossamer/lib/babe (interfaces: BlockState,ImportedBlockNotifierManager,StorageState,TransactionState,EpochState,DigestHandler,BlockImportHandler,SlashingProtection)
 
 // Package babe is a generated GoMock package.
 package babe
@@ -11,6 +11,7 @@
 
 	types "github.com/ChainSafe/gossamer/dot/types"
 	common "github.com/ChainSafe/gossamer/lib/common"
+	crypto "github.com/ChainSafe/gossamer/lib/crypto"
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
 	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	transaction "github.com/ChainSafe/gossamer/lib/transaction"
@@ -257,6 +258,21 @@ func (mr *MockBlockStateMockRecorder) GetRuntime(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntime", reflect.TypeOf((*MockBlockState)(nil).GetRuntime), arg0)
 }
 
+// GetRuntimePool mocks base method.
+func (m *MockBlockState) GetRuntimePool(arg0 *common.Hash) (*runtime.InstancePool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuntimePool", arg0)
+	ret0, _ := ret[0].(*runtime.InstancePool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuntimePool indicates an expected call of GetRuntimePool.
+func (mr *MockBlockStateMockRecorder) GetRuntimePool(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimePool", reflect.TypeOf((*MockBlockState)(nil).GetRuntimePool), arg0)
+}
+
 // GetSlotForBlock mocks base method.
 func (m *MockBlockState) GetSlotForBlock(arg0 common.Hash) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -837,3 +853,40 @@ func (mr *MockBlockImportHandlerMockRecorder) HandleBlockProduced(arg0, arg1 int
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBlockProduced", reflect.TypeOf((*MockBlockImportHandler)(nil).HandleBlockProduced), arg0, arg1)
 }
+
+// MockSlashingProtection is a mock of SlashingProtection interface.
+type MockSlashingProtection struct {
+	ctrl     *gomock.Controller
+	recorder *MockSlashingProtectionMockRecorder
+}
+
+// MockSlashingProtectionMockRecorder is the mock recorder for MockSlashingProtection.
+type MockSlashingProtectionMockRecorder struct {
+	mock *MockSlashingProtection
+}
+
+// NewMockSlashingProtection creates a new mock instance.
+func NewMockSlashingProtection(ctrl *gomock.Controller) *MockSlashingProtection {
+	mock := &MockSlashingProtection{ctrl: ctrl}
+	mock.recorder = &MockSlashingProtectionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSlashingProtection) EXPECT() *MockSlashingProtectionMockRecorder {
+	return m.recorder
+}
+
+// CheckAndRecordBlock mocks base method.
+func (m *MockSlashingProtection) CheckAndRecordBlock(arg0 crypto.PublicKey, arg1 uint64, arg2 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAndRecordBlock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckAndRecordBlock indicates an expected call of CheckAndRecordBlock.
+func (mr *MockSlashingProtectionMockRecorder) CheckAndRecordBlock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAndRecordBlock", reflect.TypeOf((*MockSlashingProtection)(nil).CheckAndRecordBlock), arg0, arg1, arg2)
+}
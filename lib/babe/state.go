@@ -10,12 +10,13 @@
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 )
 
-//go:generate mockgen -destination=./mock_state_test.go -package $GOPACKAGE . BlockState,ImportedBlockNotifierManager,StorageState,TransactionState,EpochState,DigestHandler,BlockImportHandler
+//go:generate mockgen -destination=./mock_state_test.go -package $GOPACKAGE . BlockState,ImportedBlockNotifierManager,StorageState,TransactionState,EpochState,DigestHandler,BlockImportHandler,SlashingProtection
 
 // BlockState interface for block state methods
 type BlockState interface {
@@ -36,6 +37,7 @@ type BlockState interface {
 	IsDescendantOf(parent, child common.Hash) (bool, error)
 	NumberIsFinalised(num *big.Int) (bool, error)
 	GetRuntime(*common.Hash) (runtime.Instance, error)
+	GetRuntimePool(*common.Hash) (*runtime.InstancePool, error)
 	StoreRuntime(common.Hash, runtime.Instance)
 	ImportedBlockNotifierManager
 }
@@ -88,3 +90,9 @@ type DigestHandler interface {
 type BlockImportHandler interface {
 	HandleBlockProduced(block *types.Block, state *rtstorage.TrieState) error
 }
+
+// SlashingProtection is the interface for the slashing protection database, which refuses
+// to sign two different blocks for the same slot
+type SlashingProtection interface {
+	CheckAndRecordBlock(authority crypto.PublicKey, slot uint64, hash common.Hash) error
+}
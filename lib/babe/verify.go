@@ -31,6 +31,17 @@ type onDisabledInfo struct {
 	blockHash   common.Hash
 }
 
+// maxEquivocationSlotCapacity is the number of slots, before the latest verified slot, for which
+// the headers are tracked to detect equivocations
+const maxEquivocationSlotCapacity = 1000
+
+//go:generate mockgen -destination=./mock_equivocation_reporter_test.go -package $GOPACKAGE . EquivocationReporter
+
+// EquivocationReporter reports the equivocations of BABE authorities
+type EquivocationReporter interface {
+	ReportBabeEquivocation(proof *types.BabeEquivocationProof) error
+}
+
 // VerificationManager deals with verification that a BABE block producer was authorized to produce a given block.
 // It trakcs the BABE epoch data that is needed for verification.
 type VerificationManager struct {
@@ -42,6 +53,11 @@ type VerificationManager struct {
 	// branches of the chain, so we need to keep track of all of them.
 	// map of epoch number -> block producer index -> block number and hash
 	onDisabled map[uint64]map[uint32][]*onDisabledInfo
+	// the headers of the blocks verified in the latest slots, to detect equivocations.
+	// map of slot -> block producer index -> header of the first block produced in the slot
+	slotHeaders          map[uint64]map[uint32]*types.Header
+	latestSlot           uint64
+	equivocationReporter EquivocationReporter
 }
 
 // NewVerificationManager returns a new NewVerificationManager
@@ -55,13 +71,21 @@ func NewVerificationManager(blockState BlockState, epochState EpochState) (*Veri
 	}
 
 	return &VerificationManager{
-		epochState: epochState,
-		blockState: blockState,
-		epochInfo:  make(map[uint64]*verifierInfo),
-		onDisabled: make(map[uint64]map[uint32][]*onDisabledInfo),
+		epochState:  epochState,
+		blockState:  blockState,
+		epochInfo:   make(map[uint64]*verifierInfo),
+		onDisabled:  make(map[uint64]map[uint32][]*onDisabledInfo),
+		slotHeaders: make(map[uint64]map[uint32]*types.Header),
 	}, nil
 }
 
+// SetEquivocationReporter sets the reporter of the equivocations detected while verifying blocks
+func (v *VerificationManager) SetEquivocationReporter(reporter EquivocationReporter) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.equivocationReporter = reporter
+}
+
 // SetOnDisabled sets the BABE authority with the given index as disabled for the rest of the epoch
 func (v *VerificationManager) SetOnDisabled(index uint32, header *types.Header) error {
 	epoch, err := v.epochState.GetEpochForBlock(header)
@@ -194,7 +218,111 @@ func (v *VerificationManager) VerifyBlock(header *types.Header) error {
 		return fmt.Errorf("failed to create new BABE verifier: %w", err)
 	}
 
-	return verifier.verifyAuthorshipRight(header)
+	err = verifier.verifyAuthorshipRight(header)
+	if err != nil {
+		return err
+	}
+
+	// the block is imported even if its producer equivocated, the producer is slashed
+	// once the report of the equivocation is included in a block
+	proof, err := v.checkEquivocation(header, info)
+	if err != nil {
+		return fmt.Errorf("failed to check for equivocation: %w", err)
+	}
+
+	if proof != nil {
+		v.reportEquivocation(proof)
+	}
+
+	return nil
+}
+
+// checkEquivocation tracks the header of the given verified block, and returns the proof of
+// equivocation if its producer produced another block in the same slot.
+func (v *VerificationManager) checkEquivocation(header *types.Header,
+	info *verifierInfo) (*types.BabeEquivocationProof, error) {
+	slot, err := types.GetSlotFromHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	authIdx, err := getAuthorityIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if slot+maxEquivocationSlotCapacity < v.latestSlot {
+		// the slot is too old to be tracked
+		return nil, nil
+	}
+
+	if slot > v.latestSlot {
+		v.latestSlot = slot
+		for s := range v.slotHeaders {
+			if s+maxEquivocationSlotCapacity < slot {
+				delete(v.slotHeaders, s)
+			}
+		}
+	}
+
+	producers, has := v.slotHeaders[slot]
+	if !has {
+		producers = make(map[uint32]*types.Header)
+		v.slotHeaders[slot] = producers
+	}
+
+	first, has := producers[authIdx]
+	if !has {
+		producers[authIdx], err = header.DeepCopy()
+		return nil, err
+	}
+
+	if first.Hash() == header.Hash() {
+		return nil, nil
+	}
+
+	offender, err := sr25519.NewPublicKey(info.authorities[authIdx].Key.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	second, err := header.DeepCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.BabeEquivocationProof{
+		Offender:     offender.AsBytes(),
+		Slot:         slot,
+		FirstHeader:  *first,
+		SecondHeader: *second,
+	}, nil
+}
+
+// reportEquivocation reports the given equivocation in the background, since reporting it
+// requires calls to the runtime.
+func (v *VerificationManager) reportEquivocation(proof *types.BabeEquivocationProof) {
+	logger.Warnf("authority 0x%x equivocated in slot %d with blocks %s and %s",
+		proof.Offender, proof.Slot, proof.FirstHeader.Hash(), proof.SecondHeader.Hash())
+
+	v.lock.RLock()
+	reporter := v.equivocationReporter
+	v.lock.RUnlock()
+
+	if reporter == nil {
+		return
+	}
+
+	go func() {
+		err := reporter.ReportBabeEquivocation(proof)
+		if err != nil {
+			logger.Errorf("failed to report equivocation of authority 0x%x in slot %d: %s",
+				proof.Offender, proof.Slot, err)
+		}
+	}()
 }
 
 func (v *VerificationManager) getVerifierInfo(epoch uint64) (*verifierInfo, error) {
@@ -341,35 +469,6 @@ func (b *verifier) verifyAuthorshipRight(header *types.Header) error {
 		return ErrBadSignature
 	}
 
-	// check if the producer has equivocated, ie. have they produced a conflicting block?
-	hashes := b.blockState.GetAllBlocksAtDepth(header.ParentHash)
-
-	for _, hash := range hashes {
-		currentHeader, err := b.blockState.GetHeader(hash)
-		if err != nil {
-			continue
-		}
-
-		currentBlockProducerIndex, err := getAuthorityIndex(currentHeader)
-		if err != nil {
-			continue
-		}
-
-		var existingBlockProducerIndex uint32
-		switch d := babePreDigest.(type) {
-		case types.BabePrimaryPreDigest:
-			existingBlockProducerIndex = d.AuthorityIndex
-		case types.BabeSecondaryVRFPreDigest:
-			existingBlockProducerIndex = d.AuthorityIndex
-		case types.BabeSecondaryPlainPreDigest:
-			existingBlockProducerIndex = d.AuthorityIndex
-		}
-
-		if currentBlockProducerIndex == existingBlockProducerIndex && hash != header.Hash() {
-			return ErrProducerEquivocated
-		}
-	}
-
 	return nil
 }
 
@@ -342,7 +342,7 @@ func TestVerifyPimarySlotWinner(t *testing.T) {
 	require.NoError(t, err)
 
 	builder, _ := NewBlockBuilder(
-		babeService.keypair,
+		babeService.signer,
 		babeService.transactionState,
 		babeService.blockState,
 		outAndProof,
@@ -400,7 +400,7 @@ func TestVerifyAuthorshipRight(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestVerifyAuthorshipRight_Equivocation(t *testing.T) {
+func TestVerificationManager_VerifyBlock_Equivocation(t *testing.T) {
 	kp, err := sr25519.GenerateKeypair()
 	require.NoError(t, err)
 
@@ -419,30 +419,48 @@ func TestVerifyAuthorshipRight_Equivocation(t *testing.T) {
 		},
 	}
 
-	// create and add first block
-	block := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 1, testEpochIndex, epochData)
-	block.Header.Hash()
-
-	err = babeService.blockState.AddBlock(block)
-	require.NoError(t, err)
-
-	verifier, err := newVerifier(babeService.blockState, testEpochIndex, &verifierInfo{
+	vm := newTestVerificationManager(t, nil)
+	vm.epochInfo[testEpochIndex] = &verifierInfo{
 		authorities: epochData.authorities,
 		threshold:   epochData.threshold,
 		randomness:  epochData.randomness,
-	})
+	}
+
+	ctrl := gomock.NewController(t)
+	reporter := NewMockEquivocationReporter(ctrl)
+	vm.SetEquivocationReporter(reporter)
+
+	// create and verify first block
+	block := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 1, testEpochIndex, epochData)
+	err = vm.VerifyBlock(&block.Header)
 	require.NoError(t, err)
 
-	err = verifier.verifyAuthorshipRight(&block.Header)
+	// verifying the same block again is not an equivocation
+	err = vm.VerifyBlock(&block.Header)
 	require.NoError(t, err)
 
-	// create new block
+	// create another block in the same slot, its producer equivocated
 	block2 := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 1, testEpochIndex, epochData)
-	block2.Header.Hash()
+	require.NotEqual(t, block.Header.Hash(), block2.Header.Hash())
+
+	reported := make(chan *types.BabeEquivocationProof, 1)
+	reporter.EXPECT().ReportBabeEquivocation(gomock.Any()).
+		DoAndReturn(func(proof *types.BabeEquivocationProof) error {
+			reported <- proof
+			return nil
+		})
 
-	err = babeService.blockState.AddBlock(block2)
+	// the block is valid even though its producer equivocated
+	err = vm.VerifyBlock(&block2.Header)
 	require.NoError(t, err)
 
-	err = verifier.verifyAuthorshipRight(&block2.Header)
-	require.Equal(t, ErrProducerEquivocated, err)
+	select {
+	case proof := <-reported:
+		require.Equal(t, kp.Public().(*sr25519.PublicKey).AsBytes(), proof.Offender)
+		require.Equal(t, uint64(1), proof.Slot)
+		require.Equal(t, block.Header.Hash(), proof.FirstHeader.Hash())
+		require.Equal(t, block2.Header.Hash(), proof.SecondHeader.Hash())
+	case <-time.After(time.Second):
+		t.Fatal("equivocation was not reported")
+	}
 }
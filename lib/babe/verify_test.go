@@ -8,6 +8,7 @@
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -469,10 +470,6 @@ type args struct {
 func Test_verifier_verifyAuthorshipRight(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockBlockState := NewMockBlockState(ctrl)
-	mockBlockStateErr := NewMockBlockState(ctrl)
-	mockBlockStateEquiv1 := NewMockBlockState(ctrl)
-	mockBlockStateEquiv2 := NewMockBlockState(ctrl)
-	mockBlockStateEquiv3 := NewMockBlockState(ctrl)
 
 	//Generate keys
 	kp, err := sr25519.GenerateKeypair()
@@ -506,55 +503,6 @@ func Test_verifier_verifyAuthorshipRight(t *testing.T) {
 		Data:              []byte{1},
 	}
 
-	// Primary Test Header
-	encTestDigest := newEncodedBabeDigest(t, types.BabePrimaryPreDigest{AuthorityIndex: 0})
-
-	testDigestPrimary := types.NewDigest()
-	err = testDigestPrimary.Add(types.PreRuntimeDigest{
-		ConsensusEngineID: types.BabeEngineID,
-		Data:              encTestDigest,
-	})
-	assert.NoError(t, err)
-	testHeaderPrimary := types.NewEmptyHeader()
-	testHeaderPrimary.Digest = testDigestPrimary
-
-	// Secondary Plain Test Header
-	testParentPrd, err := testBabeSecondaryPlainPreDigest.ToPreRuntimeDigest()
-	assert.NoError(t, err)
-	testParentHeader := newTestHeader(t, *testParentPrd)
-
-	testParentHash := encodeAndHashHeader(t, testParentHeader)
-	testSecondaryPrd, err := testBabeSecondaryPlainPreDigest.ToPreRuntimeDigest()
-	assert.NoError(t, err)
-	testSecPlainHeader := newTestHeader(t, *testSecondaryPrd)
-	testSecPlainHeader.ParentHash = testParentHash
-
-	// Secondary Vrf Test Header
-	encParentVrfDigest := newEncodedBabeDigest(t, testBabeSecondaryVRFPreDigest)
-	testParentVrfHeader := newTestHeader(t, *types.NewBABEPreRuntimeDigest(encParentVrfDigest))
-
-	testVrfParentHash := encodeAndHashHeader(t, testParentVrfHeader)
-	encVrfHeader := newEncodedBabeDigest(t, testBabeSecondaryVRFPreDigest)
-	testSecVrfHeader := newTestHeader(t, *types.NewBABEPreRuntimeDigest(encVrfHeader))
-	testSecVrfHeader.ParentHash = testVrfParentHash
-
-	h := common.MustHexToHash("0x01")
-	h1 := []common.Hash{h}
-
-	mockBlockState.EXPECT().GetAllBlocksAtDepth(gomock.Any()).Return(h1)
-	mockBlockState.EXPECT().GetHeader(h).Return(types.NewEmptyHeader(), nil)
-
-	mockBlockStateErr.EXPECT().GetAllBlocksAtDepth(gomock.Any()).Return(h1)
-	mockBlockStateErr.EXPECT().GetHeader(h).Return(nil, errors.New("get header error"))
-
-	mockBlockStateEquiv1.EXPECT().GetAllBlocksAtDepth(gomock.Any()).Return(h1)
-	mockBlockStateEquiv1.EXPECT().GetHeader(h).Return(testHeaderPrimary, nil)
-
-	mockBlockStateEquiv2.EXPECT().GetAllBlocksAtDepth(gomock.Any()).Return(h1)
-	mockBlockStateEquiv2.EXPECT().GetHeader(h).Return(testSecPlainHeader, nil)
-	mockBlockStateEquiv3.EXPECT().GetAllBlocksAtDepth(gomock.Any()).Return(h1)
-	mockBlockStateEquiv3.EXPECT().GetHeader(h).Return(testSecVrfHeader, nil)
-
 	// Case 0: First element not preruntime digest
 	header0 := newTestHeader(t, testInvalidSeal, testInvalidSeal)
 
@@ -594,45 +542,31 @@ func Test_verifier_verifyAuthorshipRight(t *testing.T) {
 	signAndAddSeal(t, kp, header6, []byte{1})
 	babeVerifier4 := newTestVerifier(t, kp, mockBlockState, scale.MaxUint128, true)
 
-	// Case 7: GetAuthorityIndex Err
-	babeParentPrd, err := testBabePrimaryPreDigest.ToPreRuntimeDigest()
-	assert.NoError(t, err)
-	babeParentHeader := newTestHeader(t, *babeParentPrd)
-
-	parentHash := encodeAndHashHeader(t, babeParentHeader)
+	// Case 7: Valid seal - BabePrimaryPreDigest
 	babePrd3, err := testBabePrimaryPreDigest.ToPreRuntimeDigest()
 	assert.NoError(t, err)
-
 	header7 := newTestHeader(t, *babePrd3)
-	header7.ParentHash = parentHash
 
 	hash := encodeAndHashHeader(t, header7)
 	signAndAddSeal(t, kp, header7, hash[:])
 	babeVerifier5 := newTestVerifier(t, kp, mockBlockState, scale.MaxUint128, false)
 
-	//// Case 8: Get header error
-	babeVerifier6 := newTestVerifier(t, kp, mockBlockStateErr, scale.MaxUint128, false)
-
-	// Case 9: Equivocate case primary
-	babeVerifier7 := newTestVerifier(t, kp, mockBlockStateEquiv1, scale.MaxUint128, false)
-
-	// Case 10: Equivocate case secondary plain
+	// Case 8: Valid seal - BabeSecondaryPlainPreDigest
 	babeSecPlainPrd2, err := testBabeSecondaryPlainPreDigest.ToPreRuntimeDigest()
 	assert.NoError(t, err)
 	header8 := newTestHeader(t, *babeSecPlainPrd2)
 
 	hash2 := encodeAndHashHeader(t, header8)
 	signAndAddSeal(t, kp, header8, hash2[:])
-	babeVerifier8 := newTestVerifier(t, kp, mockBlockStateEquiv2, scale.MaxUint128, true)
+	babeVerifier6 := newTestVerifier(t, kp, mockBlockState, scale.MaxUint128, true)
 
-	// Case 11: equivocation case secondary VRF
+	// Case 9: Valid seal - BabeSecondaryVrfPreDigest
 	encVrfDigest := newEncodedBabeDigest(t, testBabeSecondaryVRFPreDigest)
-	assert.NoError(t, err)
 	header9 := newTestHeader(t, *types.NewBABEPreRuntimeDigest(encVrfDigest))
 
 	hash3 := encodeAndHashHeader(t, header9)
 	signAndAddSeal(t, kp, header9, hash3[:])
-	babeVerifier9 := newTestVerifier(t, kp, mockBlockStateEquiv3, scale.MaxUint128, true)
+	babeVerifier7 := newTestVerifier(t, kp, mockBlockState, scale.MaxUint128, true)
 
 	tests := []struct {
 		name     string
@@ -689,32 +623,19 @@ func Test_verifier_verifyAuthorshipRight(t *testing.T) {
 			expErr:   ErrBadSignature,
 		},
 		{
-			name:     "valid digest items, getAuthorityIndex error",
+			name:     "valid seal - primary",
 			verifier: *babeVerifier5,
 			header:   header7,
 		},
 		{
-			name:     "get header err",
+			name:     "valid seal - secondary plain",
 			verifier: *babeVerifier6,
-			header:   header7,
-		},
-		{
-			name:     "equivocate - primary",
-			verifier: *babeVerifier7,
-			header:   header7,
-			expErr:   ErrProducerEquivocated,
-		},
-		{
-			name:     "equivocate - secondary plain",
-			verifier: *babeVerifier8,
 			header:   header8,
-			expErr:   ErrProducerEquivocated,
 		},
 		{
-			name:     "equivocate - secondary vrf",
-			verifier: *babeVerifier9,
+			name:     "valid seal - secondary vrf",
+			verifier: *babeVerifier7,
 			header:   header9,
-			expErr:   ErrProducerEquivocated,
 		},
 	}
 	for _, tt := range tests {
@@ -1205,3 +1126,87 @@ type args struct {
 		})
 	}
 }
+
+func TestVerificationManager_checkEquivocation(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	info := &verifierInfo{
+		authorities: []types.Authority{*types.NewAuthority(kp.Public(), 1)},
+	}
+
+	newHeader := func(slot uint64, number int64) *types.Header {
+		preDigest := types.BabeSecondaryPlainPreDigest{SlotNumber: slot}
+		prd, err := preDigest.ToPreRuntimeDigest()
+		require.NoError(t, err)
+		header := newTestHeader(t, *prd)
+		header.Number = big.NewInt(number)
+		signAndAddSeal(t, kp, header, []byte{1})
+		return header
+	}
+
+	ctrl := gomock.NewController(t)
+	vm, err := NewVerificationManager(NewMockBlockState(ctrl), NewMockEpochState(ctrl))
+	require.NoError(t, err)
+
+	first := newHeader(1, 1)
+	proof, err := vm.checkEquivocation(first, info)
+	require.NoError(t, err)
+	require.Nil(t, proof)
+
+	// the same block is verified twice
+	proof, err = vm.checkEquivocation(first, info)
+	require.NoError(t, err)
+	require.Nil(t, proof)
+
+	second := newHeader(1, 2)
+	proof, err = vm.checkEquivocation(second, info)
+	require.NoError(t, err)
+	require.NotNil(t, proof)
+	require.Equal(t, kp.Public().(*sr25519.PublicKey).AsBytes(), proof.Offender)
+	require.Equal(t, uint64(1), proof.Slot)
+	require.Equal(t, first.Hash(), proof.FirstHeader.Hash())
+	require.Equal(t, second.Hash(), proof.SecondHeader.Hash())
+
+	// the headers of old slots are pruned
+	proof, err = vm.checkEquivocation(newHeader(maxEquivocationSlotCapacity+2, 3), info)
+	require.NoError(t, err)
+	require.Nil(t, proof)
+	require.NotContains(t, vm.slotHeaders, uint64(1))
+
+	proof, err = vm.checkEquivocation(newHeader(1, 4), info)
+	require.NoError(t, err)
+	require.Nil(t, proof)
+	require.NotContains(t, vm.slotHeaders, uint64(1))
+}
+
+func TestVerificationManager_reportEquivocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	vm, err := NewVerificationManager(NewMockBlockState(ctrl), NewMockEpochState(ctrl))
+	require.NoError(t, err)
+
+	proof := &types.BabeEquivocationProof{
+		Slot:         1,
+		FirstHeader:  *types.NewEmptyHeader(),
+		SecondHeader: *types.NewEmptyHeader(),
+	}
+
+	// equivocations are only logged without reporter
+	vm.reportEquivocation(proof)
+
+	reported := make(chan struct{})
+	reporter := NewMockEquivocationReporter(ctrl)
+	reporter.EXPECT().ReportBabeEquivocation(proof).DoAndReturn(func(*types.BabeEquivocationProof) error {
+		close(reported)
+		return errors.New("test error")
+	})
+	vm.SetEquivocationReporter(reporter)
+
+	vm.reportEquivocation(proof)
+
+	select {
+	case <-reported:
+	case <-time.After(time.Second):
+		t.Fatal("equivocation was not reported")
+	}
+}
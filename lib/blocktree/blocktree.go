@@ -32,6 +32,10 @@ type BlockTree struct {
 	leaves *leafMap
 	sync.RWMutex
 	runtime *sync.Map // map[Hash]runtime.Instance
+
+	// pools holds the instance pools of the runtimes of the blocks, keyed by code hash
+	pools     map[Hash]*runtime.InstancePool
+	poolsLock sync.Mutex
 }
 
 // NewEmptyBlockTree creates a BlockTree with a nil head
@@ -40,6 +44,7 @@ func NewEmptyBlockTree() *BlockTree {
 		root:    nil,
 		leaves:  newEmptyLeafMap(),
 		runtime: &sync.Map{},
+		pools:   make(map[Hash]*runtime.InstancePool),
 	}
 }
 
@@ -58,6 +63,7 @@ func NewBlockTreeFromRoot(root *types.Header) *BlockTree {
 		root:    n,
 		leaves:  newLeafMap(n),
 		runtime: &sync.Map{},
+		pools:   make(map[Hash]*runtime.InstancePool),
 	}
 }
 
@@ -169,6 +175,7 @@ func (bt *BlockTree) Prune(finalised Hash) (pruned []Hash) {
 	for _, hash := range pruned {
 		bt.runtime.Delete(hash)
 	}
+	bt.prunePools()
 
 	leavesGauge.Set(float64(len(bt.leaves.nodes())))
 	return pruned
@@ -419,3 +426,67 @@ func (bt *BlockTree) GetBlockRuntime(hash common.Hash) (runtime.Instance, error)
 	}
 	return ins.(runtime.Instance), nil
 }
+
+// GetBlockRuntimePool returns the instance pool of the runtime of the block with the given hash.
+// The blocks whose runtimes have the same code hash share the same pool, which is created
+// with the given size from the runtime of the first block requesting it.
+func (bt *BlockTree) GetBlockRuntimePool(hash common.Hash, size int) (*runtime.InstancePool, error) {
+	instance, err := bt.GetBlockRuntime(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	bt.poolsLock.Lock()
+	defer bt.poolsLock.Unlock()
+
+	codeHash := instance.GetCodeHash()
+	pool, has := bt.pools[codeHash]
+	if has {
+		return pool, nil
+	}
+
+	pool, err = runtime.NewInstancePool(instance, size)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create runtime instance pool for code hash %s: %w", codeHash, err)
+	}
+
+	bt.pools[codeHash] = pool
+	return pool, nil
+}
+
+// prunePools stops and removes the instance pools of the code hashes
+// which aren't used by the runtimes of the blocks anymore.
+// The pools are stopped once the calls in progress complete.
+func (bt *BlockTree) prunePools() {
+	for _, pool := range bt.removeUnusedPools() {
+		go pool.Stop()
+	}
+}
+
+// removeUnusedPools removes and returns the instance pools of the code hashes
+// which aren't used by the runtimes of the blocks anymore.
+func (bt *BlockTree) removeUnusedPools() (unused []*runtime.InstancePool) {
+	bt.poolsLock.Lock()
+	defer bt.poolsLock.Unlock()
+
+	if len(bt.pools) == 0 {
+		return nil
+	}
+
+	used := make(map[Hash]struct{})
+	bt.runtime.Range(func(_, instance interface{}) bool {
+		used[instance.(runtime.Instance).GetCodeHash()] = struct{}{}
+		return true
+	})
+
+	for codeHash, pool := range bt.pools {
+		if _, has := used[codeHash]; has {
+			continue
+		}
+
+		unused = append(unused, pool)
+		delete(bt.pools, codeHash)
+	}
+
+	return unused
+}
@@ -14,6 +14,7 @@
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/mocks"
 	"github.com/stretchr/testify/require"
 )
 
@@ -456,6 +457,50 @@ func TestBlockTree_Prune(t *testing.T) {
 	}
 }
 
+func TestBlockTree_GetBlockRuntimePool(t *testing.T) {
+	bt := NewBlockTreeFromRoot(testHeader)
+
+	first := &types.Header{ParentHash: testHeader.Hash(), Number: big.NewInt(1)}
+	second := &types.Header{ParentHash: testHeader.Hash(), Number: big.NewInt(1), StateRoot: common.Hash{1}}
+	require.NoError(t, bt.AddBlock(first, time.Unix(0, 0)))
+	require.NoError(t, bt.AddBlock(second, time.Unix(0, 0)))
+
+	newInstance := func(codeHash common.Hash) *mocks.Instance {
+		clone := new(mocks.Instance)
+		clone.On("Stop")
+		instance := new(mocks.Instance)
+		instance.On("GetCodeHash").Return(codeHash)
+		instance.On("Clone").Return(clone, nil)
+		return instance
+	}
+
+	// the root and the first block run the same code
+	instance := newInstance(common.Hash{1})
+	bt.StoreRuntime(testHeader.Hash(), instance)
+	bt.StoreRuntime(first.Hash(), instance)
+	bt.StoreRuntime(second.Hash(), newInstance(common.Hash{2}))
+
+	rootPool, err := bt.GetBlockRuntimePool(testHeader.Hash(), 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, rootPool.Size())
+
+	pool, err := bt.GetBlockRuntimePool(first.Hash(), 2)
+	require.NoError(t, err)
+	require.Same(t, rootPool, pool)
+
+	pool, err = bt.GetBlockRuntimePool(second.Hash(), 2)
+	require.NoError(t, err)
+	require.NotSame(t, rootPool, pool)
+
+	_, err = bt.GetBlockRuntimePool(common.Hash{3}, 2)
+	require.ErrorIs(t, err, ErrFailedToGetRuntime)
+
+	// finalising the first block prunes the pool of the code run by the second block
+	bt.Prune(first.Hash())
+	require.Len(t, bt.pools, 1)
+	require.Same(t, rootPool, bt.pools[common.Hash{1}])
+}
+
 func TestBlockTree_GetHashByNumber(t *testing.T) {
 	bt, _ := createTestBlockTree(t, testHeader, 8)
 	best := bt.DeepestBlockHash()
@@ -62,6 +62,12 @@ func PublicKeyToAddress(pub PublicKey) common.Address {
 	return publicKeyBytesToAddress(enc)
 }
 
+// AccountIDToAddress returns an ss58 address given the 32 bytes id of an account
+func AccountIDToAddress(id []byte) common.Address {
+	enc := append([]byte{42}, id...)
+	return publicKeyBytesToAddress(enc)
+}
+
 func publicKeyBytesToAddress(b []byte) common.Address {
 	hasher, err := blake2b.New(64, nil)
 	if err != nil {
@@ -5,8 +5,8 @@
 
 import (
 	"errors"
+	"runtime"
 	"sync"
-	"time"
 
 	"github.com/ChainSafe/gossamer/internal/log"
 )
@@ -24,15 +24,15 @@ type SignatureInfo struct {
 	VerifyFunc SigVerifyFunc
 }
 
-// SignatureVerifier ...
+// SignatureVerifier verifies a batch of signatures with a pool of workers
 type SignatureVerifier struct {
-	batch   []*SignatureInfo
-	init    bool // Indicates whether the batch processing is started.
-	invalid bool // Set to true if any signature verification fails.
+	batch   []*SignatureInfo // signatures added before the batch processing is started
+	init    bool             // Indicates whether the batch processing is started.
+	invalid bool             // Set to true if any signature verification fails.
+	workers int
+	jobs    chan *SignatureInfo
 	logger  log.LeveledLogger
-	closeCh chan struct{}
 	sync.RWMutex
-	sync.Once
 	sync.WaitGroup
 }
 
@@ -43,43 +43,52 @@ type SignatureVerifier struct {
 func NewSignatureVerifier(logger log.LeveledLogger) *SignatureVerifier {
 	return &SignatureVerifier{
 		batch:   make([]*SignatureInfo, 0),
-		init:    false,
-		invalid: false,
+		workers: runtime.NumCPU(),
 		logger:  logger,
-		RWMutex: sync.RWMutex{},
-		closeCh: make(chan struct{}),
 	}
 }
 
-// Start signature verification in batch.
+// Start signature verification in batch. The signatures added to the batch are verified
+// in the background by a pool of workers.
 func (sv *SignatureVerifier) Start() {
-	// Update the init state.
 	sv.Lock()
+	if sv.init {
+		sv.Unlock()
+		return
+	}
+
 	sv.init = true
+	sv.jobs = make(chan *SignatureInfo, sv.workers)
+	for i := 0; i < sv.workers; i++ {
+		sv.WaitGroup.Add(1)
+		go sv.work(sv.jobs)
+	}
+
+	pending := sv.batch
+	sv.batch = make([]*SignatureInfo, 0)
+	jobs := sv.jobs
 	sv.Unlock()
 
-	sv.WaitGroup.Add(1)
-
-	go func() {
-		defer sv.Done()
-		for {
-			select {
-			case <-sv.closeCh:
-				return
-			default:
-				signature := sv.Remove()
-				if signature == nil {
-					continue
-				}
-				err := signature.VerifyFunc(signature.PubKey, signature.Sign, signature.Msg)
-				if err != nil {
-					sv.logger.Errorf("[ext_crypto_start_batch_verify_version_1]: %s", err)
-					sv.Invalid()
-					return
-				}
-			}
+	for _, signature := range pending {
+		jobs <- signature
+	}
+}
+
+func (sv *SignatureVerifier) work(jobs <-chan *SignatureInfo) {
+	defer sv.Done()
+
+	for signature := range jobs {
+		// the result of the batch is already known, the remaining signatures are skipped
+		if sv.IsInvalid() {
+			continue
+		}
+
+		err := signature.VerifyFunc(signature.PubKey, signature.Sign, signature.Msg)
+		if err != nil {
+			sv.logger.Debugf("[ext_crypto_start_batch_verify_version_1]: %s", err)
+			sv.Invalid()
 		}
-	}()
+	}
 }
 
 // IsStarted ...
@@ -96,59 +105,70 @@ func (sv *SignatureVerifier) IsInvalid() bool {
 	return sv.invalid
 }
 
-// Invalid ...
+// Invalid marks the batch as invalid
 func (sv *SignatureVerifier) Invalid() {
-	sv.RLock()
-	defer sv.RUnlock()
+	sv.Lock()
+	defer sv.Unlock()
 	sv.invalid = true
 }
 
-// Add ...
+// Add adds the signature to the batch. The signature, message and public key are copied,
+// since they may be views of the memory of the runtime. Add must not be called concurrently
+// with Finish.
 func (sv *SignatureVerifier) Add(s *SignatureInfo) {
 	if sv.IsInvalid() {
 		return
 	}
 
-	sv.Lock()
-	defer sv.Unlock()
-	sv.batch = append(sv.batch, s)
-}
+	signature := &SignatureInfo{
+		PubKey:     append([]byte{}, s.PubKey...),
+		Sign:       append([]byte{}, s.Sign...),
+		Msg:        append([]byte{}, s.Msg...),
+		VerifyFunc: s.VerifyFunc,
+	}
 
-// Remove returns the first signature from the batch. Returns nil if batch is empty.
-func (sv *SignatureVerifier) Remove() *SignatureInfo {
 	sv.Lock()
-	defer sv.Unlock()
-	if len(sv.batch) == 0 {
-		return nil
+	if !sv.init {
+		sv.batch = append(sv.batch, signature)
+		sv.Unlock()
+		return
 	}
-	sign := sv.batch[0]
-	sv.batch = sv.batch[1:len(sv.batch)]
-	return sign
+	jobs := sv.jobs
+	sv.Unlock()
+
+	jobs <- signature
 }
 
-// Reset reset the signature verifier for reuse.
+// Reset reset the signature verifier for reuse, stopping the workers of the current batch.
 func (sv *SignatureVerifier) Reset() {
+	sv.Lock()
+	jobs := sv.jobs
+	sv.jobs = nil
+	sv.Unlock()
+
+	if jobs != nil {
+		close(jobs)
+		sv.Wait()
+	}
+
 	sv.Lock()
 	defer sv.Unlock()
 	sv.init = false
 	sv.batch = make([]*SignatureInfo, 0)
 	sv.invalid = false
-	sv.closeCh = make(chan struct{})
 }
 
 // Finish waits till batch is finished. Returns true if all the signatures are valid, Otherwise returns false.
 func (sv *SignatureVerifier) Finish() bool {
-	for {
-		time.Sleep(100 * time.Millisecond)
-		sv.Lock()
-		if sv.invalid || len(sv.batch) == 0 {
-			close(sv.closeCh)
-			sv.Unlock()
-			break
-		}
-		sv.RUnlock()
-	}
-	// Wait till start function to finish and then reset it.
+	// the signatures added before the start of the batch are verified as well
+	sv.Start()
+
+	sv.Lock()
+	close(sv.jobs)
+	sv.jobs = nil
+	sv.Unlock()
+
+	// Wait till the workers verified all the signatures and then reset it.
 	sv.Wait()
 	isInvalid := sv.IsInvalid()
 	sv.Reset()
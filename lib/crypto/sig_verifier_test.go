@@ -124,3 +124,50 @@ func TestVerifySignature(t *testing.T) {
 	}
 
 }
+
+func TestSignatureVerifier_Batch(t *testing.T) {
+	t.Parallel()
+
+	kp, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	signatures := make([]*crypto.SignatureInfo, 64)
+	for i := range signatures {
+		msg := []byte{byte(i)}
+		sig, err := kp.Sign(msg)
+		require.NoError(t, err)
+
+		signatures[i] = &crypto.SignatureInfo{
+			PubKey:     kp.Public().Encode(),
+			Sign:       sig,
+			Msg:        msg,
+			VerifyFunc: ed25519.VerifySignature,
+		}
+	}
+
+	signVerify := crypto.NewSignatureVerifier(log.New(log.SetWriter(io.Discard)))
+	for _, invalid := range []bool{false, true, false} {
+		signVerify.Start()
+		require.True(t, signVerify.IsStarted())
+
+		for _, sig := range signatures {
+			signVerify.Add(sig)
+		}
+
+		if invalid {
+			signVerify.Add(&crypto.SignatureInfo{
+				PubKey:     kp.Public().Encode(),
+				Sign:       signatures[0].Sign,
+				Msg:        []byte{0xff},
+				VerifyFunc: ed25519.VerifySignature,
+			})
+		}
+
+		// the message is copied when the signature is added
+		signatures[0].Msg[0] = 0xff
+
+		require.Equal(t, !invalid, signVerify.Finish())
+		signatures[0].Msg[0] = 0
+		require.False(t, signVerify.IsStarted())
+	}
+}
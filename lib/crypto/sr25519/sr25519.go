@@ -13,6 +13,7 @@
 
 	sr25519 "github.com/ChainSafe/go-schnorrkel"
 	"github.com/gtank/merlin"
+	"golang.org/x/crypto/blake2b"
 )
 
 const (
@@ -28,6 +29,8 @@
 	VRFOutputLength = 32
 	// VRFProofLength is the expected VFR proof length for sr25519.
 	VRFProofLength = 64
+	// Ed25519BytesLength is the length of the ed25519 compatible encoding of a private key.
+	Ed25519BytesLength = 64
 )
 
 // SigningContext is the context for signatures used or created with substrate
@@ -283,6 +286,62 @@ func (k *PrivateKey) Hex() string {
 	return "0x" + h
 }
 
+// NewPrivateKeyFromEd25519Bytes creates a private key from its ed25519 compatible encoding, which is
+// the encoding used by schnorrkel and polkadot-js: the secret scalar multiplied by the cofactor followed
+// by the nonce
+func NewPrivateKeyFromEd25519Bytes(in []byte) (*PrivateKey, error) {
+	if len(in) != Ed25519BytesLength {
+		return nil, fmt.Errorf("input to create sr25519 private key is not %d bytes", Ed25519BytesLength)
+	}
+
+	key := [32]byte{}
+	copy(key[:], in[:32])
+	divideScalarByCofactor(key[:])
+
+	nonce := [32]byte{}
+	copy(nonce[:], in[32:])
+
+	return &PrivateKey{key: sr25519.NewSecretKey(key, nonce)}, nil
+}
+
+// Ed25519Bytes returns the ed25519 compatible encoding of the private key, ie. the secret scalar
+// multiplied by the cofactor followed by the nonce. The nonce of the key is not accessible, so
+// it is derived from the secret scalar.
+func (k *PrivateKey) Ed25519Bytes() []byte {
+	if k.key == nil {
+		return nil
+	}
+
+	key := k.key.Encode()
+	nonce := blake2b.Sum256(key[:])
+	multiplyScalarByCofactor(key[:])
+
+	return append(key[:], nonce[:]...)
+}
+
+// multiplyScalarByCofactor multiplies the little endian scalar by the cofactor 8
+// see: https://github.com/w3f/schnorrkel/blob/master/src/scalars.rs
+func multiplyScalarByCofactor(s []byte) {
+	high := byte(0)
+	for i := range s {
+		r := s[i] & 0xe0 // carry
+		s[i] <<= 3
+		s[i] += high
+		high = r >> 5
+	}
+}
+
+// divideScalarByCofactor divides the little endian scalar by the cofactor 8
+func divideScalarByCofactor(s []byte) {
+	low := byte(0)
+	for i := len(s) - 1; i >= 0; i-- {
+		r := s[i] & 0x07 // remainder
+		s[i] >>= 3
+		s[i] += low
+		low = r << 5
+	}
+}
+
 // Verify uses the sr25519 signature algorithm to verify that the message was signed by
 // this public key; it returns true if this key created the signature for the message,
 // false otherwise
@@ -5,6 +5,7 @@
 
 import (
 	"crypto/rand"
+	"crypto/sha512"
 	"errors"
 	"fmt"
 	"testing"
@@ -70,6 +71,36 @@ func TestEncodeAndDecodePrivateKey(t *testing.T) {
 	require.Equal(t, exp, res.key.Encode())
 }
 
+func TestPrivateKey_Ed25519Bytes(t *testing.T) {
+	seed := make([]byte, SeedLength)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+
+	kp, err := NewKeypairFromSeed(seed)
+	require.NoError(t, err)
+
+	// the ed25519 compatible secret scalar is the clamped hash of the seed
+	h := sha512.Sum512(seed)
+	h[0] &= 248
+	h[31] &= 63
+	h[31] |= 64
+
+	enc := kp.Private().(*PrivateKey).Ed25519Bytes()
+	require.Len(t, enc, Ed25519BytesLength)
+	require.Equal(t, h[:32], enc[:32])
+
+	priv, err := NewPrivateKeyFromEd25519Bytes(enc)
+	require.NoError(t, err)
+	require.Equal(t, kp.Private().Encode(), priv.Encode())
+
+	pub, err := priv.Public()
+	require.NoError(t, err)
+	require.Equal(t, kp.Public(), pub)
+
+	_, err = NewPrivateKeyFromEd25519Bytes(enc[:32])
+	require.Error(t, err)
+}
+
 func TestEncodeAndDecodePublicKey(t *testing.T) {
 	kp, err := GenerateKeypair()
 	require.NoError(t, err)
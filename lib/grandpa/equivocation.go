@@ -0,0 +1,100 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package grandpa
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/telemetry"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+)
+
+// maxMisbehaviours is the number of the most recent equivocations kept by the service
+const maxMisbehaviours = 100
+
+// Misbehaviour is an equivocation of a GRANDPA voter detected by the service
+type Misbehaviour struct {
+	Proof    types.GrandpaEquivocationProof
+	Detected time.Time
+}
+
+// SetEquivocationReporter sets the reporter of the equivocations detected while validating votes
+func (s *Service) SetEquivocationReporter(reporter EquivocationReporter) {
+	s.mapLock.Lock()
+	defer s.mapLock.Unlock()
+	s.equivocationReporter = reporter
+}
+
+// Misbehaviours returns the most recent equivocations detected by the service, oldest first
+func (s *Service) Misbehaviours() []Misbehaviour {
+	s.mapLock.Lock()
+	defer s.mapLock.Unlock()
+
+	misbehaviours := make([]Misbehaviour, len(s.misbehaviours))
+	copy(misbehaviours, s.misbehaviours)
+	return misbehaviours
+}
+
+// handleEquivocation records the equivocation of the given voter in the current round, and
+// reports it in the background. It must be called with the map lock held.
+func (s *Service) handleEquivocation(voter ed25519.PublicKeyBytes, stage Subround, first, second *SignedVote) {
+	var equivocationStage types.GrandpaEquivocationStage
+	switch stage {
+	case prevote:
+		equivocationStage = types.PrevoteEquivocation
+	case precommit:
+		equivocationStage = types.PrecommitEquivocation
+	default:
+		// the runtime only accepts equivocations of prevotes and precommits
+		return
+	}
+
+	proof := &types.GrandpaEquivocationProof{
+		SetID: s.state.setID,
+		Stage: equivocationStage,
+		Equivocation: types.GrandpaEquivocation{
+			RoundNumber:     s.state.round,
+			ID:              voter,
+			FirstVote:       first.Vote,
+			FirstSignature:  first.Signature,
+			SecondVote:      second.Vote,
+			SecondSignature: second.Signature,
+		},
+	}
+
+	logger.Warnf("voter 0x%x equivocated in %s of round %d with votes for %s and %s",
+		voter, stage, s.state.round, first.Vote.Hash, second.Vote.Hash)
+
+	s.misbehaviours = append(s.misbehaviours, Misbehaviour{
+		Proof:    *proof,
+		Detected: time.Now(),
+	})
+	if len(s.misbehaviours) > maxMisbehaviours {
+		s.misbehaviours = append([]Misbehaviour(nil), s.misbehaviours[len(s.misbehaviours)-maxMisbehaviours:]...)
+	}
+
+	s.telemetry.SendMessage(telemetry.NewAfgEquivocation(
+		fmt.Sprintf("0x%x", voter),
+		fmt.Sprint(s.state.setID),
+		fmt.Sprint(s.state.round),
+		stage.String(),
+		first.Vote.Hash,
+		second.Vote.Hash,
+	))
+
+	reporter := s.equivocationReporter
+	if reporter == nil {
+		return
+	}
+
+	go func() {
+		err := reporter.ReportGrandpaEquivocation(proof)
+		if err != nil {
+			logger.Errorf("failed to report equivocation of voter 0x%x in round %d: %s",
+				voter, proof.Equivocation.RoundNumber, err)
+		}
+	}()
+}
@@ -70,7 +70,7 @@ func errRoundMismatch(got, want uint64) error {
 	ErrInvalidCatchUpRound = errors.New("catch up request is for future round")
 
 	// ErrInvalidCatchUpResponseRound is returned when a catch-up response is received with an invalid round
-	ErrInvalidCatchUpResponseRound = errors.New("catch up response is not for previous round")
+	ErrInvalidCatchUpResponseRound = errors.New("catch up response is not for the requested round")
 
 	// ErrGHOSTlessCatchUp is returned when a catch up response
 	// does not contain a valid grandpa-GHOST (ie. finalised block)
@@ -79,6 +79,10 @@ func errRoundMismatch(got, want uint64) error {
 	// ErrCatchUpResponseNotCompletable is returned when the round represented by the catch up response is not completable
 	ErrCatchUpResponseNotCompletable = errors.New("catch up response is not completable")
 
+	// ErrCatchUpResponseUnexpectedPeer is returned when a catch up response is received from
+	// another peer than the one the catch up request was sent to
+	ErrCatchUpResponseUnexpectedPeer = errors.New("catch up response is not from the requested peer")
+
 	// ErrServicePaused is returned if the service is paused and waiting for catch up messages
 	ErrServicePaused = errors.New("service is paused")
 
@@ -96,4 +100,11 @@ func errRoundMismatch(got, want uint64) error {
 	errVoteExists              = errors.New("already have vote")
 	errVoteToSignatureMismatch = errors.New("votes and authority count mismatch")
 	errInvalidVoteBlock        = errors.New("block in vote is not descendant of previously finalised block")
+
+	// warp sync errors
+	errWarpSyncBeginNotFinalised   = errors.New("warp sync begin block is not finalised")
+	errNoSetChangeDigest           = errors.New("no authority set change digest found")
+	errEmptyWarpSyncProof          = errors.New("warp sync proof has no fragments")
+	errWarpSyncFragmentNoChange    = errors.New("warp sync fragment does not change the authority set")
+	errJustificationTargetMismatch = errors.New("justification target does not match the header")
 )
@@ -20,6 +20,7 @@
 	"github.com/ChainSafe/gossamer/lib/blocktree"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/signer"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -48,7 +49,7 @@ type Service struct {
 	blockState     BlockState
 	grandpaState   GrandpaState
 	digestHandler  DigestHandler
-	keypair        *ed25519.Keypair // TODO: change to grandpa keystore (#1870)
+	signer         signer.Signer // signer of the GRANDPA authority key
 	mapLock        sync.Mutex
 	chanLock       sync.Mutex
 	roundLock      sync.Mutex
@@ -79,21 +80,27 @@ type Service struct {
 	finalisedCh      chan *types.FinalisationInfo
 	neighbourMessage *NeighbourMessage // cached neighbour message
 
+	equivocationReporter EquivocationReporter
+	slashingProtection   SlashingProtection
+	misbehaviours        []Misbehaviour // most recent equivocations, oldest first
+
 	telemetry telemetry.Client
 }
 
 // Config represents a GRANDPA service configuration
 type Config struct {
-	LogLvl        log.Level
-	BlockState    BlockState
-	GrandpaState  GrandpaState
-	DigestHandler DigestHandler
-	Network       Network
-	Voters        []Voter
-	Keypair       *ed25519.Keypair
-	Authority     bool
-	Interval      time.Duration
-	Telemetry     telemetry.Client
+	LogLvl             log.Level
+	BlockState         BlockState
+	GrandpaState       GrandpaState
+	DigestHandler      DigestHandler
+	Network            Network
+	Voters             []Voter
+	Keypair            *ed25519.Keypair
+	Signer             signer.Signer      // used instead of Keypair if set, eg. for a remote signer
+	SlashingProtection SlashingProtection // if set, checked before signing a vote
+	Authority          bool
+	Interval           time.Duration
+	Telemetry          telemetry.Client
 }
 
 // NewService returns a new GRANDPA Service instance.
@@ -110,7 +117,12 @@ func NewService(cfg *Config) (*Service, error) {
 		return nil, ErrNilDigestHandler
 	}
 
-	if cfg.Keypair == nil && cfg.Authority {
+	voteSigner := cfg.Signer
+	if voteSigner == nil && cfg.Keypair != nil {
+		voteSigner = signer.NewLocal(cfg.Keypair)
+	}
+
+	if voteSigner == nil && cfg.Authority {
 		return nil, ErrNilKeypair
 	}
 
@@ -122,7 +134,7 @@ func NewService(cfg *Config) (*Service, error) {
 
 	var pub string
 	if cfg.Authority {
-		pub = cfg.Keypair.Public().Hex()
+		pub = voteSigner.Public().Hex()
 	}
 
 	logger.Debugf(
@@ -159,7 +171,7 @@ func NewService(cfg *Config) (*Service, error) {
 		blockState:         cfg.BlockState,
 		grandpaState:       cfg.GrandpaState,
 		digestHandler:      cfg.DigestHandler,
-		keypair:            cfg.Keypair,
+		signer:             voteSigner,
 		authority:          cfg.Authority,
 		prevotes:           new(sync.Map),
 		precommits:         new(sync.Map),
@@ -173,6 +185,7 @@ func NewService(cfg *Config) (*Service, error) {
 		network:            cfg.Network,
 		finalisedCh:        finalisedCh,
 		interval:           cfg.Interval,
+		slashingProtection: cfg.SlashingProtection,
 		telemetry:          cfg.Telemetry,
 	}
 
@@ -272,11 +285,11 @@ func (s *Service) updateAuthorities() error {
 }
 
 func (s *Service) publicKeyBytes() ed25519.PublicKeyBytes {
-	return s.keypair.Public().(*ed25519.PublicKey).AsBytes()
+	return s.signer.Public().(*ed25519.PublicKey).AsBytes()
 }
 
 func (s *Service) sendTelemetryAuthoritySet() {
-	authorityID := s.keypair.Public().Hex()
+	authorityID := s.signer.Public().Hex()
 	authorities := make([]string, len(s.state.voters))
 	for i, voter := range s.state.voters {
 		authorities[i] = fmt.Sprint(voter.ID)
@@ -418,7 +431,7 @@ func (s *Service) handleIsPrimary() (bool, error) {
 
 	// if primary, broadcast the best final candidate from the previous round
 	// otherwise, do nothing
-	if !bytes.Equal(primary.Key.Encode(), s.keypair.Public().Encode()) {
+	if !bytes.Equal(primary.Key.Encode(), s.signer.Public().Encode()) {
 		return false, nil
 	}
 
@@ -1319,6 +1332,13 @@ func (s *Service) GetRound() uint64 {
 	return s.state.round
 }
 
+// roundAndSetID returns the current round number and set ID
+func (s *Service) roundAndSetID() (round, setID uint64) {
+	s.roundLock.Lock()
+	defer s.roundLock.Unlock()
+	return s.state.round, s.state.setID
+}
+
 // GetVoters returns the list of current grandpa.Voters
 func (s *Service) GetVoters() Voters {
 	return s.state.voters
@@ -9,6 +9,8 @@
 	"fmt"
 	"math/big"
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/telemetry"
@@ -21,11 +23,26 @@
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
+// catchUpRequestTimeout is the duration after which a catch up request that
+// has not been answered is dropped, so that another one can be sent
+var catchUpRequestTimeout = time.Second * 45
+
 // MessageHandler handles GRANDPA consensus messages
 type MessageHandler struct {
 	grandpa    *Service
 	blockState BlockState
 	telemetry  telemetry.Client
+
+	// catchUpRequest is the catch up request we are waiting a response for, if any
+	catchUpRequest     *pendingCatchUpRequest
+	catchUpRequestLock sync.Mutex
+}
+
+type pendingCatchUpRequest struct {
+	to    peer.ID
+	round uint64
+	setID uint64
+	sent  time.Time
 }
 
 // NewMessageHandler returns a new MessageHandler
@@ -56,17 +73,15 @@ func (h *MessageHandler) handleMessage(from peer.ID, m GrandpaMessage) (network.
 		return nil, h.handleCommitMessage(msg)
 	case *NeighbourMessage:
 		// we can afford to not retry handling neighbour message, if it errors.
-		return nil, h.handleNeighbourMessage(msg)
+		return nil, h.handleNeighbourMessage(from, msg)
 	case *CatchUpRequest:
 		return h.handleCatchUpRequest(msg)
 	case *CatchUpResponse:
-		err := h.handleCatchUpResponse(msg)
+		err := h.handleCatchUpResponse(from, msg)
 		if errors.Is(err, blocktree.ErrNodeNotFound) {
-			// TODO: we are adding these messages to reprocess them again, but we
-			// haven't added code to reprocess them. Do that.
-			// Also, revisit if we need to add these message in synchronous manner
-			// or not. If not, change catchUpResponseMessages to a normal map.  #1531
-			h.grandpa.tracker.addCatchUpResponse(msg)
+			// we haven't synced the caught up block yet, add this to the tracker
+			// to process it again once the block is imported
+			h.grandpa.tracker.addCatchUpResponse(from, msg)
 		}
 		return nil, err
 	default:
@@ -74,7 +89,11 @@ func (h *MessageHandler) handleMessage(from peer.ID, m GrandpaMessage) (network.
 	}
 }
 
-func (h *MessageHandler) handleNeighbourMessage(msg *NeighbourMessage) error {
+func (h *MessageHandler) handleNeighbourMessage(from peer.ID, msg *NeighbourMessage) error {
+	if err := h.sendCatchUpRequest(from, msg); err != nil {
+		return err
+	}
+
 	currFinalized, err := h.blockState.GetFinalisedHeader(0, 0)
 	if err != nil {
 		return err
@@ -145,7 +164,49 @@ func (h *MessageHandler) handleCommitMessage(msg *CommitMessage) error {
 		return err
 	}
 
-	// TODO: re-add catch-up logic (#1531)
+	return nil
+}
+
+// sendCatchUpRequest sends a catch up request to the given peer if its neighbour message
+// shows that it finalised a round higher than our current round in the same voter set.
+// Only one catch up request is sent at a time, until it is answered or times out.
+func (h *MessageHandler) sendCatchUpRequest(to peer.ID, msg *NeighbourMessage) error {
+	if !h.grandpa.authority {
+		return nil
+	}
+
+	round, setID := h.grandpa.roundAndSetID()
+	if msg.SetID != setID || msg.Round <= round {
+		return nil
+	}
+
+	h.catchUpRequestLock.Lock()
+	defer h.catchUpRequestLock.Unlock()
+
+	if h.catchUpRequest != nil && time.Since(h.catchUpRequest.sent) < catchUpRequestTimeout {
+		return nil
+	}
+
+	req := newCatchUpRequest(msg.Round, msg.SetID)
+	cm, err := req.ToConsensusMessage()
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf(
+		"sending catch up request for round %d and set id %d to peer %s, our round is %d",
+		req.Round, req.SetID, to, round)
+
+	if err = h.grandpa.network.SendMessage(to, cm); err != nil {
+		return fmt.Errorf("cannot send catch up request to peer %s: %w", to, err)
+	}
+
+	h.catchUpRequest = &pendingCatchUpRequest{
+		to:    to,
+		round: req.Round,
+		setID: req.SetID,
+		sent:  time.Now(),
+	}
 	return nil
 }
 
@@ -157,11 +218,12 @@ func (h *MessageHandler) handleCatchUpRequest(msg *CatchUpRequest) (*ConsensusMe
 	logger.Debugf("received catch up request for round %d and set id %d",
 		msg.Round, msg.SetID)
 
-	if msg.SetID != h.grandpa.state.setID {
+	round, setID := h.grandpa.roundAndSetID()
+	if msg.SetID != setID {
 		return nil, ErrSetIDMismatch
 	}
 
-	if msg.Round >= h.grandpa.state.round {
+	if msg.Round >= round {
 		return nil, ErrInvalidCatchUpRound
 	}
 
@@ -176,34 +238,57 @@ func (h *MessageHandler) handleCatchUpRequest(msg *CatchUpRequest) (*ConsensusMe
 	return resp.ToConsensusMessage()
 }
 
-func (h *MessageHandler) handleCatchUpResponse(msg *CatchUpResponse) error {
+func (h *MessageHandler) handleCatchUpResponse(from peer.ID, msg *CatchUpResponse) error {
 	if !h.grandpa.authority {
 		return nil
 	}
 
 	logger.Debugf(
-		"received catch up response with hash %s for round %d and set id %d",
-		msg.Hash, msg.Round, msg.SetID)
+		"received catch up response with hash %s for round %d and set id %d from peer %s",
+		msg.Hash, msg.Round, msg.SetID, from)
 
-	// TODO: re-add catch-up logic (#1531)
-	if true {
-		return nil
-	}
+	h.catchUpRequestLock.Lock()
+	defer h.catchUpRequestLock.Unlock()
 
 	// if we aren't currently expecting a catch up response, return
-	if !h.grandpa.paused.Load().(bool) {
-		logger.Debug("not currently paused, ignoring catch up response")
+	req := h.catchUpRequest
+	if req == nil {
+		logger.Debug("no catch up request sent, ignoring catch up response")
 		return nil
 	}
 
-	if msg.SetID != h.grandpa.state.setID {
+	if from != req.to {
+		return fmt.Errorf("%w: received from peer %s, requested from peer %s",
+			ErrCatchUpResponseUnexpectedPeer, from, req.to)
+	}
+
+	err := h.processCatchUpResponse(req, msg)
+	if errors.Is(err, blocktree.ErrNodeNotFound) {
+		// the request stays pending while the tracker waits for the block
+		return err
+	}
+
+	// the response is handled or invalid, either way another request can be sent
+	h.catchUpRequest = nil
+	return err
+}
+
+// processCatchUpResponse verifies the catch up response to the given request and applies it
+func (h *MessageHandler) processCatchUpResponse(req *pendingCatchUpRequest, msg *CatchUpResponse) error {
+	round, setID := h.grandpa.roundAndSetID()
+	if msg.SetID != req.setID || msg.SetID != setID {
 		return ErrSetIDMismatch
 	}
 
-	if msg.Round != h.grandpa.state.round-1 {
+	if msg.Round != req.round {
 		return ErrInvalidCatchUpResponseRound
 	}
 
+	if msg.Round < round {
+		logger.Debugf("already at round %d, ignoring catch up response", round)
+		return nil
+	}
+
 	prevote, err := h.verifyPreVoteJustification(msg)
 	if err != nil {
 		return err
@@ -221,6 +306,24 @@ func (h *MessageHandler) handleCatchUpResponse(msg *CatchUpResponse) error {
 		return err
 	}
 
+	if err = h.applyCatchUpResponse(msg, prevote); err != nil {
+		return err
+	}
+
+	logger.Debugf("caught up to round %d with set id %d, finalised block %s",
+		msg.Round, msg.SetID, msg.Hash)
+	return nil
+}
+
+// applyCatchUpResponse stores the votes of a verified catch up response and finalises
+// the block it contains. The voter then moves on to the round following the response
+// round, once it notices that a higher round has been finalised.
+func (h *MessageHandler) applyCatchUpResponse(msg *CatchUpResponse, prevote common.Hash) error {
+	prevoted, err := h.blockState.GetHeader(prevote)
+	if err != nil {
+		return err
+	}
+
 	// set prevotes and precommits in db
 	if err = h.grandpa.grandpaState.SetPrevotes(msg.Round, msg.SetID, msg.PreVoteJustification); err != nil {
 		return err
@@ -230,19 +333,26 @@ func (h *MessageHandler) handleCatchUpResponse(msg *CatchUpResponse) error {
 		return err
 	}
 
-	// update state and signal to grandpa we are ready to initiate
-	head, err := h.grandpa.blockState.GetHeader(msg.Hash)
+	h.grandpa.mapLock.Lock()
+	h.grandpa.preVotedBlock[msg.Round] = NewVoteFromHeader(prevoted)
+	h.grandpa.bestFinalCandidate[msg.Round] = NewVote(msg.Hash, msg.Number)
+	h.grandpa.mapLock.Unlock()
+
+	if has, _ := h.blockState.HasFinalisedBlock(msg.Round, msg.SetID); has {
+		return nil
+	}
+
+	just, err := scale.Marshal(*newJustification(msg.Round, msg.Hash, msg.Number, msg.PreCommitJustification))
 	if err != nil {
 		return err
 	}
 
-	h.grandpa.head = head
-	h.grandpa.state.round = msg.Round
-	close(h.grandpa.resumed)
-	h.grandpa.resumed = make(chan struct{})
-	h.grandpa.paused.Store(false)
-	logger.Debugf("caught up to round; unpaused service and grandpa state round is %d", h.grandpa.state.round)
-	return nil
+	if err = h.blockState.SetJustification(msg.Hash, just); err != nil {
+		return err
+	}
+
+	// set finalised head for round in db
+	return h.blockState.SetFinalisedHash(msg.Hash, msg.Round, msg.SetID)
 }
 
 // verifyCatchUpResponseCompletability verifies that the pre-commit block is a descendant of, or is, the pre-voted block
@@ -16,6 +16,7 @@
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/stretchr/testify/require"
 )
@@ -229,6 +230,43 @@ func TestMessageHandler_NeighbourMessage(t *testing.T) {
 	require.Nil(t, out)
 }
 
+func TestMessageHandler_NeighbourMessage_CatchUpRequest(t *testing.T) {
+	gs, st := newTestService(t)
+
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	h := NewMessageHandler(gs, st.Block, telemetryMock)
+	gs.state.round = 1
+
+	// neighbour message for a lower round doesn't trigger a catch up request
+	msg := &NeighbourMessage{
+		Version: 1,
+		Round:   1,
+		SetID:   gs.state.setID,
+		Number:  1,
+	}
+	_, err := h.handleMessage("testpeer", msg)
+	require.NoError(t, err)
+	require.Nil(t, h.catchUpRequest)
+
+	msg.Round = 5
+	_, err = h.handleMessage("testpeer", msg)
+	require.NoError(t, err)
+	require.NotNil(t, h.catchUpRequest)
+	require.Equal(t, peer.ID("testpeer"), h.catchUpRequest.to)
+	require.Equal(t, uint64(5), h.catchUpRequest.round)
+	require.Equal(t, gs.state.setID, h.catchUpRequest.setID)
+
+	// only one catch up request is outstanding at a time
+	msg.Round = 6
+	_, err = h.handleMessage("otherpeer", msg)
+	require.NoError(t, err)
+	require.Equal(t, peer.ID("testpeer"), h.catchUpRequest.to)
+	require.Equal(t, uint64(5), h.catchUpRequest.round)
+}
+
 func TestMessageHandler_VerifyJustification_InvalidSig(t *testing.T) {
 	gs, st := newTestService(t)
 	gs.state.round = 77
@@ -574,6 +612,84 @@ func TestMessageHandler_HandleCatchUpResponse(t *testing.T) {
 	require.Equal(t, round+1, gs.state.round)
 }
 
+func TestMessageHandler_HandleCatchUpResponse_Requested(t *testing.T) {
+	gs, st := newTestService(t)
+
+	body, err := types.NewBodyFromBytes([]byte{0})
+	require.NoError(t, err)
+
+	block := &types.Block{
+		Header: *testHeader,
+		Body:   *body,
+	}
+
+	err = st.Block.AddBlock(block)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	h := NewMessageHandler(gs, st.Block, telemetryMock)
+
+	round := uint64(77)
+	gs.state.round = 1
+
+	pvJust := buildTestJustification(t, int(gs.state.threshold()), round, gs.state.setID, kr, prevote)
+	pcJust := buildTestJustification(t, int(gs.state.threshold()), round, gs.state.setID, kr, precommit)
+	msg := &CatchUpResponse{
+		Round:                  round,
+		SetID:                  gs.state.setID,
+		PreVoteJustification:   pvJust,
+		PreCommitJustification: pcJust,
+		Hash:                   testHash,
+		Number:                 uint32(round),
+	}
+
+	const testPeer = peer.ID("testpeer")
+	h.catchUpRequest = &pendingCatchUpRequest{
+		to:    testPeer,
+		round: round + 1,
+		setID: gs.state.setID,
+		sent:  time.Now(),
+	}
+
+	// a response from another peer is ignored and the request stays pending
+	_, err = h.handleMessage(peer.ID("otherpeer"), msg)
+	require.ErrorIs(t, err, ErrCatchUpResponseUnexpectedPeer)
+	require.NotNil(t, h.catchUpRequest)
+
+	// an invalid response clears the request so another one can be sent
+	_, err = h.handleMessage(testPeer, msg)
+	require.ErrorIs(t, err, ErrInvalidCatchUpResponseRound)
+	require.Nil(t, h.catchUpRequest)
+
+	h.catchUpRequest = &pendingCatchUpRequest{
+		to:    testPeer,
+		round: round,
+		setID: gs.state.setID,
+		sent:  time.Now(),
+	}
+
+	out, err := h.handleMessage(testPeer, msg)
+	require.NoError(t, err)
+	require.Nil(t, out)
+	require.Nil(t, h.catchUpRequest)
+
+	highestRound, setID, err := st.Block.GetHighestRoundAndSetID()
+	require.NoError(t, err)
+	require.Equal(t, round, highestRound)
+	require.Equal(t, gs.state.setID, setID)
+
+	finalised, err := st.Block.GetFinalisedHash(round, gs.state.setID)
+	require.NoError(t, err)
+	require.Equal(t, testHash, finalised)
+
+	pvs, err := st.Grandpa.GetPrevotes(round, gs.state.setID)
+	require.NoError(t, err)
+	require.Equal(t, pvJust, pvs)
+}
+
 func TestMessageHandler_VerifyBlockJustification_WithEquivocatoryVotes(t *testing.T) {
 	auths := []types.GrandpaVoter{
 		{
@@ -85,7 +85,7 @@ func TestVoteMessageToConsensusMessage(t *testing.T) {
 			Stage:       precommit,
 			Hash:        v.Hash,
 			Number:      v.Number,
-			AuthorityID: gs.keypair.Public().(*ed25519.PublicKey).AsBytes(),
+			AuthorityID: gs.signer.Public().(*ed25519.PublicKey).AsBytes(),
 		},
 	}
 
@@ -103,7 +103,7 @@ func TestVoteMessageToConsensusMessage(t *testing.T) {
 			Stage:       prevote,
 			Hash:        v.Hash,
 			Number:      v.Number,
-			AuthorityID: gs.keypair.Public().(*ed25519.PublicKey).AsBytes(),
+			AuthorityID: gs.signer.Public().(*ed25519.PublicKey).AsBytes(),
 		},
 	}
 
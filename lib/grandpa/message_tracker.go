@@ -9,6 +9,8 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+
+	"github.com/libp2p/go-libp2p-core/peer"
 )
 
 // tracker keeps track of messages that have been received, but have failed to
@@ -26,8 +28,14 @@ type tracker struct {
 	stopped        chan struct{}
 
 	catchUpResponseMessageMutex sync.Mutex
-	// round(uint64) is used as key and *CatchUpResponse as value
-	catchUpResponseMessages map[uint64]*CatchUpResponse
+	// block hash is used as key and the response with the peer it was received from as value
+	catchUpResponseMessages map[common.Hash]*networkCatchUpResponse
+}
+
+// networkCatchUpResponse is a catch up response along with the peer it was received from
+type networkCatchUpResponse struct {
+	from peer.ID
+	msg  *CatchUpResponse
 }
 
 func newTracker(bs BlockState, handler *MessageHandler) *tracker {
@@ -39,7 +47,7 @@ func newTracker(bs BlockState, handler *MessageHandler) *tracker {
 		mapLock:                 sync.Mutex{},
 		in:                      bs.GetImportedBlockNotifierChannel(),
 		stopped:                 make(chan struct{}),
-		catchUpResponseMessages: make(map[uint64]*CatchUpResponse),
+		catchUpResponseMessages: make(map[common.Hash]*networkCatchUpResponse),
 	}
 }
 
@@ -75,10 +83,13 @@ func (t *tracker) addCommit(cm *CommitMessage) {
 	t.commitMessages[cm.Vote.Hash] = cm
 }
 
-func (t *tracker) addCatchUpResponse(cr *CatchUpResponse) {
+func (t *tracker) addCatchUpResponse(from peer.ID, cr *CatchUpResponse) {
 	t.catchUpResponseMessageMutex.Lock()
 	defer t.catchUpResponseMessageMutex.Unlock()
-	t.catchUpResponseMessages[cr.Round] = cr
+	t.catchUpResponseMessages[cr.Hash] = &networkCatchUpResponse{
+		from: from,
+		msg:  cr,
+	}
 }
 
 func (t *tracker) handleBlocks() {
@@ -121,4 +132,18 @@ func (t *tracker) handleBlock(b *types.Block) {
 
 		delete(t.commitMessages, h)
 	}
+
+	// the catch up response is removed before handling it, since it is added
+	// back to the tracker if it still references a block we don't have
+	t.catchUpResponseMessageMutex.Lock()
+	cr, has := t.catchUpResponseMessages[h]
+	delete(t.catchUpResponseMessages, h)
+	t.catchUpResponseMessageMutex.Unlock()
+
+	if has {
+		_, err := t.handler.handleMessage(cr.from, cr.msg)
+		if err != nil {
+			logger.Warnf("failed to handle catch up response %v: %s", cr.msg, err)
+		}
+	}
 }
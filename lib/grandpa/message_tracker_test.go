@@ -12,6 +12,7 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/signer"
 
 	"github.com/stretchr/testify/require"
 )
@@ -28,10 +29,10 @@ func TestMessageTracker_ValidateMessage(t *testing.T) {
 		Number: big.NewInt(77),
 	}
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	_, msg, err := gs.createSignedVoteAndVoteMessage(NewVoteFromHeader(fake), prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	expected := &networkVoteMessage{
 		msg: msg,
@@ -60,10 +61,10 @@ func TestMessageTracker_SendMessage(t *testing.T) {
 		Number:     big.NewInt(4),
 	}
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	_, msg, err := gs.createSignedVoteAndVoteMessage(NewVoteFromHeader(next), prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	expected := &networkVoteMessage{
 		msg: msg,
@@ -106,10 +107,10 @@ func TestMessageTracker_ProcessMessage(t *testing.T) {
 		Number:     big.NewInt(4),
 	}
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	_, msg, err := gs.createSignedVoteAndVoteMessage(NewVoteFromHeader(next), prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	expected := &networkVoteMessage{
 		msg: msg,
@@ -151,11 +152,11 @@ func TestMessageTracker_MapInsideMap(t *testing.T) {
 	_, ok := gs.tracker.voteMessages[hash]
 	require.False(t, ok)
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	authorityID := kr.Alice().Public().(*ed25519.PublicKey).AsBytes()
 	_, msg, err := gs.createSignedVoteAndVoteMessage(NewVoteFromHeader(header), prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	gs.tracker.addVote(&networkVoteMessage{
 		msg: msg,
@@ -142,9 +142,19 @@ func (s *Service) handleNetworkMessage(from peer.ID, msg NotificationsMessage) (
 
 	switch r := resp.(type) {
 	case *ConsensusMessage:
-		if r != nil {
-			s.network.GossipMessage(resp)
+		if r == nil {
+			break
 		}
+
+		// catch up responses are only sent to the peer that requested them
+		if _, isCatchUpRequest := m.(*CatchUpRequest); isCatchUpRequest {
+			if err = s.network.SendMessage(from, resp); err != nil {
+				logger.Warnf("failed to send catch up response to peer %s: %s", from, err)
+			}
+			break
+		}
+
+		s.network.GossipMessage(resp)
 	case nil:
 	default:
 		logger.Warnf(
@@ -155,6 +165,8 @@ func (s *Service) handleNetworkMessage(from peer.ID, msg NotificationsMessage) (
 	switch m.(type) {
 	case *NeighbourMessage:
 		return false, nil
+	case *CatchUpRequest:
+		return false, nil
 	case *CatchUpResponse:
 		return false, nil
 	}
@@ -12,6 +12,7 @@
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
 )
 
 // BlockState is the interface required by GRANDPA into the block state
@@ -46,6 +47,9 @@ type GrandpaState interface {
 	GetCurrentSetID() (uint64, error)
 	GetAuthorities(setID uint64) ([]types.GrandpaVoter, error)
 	GetSetIDByBlockNumber(num *big.Int) (uint64, error)
+	GetSetIDChange(setID uint64) (*big.Int, error)
+	SetNextChange(authorities []types.GrandpaVoter, number *big.Int) error
+	IncrementSetID() error
 	SetLatestRound(round uint64) error
 	GetLatestRound() (uint64, error)
 	SetPrevotes(round, setID uint64, data []SignedVote) error
@@ -77,3 +81,19 @@ type Network interface {
 		batchHandler network.NotificationsMessageBatchHandler,
 	) error
 }
+
+//go:generate mockery --name EquivocationReporter --structname EquivocationReporter --case underscore --keeptree
+
+// EquivocationReporter is the interface required by GRANDPA to report equivocations to the runtime
+type EquivocationReporter interface {
+	ReportGrandpaEquivocation(proof *types.GrandpaEquivocationProof) error
+}
+
+//go:generate mockery --name SlashingProtection --structname SlashingProtection --case underscore --keeptree
+
+// SlashingProtection is the interface required by GRANDPA for the slashing protection database,
+// which refuses to sign two different votes in the same subround
+type SlashingProtection interface {
+	CheckAndRecordVote(authority crypto.PublicKey, setID, round uint64, subround uint8,
+		hash common.Hash, number uint32) error
+}
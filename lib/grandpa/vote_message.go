@@ -78,6 +78,14 @@ func (s *Service) receiveVoteMessages(ctx context.Context) {
 }
 
 func (s *Service) createSignedVoteAndVoteMessage(vote *Vote, stage Subround) (*SignedVote, *VoteMessage, error) {
+	if s.slashingProtection != nil {
+		err := s.slashingProtection.CheckAndRecordVote(s.signer.Public(), s.state.setID, s.state.round,
+			uint8(stage), vote.Hash, vote.Number)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	msg, err := scale.Marshal(FullVote{
 		Stage: stage,
 		Vote:  *vote,
@@ -88,7 +96,7 @@ func (s *Service) createSignedVoteAndVoteMessage(vote *Vote, stage Subround) (*S
 		return nil, nil, err
 	}
 
-	sig, err := s.keypair.Sign(msg)
+	sig, err := s.signer.Sign(msg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -96,7 +104,7 @@ func (s *Service) createSignedVoteAndVoteMessage(vote *Vote, stage Subround) (*S
 	pc := &SignedVote{
 		Vote:        *vote,
 		Signature:   ed25519.NewSignatureBytes(sig),
-		AuthorityID: s.keypair.Public().(*ed25519.PublicKey).AsBytes(),
+		AuthorityID: s.publicKeyBytes(),
 	}
 
 	sm := &SignedMessage{
@@ -104,7 +112,7 @@ func (s *Service) createSignedVoteAndVoteMessage(vote *Vote, stage Subround) (*S
 		Hash:        pc.Vote.Hash,
 		Number:      pc.Vote.Number,
 		Signature:   ed25519.NewSignatureBytes(sig),
-		AuthorityID: s.keypair.Public().(*ed25519.PublicKey).AsBytes(),
+		AuthorityID: s.publicKeyBytes(),
 	}
 
 	vm := &VoteMessage{
@@ -270,6 +278,7 @@ func (s *Service) checkForEquivocation(voter *Voter, vote *SignedVote, stage Sub
 		// the voter has already voted, all their votes are now equivocatory
 		eq[v] = []*SignedVote{existingVote, vote}
 		s.deleteVote(v, stage)
+		s.handleEquivocation(v, stage, existingVote, vote)
 		return true
 	}
 
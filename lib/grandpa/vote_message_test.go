@@ -9,9 +9,15 @@
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/telemetry"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/grandpa/mocks"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/signer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -57,6 +63,10 @@ func TestCheckForEquivocation_WithEquivocation(t *testing.T) {
 	kr, err := keystore.NewEd25519Keyring()
 	require.NoError(t, err)
 
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.AssignableToTypeOf(&telemetry.AfgEquivocation{}))
+
 	cfg := &Config{
 		BlockState:    st.Block,
 		GrandpaState:  st.Grandpa,
@@ -65,11 +75,19 @@ func TestCheckForEquivocation_WithEquivocation(t *testing.T) {
 		Keypair:       kr.Bob().(*ed25519.Keypair),
 		Network:       net,
 		Interval:      time.Second,
+		Telemetry:     telemetryMock,
 	}
 
 	gs, err := NewService(cfg)
 	require.NoError(t, err)
 
+	reported := make(chan *types.GrandpaEquivocationProof, 1)
+	reporter := new(mocks.EquivocationReporter)
+	reporter.On("ReportGrandpaEquivocation", mock.Anything).Run(func(args mock.Arguments) {
+		reported <- args.Get(0).(*types.GrandpaEquivocationProof)
+	}).Return(nil).Once()
+	gs.SetEquivocationReporter(reporter)
+
 	branches := make(map[int]int)
 	branches[6] = 1
 	state.AddBlocksToStateWithFixedBranches(t, st.Block, 8, branches, 0)
@@ -95,6 +113,28 @@ func TestCheckForEquivocation_WithEquivocation(t *testing.T) {
 	require.Equal(t, 0, gs.lenVotes(prevote))
 	require.Equal(t, 1, len(gs.pvEquivocations))
 	require.Equal(t, 2, len(gs.pvEquivocations[voter.Key.AsBytes()]))
+
+	expected := types.GrandpaEquivocationProof{
+		SetID: gs.state.setID,
+		Stage: types.PrevoteEquivocation,
+		Equivocation: types.GrandpaEquivocation{
+			RoundNumber: gs.state.round,
+			ID:          voter.Key.AsBytes(),
+			FirstVote:   *vote1,
+			SecondVote:  *vote2,
+		},
+	}
+
+	misbehaviours := gs.Misbehaviours()
+	require.Len(t, misbehaviours, 1)
+	require.Equal(t, expected, misbehaviours[0].Proof)
+
+	select {
+	case proof := <-reported:
+		require.Equal(t, expected, *proof)
+	case <-time.After(time.Second):
+		t.Fatal("equivocation was not reported")
+	}
 }
 
 func TestCheckForEquivocation_WithExistingEquivocation(t *testing.T) {
@@ -104,6 +144,10 @@ func TestCheckForEquivocation_WithExistingEquivocation(t *testing.T) {
 	kr, err := keystore.NewEd25519Keyring()
 	require.NoError(t, err)
 
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.AssignableToTypeOf(&telemetry.AfgEquivocation{}))
+
 	cfg := &Config{
 		BlockState:    st.Block,
 		GrandpaState:  st.Grandpa,
@@ -112,6 +156,7 @@ func TestCheckForEquivocation_WithExistingEquivocation(t *testing.T) {
 		Keypair:       kr.Bob().(*ed25519.Keypair),
 		Network:       net,
 		Interval:      time.Second,
+		Telemetry:     telemetryMock,
 	}
 
 	gs, err := NewService(cfg)
@@ -178,10 +223,10 @@ func TestValidateMessage_Valid(t *testing.T) {
 	h, err := st.Block.BestBlockHeader()
 	require.NoError(t, err)
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	_, msg, err := gs.createSignedVoteAndVoteMessage(NewVoteFromHeader(h), prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	vote, err := gs.validateVoteMessage("", msg)
 	require.NoError(t, err)
@@ -212,10 +257,10 @@ func TestValidateMessage_InvalidSignature(t *testing.T) {
 	h, err := st.Block.BestBlockHeader()
 	require.NoError(t, err)
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	_, msg, err := gs.createSignedVoteAndVoteMessage(NewVoteFromHeader(h), prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	msg.Message.Signature[63] = 0
 
@@ -246,10 +291,10 @@ func TestValidateMessage_SetIDMismatch(t *testing.T) {
 	h, err := st.Block.BestBlockHeader()
 	require.NoError(t, err)
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	_, msg, err := gs.createSignedVoteAndVoteMessage(NewVoteFromHeader(h), prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	gs.state.setID = 1
 
@@ -264,6 +309,10 @@ func TestValidateMessage_Equivocation(t *testing.T) {
 	kr, err := keystore.NewEd25519Keyring()
 	require.NoError(t, err)
 
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
 	cfg := &Config{
 		BlockState:    st.Block,
 		GrandpaState:  st.Grandpa,
@@ -272,6 +321,7 @@ func TestValidateMessage_Equivocation(t *testing.T) {
 		Keypair:       kr.Bob().(*ed25519.Keypair),
 		Network:       net,
 		Interval:      time.Second,
+		Telemetry:     telemetryMock,
 	}
 
 	gs, err := NewService(cfg)
@@ -293,10 +343,10 @@ func TestValidateMessage_Equivocation(t *testing.T) {
 		Vote: *voteA,
 	})
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	_, msg, err := gs.createSignedVoteAndVoteMessage(voteB, prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	_, err = gs.validateVoteMessage("", msg)
 	require.Equal(t, ErrEquivocation, err, gs.prevotes)
@@ -328,10 +378,10 @@ func TestValidateMessage_BlockDoesNotExist(t *testing.T) {
 		Number: big.NewInt(77),
 	}
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	_, msg, err := gs.createSignedVoteAndVoteMessage(NewVoteFromHeader(fake), prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	_, err = gs.validateVoteMessage("", msg)
 	require.Equal(t, err, ErrBlockDoesNotExist)
@@ -366,14 +416,42 @@ func TestValidateMessage_IsNotDescendant(t *testing.T) {
 	gs.head, err = gs.blockState.GetHeader(leaves[0])
 	require.NoError(t, err)
 
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Alice())
 	vote, err := NewVoteFromHash(leaves[1], gs.blockState)
 	require.NoError(t, err)
 
 	_, msg, err := gs.createSignedVoteAndVoteMessage(vote, prevote)
 	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
+	gs.signer = signer.NewLocal(kr.Bob())
 
 	_, err = gs.validateVoteMessage("", msg)
 	require.Equal(t, errInvalidVoteBlock, err, gs.prevotes)
 }
+
+func TestCreateSignedVoteAndVoteMessage_SlashingProtection(t *testing.T) {
+	kr, err := keystore.NewEd25519Keyring()
+	require.NoError(t, err)
+
+	vote := NewVote(common.Hash{1}, 1)
+	otherVote := NewVote(common.Hash{2}, 2)
+
+	slashingProtection := new(mocks.SlashingProtection)
+	slashingProtection.On("CheckAndRecordVote", kr.Alice().Public(), uint64(1), uint64(2), uint8(prevote),
+		vote.Hash, vote.Number).Return(nil)
+	slashingProtection.On("CheckAndRecordVote", kr.Alice().Public(), uint64(1), uint64(2), uint8(prevote),
+		otherVote.Hash, otherVote.Number).Return(state.ErrSlashable)
+
+	gs := &Service{
+		signer:             signer.NewLocal(kr.Alice()),
+		state:              NewState(nil, 1, 2),
+		slashingProtection: slashingProtection,
+	}
+
+	_, msg, err := gs.createSignedVoteAndVoteMessage(vote, prevote)
+	require.NoError(t, err)
+	require.Equal(t, *vote, Vote{Hash: msg.Message.Hash, Number: msg.Message.Number})
+
+	_, _, err = gs.createSignedVoteAndVoteMessage(otherVote, prevote)
+	require.ErrorIs(t, err, state.ErrSlashable)
+	slashingProtection.AssertExpectations(t)
+}
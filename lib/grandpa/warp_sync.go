@@ -0,0 +1,393 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package grandpa
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// GenerateWarpSyncProof returns the blocks enacting the authority set changes finalised after
+// the given block, each with the justification finalising it. If the proof covers every set
+// change up to our latest finalised block, it ends with the justification of that block.
+func (s *Service) GenerateWarpSyncProof(begin common.Hash) (*network.WarpSyncProof, error) {
+	beginHeader, err := s.blockState.GetHeader(begin)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get begin header: %w", err)
+	}
+
+	round, setID, err := s.blockState.GetHighestRoundAndSetID()
+	if err != nil {
+		return nil, err
+	}
+
+	finalised, err := s.blockState.GetFinalisedHeader(round, setID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get latest finalised header: %w", err)
+	}
+
+	if beginHeader.Number.Cmp(finalised.Number) > 0 {
+		return nil, errWarpSyncBeginNotFinalised
+	}
+
+	beginSetID, err := s.grandpaState.GetSetIDByBlockNumber(beginHeader.Number)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get set ID of begin block: %w", err)
+	}
+
+	currSetID, err := s.grandpaState.GetCurrentSetID()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		proof = &network.WarpSyncProof{}
+		size  int
+		last  = beginHeader
+	)
+
+	for id := beginSetID + 1; id <= currSetID; id++ {
+		fragment, err := s.setChangeFragment(id)
+		if err != nil {
+			logger.Debugf("cannot create warp sync fragment for set id %d: %s", id, err)
+			return warpSyncProofOrError(proof)
+		}
+
+		if fragment.Header.Number.Cmp(finalised.Number) > 0 {
+			break
+		}
+
+		header, err := scale.Marshal(fragment.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		size += len(header) + len(fragment.Justification)
+		if size > network.MaxWarpSyncProofSize {
+			return warpSyncProofOrError(proof)
+		}
+
+		proof.Fragments = append(proof.Fragments, *fragment)
+		last = &fragment.Header
+	}
+
+	proof.IsFinished = true
+
+	if finalised.Number.Cmp(last.Number) <= 0 {
+		return proof, nil
+	}
+
+	// our latest finalised block may have been finalised by a descendant's justification
+	justification, err := s.blockState.GetJustification(finalised.Hash())
+	if err == nil {
+		proof.Fragments = append(proof.Fragments, network.WarpSyncFragment{
+			Header:        *finalised,
+			Justification: withVotesAncestries(justification),
+		})
+	}
+
+	return proof, nil
+}
+
+func warpSyncProofOrError(proof *network.WarpSyncProof) (*network.WarpSyncProof, error) {
+	if len(proof.Fragments) == 0 {
+		return nil, errEmptyWarpSyncProof
+	}
+
+	return proof, nil
+}
+
+// setChangeFragment returns the block signalling the change to the given set ID,
+// along with its justification
+func (s *Service) setChangeFragment(setID uint64) (*network.WarpSyncFragment, error) {
+	enacted, err := s.grandpaState.GetSetIDChange(setID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.grandpaState.GetSetIDChange(setID - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// the change is enacted after a delay, find the block which signalled it
+	for num := new(big.Int).Set(enacted); num.Cmp(previous) > 0; num.Sub(num, big.NewInt(1)) {
+		header, err := s.blockState.GetHeaderByNumber(num)
+		if err != nil {
+			return nil, err
+		}
+
+		_, _, ok, err := authoritySetChange(header)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		justification, err := s.blockState.GetJustification(header.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrNoJustification, err)
+		}
+
+		return &network.WarpSyncFragment{
+			Header:        *header,
+			Justification: withVotesAncestries(justification),
+		}, nil
+	}
+
+	return nil, errNoSetChangeDigest
+}
+
+// withVotesAncestries appends an empty list of votes ancestries to the justification if it
+// doesn't have any, which is the case for the justifications we create ourselves
+func withVotesAncestries(justification []byte) []byte {
+	fj := Justification{}
+	err := scale.Unmarshal(justification, &fj)
+	if err != nil {
+		return justification
+	}
+
+	enc, err := scale.Marshal(fj)
+	if err != nil || len(enc) != len(justification) {
+		return justification
+	}
+
+	return append(enc, 0)
+}
+
+// authoritySetChange returns the new authorities and the delay of the scheduled
+// or forced GRANDPA authority set change of the header, if any
+func authoritySetChange(header *types.Header) ([]types.GrandpaVoter, uint32, bool, error) {
+	for _, d := range header.Digest.Types {
+		digest, ok := d.Value().(types.ConsensusDigest)
+		if !ok || digest.ConsensusEngineID != types.GrandpaEngineID {
+			continue
+		}
+
+		data := types.NewGrandpaConsensusDigest()
+		err := scale.Unmarshal(digest.Data, &data)
+		if err != nil {
+			return nil, 0, false, err
+		}
+
+		var (
+			auths []types.GrandpaAuthoritiesRaw
+			delay uint32
+		)
+
+		switch change := data.Value().(type) {
+		case types.GrandpaScheduledChange:
+			auths, delay = change.Auths, change.Delay
+		case types.GrandpaForcedChange:
+			auths, delay = change.Auths, change.Delay
+		default:
+			continue
+		}
+
+		voters, err := types.NewGrandpaVotersFromAuthoritiesRaw(auths)
+		if err != nil {
+			return nil, 0, false, err
+		}
+
+		return voters, delay, true, nil
+	}
+
+	return nil, 0, false, nil
+}
+
+// VerifyWarpSyncProof verifies the fragments of the warp sync proof, starting with the current
+// authority set, and stores the authority set changes they prove once the whole proof is verified.
+// It returns the round and the set ID of the justification of the last fragment.
+func (s *Service) VerifyWarpSyncProof(proof *network.WarpSyncProof) (round, setID uint64, err error) {
+	if len(proof.Fragments) == 0 {
+		return 0, 0, errEmptyWarpSyncProof
+	}
+
+	setID, err = s.grandpaState.GetCurrentSetID()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	auths, err := s.grandpaState.GetAuthorities(setID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cannot get authorities for set ID: %w", err)
+	}
+
+	type setChange struct {
+		auths   []types.GrandpaVoter
+		enacted *big.Int
+	}
+
+	var (
+		justificationSetID uint64
+		changes            []setChange
+	)
+
+	for i := range proof.Fragments {
+		fragment := &proof.Fragments[i]
+
+		round, err = verifyWarpSyncJustification(&fragment.Header, fragment.Justification, setID, auths)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid justification for fragment %d: %w", i, err)
+		}
+		justificationSetID = setID
+
+		next, delay, ok, err := authoritySetChange(&fragment.Header)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if !ok {
+			// only the last fragment can be the latest finalised block rather than a set change
+			if i != len(proof.Fragments)-1 {
+				return 0, 0, fmt.Errorf("%w: fragment %d", errWarpSyncFragmentNoChange, i)
+			}
+			break
+		}
+
+		changes = append(changes, setChange{
+			auths:   next,
+			enacted: new(big.Int).Add(fragment.Header.Number, big.NewInt(int64(delay))),
+		})
+
+		setID++
+		auths = next
+
+		logger.Debugf("verified warp sync fragment for block %s with number %s, set id is now %d",
+			fragment.Header.Hash(), fragment.Header.Number, setID)
+	}
+
+	for _, change := range changes {
+		if err = s.grandpaState.SetNextChange(change.auths, change.enacted); err != nil {
+			return 0, 0, err
+		}
+
+		if err = s.grandpaState.IncrementSetID(); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return round, justificationSetID, nil
+}
+
+// verifyWarpSyncJustification verifies that the justification finalises the header and is signed
+// by the given authority set. The precommits for descendants of the header are only counted if
+// the votes ancestries of the justification prove that they are descendants.
+func verifyWarpSyncJustification(header *types.Header, justification []byte,
+	setID uint64, auths []types.GrandpaVoter) (uint64, error) {
+	r := bytes.NewReader(justification)
+	decoder := scale.NewDecoder(r)
+
+	fj := Justification{}
+	if err := decoder.Decode(&fj); err != nil {
+		return 0, err
+	}
+
+	hash := header.Hash()
+	if !fj.Commit.Hash.Equal(hash) || big.NewInt(int64(fj.Commit.Number)).Cmp(header.Number) != 0 {
+		return 0, errJustificationTargetMismatch
+	}
+
+	var length uint
+	if err := decoder.Decode(&length); err != nil {
+		return 0, fmt.Errorf("cannot decode votes ancestries: %w", err)
+	}
+
+	ancestries := make(map[common.Hash]*types.Header, length)
+	for i := uint(0); i < length; i++ {
+		ancestry := types.NewEmptyHeader()
+		if err := decoder.Decode(ancestry); err != nil {
+			return 0, fmt.Errorf("cannot decode votes ancestries: %w", err)
+		}
+		ancestries[ancestry.Hash()] = ancestry
+	}
+
+	var totalWeight uint64
+	weights := make(map[ed25519.PublicKeyBytes]uint64, len(auths))
+	for i := range auths {
+		weights[auths[i].PublicKeyBytes()] = auths[i].ID
+		totalWeight += auths[i].ID
+	}
+
+	var votedWeight uint64
+	voted := make(map[ed25519.PublicKeyBytes]struct{})
+
+	for _, just := range fj.Commit.Precommits {
+		if _, ok := voted[just.AuthorityID]; ok {
+			continue
+		}
+
+		if !isDescendantInAncestries(hash, just.Vote.Hash, ancestries) {
+			continue
+		}
+
+		weight, ok := weights[just.AuthorityID]
+		if !ok {
+			return 0, ErrAuthorityNotInSet
+		}
+
+		pk, err := ed25519.NewPublicKey(just.AuthorityID[:])
+		if err != nil {
+			return 0, err
+		}
+
+		msg, err := scale.Marshal(FullVote{
+			Stage: precommit,
+			Vote:  just.Vote,
+			Round: fj.Round,
+			SetID: setID,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		ok, err = pk.Verify(msg, just.Signature[:])
+		if err != nil {
+			return 0, err
+		}
+
+		if !ok {
+			return 0, ErrInvalidSignature
+		}
+
+		voted[just.AuthorityID] = struct{}{}
+		votedWeight += weight
+	}
+
+	// the precommits must be signed by more than two thirds of the authority set weight
+	if 3*votedWeight <= 2*totalWeight {
+		return 0, ErrMinVotesNotMet
+	}
+
+	return fj.Round, nil
+}
+
+// isDescendantInAncestries returns true if the block with the hash descendant is the block
+// with the hash ancestor, or if the ancestries link it to the block
+func isDescendantInAncestries(ancestor, descendant common.Hash,
+	ancestries map[common.Hash]*types.Header) bool {
+	curr := descendant
+	for i := 0; i <= len(ancestries); i++ {
+		if curr.Equal(ancestor) {
+			return true
+		}
+
+		header, ok := ancestries[curr]
+		if !ok {
+			return false
+		}
+		curr = header.ParentHash
+	}
+
+	return false
+}
@@ -0,0 +1,405 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package grandpa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/utils"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newWarpSyncTestService(t *testing.T) (*Service, *state.GrandpaState) {
+	t.Helper()
+
+	db, err := utils.SetupDatabase(t.TempDir(), true)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	gs, err := state.NewGrandpaStateFromGenesis(db, voters)
+	require.NoError(t, err)
+
+	return &Service{grandpaState: gs}, gs
+}
+
+func newWarpSyncTestHeader(t *testing.T, parent common.Hash, number int64,
+	change *types.GrandpaScheduledChange) *types.Header {
+	t.Helper()
+
+	prd, err := types.NewBabeSecondaryPlainPreDigest(0, uint64(number)).ToPreRuntimeDigest()
+	require.NoError(t, err)
+
+	digest := types.NewDigest()
+	err = digest.Add(*prd)
+	require.NoError(t, err)
+
+	if change != nil {
+		data := types.NewGrandpaConsensusDigest()
+		err := data.Set(*change)
+		require.NoError(t, err)
+
+		enc, err := scale.Marshal(data)
+		require.NoError(t, err)
+
+		err = digest.Add(types.ConsensusDigest{
+			ConsensusEngineID: types.GrandpaEngineID,
+			Data:              enc,
+		})
+		require.NoError(t, err)
+	}
+
+	header, err := types.NewHeader(parent, common.Hash{}, common.Hash{}, big.NewInt(number), digest)
+	require.NoError(t, err)
+	return header
+}
+
+// newWarpSyncTestJustification returns a justification of the header signed by the given
+// keys, the first key voting for the descendant if it is set
+func newWarpSyncTestJustification(t *testing.T, header, descendant *types.Header, round, setID uint64,
+	keys []*ed25519.Keypair) []byte {
+	t.Helper()
+
+	var ancestries []types.Header
+	precommits := make([]SignedVote, len(keys))
+	for i, key := range keys {
+		vote := *NewVoteFromHeader(header)
+		if i == 0 && descendant != nil {
+			vote = *NewVoteFromHeader(descendant)
+			ancestries = append(ancestries, *descendant)
+		}
+
+		msg, err := scale.Marshal(FullVote{Stage: precommit, Vote: vote, Round: round, SetID: setID})
+		require.NoError(t, err)
+
+		sig, err := key.Sign(msg)
+		require.NoError(t, err)
+
+		precommits[i] = SignedVote{
+			Vote:        vote,
+			AuthorityID: key.Public().(*ed25519.PublicKey).AsBytes(),
+		}
+		copy(precommits[i].Signature[:], sig)
+	}
+
+	just, err := scale.Marshal(*newJustification(round, header.Hash(), uint32(header.Number.Int64()), precommits))
+	require.NoError(t, err)
+
+	enc, err := scale.Marshal(ancestries)
+	require.NoError(t, err)
+	return append(just, enc...)
+}
+
+func TestVerifyWarpSyncProof(t *testing.T) {
+	t.Parallel()
+
+	s, gs := newWarpSyncTestService(t)
+
+	nextKeys := kr.Keys[:3]
+	change := &types.GrandpaScheduledChange{Delay: 2}
+	for i, key := range nextKeys {
+		change.Auths = append(change.Auths, types.GrandpaAuthoritiesRaw{
+			Key: key.Public().(*ed25519.PublicKey).AsBytes(),
+			ID:  uint64(i),
+		})
+	}
+
+	changeHeader := newWarpSyncTestHeader(t, common.Hash{1}, 5, change)
+	descendant := newWarpSyncTestHeader(t, changeHeader.Hash(), 6, nil)
+	finalHeader := newWarpSyncTestHeader(t, common.Hash{2}, 10, nil)
+
+	proof := &network.WarpSyncProof{
+		Fragments: []network.WarpSyncFragment{
+			{
+				Header:        *changeHeader,
+				Justification: newWarpSyncTestJustification(t, changeHeader, descendant, 4, 0, kr.Keys),
+			},
+			{
+				Header:        *finalHeader,
+				Justification: newWarpSyncTestJustification(t, finalHeader, nil, 2, 1, nextKeys),
+			},
+		},
+		IsFinished: true,
+	}
+
+	round, setID, err := s.VerifyWarpSyncProof(proof)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), round)
+	require.Equal(t, uint64(1), setID)
+
+	currSetID, err := gs.GetCurrentSetID()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), currSetID)
+
+	auths, err := gs.GetAuthorities(1)
+	require.NoError(t, err)
+	require.Len(t, auths, 3)
+
+	enacted, err := gs.GetSetIDChange(1)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(7), enacted)
+}
+
+func TestService_GenerateWarpSyncProof(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockClient(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	db, err := utils.SetupDatabase(t.TempDir(), true)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	bs, err := state.NewBlockStateFromGenesis(db, testGenesisHeader, telemetryMock)
+	require.NoError(t, err)
+
+	gs, err := state.NewGrandpaStateFromGenesis(db, voters)
+	require.NoError(t, err)
+
+	nextKeys := kr.Keys[:3]
+	change := &types.GrandpaScheduledChange{}
+	for i, key := range nextKeys {
+		change.Auths = append(change.Auths, types.GrandpaAuthoritiesRaw{
+			Key: key.Public().(*ed25519.PublicKey).AsBytes(),
+			ID:  uint64(i),
+		})
+	}
+
+	parent := testGenesisHeader.Hash()
+	headers := make([]*types.Header, 11)
+	for i := int64(1); i <= 10; i++ {
+		var c *types.GrandpaScheduledChange
+		if i == 5 {
+			c = change
+		}
+
+		headers[i] = newWarpSyncTestHeader(t, parent, i, c)
+		err = bs.AddBlock(&types.Block{Header: *headers[i], Body: *types.NewBody([]types.Extrinsic{})})
+		require.NoError(t, err)
+		parent = headers[i].Hash()
+	}
+
+	nextVoters, err := types.NewGrandpaVotersFromAuthoritiesRaw(change.Auths)
+	require.NoError(t, err)
+	err = gs.SetNextChange(nextVoters, big.NewInt(5))
+	require.NoError(t, err)
+	err = gs.IncrementSetID()
+	require.NoError(t, err)
+
+	// the justification created by the node itself has no votes ancestries
+	just := newWarpSyncTestJustification(t, headers[5], nil, 3, 0, kr.Keys)
+	err = bs.SetJustification(headers[5].Hash(), just[:len(just)-1])
+	require.NoError(t, err)
+	err = bs.SetFinalisedHash(headers[5].Hash(), 3, 0)
+	require.NoError(t, err)
+
+	err = bs.SetJustification(headers[10].Hash(), newWarpSyncTestJustification(t, headers[10], nil, 1, 1, nextKeys))
+	require.NoError(t, err)
+	err = bs.SetFinalisedHash(headers[10].Hash(), 1, 1)
+	require.NoError(t, err)
+
+	s := &Service{blockState: bs, grandpaState: gs}
+	proof, err := s.GenerateWarpSyncProof(testGenesisHeader.Hash())
+	require.NoError(t, err)
+	require.True(t, proof.IsFinished)
+	require.Len(t, proof.Fragments, 2)
+	require.Equal(t, headers[5].Hash(), proof.Fragments[0].Header.Hash())
+	require.Equal(t, just, proof.Fragments[0].Justification)
+	require.Equal(t, headers[10].Hash(), proof.Fragments[1].Header.Hash())
+
+	// the proof is valid for a node which only knows the genesis authorities
+	verifier, _ := newWarpSyncTestService(t)
+	round, setID, err := verifier.VerifyWarpSyncProof(proof)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), round)
+	require.Equal(t, uint64(1), setID)
+
+	// there are no set changes after the latest finalised block
+	proof, err = s.GenerateWarpSyncProof(headers[10].Hash())
+	require.NoError(t, err)
+	require.True(t, proof.IsFinished)
+	require.Empty(t, proof.Fragments)
+}
+
+func TestVerifyWarpSyncProof_Invalid(t *testing.T) {
+	t.Parallel()
+
+	header := newWarpSyncTestHeader(t, common.Hash{1}, 5, nil)
+	valid := newWarpSyncTestJustification(t, header, nil, 1, 0, kr.Keys)
+
+	tests := map[string]struct {
+		proof *network.WarpSyncProof
+		err   error
+	}{
+		"empty proof": {
+			proof: &network.WarpSyncProof{},
+			err:   errEmptyWarpSyncProof,
+		},
+		"not enough votes": {
+			proof: &network.WarpSyncProof{Fragments: []network.WarpSyncFragment{{
+				Header:        *header,
+				Justification: newWarpSyncTestJustification(t, header, nil, 1, 0, kr.Keys[:2]),
+			}}},
+			err: ErrMinVotesNotMet,
+		},
+		"wrong set id": {
+			proof: &network.WarpSyncProof{Fragments: []network.WarpSyncFragment{{
+				Header:        *header,
+				Justification: newWarpSyncTestJustification(t, header, nil, 1, 1, kr.Keys),
+			}}},
+			err: ErrInvalidSignature,
+		},
+		"wrong target": {
+			proof: &network.WarpSyncProof{Fragments: []network.WarpSyncFragment{{
+				Header:        *newWarpSyncTestHeader(t, common.Hash{2}, 5, nil),
+				Justification: valid,
+			}}},
+			err: errJustificationTargetMismatch,
+		},
+		"fragment without set change": {
+			proof: &network.WarpSyncProof{Fragments: []network.WarpSyncFragment{
+				{Header: *header, Justification: valid},
+				{Header: *header, Justification: valid},
+			}},
+			err: errWarpSyncFragmentNoChange,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, _ := newWarpSyncTestService(t)
+			_, _, err := s.VerifyWarpSyncProof(tt.proof)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestVerifyWarpSyncJustification_UnprovenDescendant(t *testing.T) {
+	t.Parallel()
+
+	header := newWarpSyncTestHeader(t, common.Hash{1}, 5, nil)
+	descendant := newWarpSyncTestHeader(t, header.Hash(), 6, nil)
+	just := newWarpSyncTestJustification(t, header, descendant, 1, 0, kr.Keys)
+
+	_, err := verifyWarpSyncJustification(header, just, 0, voters)
+	require.NoError(t, err)
+
+	// without the votes ancestries, the precommit for the descendant is not counted
+	fj := Justification{}
+	err = scale.Unmarshal(just, &fj)
+	require.NoError(t, err)
+	fj.Commit.Precommits = fj.Commit.Precommits[:6]
+
+	enc, err := scale.Marshal(fj)
+	require.NoError(t, err)
+
+	_, err = verifyWarpSyncJustification(header, append(enc, 0), 0, voters)
+	require.ErrorIs(t, err, ErrMinVotesNotMet)
+}
+
+func TestVerifyWarpSyncJustification_Threshold(t *testing.T) {
+	t.Parallel()
+
+	header := newWarpSyncTestHeader(t, common.Hash{1}, 5, nil)
+
+	newVoters := func(weights ...uint64) []types.GrandpaVoter {
+		auths := make([]types.GrandpaVoter, len(weights))
+		for i, weight := range weights {
+			auths[i] = types.GrandpaVoter{Key: *kr.Keys[i].Public().(*ed25519.PublicKey), ID: weight}
+		}
+		return auths
+	}
+
+	tests := map[string]struct {
+		auths  []types.GrandpaVoter
+		signed int
+		err    error
+	}{
+		"3 of 3": {auths: newVoters(1, 1, 1), signed: 3},
+		"2 of 3": {auths: newVoters(1, 1, 1), signed: 2, err: ErrMinVotesNotMet},
+		"3 of 4": {auths: newVoters(1, 1, 1, 1), signed: 3},
+		"2 of 4": {auths: newVoters(1, 1, 1, 1), signed: 2, err: ErrMinVotesNotMet},
+		"4 of 6": {auths: newVoters(1, 1, 1, 1, 1, 1), signed: 4, err: ErrMinVotesNotMet},
+		"5 of 6": {auths: newVoters(1, 1, 1, 1, 1, 1), signed: 5},
+		// the first authority has half of the total weight
+		"weight 4 of 6": {auths: newVoters(3, 1, 1, 1), signed: 2, err: ErrMinVotesNotMet},
+		"weight 5 of 6": {auths: newVoters(3, 1, 1, 1), signed: 3},
+		"no weight":     {auths: newVoters(0, 0), signed: 2, err: ErrMinVotesNotMet},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			just := newWarpSyncTestJustification(t, header, nil, 1, 0, kr.Keys[:tt.signed])
+			_, err := verifyWarpSyncJustification(header, just, 0, tt.auths)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestVerifyWarpSyncProof_InvalidLastFragment(t *testing.T) {
+	t.Parallel()
+
+	s, gs := newWarpSyncTestService(t)
+
+	nextKeys := kr.Keys[:3]
+	change := &types.GrandpaScheduledChange{}
+	for _, key := range nextKeys {
+		change.Auths = append(change.Auths, types.GrandpaAuthoritiesRaw{
+			Key: key.Public().(*ed25519.PublicKey).AsBytes(),
+			ID:  1,
+		})
+	}
+
+	changeHeader := newWarpSyncTestHeader(t, common.Hash{1}, 5, change)
+	finalHeader := newWarpSyncTestHeader(t, common.Hash{2}, 10, nil)
+
+	proof := &network.WarpSyncProof{
+		Fragments: []network.WarpSyncFragment{
+			{
+				Header:        *changeHeader,
+				Justification: newWarpSyncTestJustification(t, changeHeader, nil, 4, 0, kr.Keys),
+			},
+			{
+				Header:        *finalHeader,
+				Justification: newWarpSyncTestJustification(t, finalHeader, nil, 2, 1, nextKeys[:2]),
+			},
+		},
+	}
+
+	_, _, err := s.VerifyWarpSyncProof(proof)
+	require.ErrorIs(t, err, ErrMinVotesNotMet)
+
+	// the set change proven by the first fragment isn't stored
+	setID, err := gs.GetCurrentSetID()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), setID)
+
+	_, err = gs.GetAuthorities(1)
+	require.Error(t, err)
+}
+
+func TestWithVotesAncestries(t *testing.T) {
+	t.Parallel()
+
+	header := newWarpSyncTestHeader(t, common.Hash{1}, 5, nil)
+	just := newWarpSyncTestJustification(t, header, nil, 1, 0, kr.Keys[:1])
+
+	// the justification already has an empty list of ancestries
+	require.Equal(t, just, withVotesAncestries(just))
+	require.Equal(t, just, withVotesAncestries(just[:len(just)-1]))
+}
@@ -8,21 +8,18 @@
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/ChainSafe/gossamer/lib/crypto"
-	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
-	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
-	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 
 	"golang.org/x/crypto/blake2b"
 )
 
-// EncryptedKeystore holds Type PublicKey and Ciphertext
+// EncryptedKeystore holds Type PublicKey and Ciphertext. It is the legacy format of the key files of
+// gossamer, whose ciphertext is encrypted by Encrypt. Key files are now written as a KeyFile.
 type EncryptedKeystore struct {
 	Type       string
 	PublicKey  string
@@ -46,7 +43,8 @@ func gcmFromPassphrase(password []byte) (cipher.AEAD, error) {
 	return gcm, nil
 }
 
-// Encrypt uses AES to encrypt `msg` with the symmetric key deterministically created from `password`
+// Encrypt uses AES to encrypt `msg` with the symmetric key deterministically created from `password`.
+// It is the encryption of the legacy EncryptedKeystore format.
 func Encrypt(msg, password []byte) ([]byte, error) {
 	gcm, err := gcmFromPassphrase(password)
 	if err != nil {
@@ -98,70 +96,121 @@ func DecryptPrivateKey(data, password []byte, keytype string) (crypto.PrivateKey
 }
 
 // EncryptAndWriteToFile encrypts the `crypto.PrivateKey` using the password and saves it to the specified file
+// in the format of the polkadot-js keyring
 func EncryptAndWriteToFile(path string, pk crypto.PrivateKey, password []byte) error {
-	ciphertext, err := EncryptPrivateKey(pk, password)
+	keyFile, err := NewKeyFile(pk, password)
 	if err != nil {
 		return err
 	}
 
-	pub, err := pk.Public()
+	return writeKeyFile(path, keyFile)
+}
+
+// ReadFromFileAndDecrypt reads ciphertext from a file and decrypts it using the password into a `crypto.PrivateKey`.
+// The file is either in the format of the polkadot-js keyring or in the legacy EncryptedKeystore format.
+func ReadFromFileAndDecrypt(filename string, password []byte) (crypto.PrivateKey, error) {
+	keyFile, legacy, err := readKeyFile(filename)
 	if err != nil {
-		return fmt.Errorf("cannot get public key: %s", err)
+		return nil, err
 	}
 
-	keytype := ""
-	if _, ok := pk.(*ed25519.PrivateKey); ok {
-		keytype = crypto.Ed25519Type
+	if legacy != nil {
+		return DecryptPrivateKey(legacy.Ciphertext, password, legacy.Type)
 	}
 
-	if _, ok := pk.(*sr25519.PrivateKey); ok {
-		keytype = crypto.Sr25519Type
+	return keyFile.Decrypt(password)
+}
+
+// MigrateKeyFile rewrites the given key file in the legacy EncryptedKeystore format in the format of the
+// polkadot-js keyring, the key is encrypted again with the same password. It returns false if the key file
+// is already in the format of the polkadot-js keyring.
+func MigrateKeyFile(filename string, password []byte) (bool, error) {
+	_, legacy, err := readKeyFile(filename)
+	if err != nil {
+		return false, err
 	}
 
-	if _, ok := pk.(*secp256k1.PrivateKey); ok {
-		keytype = crypto.Secp256k1Type
+	if legacy == nil {
+		return false, nil
 	}
 
-	if keytype == "" {
-		return errors.New("cannot write key not of type sr25519, ed25519, secp256k1")
+	priv, err := DecryptPrivateKey(legacy.Ciphertext, password, legacy.Type)
+	if err != nil {
+		return false, fmt.Errorf("cannot decrypt key file: %w", err)
 	}
 
-	keydata := &EncryptedKeystore{
-		Type:       keytype,
-		PublicKey:  pub.Hex(),
-		Ciphertext: ciphertext,
+	keyFile, err := NewKeyFile(priv, password)
+	if err != nil {
+		return false, err
 	}
 
-	data, err := json.MarshalIndent(keydata, "", "\t")
+	// the key file is written to a temporary file first, so that the key
+	// is not lost if the migration is interrupted
+	tmpPath := filename + ".tmp"
+	err = writeKeyFile(tmpPath, keyFile)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	err = os.WriteFile(filepath.Clean(path), append(data, byte('\n')), 0600)
+	err = os.Rename(tmpPath, filename)
 	if err != nil {
-		return fmt.Errorf("cannot write to destination file: %w", err)
+		_ = os.Remove(tmpPath)
+		return false, fmt.Errorf("cannot replace key file: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
-// ReadFromFileAndDecrypt reads ciphertext from a file and decrypts it using the password into a `crypto.PrivateKey`
-func ReadFromFileAndDecrypt(filename string, password []byte) (crypto.PrivateKey, error) {
+// readKeyFile reads a key file, which is returned as a KeyFile, or as an EncryptedKeystore if it is
+// in the legacy format
+func readKeyFile(filename string) (*KeyFile, *EncryptedKeystore, error) {
 	fp, err := filepath.Abs(filename)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	data, err := os.ReadFile(filepath.Clean(fp))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	keydata := new(EncryptedKeystore)
-	err = json.Unmarshal(data, keydata)
+	return decodeKeyFile(data)
+}
+
+func decodeKeyFile(data []byte) (*KeyFile, *EncryptedKeystore, error) {
+	keyFile := new(KeyFile)
+	err := json.Unmarshal(data, keyFile)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return DecryptPrivateKey(keydata.Ciphertext, password, keydata.Type)
+	if keyFile.Encoded != "" {
+		return keyFile, nil, nil
+	}
+
+	legacy := new(EncryptedKeystore)
+	err = json.Unmarshal(data, legacy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(legacy.Ciphertext) == 0 {
+		return nil, nil, fmt.Errorf("%w: no encrypted key", errInvalidKeyFile)
+	}
+
+	return nil, legacy, nil
+}
+
+func writeKeyFile(path string, keyFile *KeyFile) error {
+	data, err := json.MarshalIndent(keyFile, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Clean(path), append(data, byte('\n')), 0600)
+	if err != nil {
+		return fmt.Errorf("cannot write to destination file: %w", err)
+	}
+
+	return nil
 }
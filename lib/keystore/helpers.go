@@ -5,7 +5,6 @@
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -163,7 +162,8 @@ func LoadKeystore(key string, ks Keystore) error {
 
 // ImportKeypair imports a key specified by its filename into a subdirectory
 // by the name "keystore" and saves it under the filename "[publickey].key",
-// returns the absolute path of the imported key file
+// returns the absolute path of the imported key file. The key file is either
+// exported from the polkadot-js keyring, or in the legacy gossamer format.
 func ImportKeypair(fp, dir string) (string, error) {
 	keyDir, err := utils.KeystoreDir(dir)
 	if err != nil {
@@ -175,13 +175,25 @@ func ImportKeypair(fp, dir string) (string, error) {
 		return "", fmt.Errorf("failed to read keystore file: %s", err)
 	}
 
-	keystore := new(EncryptedKeystore)
-	err = json.Unmarshal(keyData, keystore)
+	keyFile, legacy, err := decodeKeyFile(keyData)
 	if err != nil {
 		return "", fmt.Errorf("failed to read import keystore data: %s", err)
 	}
 
-	keyFilePath, err := filepath.Abs(keyDir + "/" + keystore.PublicKey[2:] + ".key")
+	// key files in the format of the polkadot-js keyring are named after their account id,
+	// since their public key is encrypted
+	var name string
+	if legacy != nil {
+		name = strings.TrimPrefix(legacy.PublicKey, "0x")
+	} else {
+		id, err := keyFile.AccountID()
+		if err != nil {
+			return "", fmt.Errorf("failed to read import keystore data: %s", err)
+		}
+		name = hex.EncodeToString(id)
+	}
+
+	keyFilePath, err := filepath.Abs(keyDir + "/" + name + ".key")
 	if err != nil {
 		return "", fmt.Errorf("failed to create keystore filepath: %s", err)
 	}
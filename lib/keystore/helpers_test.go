@@ -102,14 +102,14 @@ func TestGenerateKey_Ed25519(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	kscontents := new(EncryptedKeystore)
+	kscontents := new(KeyFile)
 	err = json.Unmarshal(contents, kscontents)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if kscontents.Type != "ed25519" {
-		t.Fatalf("Fail: got %s expected %s", kscontents.Type, "ed25519")
+	if kscontents.Encoding.Content[1] != "ed25519" {
+		t.Fatalf("Fail: got %s expected %s", kscontents.Encoding.Content[1], "ed25519")
 	}
 }
 
@@ -139,14 +139,14 @@ func TestGenerateKey_Secp256k1(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	kscontents := new(EncryptedKeystore)
+	kscontents := new(KeyFile)
 	err = json.Unmarshal(contents, kscontents)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if kscontents.Type != "secp256k1" {
-		t.Fatalf("Fail: got %s expected %s", kscontents.Type, "secp256k1")
+	if kscontents.Encoding.Content[1] != "ecdsa" {
+		t.Fatalf("Fail: got %s expected %s", kscontents.Encoding.Content[1], "ecdsa")
 	}
 }
 
@@ -163,14 +163,14 @@ func TestGenerateKey_NoType(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	kscontents := new(EncryptedKeystore)
+	kscontents := new(KeyFile)
 	err = json.Unmarshal(contents, kscontents)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if kscontents.Type != "sr25519" {
-		t.Fatalf("Fail: got %s expected %s", kscontents.Type, "sr25519")
+	if kscontents.Encoding.Content[1] != "sr25519" {
+		t.Fatalf("Fail: got %s expected %s", kscontents.Encoding.Content[1], "sr25519")
 	}
 }
 
@@ -294,13 +294,18 @@ func TestImportRawPrivateKey_NoType(t *testing.T) {
 	contents, err := os.ReadFile(keyfile)
 	require.NoError(t, err)
 
-	kscontents := new(EncryptedKeystore)
+	kscontents := new(KeyFile)
 	err = json.Unmarshal(contents, kscontents)
 	require.NoError(t, err)
-	require.Equal(t, "sr25519", kscontents.Type)
+	require.Equal(t, "sr25519", kscontents.Encoding.Content[1])
+
+	priv, err := kscontents.Decrypt(testPassword)
+	require.NoError(t, err)
+	pub, err := priv.Public()
+	require.NoError(t, err)
 	require.Equal(t,
 		"0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d",
-		kscontents.PublicKey)
+		pub.Hex())
 }
 
 func TestImportRawPrivateKey_Sr25519(t *testing.T) {
@@ -314,13 +319,18 @@ func TestImportRawPrivateKey_Sr25519(t *testing.T) {
 	contents, err := os.ReadFile(keyfile)
 	require.NoError(t, err)
 
-	kscontents := new(EncryptedKeystore)
+	kscontents := new(KeyFile)
 	err = json.Unmarshal(contents, kscontents)
 	require.NoError(t, err)
-	require.Equal(t, "sr25519", kscontents.Type)
+	require.Equal(t, "sr25519", kscontents.Encoding.Content[1])
+
+	priv, err := kscontents.Decrypt(testPassword)
+	require.NoError(t, err)
+	pub, err := priv.Public()
+	require.NoError(t, err)
 	require.Equal(t,
 		"0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d",
-		kscontents.PublicKey)
+		pub.Hex())
 }
 
 func TestImportRawPrivateKey_Ed25519(t *testing.T) {
@@ -334,13 +344,18 @@ func TestImportRawPrivateKey_Ed25519(t *testing.T) {
 	contents, err := os.ReadFile(keyfile)
 	require.NoError(t, err)
 
-	kscontents := new(EncryptedKeystore)
+	kscontents := new(KeyFile)
 	err = json.Unmarshal(contents, kscontents)
 	require.NoError(t, err)
-	require.Equal(t, "ed25519", kscontents.Type)
+	require.Equal(t, "ed25519", kscontents.Encoding.Content[1])
+
+	priv, err := kscontents.Decrypt(testPassword)
+	require.NoError(t, err)
+	pub, err := priv.Public()
+	require.NoError(t, err)
 	require.Equal(t,
 		"0x6dfb362eb332449782b7260bcff6d8777242acdea3293508b22d33ce7336a8b3",
-		kscontents.PublicKey)
+		pub.Hex())
 }
 
 func TestImportRawPrivateKey_Secp256k1(t *testing.T) {
@@ -354,13 +369,18 @@ func TestImportRawPrivateKey_Secp256k1(t *testing.T) {
 	contents, err := os.ReadFile(keyfile)
 	require.NoError(t, err)
 
-	kscontents := new(EncryptedKeystore)
+	kscontents := new(KeyFile)
 	err = json.Unmarshal(contents, kscontents)
 	require.NoError(t, err)
-	require.Equal(t, "secp256k1", kscontents.Type)
+	require.Equal(t, "ecdsa", kscontents.Encoding.Content[1])
+
+	priv, err := kscontents.Decrypt(testPassword)
+	require.NoError(t, err)
+	pub, err := priv.Public()
+	require.NoError(t, err)
 	require.Equal(t,
 		"0x03409094a319b2961660c3ebcc7d206266182c1b3e60d341b5fb17e6851865825c",
-		kscontents.PublicKey)
+		pub.Hex())
 }
 
 func TestDecodeKeyPairFromHex(t *testing.T) {
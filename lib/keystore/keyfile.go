@@ -0,0 +1,314 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keyFileVersion     = "3"
+	keyFileContentType = "pkcs8"
+	keyFileKDF         = "scrypt"
+	keyFileCipher      = "xsalsa20-poly1305"
+
+	// polkadot-js names secp256k1 keys ecdsa keys
+	keyFileSecp256k1Type = "ecdsa"
+
+	scryptSaltLength   = 32
+	scryptParamsLength = scryptSaltLength + 3*4
+	scryptKeyLength    = 64
+	// the default scrypt parameters of polkadot-js
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	secretboxNonceLength = 24
+	secretboxKeyLength   = 32
+)
+
+var (
+	pkcs8Header  = []byte{48, 83, 2, 1, 1, 48, 5, 6, 3, 43, 101, 112, 4, 34, 4, 32}
+	pkcs8Divider = []byte{161, 35, 3, 33, 0}
+
+	errInvalidKeyFile  = errors.New("invalid key file")
+	errInvalidPassword = errors.New("invalid password")
+)
+
+// scryptParams are the parameters of scrypt used to derive the key of a key file
+type scryptParams struct {
+	n, p, r uint32
+}
+
+// allowedScryptParams are the scrypt parameters accepted by polkadot-js, any other parameters
+// are rejected since they bound the memory used to decrypt a key file
+var allowedScryptParams = map[scryptParams]struct{}{
+	{n: 1 << 13, p: 10, r: 8}: {},
+	{n: 1 << 14, p: 5, r: 8}:  {},
+	{n: 1 << 15, p: 3, r: 8}:  {},
+	{n: 1 << 15, p: 1, r: 8}:  {},
+	{n: 1 << 16, p: 2, r: 8}:  {},
+	{n: 1 << 17, p: 1, r: 8}:  {},
+}
+
+// KeyFile is a key file in the JSON format of the polkadot-js keyring, the private key is
+// encoded in PKCS8 and encrypted with xsalsa20-poly1305 using a key derived by scrypt from
+// the password.
+// see: https://github.com/polkadot-js/common/tree/master/packages/keyring
+type KeyFile struct {
+	Encoded  string          `json:"encoded"`
+	Encoding KeyFileEncoding `json:"encoding"`
+	Address  string          `json:"address"`
+	Meta     KeyFileMeta     `json:"meta"`
+}
+
+// KeyFileEncoding describes the encoding of the key of a KeyFile
+type KeyFileEncoding struct {
+	Content []string `json:"content"`
+	Type    []string `json:"type"`
+	Version string   `json:"version"`
+}
+
+// KeyFileMeta holds the metadata of a KeyFile
+type KeyFileMeta struct {
+	Name        string `json:"name,omitempty"`
+	WhenCreated int64  `json:"whenCreated"`
+}
+
+// NewKeyFile encrypts the given private key using the password into a KeyFile
+func NewKeyFile(priv crypto.PrivateKey, password []byte) (*KeyFile, error) {
+	kp, err := PrivateKeyToKeypair(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	keytype, secret, address, err := keyFileSecret(kp)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := make([]byte, 0, len(pkcs8Header)+len(secret)+len(pkcs8Divider)+len(kp.Public().Encode()))
+	encoded = append(encoded, pkcs8Header...)
+	encoded = append(encoded, secret...)
+	encoded = append(encoded, pkcs8Divider...)
+	encoded = append(encoded, kp.Public().Encode()...)
+
+	encrypted, err := encryptKeyFileData(encoded, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyFile{
+		Encoded: base64.StdEncoding.EncodeToString(encrypted),
+		Encoding: KeyFileEncoding{
+			Content: []string{keyFileContentType, keytype},
+			Type:    []string{keyFileKDF, keyFileCipher},
+			Version: keyFileVersion,
+		},
+		Address: string(address),
+		Meta: KeyFileMeta{
+			WhenCreated: time.Now().UnixMilli(),
+		},
+	}, nil
+}
+
+// KeyType returns the type of the key of the key file
+func (f *KeyFile) KeyType() (crypto.KeyType, error) {
+	if len(f.Encoding.Content) != 2 || f.Encoding.Content[0] != keyFileContentType {
+		return "", fmt.Errorf("%w: unsupported content %v", errInvalidKeyFile, f.Encoding.Content)
+	}
+
+	switch keytype := f.Encoding.Content[1]; keytype {
+	case crypto.Sr25519Type, crypto.Ed25519Type:
+		return keytype, nil
+	case keyFileSecp256k1Type:
+		return crypto.Secp256k1Type, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported key type %s", errInvalidKeyFile, keytype)
+	}
+}
+
+// AccountID returns the id of the account of the key file, which is decoded from its address
+func (f *KeyFile) AccountID() ([]byte, error) {
+	id := crypto.PublicAddressToByteArray(common.Address(f.Address))
+	if len(id) != 32 || crypto.AccountIDToAddress(id) != common.Address(f.Address) {
+		return nil, fmt.Errorf("%w: invalid address %s", errInvalidKeyFile, f.Address)
+	}
+
+	return id, nil
+}
+
+// Decrypt decrypts the private key of the key file using the password
+func (f *KeyFile) Decrypt(password []byte) (crypto.PrivateKey, error) {
+	keytype, err := f.KeyType()
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Encoding.Version != keyFileVersion ||
+		len(f.Encoding.Type) != 2 || f.Encoding.Type[0] != keyFileKDF || f.Encoding.Type[1] != keyFileCipher {
+		return nil, fmt.Errorf("%w: unsupported encoding %v version %s",
+			errInvalidKeyFile, f.Encoding.Type, f.Encoding.Version)
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(f.Encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidKeyFile, err)
+	}
+
+	encoded, err := decryptKeyFileData(encrypted, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePKCS8(encoded, keytype)
+}
+
+// keyFileSecret returns the polkadot-js key type, the secret key in the encoding of
+// polkadot-js and the address of the given keypair
+func keyFileSecret(kp crypto.Keypair) (string, []byte, common.Address, error) {
+	switch priv := kp.Private().(type) {
+	case *sr25519.PrivateKey:
+		return crypto.Sr25519Type, priv.Ed25519Bytes(), kp.Public().Address(), nil
+	case *ed25519.PrivateKey:
+		return crypto.Ed25519Type, priv.Encode(), kp.Public().Address(), nil
+	case *secp256k1.PrivateKey:
+		// the account of a secp256k1 key is the hash of its compressed public key
+		id := blake2b.Sum256(kp.Public().Encode())
+		return keyFileSecp256k1Type, priv.Encode(), crypto.AccountIDToAddress(id[:]), nil
+	default:
+		return "", nil, "", errors.New("cannot write key not of type sr25519, ed25519, secp256k1")
+	}
+}
+
+// decodePKCS8 decodes the private key of the given type from its PKCS8 encoding, and checks
+// it against the public key of the encoding
+func decodePKCS8(encoded []byte, keytype crypto.KeyType) (crypto.PrivateKey, error) {
+	secretLength := 64
+	if keytype == crypto.Secp256k1Type {
+		secretLength = secp256k1.PrivateKeyLength
+	}
+
+	dividerOffset := len(pkcs8Header) + secretLength
+	if len(encoded) < dividerOffset+len(pkcs8Divider) ||
+		!bytes.Equal(encoded[:len(pkcs8Header)], pkcs8Header) ||
+		!bytes.Equal(encoded[dividerOffset:dividerOffset+len(pkcs8Divider)], pkcs8Divider) {
+		return nil, fmt.Errorf("%w: invalid PKCS8 encoding", errInvalidKeyFile)
+	}
+
+	secret := encoded[len(pkcs8Header):dividerOffset]
+	public := encoded[dividerOffset+len(pkcs8Divider):]
+
+	var (
+		priv crypto.PrivateKey
+		err  error
+	)
+	switch keytype {
+	case crypto.Sr25519Type:
+		priv, err = sr25519.NewPrivateKeyFromEd25519Bytes(secret)
+	default:
+		priv, err = DecodePrivateKey(secret, keytype)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidKeyFile, err)
+	}
+
+	kp, err := PrivateKeyToKeypair(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(kp.Public().Encode(), public) {
+		return nil, fmt.Errorf("%w: public key does not match private key", errInvalidKeyFile)
+	}
+
+	return priv, nil
+}
+
+// encryptKeyFileData encrypts the data with a key derived from the password by scrypt, the
+// result is the salt and the parameters of scrypt followed by the nonce and the ciphertext
+func encryptKeyFileData(data, password []byte) ([]byte, error) {
+	salt := make([]byte, scryptSaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	key, err := scryptKey(password, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [secretboxNonceLength]byte
+	if _, err = io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, scryptParamsLength, scryptParamsLength+secretboxNonceLength+secretbox.Overhead+len(data))
+	copy(out, salt)
+	binary.LittleEndian.PutUint32(out[scryptSaltLength:], scryptN)
+	binary.LittleEndian.PutUint32(out[scryptSaltLength+4:], scryptP)
+	binary.LittleEndian.PutUint32(out[scryptSaltLength+8:], scryptR)
+	out = append(out, nonce[:]...)
+
+	return secretbox.Seal(out, data, &nonce, key), nil
+}
+
+// decryptKeyFileData decrypts data encrypted by encryptKeyFileData
+func decryptKeyFileData(data, password []byte) ([]byte, error) {
+	if len(data) < scryptParamsLength+secretboxNonceLength+secretbox.Overhead {
+		return nil, fmt.Errorf("%w: encrypted data too short", errInvalidKeyFile)
+	}
+
+	salt := data[:scryptSaltLength]
+	n := binary.LittleEndian.Uint32(data[scryptSaltLength:])
+	p := binary.LittleEndian.Uint32(data[scryptSaltLength+4:])
+	r := binary.LittleEndian.Uint32(data[scryptSaltLength+8:])
+	if _, ok := allowedScryptParams[scryptParams{n: n, p: p, r: r}]; !ok {
+		return nil, fmt.Errorf("%w: unsupported scrypt parameters N=%d p=%d r=%d", errInvalidKeyFile, n, p, r)
+	}
+
+	key, err := scryptKey(password, salt, int(n), int(r), int(p))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidKeyFile, err)
+	}
+
+	var nonce [secretboxNonceLength]byte
+	copy(nonce[:], data[scryptParamsLength:])
+
+	plaintext, ok := secretbox.Open(nil, data[scryptParamsLength+secretboxNonceLength:], &nonce, key)
+	if !ok {
+		return nil, errInvalidPassword
+	}
+
+	return plaintext, nil
+}
+
+// scryptKey derives the secretbox key from the password, as polkadot-js does
+func scryptKey(password, salt []byte, n, r, p int) (*[secretboxKeyLength]byte, error) {
+	derived, err := scrypt.Key(password, salt, n, r, p, scryptKeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	var key [secretboxKeyLength]byte
+	copy(key[:], derived)
+	return &key, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package keystore
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyFile(t *testing.T) {
+	srKp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	edKp, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+	secpKp, err := secp256k1.GenerateKeypair()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		kp      crypto.Keypair
+		keytype string
+	}{
+		{kp: srKp, keytype: "sr25519"},
+		{kp: edKp, keytype: "ed25519"},
+		{kp: secpKp, keytype: "ecdsa"},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.keytype, func(t *testing.T) {
+			keyFile, err := NewKeyFile(test.kp.Private(), testPassword)
+			require.NoError(t, err)
+			require.Equal(t, []string{"pkcs8", test.keytype}, keyFile.Encoding.Content)
+			require.Equal(t, []string{"scrypt", "xsalsa20-poly1305"}, keyFile.Encoding.Type)
+			require.Equal(t, "3", keyFile.Encoding.Version)
+
+			keytype, err := keyFile.KeyType()
+			require.NoError(t, err)
+			require.Equal(t, test.kp.Type(), keytype)
+
+			encrypted, err := base64.StdEncoding.DecodeString(keyFile.Encoded)
+			require.NoError(t, err)
+			encoded, err := decryptKeyFileData(encrypted, testPassword)
+			require.NoError(t, err)
+			require.Equal(t, pkcs8Header, encoded[:len(pkcs8Header)])
+
+			priv, err := keyFile.Decrypt(testPassword)
+			require.NoError(t, err)
+			require.Equal(t, test.kp.Private().Encode(), priv.Encode())
+
+			_, err = keyFile.Decrypt([]byte("wrong"))
+			require.ErrorIs(t, err, errInvalidPassword)
+
+			id, err := keyFile.AccountID()
+			require.NoError(t, err)
+			require.Equal(t, keyFile.Address, string(crypto.AccountIDToAddress(id)))
+		})
+	}
+}
+
+func TestKeyFile_Sr25519Address(t *testing.T) {
+	kr, err := NewSr25519Keyring()
+	require.NoError(t, err)
+
+	keyFile, err := NewKeyFile(kr.Alice().Private(), testPassword)
+	require.NoError(t, err)
+	require.Equal(t, "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", keyFile.Address)
+
+	id, err := keyFile.AccountID()
+	require.NoError(t, err)
+	require.Equal(t, kr.Alice().Public().Encode(), id)
+}
+
+func TestDecodePKCS8_PolkadotJS(t *testing.T) {
+	kr, err := NewSr25519Keyring()
+	require.NoError(t, err)
+
+	// secret key of Alice in polkadot-js
+	secret := common.MustHexToBytes("0x98319d4ff8a9508c4bb0cf0b5a78d760a0b2082c02775e6e82370816fedfff48" +
+		"925a225d97aa00682d6a59b95b18780c10d7032336e88f3442b42361f4a66011")
+
+	encoded := append(append(append(append([]byte{}, pkcs8Header...), secret...), pkcs8Divider...),
+		kr.Alice().Public().Encode()...)
+
+	priv, err := decodePKCS8(encoded, crypto.Sr25519Type)
+	require.NoError(t, err)
+	require.Equal(t, kr.Alice().Private().Encode(), priv.Encode())
+
+	// the public key must match the private key
+	_, err = decodePKCS8(encoded[:len(encoded)-1], crypto.Sr25519Type)
+	require.ErrorIs(t, err, errInvalidKeyFile)
+}
+
+func TestKeyFile_Decrypt_Invalid(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	keyFile, err := NewKeyFile(kp.Private(), testPassword)
+	require.NoError(t, err)
+
+	unencrypted := *keyFile
+	unencrypted.Encoding.Type = []string{"none"}
+	_, err = unencrypted.Decrypt(testPassword)
+	require.ErrorIs(t, err, errInvalidKeyFile)
+
+	unknown := *keyFile
+	unknown.Encoding.Content = []string{"pkcs8", "ethereum"}
+	_, err = unknown.Decrypt(testPassword)
+	require.ErrorIs(t, err, errInvalidKeyFile)
+
+	short := *keyFile
+	short.Encoded = base64.StdEncoding.EncodeToString([]byte{1, 2, 3})
+	_, err = short.Decrypt(testPassword)
+	require.ErrorIs(t, err, errInvalidKeyFile)
+}
+
+func TestKeyFile_Decrypt_ScryptParams(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	keyFile, err := NewKeyFile(kp.Private(), testPassword)
+	require.NoError(t, err)
+
+	encrypted, err := base64.StdEncoding.DecodeString(keyFile.Encoded)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		offset int
+		value  uint32
+	}{
+		"oversized N": {offset: scryptSaltLength, value: 1 << 20},
+		"oversized p": {offset: scryptSaltLength + 4, value: 1 << 10},
+		"oversized r": {offset: scryptSaltLength + 8, value: 1 << 20},
+	}
+
+	for name, test := range testCases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			data := append([]byte{}, encrypted...)
+			binary.LittleEndian.PutUint32(data[test.offset:], test.value)
+
+			crafted := *keyFile
+			crafted.Encoded = base64.StdEncoding.EncodeToString(data)
+			_, err := crafted.Decrypt(testPassword)
+			require.ErrorIs(t, err, errInvalidKeyFile)
+		})
+	}
+}
+
+func TestMigrateKeyFile(t *testing.T) {
+	kp, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	ciphertext, err := EncryptPrivateKey(kp.Private(), testPassword)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(&EncryptedKeystore{
+		Type:       crypto.Ed25519Type,
+		PublicKey:  kp.Public().Hex(),
+		Ciphertext: ciphertext,
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "legacy.key")
+	err = os.WriteFile(path, data, 0600)
+	require.NoError(t, err)
+
+	// the legacy key file can be read
+	priv, err := ReadFromFileAndDecrypt(path, testPassword)
+	require.NoError(t, err)
+	require.Equal(t, kp.Private().Encode(), priv.Encode())
+
+	_, err = MigrateKeyFile(path, []byte("wrong"))
+	require.Error(t, err)
+
+	migrated, err := MigrateKeyFile(path, testPassword)
+	require.NoError(t, err)
+	require.True(t, migrated)
+
+	keyFile, legacy, err := readKeyFile(path)
+	require.NoError(t, err)
+	require.Nil(t, legacy)
+	require.Equal(t, string(kp.Public().Address()), keyFile.Address)
+
+	priv, err = ReadFromFileAndDecrypt(path, testPassword)
+	require.NoError(t, err)
+	require.Equal(t, kp.Private().Encode(), priv.Encode())
+
+	migrated, err = MigrateKeyFile(path, testPassword)
+	require.NoError(t, err)
+	require.False(t, migrated)
+}
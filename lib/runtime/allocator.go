@@ -33,6 +33,7 @@ type FreeingBumpHeapAllocator struct {
 	maxHeapSize uint32
 	ptrOffset   uint32
 	totalSize   uint32
+	tracer      *Tracer
 }
 
 // NewAllocator Creates a new allocation heap which follows a freeing-bump strategy.
@@ -133,6 +134,7 @@ func (fbha *FreeingBumpHeapAllocator) Allocate(size uint32) (uint32, error) {
 	}
 	fbha.setHeap(ptr-8, uint8(listIndex))
 	fbha.totalSize = fbha.totalSize + itemSize + 8
+	fbha.tracer.Record(TraceEvent{Type: TraceAllocate, Ptr: fbha.ptrOffset + ptr, Size: size})
 	return fbha.ptrOffset + ptr, nil
 }
 
@@ -155,10 +157,16 @@ func (fbha *FreeingBumpHeapAllocator) Deallocate(pointer uint32) error {
 	// update heap total size
 	itemSize := getItemSizeFromIndex(uint(listIndex))
 	fbha.totalSize = fbha.totalSize - uint32(itemSize+8)
+	fbha.tracer.Record(TraceEvent{Type: TraceDeallocate, Ptr: pointer})
 
 	return nil
 }
 
+// SetTracer sets the tracer recording the allocations and deallocations
+func (fbha *FreeingBumpHeapAllocator) SetTracer(tracer *Tracer) {
+	fbha.tracer = tracer
+}
+
 // Clear resets the allocator, effectively freeing all allocated memory
 func (fbha *FreeingBumpHeapAllocator) Clear() {
 	fbha.bumper = 0
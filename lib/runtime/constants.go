@@ -55,20 +55,37 @@
 	TaggedTransactionQueueValidateTransaction = "TaggedTransactionQueue_validate_transaction"
 	// GrandpaAuthorities is the runtime API call GrandpaApi_grandpa_authorities
 	GrandpaAuthorities = "GrandpaApi_grandpa_authorities"
+	// GrandpaAPIGenerateKeyOwnershipProof is the runtime API call GrandpaApi_generate_key_ownership_proof
+	GrandpaAPIGenerateKeyOwnershipProof = "GrandpaApi_generate_key_ownership_proof"
+	// GrandpaAPISubmitReportEquivocationUnsignedExtrinsic is the runtime API call
+	// GrandpaApi_submit_report_equivocation_unsigned_extrinsic
+	GrandpaAPISubmitReportEquivocationUnsignedExtrinsic = "GrandpaApi_submit_report_equivocation_unsigned_extrinsic"
 	// BabeAPIConfiguration is the runtime API call BabeApi_configuration
 	BabeAPIConfiguration = "BabeApi_configuration"
+	// BabeAPIGenerateKeyOwnershipProof is the runtime API call BabeApi_generate_key_ownership_proof
+	BabeAPIGenerateKeyOwnershipProof = "BabeApi_generate_key_ownership_proof"
+	// BabeAPISubmitReportEquivocationUnsignedExtrinsic is the runtime API call
+	// BabeApi_submit_report_equivocation_unsigned_extrinsic
+	BabeAPISubmitReportEquivocationUnsignedExtrinsic = "BabeApi_submit_report_equivocation_unsigned_extrinsic"
 	// BlockBuilderInherentExtrinsics is the runtime API call BlockBuilder_inherent_extrinsics
 	BlockBuilderInherentExtrinsics = "BlockBuilder_inherent_extrinsics"
 	// BlockBuilderApplyExtrinsic is the runtime API call BlockBuilder_apply_extrinsic
 	BlockBuilderApplyExtrinsic = "BlockBuilder_apply_extrinsic"
+	// BlockBuilderCheckInherents is the runtime API call BlockBuilder_check_inherents
+	BlockBuilderCheckInherents = "BlockBuilder_check_inherents"
 	// BlockBuilderFinalizeBlock is the runtime API call BlockBuilder_finalize_block
 	BlockBuilderFinalizeBlock = "BlockBuilder_finalize_block"
 	// DecodeSessionKeys is the runtime API call SessionKeys_decode_session_keys
 	DecodeSessionKeys = "SessionKeys_decode_session_keys"
 	// TransactionPaymentAPIQueryInfo returns information of a given extrinsic
 	TransactionPaymentAPIQueryInfo = "TransactionPaymentApi_query_info"
+	// OffchainWorkerAPI is the runtime API call OffchainWorkerApi_offchain_worker
+	OffchainWorkerAPI = "OffchainWorkerApi_offchain_worker"
 )
 
+// OffchainWorkerAPIID is the blake2b-64 hash of the name of the OffchainWorkerApi runtime API
+var OffchainWorkerAPIID = [8]byte{0xf7, 0x8b, 0x27, 0x8b, 0xe5, 0x3f, 0x45, 0x4c}
+
 // GrandpaAuthoritiesKey is the location of GRANDPA authority data
 // in the storage trie for LEGACY_NODE_RUNTIME and NODE_RUNTIME
 var GrandpaAuthoritiesKey, _ = common.HexToBytes("0x3a6772616e6470615f617574686f726974696573")
@@ -22,3 +22,15 @@
 
 // ErrNilStorage is returned when the runtime context storage isn't set
 var ErrNilStorage = errors.New("runtime context storage is nil")
+
+// ErrEquivocationReportNotSubmitted is returned when the runtime fails to submit an equivocation report
+var ErrEquivocationReportNotSubmitted = errors.New("equivocation report was not submitted")
+
+// ErrCloneNotSupported is returned when a runtime instance cannot be cloned
+var ErrCloneNotSupported = errors.New("runtime instance cannot be cloned")
+
+// ErrInstancePoolStopped is returned when calling a runtime instance pool which is stopped
+var ErrInstancePoolStopped = errors.New("runtime instance pool is stopped")
+
+// ErrFatalInherentError is returned when the runtime reports a fatal error of the inherents of a block
+var ErrFatalInherentError = errors.New("fatal inherent error")
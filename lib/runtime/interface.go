@@ -6,6 +6,8 @@
 import (
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/lib/trie"
@@ -24,12 +26,21 @@ type Instance interface {
 	Validator() bool
 	Exec(function string, data []byte) ([]byte, error)
 	SetContextStorage(s Storage) // used to set the TrieState before a runtime call
+	Clone() (Instance, error)    // returns a new instance running the same code
 
 	GetCodeHash() common.Hash
 	Version() (Version, error)
 	Metadata() ([]byte, error)
 	BabeConfiguration() (*types.BabeConfiguration, error)
+	BabeGenerateKeyOwnershipProof(slot uint64, authorityID [sr25519.PublicKeyLength]byte) (
+		types.OpaqueKeyOwnershipProof, error)
+	BabeSubmitReportEquivocationUnsignedExtrinsic(proof types.BabeEquivocationProof,
+		keyOwnershipProof types.OpaqueKeyOwnershipProof) error
 	GrandpaAuthorities() ([]types.Authority, error)
+	GrandpaGenerateKeyOwnershipProof(setID uint64, authorityID ed25519.PublicKeyBytes) (
+		types.OpaqueKeyOwnershipProof, error)
+	GrandpaSubmitReportEquivocationUnsignedExtrinsic(proof types.GrandpaEquivocationProof,
+		keyOwnershipProof types.OpaqueKeyOwnershipProof) error
 	ValidateTransaction(e types.Extrinsic) (*transaction.Validity, error)
 	InitializeBlock(header *types.Header) error
 	InherentExtrinsics(data []byte) ([]byte, error)
@@ -38,12 +49,12 @@ type Instance interface {
 	ExecuteBlock(block *types.Block) ([]byte, error)
 	DecodeSessionKeys(enc []byte) ([]byte, error)
 	PaymentQueryInfo(ext []byte) (*types.TransactionPaymentQueryInfo, error)
+	OffchainWorker(header *types.Header) error
 
-	CheckInherents() // TODO: use this in block verification process (#1873)
+	CheckInherents(block *types.Block, inherentsData *types.InherentsData) (*types.CheckInherentsResult, error)
 
 	// parameters and return values for these are undefined in the spec
 	RandomSeed()
-	OffchainWorker()
 	GenerateSessionKeys()
 }
 
@@ -52,6 +63,7 @@ type Storage interface {
 	Set(key []byte, value []byte)
 	Get(key []byte) []byte
 	Root() (common.Hash, error)
+	RootWithVersion(version trie.Version) (common.Hash, error)
 	SetChild(keyToChild []byte, child *trie.Trie) error
 	SetChildStorage(keyToChild, key, value []byte) error
 	GetChildStorage(keyToChild, key []byte) ([]byte, error)
@@ -69,6 +81,7 @@ type Storage interface {
 	CommitStorageTransaction()
 	RollbackStorageTransaction()
 	LoadCode() []byte
+	AddIndexOperation(op types.IndexOperation)
 }
 
 // BasicNetwork interface for functions used by runtime network state function
@@ -89,3 +102,9 @@ type BasicStorage interface {
 type TransactionState interface {
 	AddToPool(vt *transaction.ValidTransaction) common.Hash
 }
+
+// TransactionSubmitter is implemented by the transaction states which validate the submitted
+// transactions, so that the runtime can be told whether the transaction was accepted
+type TransactionSubmitter interface {
+	SubmitTransaction(ext types.Extrinsic) error
+}
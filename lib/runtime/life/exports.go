@@ -10,6 +10,8 @@
 	"strings"
 
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -76,6 +78,62 @@ func (in *Instance) BabeConfiguration() (*types.BabeConfiguration, error) {
 	return bc, nil
 }
 
+// BabeGenerateKeyOwnershipProof returns the proof that the key of the given BABE authority
+// belongs to a validator of the session of the given slot. It returns nil if the runtime
+// cannot generate the proof, ie. if the key doesn't belong to a validator of the session.
+func (in *Instance) BabeGenerateKeyOwnershipProof(slot uint64, authorityID [sr25519.PublicKeyLength]byte) (
+	types.OpaqueKeyOwnershipProof, error) {
+	encSlot, err := scale.Marshal(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := in.Exec(runtime.BabeAPIGenerateKeyOwnershipProof, append(encSlot, authorityID[:]...))
+	if err != nil {
+		return nil, err
+	}
+
+	var proof *[]byte
+	err = scale.Unmarshal(res, &proof)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode key ownership proof: %w", err)
+	}
+
+	if proof == nil {
+		return nil, nil
+	}
+
+	return *proof, nil
+}
+
+// BabeSubmitReportEquivocationUnsignedExtrinsic submits an unsigned extrinsic reporting
+// the given BABE equivocation to the transaction pool.
+func (in *Instance) BabeSubmitReportEquivocationUnsignedExtrinsic(proof types.BabeEquivocationProof,
+	keyOwnershipProof types.OpaqueKeyOwnershipProof) error {
+	encProof, err := scale.Marshal(proof)
+	if err != nil {
+		return fmt.Errorf("cannot encode equivocation proof: %w", err)
+	}
+
+	encKeyOwnershipProof, err := scale.Marshal([]byte(keyOwnershipProof))
+	if err != nil {
+		return fmt.Errorf("cannot encode key ownership proof: %w", err)
+	}
+
+	res, err := in.Exec(runtime.BabeAPISubmitReportEquivocationUnsignedExtrinsic,
+		append(encProof, encKeyOwnershipProof...))
+	if err != nil {
+		return err
+	}
+
+	// the runtime returns an Option<()>, which is None if the report wasn't submitted
+	if len(res) == 0 || res[0] != 1 {
+		return runtime.ErrEquivocationReportNotSubmitted
+	}
+
+	return nil
+}
+
 // GrandpaAuthorities returns the genesis authorities from the runtime
 func (in *Instance) GrandpaAuthorities() ([]types.Authority, error) {
 	ret, err := in.Exec(runtime.GrandpaAuthorities, []byte{})
@@ -92,6 +150,62 @@ func (in *Instance) GrandpaAuthorities() ([]types.Authority, error) {
 	return types.GrandpaAuthoritiesRawToAuthorities(gar)
 }
 
+// GrandpaGenerateKeyOwnershipProof returns the proof that the key of the given GRANDPA authority
+// belongs to a validator of the session of the given authority set. It returns nil if the runtime
+// cannot generate the proof, ie. if the key doesn't belong to a validator of the session.
+func (in *Instance) GrandpaGenerateKeyOwnershipProof(setID uint64, authorityID ed25519.PublicKeyBytes) (
+	types.OpaqueKeyOwnershipProof, error) {
+	encSetID, err := scale.Marshal(setID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := in.Exec(runtime.GrandpaAPIGenerateKeyOwnershipProof, append(encSetID, authorityID[:]...))
+	if err != nil {
+		return nil, err
+	}
+
+	var proof *[]byte
+	err = scale.Unmarshal(res, &proof)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode key ownership proof: %w", err)
+	}
+
+	if proof == nil {
+		return nil, nil
+	}
+
+	return *proof, nil
+}
+
+// GrandpaSubmitReportEquivocationUnsignedExtrinsic submits an unsigned extrinsic reporting
+// the given GRANDPA equivocation to the transaction pool.
+func (in *Instance) GrandpaSubmitReportEquivocationUnsignedExtrinsic(proof types.GrandpaEquivocationProof,
+	keyOwnershipProof types.OpaqueKeyOwnershipProof) error {
+	encProof, err := scale.Marshal(proof)
+	if err != nil {
+		return fmt.Errorf("cannot encode equivocation proof: %w", err)
+	}
+
+	encKeyOwnershipProof, err := scale.Marshal([]byte(keyOwnershipProof))
+	if err != nil {
+		return fmt.Errorf("cannot encode key ownership proof: %w", err)
+	}
+
+	res, err := in.Exec(runtime.GrandpaAPISubmitReportEquivocationUnsignedExtrinsic,
+		append(encProof, encKeyOwnershipProof...))
+	if err != nil {
+		return err
+	}
+
+	// the runtime returns an Option<()>, which is None if the report wasn't submitted
+	if len(res) == 0 || res[0] != 1 {
+		return runtime.ErrEquivocationReportNotSubmitted
+	}
+
+	return nil
+}
+
 // InitializeBlock calls runtime API function Core_initialise_block
 func (in *Instance) InitializeBlock(header *types.Header) error {
 	encodedHeader, err := scale.Marshal(*header)
@@ -166,6 +280,53 @@ func (in *Instance) ExecuteBlock(block *types.Block) ([]byte, error) {
 	return in.Exec(runtime.CoreExecuteBlock, bdEnc)
 }
 
+// CheckInherents calls runtime API function BlockBuilder_check_inherents, which checks the
+// inherents of the given block against the given inherents data
+func (in *Instance) CheckInherents(block *types.Block, inherentsData *types.InherentsData) (
+	*types.CheckInherentsResult, error) {
+	// copy block since we're going to modify it
+	b, err := block.DeepCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	// remove seal digest only
+	b.Header.Digest = types.NewDigest()
+	for _, d := range block.Header.Digest.Types {
+		if _, ok := d.Value().(types.SealDigest); ok {
+			continue
+		}
+
+		err = b.Header.Digest.Add(d.Value())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	encBlock, err := b.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	encData, err := inherentsData.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := in.Exec(runtime.BlockBuilderCheckInherents, append(encBlock, encData...))
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(types.CheckInherentsResult)
+	err = scale.Unmarshal(res, result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode check inherents result: %w", err)
+	}
+
+	return result, nil
+}
+
 // DecodeSessionKeys decodes the given public session keys. Returns a list of raw public keys including their key type.
 func (in *Instance) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	return in.Exec(runtime.DecodeSessionKeys, enc)
@@ -177,7 +338,16 @@ func (*Instance) PaymentQueryInfo([]byte) (*types.TransactionPaymentQueryInfo, e
 	return nil, errors.New("not implemented yet")
 }
 
-func (in *Instance) CheckInherents()      {} //nolint:revive
+// OffchainWorker runs the offchain worker of the runtime for the given block header
+func (in *Instance) OffchainWorker(header *types.Header) error {
+	encHeader, err := scale.Marshal(*header)
+	if err != nil {
+		return fmt.Errorf("cannot encode header: %w", err)
+	}
+
+	_, err = in.Exec(runtime.OffchainWorkerAPI, encHeader)
+	return err
+}
+
 func (in *Instance) RandomSeed()          {} //nolint:revive
-func (in *Instance) OffchainWorker()      {} //nolint:revive
 func (in *Instance) GenerateSessionKeys() {} //nolint:revive
@@ -167,6 +167,11 @@ func (*Instance) SetContextStorage(s runtime.Storage) {
 	ctx.Storage = s
 }
 
+// Clone is not supported, since the instances share a global context
+func (*Instance) Clone() (runtime.Instance, error) {
+	return nil, runtime.ErrCloneNotSupported
+}
+
 // Exec calls the given function with the given data
 func (in *Instance) Exec(function string, data []byte) ([]byte, error) {
 	in.mu.Lock()
@@ -4,6 +4,9 @@
 
 import (
 	common "github.com/ChainSafe/gossamer/lib/common"
+
+	ed25519 "github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+
 	keystore "github.com/ChainSafe/gossamer/lib/keystore"
 
 	mock "github.com/stretchr/testify/mock"
@@ -66,9 +69,64 @@ func (_m *Instance) BabeConfiguration() (*types.BabeConfiguration, error) {
 	return r0, r1
 }
 
-// CheckInherents provides a mock function with given fields:
-func (_m *Instance) CheckInherents() {
-	_m.Called()
+// BabeGenerateKeyOwnershipProof provides a mock function with given fields: slot, authorityID
+func (_m *Instance) BabeGenerateKeyOwnershipProof(slot uint64, authorityID [32]byte) (types.OpaqueKeyOwnershipProof, error) {
+	ret := _m.Called(slot, authorityID)
+
+	var r0 types.OpaqueKeyOwnershipProof
+	if rf, ok := ret.Get(0).(func(uint64, [32]byte) types.OpaqueKeyOwnershipProof); ok {
+		r0 = rf(slot, authorityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.OpaqueKeyOwnershipProof)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, [32]byte) error); ok {
+		r1 = rf(slot, authorityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BabeSubmitReportEquivocationUnsignedExtrinsic provides a mock function with given fields: proof, keyOwnershipProof
+func (_m *Instance) BabeSubmitReportEquivocationUnsignedExtrinsic(proof types.BabeEquivocationProof, keyOwnershipProof types.OpaqueKeyOwnershipProof) error {
+	ret := _m.Called(proof, keyOwnershipProof)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(types.BabeEquivocationProof, types.OpaqueKeyOwnershipProof) error); ok {
+		r0 = rf(proof, keyOwnershipProof)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckInherents provides a mock function with given fields: block, inherentsData
+func (_m *Instance) CheckInherents(block *types.Block, inherentsData *types.InherentsData) (*types.CheckInherentsResult, error) {
+	ret := _m.Called(block, inherentsData)
+
+	var r0 *types.CheckInherentsResult
+	if rf, ok := ret.Get(0).(func(*types.Block, *types.InherentsData) *types.CheckInherentsResult); ok {
+		r0 = rf(block, inherentsData)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.CheckInherentsResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*types.Block, *types.InherentsData) error); ok {
+		r1 = rf(block, inherentsData)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // CheckRuntimeVersion provides a mock function with given fields: _a0
@@ -94,6 +152,29 @@ func (_m *Instance) CheckRuntimeVersion(_a0 []byte) (runtime.Version, error) {
 	return r0, r1
 }
 
+// Clone provides a mock function with given fields:
+func (_m *Instance) Clone() (runtime.Instance, error) {
+	ret := _m.Called()
+
+	var r0 runtime.Instance
+	if rf, ok := ret.Get(0).(func() runtime.Instance); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(runtime.Instance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DecodeSessionKeys provides a mock function with given fields: enc
 func (_m *Instance) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	ret := _m.Called(enc)
@@ -230,6 +311,43 @@ func (_m *Instance) GrandpaAuthorities() ([]types.Authority, error) {
 	return r0, r1
 }
 
+// GrandpaGenerateKeyOwnershipProof provides a mock function with given fields: setID, authorityID
+func (_m *Instance) GrandpaGenerateKeyOwnershipProof(setID uint64, authorityID ed25519.PublicKeyBytes) (types.OpaqueKeyOwnershipProof, error) {
+	ret := _m.Called(setID, authorityID)
+
+	var r0 types.OpaqueKeyOwnershipProof
+	if rf, ok := ret.Get(0).(func(uint64, ed25519.PublicKeyBytes) types.OpaqueKeyOwnershipProof); ok {
+		r0 = rf(setID, authorityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.OpaqueKeyOwnershipProof)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, ed25519.PublicKeyBytes) error); ok {
+		r1 = rf(setID, authorityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrandpaSubmitReportEquivocationUnsignedExtrinsic provides a mock function with given fields: proof, keyOwnershipProof
+func (_m *Instance) GrandpaSubmitReportEquivocationUnsignedExtrinsic(proof types.GrandpaEquivocationProof, keyOwnershipProof types.OpaqueKeyOwnershipProof) error {
+	ret := _m.Called(proof, keyOwnershipProof)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(types.GrandpaEquivocationProof, types.OpaqueKeyOwnershipProof) error); ok {
+		r0 = rf(proof, keyOwnershipProof)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InherentExtrinsics provides a mock function with given fields: data
 func (_m *Instance) InherentExtrinsics(data []byte) ([]byte, error) {
 	ret := _m.Called(data)
@@ -336,9 +454,18 @@ func (_m *Instance) NodeStorage() runtime.NodeStorage {
 	return r0
 }
 
-// OffchainWorker provides a mock function with given fields:
-func (_m *Instance) OffchainWorker() {
-	_m.Called()
+// OffchainWorker provides a mock function with given fields: header
+func (_m *Instance) OffchainWorker(header *types.Header) error {
+	ret := _m.Called(header)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Header) error); ok {
+		r0 = rf(header)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // PaymentQueryInfo provides a mock function with given fields: ext
@@ -7,9 +7,13 @@
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 type contextKey string
@@ -27,6 +31,16 @@
 	errRequestIDNotAvailable = errors.New("request id not available")
 	errRequestInvalid        = errors.New("request is invalid")
 	errInvalidHeaderKey      = errors.New("invalid header key")
+	errRequestAlreadySent    = errors.New("request already sent")
+	errBodyFinished          = errors.New("request body already finished")
+	errBodyClosed            = errors.New("request body closed")
+
+	// ErrDeadlineReached is returned when the deadline is reached before the request finished
+	ErrDeadlineReached = errors.New("deadline reached")
+	// ErrIO is returned when the request failed to be performed
+	ErrIO = errors.New("io error")
+	// ErrInvalidRequestID is returned when there is no request with the given id
+	ErrInvalidRequestID = errors.New("invalid request id")
 )
 
 // requestIDBuffer created to control the amount of available non-duplicated ids
@@ -64,6 +78,37 @@ func (b requestIDBuffer) put(i int16) error {
 // the request starts or is waiting to be read
 type Request struct {
 	Request *http.Request
+
+	// Response is set once the request is finished without error
+	Response *http.Response
+
+	sent   bool
+	done   chan struct{}
+	err    error
+	cancel context.CancelFunc
+
+	// body streams the chunks written by the runtime, it's nil once the body is finished
+	body *streamBody
+
+	// reading holds the result of a read of the response body which didn't
+	// complete before its deadline, unread the data read but not returned yet
+	reading chan readResult
+	unread  []byte
+	readErr error
+}
+
+// ResponseHeader is a header of a response, its name is lower case
+type ResponseHeader struct {
+	Name  string
+	Value string
+}
+
+// RequestStatus is the status of a request after waiting for it
+type RequestStatus struct {
+	// Code is the HTTP status code of the response, if the request is finished
+	Code uint16
+	// Err is ErrDeadlineReached, ErrInvalidRequestID or wraps ErrIO if the request isn't finished
+	Err error
 }
 
 // AddHeader adds a new HTTP header into request property, only if request is valid
@@ -73,6 +118,10 @@ func (r *Request) AddHeader(name, value string) error {
 		return errRequestInvalid
 	}
 
+	if r.sent {
+		return errRequestAlreadySent
+	}
+
 	name = strings.TrimSpace(name)
 	if len(name) == 0 {
 		return fmt.Errorf("%w: empty header key", errInvalidHeaderKey)
@@ -82,11 +131,187 @@ func (r *Request) AddHeader(name, value string) error {
 	return nil
 }
 
+// send performs the request in the background, its response or error
+// is available once the done channel is closed.
+func (r *Request) send(client *http.Client) {
+	r.sent = true
+
+	if r.body != nil {
+		r.Request.Body = r.body
+		r.Request.ContentLength = -1
+		// the length of a streamed body is only known if the runtime provided it
+		length, err := strconv.ParseInt(r.Request.Header.Get("Content-Length"), 10, 64)
+		if err == nil {
+			r.Request.ContentLength = length
+		}
+	}
+
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+		r.Response, r.err = client.Do(r.Request) //nolint:bodyclose
+	}()
+}
+
+// finish sends the request if it isn't sent yet, and finishes its body.
+func (r *Request) finish(client *http.Client) {
+	if !r.sent {
+		r.send(client)
+	}
+
+	if r.body != nil {
+		r.body.finish()
+		r.body = nil
+	}
+}
+
+// writeBody hands the chunk over to the request being sent. The chunk is either
+// entirely written or not written at all if the deadline is reached.
+func (r *Request) writeBody(ctx context.Context, chunk []byte) error {
+	if r.body == nil {
+		return fmt.Errorf("%w: %s", ErrIO, errBodyFinished)
+	}
+
+	// the chunk may be a view of the memory of the runtime
+	return r.body.write(ctx, append([]byte{}, chunk...))
+}
+
+// readBody reads the response body into the buffer. It returns 0 once the whole body is read.
+// A read which didn't complete before the deadline is resumed by the next call.
+func (r *Request) readBody(ctx context.Context, buf []byte) (int, error) {
+	for len(r.unread) == 0 && r.readErr == nil {
+		if r.reading == nil {
+			r.reading = make(chan readResult, 1)
+			go read(r.Response.Body, len(buf), r.reading)
+		}
+
+		select {
+		case res := <-r.reading:
+			r.reading = nil
+			r.unread, r.readErr = res.data, res.err
+		case <-ctx.Done():
+			return 0, ErrDeadlineReached
+		}
+	}
+
+	if len(r.unread) == 0 {
+		if errors.Is(r.readErr, io.EOF) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("%w: %s", ErrIO, r.readErr)
+	}
+
+	n := copy(buf, r.unread)
+	r.unread = r.unread[n:]
+	return n, nil
+}
+
+type readResult struct {
+	data []byte
+	err  error
+}
+
+func read(reader io.Reader, size int, result chan<- readResult) {
+	data := make([]byte, size)
+	n, err := reader.Read(data)
+	result <- readResult{data: data[:n], err: err}
+}
+
+func (r *Request) status() RequestStatus {
+	if r.err != nil {
+		return RequestStatus{Err: fmt.Errorf("%w: %s", ErrIO, r.err)}
+	}
+
+	return RequestStatus{Code: uint16(r.Response.StatusCode)}
+}
+
+// close cancels the request if it's still in progress and releases its response.
+func (r *Request) close() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	if r.body != nil {
+		_ = r.body.Close()
+	}
+
+	if !r.sent {
+		return
+	}
+
+	go func() {
+		<-r.done
+		if r.Response != nil {
+			_ = r.Response.Body.Close()
+		}
+	}()
+}
+
+// streamBody is the body of a request, streamed chunk by chunk by the runtime
+type streamBody struct {
+	chunks    chan []byte
+	chunk     []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newStreamBody() *streamBody {
+	return &streamBody{
+		chunks: make(chan []byte),
+		closed: make(chan struct{}),
+	}
+}
+
+// Read reads the chunks written by the runtime, it's called by the http client
+// while sending the request.
+func (b *streamBody) Read(p []byte) (int, error) {
+	if len(b.chunk) == 0 {
+		select {
+		case chunk, ok := <-b.chunks:
+			if !ok {
+				return 0, io.EOF
+			}
+			b.chunk = chunk
+		case <-b.closed:
+			return 0, errBodyClosed
+		}
+	}
+
+	n := copy(p, b.chunk)
+	b.chunk = b.chunk[n:]
+	return n, nil
+}
+
+// Close is called by the http client once the request is sent or failed
+func (b *streamBody) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+	return nil
+}
+
+func (b *streamBody) write(ctx context.Context, chunk []byte) error {
+	select {
+	case b.chunks <- chunk:
+		return nil
+	case <-b.closed:
+		return fmt.Errorf("%w: %s", ErrIO, errBodyClosed)
+	case <-ctx.Done():
+		return ErrDeadlineReached
+	}
+}
+
+func (b *streamBody) finish() {
+	close(b.chunks)
+}
+
 // HTTPSet holds a pool of concurrent http request calls
 type HTTPSet struct {
 	*sync.Mutex
 	reqs   map[int16]*Request
 	idBuff requestIDBuffer
+	client *http.Client
 }
 
 // NewHTTPSet creates a offchain http set that can be used
@@ -96,6 +321,7 @@ func NewHTTPSet() *HTTPSet {
 		new(sync.Mutex),
 		make(map[int16]*Request),
 		newIntBuffer(maxConcurrentRequests),
+		new(http.Client),
 	}
 }
 
@@ -114,20 +340,22 @@ func (p *HTTPSet) StartRequest(method, uri string) (int16, error) {
 		return 0, errRequestIDNotAvailable
 	}
 
-	req, err := http.NewRequest(method, uri, nil)
-	req.Header = make(http.Header)
-
-	ctx := context.WithValue(req.Context(), waitingKey, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, waitingKey, false)
 	ctx = context.WithValue(ctx, invalidKey, false)
 
-	req = req.WithContext(ctx)
-
+	req, err := http.NewRequestWithContext(ctx, method, uri, nil)
 	if err != nil {
+		cancel()
+		_ = p.idBuff.put(id)
 		return 0, err
 	}
 
+	req.Header = make(http.Header)
+
 	p.reqs[id] = &Request{
 		Request: req,
+		cancel:  cancel,
 	}
 
 	return id, nil
@@ -138,11 +366,58 @@ func (p *HTTPSet) Remove(id int16) error {
 	p.Lock()
 	defer p.Unlock()
 
+	if req, ok := p.reqs[id]; ok {
+		req.close()
+	}
+
 	delete(p.reqs, id)
 
 	return p.idBuff.put(id)
 }
 
+// Wait sends the requests with the given ids which weren't sent yet, finishes their bodies and
+// waits for their responses until the deadline. A nil deadline waits until all the requests are finished.
+// The statuses are returned in the same order as the ids.
+func (p *HTTPSet) Wait(ids []int16, deadline *time.Time) []RequestStatus {
+	p.Lock()
+	reqs := make([]*Request, len(ids))
+	for i, id := range ids {
+		req := p.reqs[id]
+		if req != nil {
+			req.finish(p.client)
+		}
+		reqs[i] = req
+	}
+	p.Unlock()
+
+	ctx, cancel := deadlineContext(deadline)
+	defer cancel()
+
+	statuses := make([]RequestStatus, len(ids))
+	for i, req := range reqs {
+		if req == nil {
+			statuses[i] = RequestStatus{Err: ErrInvalidRequestID}
+			continue
+		}
+
+		select {
+		case <-req.done:
+			statuses[i] = req.status()
+			continue
+		default:
+		}
+
+		select {
+		case <-req.done:
+			statuses[i] = req.status()
+		case <-ctx.Done():
+			statuses[i] = RequestStatus{Err: ErrDeadlineReached}
+		}
+	}
+
+	return statuses
+}
+
 // Get returns a request or nil if request not found
 func (p *HTTPSet) Get(id int16) *Request {
 	p.Lock()
@@ -150,3 +425,124 @@ func (p *HTTPSet) Get(id int16) *Request {
 
 	return p.reqs[id]
 }
+
+// WriteBody writes a chunk of the body of the request with the given id, sending the request if
+// it isn't sent yet. An empty chunk finishes the body. It returns ErrDeadlineReached if the chunk
+// couldn't be written before the deadline, an error wrapping ErrIO if the request failed or its
+// body is already finished, and ErrInvalidRequestID if there is no request with the given id.
+func (p *HTTPSet) WriteBody(id int16, chunk []byte, deadline *time.Time) error {
+	p.Lock()
+	req := p.reqs[id]
+	if req == nil {
+		p.Unlock()
+		return ErrInvalidRequestID
+	}
+
+	if len(chunk) == 0 {
+		defer p.Unlock()
+		if req.sent && req.body == nil {
+			return fmt.Errorf("%w: %s", ErrIO, errBodyFinished)
+		}
+
+		req.finish(p.client)
+		return nil
+	}
+
+	if !req.sent {
+		req.body = newStreamBody()
+		req.send(p.client)
+	}
+	p.Unlock()
+
+	ctx, cancel := deadlineContext(deadline)
+	defer cancel()
+
+	return req.writeBody(ctx, chunk)
+}
+
+// ResponseHeaders returns the headers of the response of the request with the given id, sorted
+// by name. It returns nil if there is no such request or if its response isn't received yet.
+func (p *HTTPSet) ResponseHeaders(id int16) []ResponseHeader {
+	p.Lock()
+	req := p.reqs[id]
+	p.Unlock()
+
+	if req == nil || !req.sent {
+		return nil
+	}
+
+	select {
+	case <-req.done:
+	default:
+		return nil
+	}
+
+	if req.Response == nil {
+		return nil
+	}
+
+	var headers []ResponseHeader
+	for name, values := range req.Response.Header {
+		for _, value := range values {
+			headers = append(headers, ResponseHeader{Name: strings.ToLower(name), Value: value})
+		}
+	}
+
+	sort.SliceStable(headers, func(i, j int) bool {
+		return headers[i].Name < headers[j].Name
+	})
+
+	return headers
+}
+
+// ReadBody reads the response body of the request with the given id into the buffer, sending
+// the request and waiting for its response if needed. It returns the number of bytes read, which
+// is 0 once the whole body is read. The errors are the same as the ones of WriteBody.
+// The request is removed once its body is read or if it failed.
+func (p *HTTPSet) ReadBody(id int16, buf []byte, deadline *time.Time) (int, error) {
+	status := p.Wait([]int16{id}, deadline)[0]
+	if status.Err != nil {
+		if errors.Is(status.Err, ErrIO) {
+			return 0, p.removeFailed(id, status.Err)
+		}
+		return 0, status.Err
+	}
+
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := deadlineContext(deadline)
+	defer cancel()
+
+	n, err := p.Get(id).readBody(ctx, buf)
+	switch {
+	case errors.Is(err, ErrIO):
+		return 0, p.removeFailed(id, err)
+	case err != nil:
+		return 0, err
+	case n == 0:
+		return 0, p.Remove(id)
+	}
+
+	return n, nil
+}
+
+// removeFailed removes the failed request with the given id, and returns its error.
+func (p *HTTPSet) removeFailed(id int16, err error) error {
+	removeErr := p.Remove(id)
+	if removeErr != nil {
+		return fmt.Errorf("%w: cannot remove request: %s", err, removeErr)
+	}
+
+	return err
+}
+
+// deadlineContext returns a context cancelled at the deadline, or never if the deadline is nil.
+func deadlineContext(deadline *time.Time) (context.Context, context.CancelFunc) {
+	if deadline == nil {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithDeadline(context.Background(), *deadline)
+}
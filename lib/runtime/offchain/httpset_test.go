@@ -6,8 +6,11 @@
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -64,7 +67,7 @@ func TestOffchainRequest_AddHeader(t *testing.T) {
 		headerK, headerV string
 	}{
 		"should return invalid request": {
-			offReq: Request{invalidReq},
+			offReq: Request{Request: invalidReq},
 			err:    errRequestInvalid,
 		},
 		"should add header": {
@@ -101,3 +104,196 @@ func TestOffchainRequest_AddHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPSet_Wait(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			<-release
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			if r.Header.Get("key") != "value" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+
+	set := NewHTTPSet()
+
+	okID, err := set.StartRequest(http.MethodGet, server.URL)
+	require.NoError(t, err)
+	err = set.Get(okID).AddHeader("key", "value")
+	require.NoError(t, err)
+
+	missingID, err := set.StartRequest(http.MethodGet, server.URL+"/missing")
+	require.NoError(t, err)
+
+	slowID, err := set.StartRequest(http.MethodGet, server.URL+"/slow")
+	require.NoError(t, err)
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	statuses := set.Wait([]int16{okID, missingID, slowID, 999}, &deadline)
+
+	require.Equal(t, []RequestStatus{
+		{Code: http.StatusOK},
+		{Code: http.StatusNotFound},
+		{Err: ErrDeadlineReached},
+		{Err: ErrInvalidRequestID},
+	}, statuses)
+
+	// headers can't be added once the request is sent
+	err = set.Get(okID).AddHeader("key", "value")
+	require.ErrorIs(t, err, errRequestAlreadySent)
+
+	for _, id := range []int16{okID, missingID, slowID} {
+		err = set.Remove(id)
+		require.NoError(t, err)
+	}
+}
+
+func TestHTTPSet_Wait_IOError(t *testing.T) {
+	t.Parallel()
+
+	set := NewHTTPSet()
+	id, err := set.StartRequest(http.MethodGet, "http://127.0.0.1:0")
+	require.NoError(t, err)
+
+	statuses := set.Wait([]int16{id}, nil)
+	require.Len(t, statuses, 1)
+	require.ErrorIs(t, statuses[0].Err, ErrIO)
+}
+
+func TestHTTPSet_WriteBody_ReadBody(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("X-Echo", "true")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	set := NewHTTPSet()
+	id, err := set.StartRequest(http.MethodPost, server.URL)
+	require.NoError(t, err)
+
+	for _, chunk := range []string{"hello", " ", "world"} {
+		err = set.WriteBody(id, []byte(chunk), nil)
+		require.NoError(t, err)
+	}
+
+	// the headers are only available once the response is received
+	require.Nil(t, set.ResponseHeaders(id))
+
+	err = set.WriteBody(id, nil, nil)
+	require.NoError(t, err)
+
+	err = set.WriteBody(id, []byte("late"), nil)
+	require.ErrorIs(t, err, ErrIO)
+
+	statuses := set.Wait([]int16{id}, nil)
+	require.Equal(t, []RequestStatus{{Code: http.StatusOK}}, statuses)
+
+	headers := set.ResponseHeaders(id)
+	require.Contains(t, headers, ResponseHeader{Name: "x-echo", Value: "true"})
+
+	// the body is read in chunks of the size of the buffer
+	var body []byte
+	buf := make([]byte, 4)
+	for {
+		n, err := set.ReadBody(id, buf, nil)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+		body = append(body, buf[:n]...)
+	}
+
+	require.Equal(t, "hello world", string(body))
+
+	// the request is removed once its body is read
+	require.Nil(t, set.Get(id))
+	_, err = set.ReadBody(id, buf, nil)
+	require.ErrorIs(t, err, ErrInvalidRequestID)
+}
+
+func TestHTTPSet_ReadBody_Deadline(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("first"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("second"))
+	}))
+	t.Cleanup(server.Close)
+
+	set := NewHTTPSet()
+	id, err := set.StartRequest(http.MethodGet, server.URL)
+	require.NoError(t, err)
+
+	buf := make([]byte, 16)
+	n, err := set.ReadBody(id, buf, nil)
+	require.NoError(t, err)
+	require.Equal(t, "first", string(buf[:n]))
+
+	deadline := time.Now().Add(100 * time.Millisecond)
+	_, err = set.ReadBody(id, buf, &deadline)
+	require.ErrorIs(t, err, ErrDeadlineReached)
+
+	// the read which didn't complete before the deadline is resumed
+	close(release)
+	n, err = set.ReadBody(id, buf, nil)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(buf[:n]))
+
+	n, err = set.ReadBody(id, buf, nil)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestHTTPSet_WriteBody_Deadline(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = io.ReadAll(r.Body)
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+
+	set := NewHTTPSet()
+	id, err := set.StartRequest(http.MethodPost, server.URL)
+	require.NoError(t, err)
+
+	// the server doesn't read the body, so the chunks end up blocking the client
+	chunk := make([]byte, 1<<20)
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for err == nil {
+		err = set.WriteBody(id, chunk, &deadline)
+	}
+	require.ErrorIs(t, err, ErrDeadlineReached)
+
+	err = set.WriteBody(999, chunk, nil)
+	require.ErrorIs(t, err, ErrInvalidRequestID)
+
+	err = set.Remove(id)
+	require.NoError(t, err)
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"errors"
+	"sync"
+)
+
+// DefaultInstancePoolSize is the default number of instances of an instance pool
+const DefaultInstancePoolSize = 4
+
+// InstancePool holds instances running the same runtime code, so that runtime calls can run
+// concurrently. An instance of the pool only serves one call at a time.
+type InstancePool struct {
+	instances chan Instance
+	clones    []Instance
+	stopOnce  sync.Once
+}
+
+// NewInstancePool creates a pool of the given size, made of the given instance and its clones.
+// The pool only holds the given instance if it cannot be cloned.
+func NewInstancePool(instance Instance, size int) (*InstancePool, error) {
+	if size < 1 {
+		size = 1
+	}
+
+	clones := make([]Instance, 0, size-1)
+	for len(clones) < size-1 {
+		clone, err := instance.Clone()
+		if errors.Is(err, ErrCloneNotSupported) {
+			break
+		}
+
+		if err != nil {
+			for _, clone := range clones {
+				clone.Stop()
+			}
+			return nil, err
+		}
+
+		clones = append(clones, clone)
+	}
+
+	pool := &InstancePool{
+		instances: make(chan Instance, len(clones)+1),
+		clones:    clones,
+	}
+
+	pool.instances <- instance
+	for _, clone := range clones {
+		pool.instances <- clone
+	}
+
+	return pool, nil
+}
+
+// Size returns the number of instances of the pool
+func (p *InstancePool) Size() int {
+	return cap(p.instances)
+}
+
+// Call runs fn with an instance of the pool whose context storage is set to the given storage.
+// It waits for an instance to be available if all the instances of the pool are in use.
+// It returns ErrInstancePoolStopped if the pool is stopped.
+func (p *InstancePool) Call(storage Storage, fn func(instance Instance) error) error {
+	instance, ok := <-p.instances
+	if !ok {
+		return ErrInstancePoolStopped
+	}
+	defer func() {
+		p.instances <- instance
+	}()
+
+	instance.SetContextStorage(storage)
+	return fn(instance)
+}
+
+// Stop waits for the calls in progress to complete, and stops the clones created by the pool.
+// The instance the pool was created from is left running, since it is owned by the caller of
+// NewInstancePool. The calls made once the pool is stopped return ErrInstancePoolStopped.
+// Stopping a pool more than once has no effect.
+func (p *InstancePool) Stop() {
+	p.stopOnce.Do(func() {
+		// the instances in use are returned to the pool once their call completes
+		for i := 0; i < cap(p.instances); i++ {
+			<-p.instances
+		}
+		close(p.instances)
+
+		for _, clone := range p.clones {
+			clone.Stop()
+		}
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInstancePool(t *testing.T) {
+	instance := new(mocks.Instance)
+	instance.On("Clone").Return(new(mocks.Instance), nil).Times(3)
+
+	pool, err := runtime.NewInstancePool(instance, 4)
+	require.NoError(t, err)
+	require.Equal(t, 4, pool.Size())
+	instance.AssertExpectations(t)
+
+	pool, err = runtime.NewInstancePool(new(mocks.Instance), 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, pool.Size())
+}
+
+func TestNewInstancePool_CloneNotSupported(t *testing.T) {
+	instance := new(mocks.Instance)
+	instance.On("Clone").Return(nil, runtime.ErrCloneNotSupported).Once()
+
+	pool, err := runtime.NewInstancePool(instance, 4)
+	require.NoError(t, err)
+	require.Equal(t, 1, pool.Size())
+	instance.AssertExpectations(t)
+}
+
+func TestNewInstancePool_CloneError(t *testing.T) {
+	clone := new(mocks.Instance)
+	clone.On("Stop").Once()
+
+	errTest := errors.New("test error")
+	instance := new(mocks.Instance)
+	instance.On("Clone").Return(clone, nil).Once()
+	instance.On("Clone").Return(nil, errTest).Once()
+
+	_, err := runtime.NewInstancePool(instance, 4)
+	require.ErrorIs(t, err, errTest)
+	clone.AssertExpectations(t)
+}
+
+func TestInstancePool_Call(t *testing.T) {
+	const size = 2
+
+	instance := new(mocks.Instance)
+	instance.On("SetContextStorage", mock.Anything)
+	clone := new(mocks.Instance)
+	clone.On("SetContextStorage", mock.Anything)
+	clone.On("Stop").Once()
+	instance.On("Clone").Return(clone, nil).Once()
+
+	pool, err := runtime.NewInstancePool(instance, size)
+	require.NoError(t, err)
+
+	ts, err := storage.NewTrieState(nil)
+	require.NoError(t, err)
+
+	// all the instances of the pool are in use at the same time
+	var wg, inUse sync.WaitGroup
+	wg.Add(size)
+	inUse.Add(size)
+	used := make(chan runtime.Instance, size)
+	for i := 0; i < size; i++ {
+		go func() {
+			defer wg.Done()
+			err := pool.Call(ts, func(rt runtime.Instance) error {
+				used <- rt
+				inUse.Done()
+				inUse.Wait()
+				return nil
+			})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	close(used)
+
+	var instances []runtime.Instance
+	for rt := range used {
+		instances = append(instances, rt)
+	}
+	require.ElementsMatch(t, []runtime.Instance{instance, clone}, instances)
+
+	instance.AssertCalled(t, "SetContextStorage", ts)
+	clone.AssertCalled(t, "SetContextStorage", ts)
+
+	errTest := errors.New("test error")
+	err = pool.Call(ts, func(runtime.Instance) error {
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+
+	pool.Stop()
+	clone.AssertExpectations(t)
+	instance.AssertNotCalled(t, "Stop")
+}
+
+func TestInstancePool_Stop(t *testing.T) {
+	instance := new(mocks.Instance)
+	instance.On("SetContextStorage", mock.Anything)
+	instance.On("Clone").Return(nil, runtime.ErrCloneNotSupported).Once()
+
+	pool, err := runtime.NewInstancePool(instance, 2)
+	require.NoError(t, err)
+
+	ts, err := storage.NewTrieState(nil)
+	require.NoError(t, err)
+
+	// the pool is stopped once the call in progress completes
+	inCall, release, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go func() {
+		err := pool.Call(ts, func(runtime.Instance) error {
+			close(inCall)
+			<-release
+			return nil
+		})
+		require.NoError(t, err)
+	}()
+	<-inCall
+
+	go func() {
+		pool.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("pool stopped while a call is in progress")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+
+	err = pool.Call(ts, func(runtime.Instance) error { return nil })
+	require.ErrorIs(t, err, runtime.ErrInstancePoolStopped)
+}
+
+func TestInstancePool_Stop_Twice(t *testing.T) {
+	clone := new(mocks.Instance)
+	clone.On("Stop").Once()
+
+	instance := new(mocks.Instance)
+	instance.On("Clone").Return(clone, nil).Once()
+
+	pool, err := runtime.NewInstancePool(instance, 2)
+	require.NoError(t, err)
+
+	pool.Stop()
+	pool.Stop()
+	clone.AssertExpectations(t)
+
+	ts, err := storage.NewTrieState(nil)
+	require.NoError(t, err)
+
+	err = pool.Call(ts, func(runtime.Instance) error { return nil })
+	require.ErrorIs(t, err, runtime.ErrInstancePoolStopped)
+}
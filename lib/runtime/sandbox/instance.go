@@ -0,0 +1,363 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/go-interpreter/wagon/wasm"
+	"github.com/go-interpreter/wagon/wasm/leb128"
+	"github.com/perlin-network/life/exec"
+)
+
+// the indices of the kinds of entries of an environment definition
+const (
+	externFunction = 1
+	externMemory   = 2
+)
+
+// the opcodes of the instructions of constant expressions
+const (
+	opEnd      = 0x0b
+	opI32Const = 0x41
+)
+
+var (
+	errInvalidEnvironment = errors.New("invalid sandbox environment definition")
+	errUnresolvedImport   = errors.New("import is not in the sandbox environment definition")
+	errUnsupportedImport  = errors.New("unsupported sandbox import")
+	errInvalidDataSegment = errors.New("invalid data segment")
+	errExportNotFound     = errors.New("sandbox export not found")
+	errSignatureMismatch  = errors.New("arguments do not match sandbox export signature")
+)
+
+// environment maps the imports of a sandboxed module to the host functions
+// of the supervisor and to memories of the store
+type environment struct {
+	functions map[string]uint32
+	memories  map[string]*Memory
+}
+
+func importKey(module, field string) string {
+	return module + "/" + field
+}
+
+// decodeEnvironment decodes the SCALE encoded environment definition
+func (s *Store) decodeEnvironment(envDef []byte) (*environment, error) {
+	decoder := scale.NewDecoder(bytes.NewReader(envDef))
+
+	var length uint
+	if err := decoder.Decode(&length); err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidEnvironment, err)
+	}
+
+	env := &environment{
+		functions: make(map[string]uint32),
+		memories:  make(map[string]*Memory),
+	}
+
+	for i := uint(0); i < length; i++ {
+		var (
+			module, field []byte
+			kind          byte
+			idx           uint32
+		)
+
+		for _, dst := range []interface{}{&module, &field, &kind, &idx} {
+			if err := decoder.Decode(dst); err != nil {
+				return nil, fmt.Errorf("%w: %s", errInvalidEnvironment, err)
+			}
+		}
+
+		key := importKey(string(module), string(field))
+		switch kind {
+		case externFunction:
+			env.functions[key] = idx
+		case externMemory:
+			memory, err := s.Memory(idx)
+			if err != nil {
+				return nil, err
+			}
+			env.memories[key] = memory
+		default:
+			return nil, fmt.Errorf("%w: invalid entry kind %d", errInvalidEnvironment, kind)
+		}
+	}
+
+	return env, nil
+}
+
+// hostFunction is an imported function of a sandboxed module
+type hostFunction struct {
+	index uint32
+	sig   wasm.FunctionSig
+}
+
+// Instance is a sandboxed module instantiated by a runtime. It is executed by its own
+// interpreter, whose only access to the node are the host functions and memories which
+// the runtime provided in the environment definition.
+type Instance struct {
+	vm         *exec.VirtualMachine
+	memory     *Memory
+	functions  map[string]hostFunction
+	supervisor Supervisor
+	thunk      uint32
+	// state is passed to the host functions called during the current invocation
+	state uint32
+}
+
+func newInstance(supervisor Supervisor, thunk uint32, code []byte,
+	env *environment, state uint32) (*Instance, error) {
+	m, err := wasm.DecodeModule(bytes.NewReader(code))
+	if err != nil {
+		return nil, err
+	}
+
+	in := &Instance{
+		functions:  make(map[string]hostFunction),
+		supervisor: supervisor,
+		thunk:      thunk,
+		state:      state,
+	}
+
+	// only imports provided by the environment definition are allowed
+	var memoryImport *wasm.Memory
+	if m.Import != nil {
+		for _, imp := range m.Import.Entries {
+			key := importKey(imp.ModuleName, imp.FieldName)
+
+			switch t := imp.Type.(type) {
+			case wasm.FuncImport:
+				idx, ok := env.functions[key]
+				if !ok || m.Types == nil || int(t.Type) >= len(m.Types.Entries) {
+					return nil, fmt.Errorf("%w: function %s", errUnresolvedImport, key)
+				}
+				in.functions[key] = hostFunction{index: idx, sig: m.Types.Entries[t.Type]}
+			case wasm.MemoryImport:
+				memory, ok := env.memories[key]
+				if !ok {
+					return nil, fmt.Errorf("%w: memory %s", errUnresolvedImport, key)
+				}
+				in.memory, memoryImport = memory, &t.Type
+			default:
+				return nil, fmt.Errorf("%w: %s", errUnsupportedImport, key)
+			}
+		}
+	}
+
+	cfg := exec.VMConfig{
+		MaxMemoryPages: maxMemoryPages,
+	}
+
+	if in.memory != nil {
+		limits := memoryImport.Limits
+		if uint32(in.memory.pages()) < limits.Initial ||
+			(limits.Flags&1 == 1 && limits.Maximum < in.memory.maximum) {
+			return nil, fmt.Errorf("%w: imported memory does not match its limits", errUnresolvedImport)
+		}
+
+		cfg.DefaultMemoryPages = in.memory.pages()
+		cfg.MaxMemoryPages = int(in.memory.maximum)
+	}
+
+	in.vm, err = exec.NewVirtualMachine(code, cfg, in, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.memory != nil {
+		// the data segments are written to the imported memory
+		if err = in.initMemory(); err != nil {
+			return nil, err
+		}
+		in.vm.Memory = in.memory.data
+	}
+
+	if m.Start != nil {
+		if _, err = in.run(int(m.Start.Index)); err != nil {
+			return nil, fmt.Errorf("start function failed: %w", err)
+		}
+	}
+
+	return in, nil
+}
+
+// initMemory writes the data segments of the module to its imported memory
+func (in *Instance) initMemory() error {
+	data := in.vm.Module.Base.Data
+	if data == nil {
+		return nil
+	}
+
+	for _, segment := range data.Entries {
+		r := bytes.NewReader(segment.Offset)
+		op, err := r.ReadByte()
+		if err != nil || op != opI32Const {
+			return fmt.Errorf("%w: offset is not a constant", errInvalidDataSegment)
+		}
+
+		offset, err := leb128.ReadVarint32(r)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errInvalidDataSegment, err)
+		}
+
+		if err = in.memory.Set(uint32(offset), segment.Data); err != nil {
+			return fmt.Errorf("%w: %s", errInvalidDataSegment, err)
+		}
+	}
+
+	return nil
+}
+
+// signature returns the signature of the function with the given index
+func (in *Instance) signature(idx int) (*wasm.FunctionSig, error) {
+	m := in.vm.Module.Base
+	if m.Import != nil {
+		for _, imp := range m.Import.Entries {
+			t, ok := imp.Type.(wasm.FuncImport)
+			if !ok {
+				continue
+			}
+
+			if idx == 0 {
+				return &m.Types.Entries[t.Type], nil
+			}
+			idx--
+		}
+	}
+
+	if m.Function == nil || idx >= len(m.Function.Types) {
+		return nil, fmt.Errorf("invalid function index %d", idx)
+	}
+
+	return &m.Types.Entries[m.Function.Types[idx]], nil
+}
+
+// Invoke calls the exported function of the instance with the given arguments. The state is
+// passed to the host functions of the supervisor called by the function.
+func (in *Instance) Invoke(name string, args []Value, state uint32) (*Value, error) {
+	entryID, ok := in.vm.GetFunctionExport(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errExportNotFound, name)
+	}
+
+	sig, err := in.signature(entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sig.ParamTypes) != len(args) {
+		return nil, errSignatureMismatch
+	}
+
+	params := make([]int64, len(args))
+	for i, arg := range args {
+		if arg.Type != valueType(sig.ParamTypes[i]) {
+			return nil, errSignatureMismatch
+		}
+		params[i] = int64(arg.Bits)
+	}
+
+	in.state = state
+	ret, err := in.run(entryID, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sig.ReturnTypes) == 0 {
+		return nil, nil
+	}
+
+	v := newValue(sig.ReturnTypes[0], ret)
+	return &v, nil
+}
+
+// run runs the function of the module, recovering from the panics of the interpreter
+func (in *Instance) run(entryID int, params ...int64) (ret int64, err error) {
+	defer func() {
+		if in.memory != nil {
+			in.memory.data = in.vm.Memory
+		}
+
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sandboxed function panicked: %v", r)
+		}
+	}()
+
+	// a trap doesn't prevent the instance from being invoked again
+	in.vm.ExitError = nil
+	in.vm.CurrentFrame = -1
+
+	return in.vm.Run(entryID, params...)
+}
+
+// ResolveFunc returns the implementation of the imported function, which calls the host
+// function of the supervisor. It implements the exec.ImportResolver interface.
+func (in *Instance) ResolveFunc(module, field string) exec.FunctionImport {
+	fn, ok := in.functions[importKey(module, field)]
+	if !ok {
+		panic(fmt.Errorf("%w: function %s", errUnresolvedImport, importKey(module, field)))
+	}
+
+	return func(vm *exec.VirtualMachine) int64 {
+		ret, err := in.callHostFunction(vm, fn)
+		if err != nil {
+			// the panic is recovered by the interpreter, which traps
+			panic(err)
+		}
+		return ret
+	}
+}
+
+// ResolveGlobal panics since sandboxed modules cannot import globals.
+// It implements the exec.ImportResolver interface.
+func (in *Instance) ResolveGlobal(module, field string) int64 {
+	panic(fmt.Errorf("%w: global %s", errUnsupportedImport, importKey(module, field)))
+}
+
+func (in *Instance) callHostFunction(vm *exec.VirtualMachine, fn hostFunction) (int64, error) {
+	frame := vm.GetCurrentFrame()
+
+	args := make([]Value, len(fn.sig.ParamTypes))
+	for i, t := range fn.sig.ParamTypes {
+		args[i] = newValue(t, frame.Locals[i])
+	}
+
+	enc, err := EncodeValues(args)
+	if err != nil {
+		return 0, err
+	}
+
+	// the memory may have grown, the supervisor accesses it during the call
+	if in.memory != nil {
+		in.memory.data = vm.Memory
+	}
+
+	res, err := in.supervisor.Dispatch(in.thunk, enc, in.state, fn.index)
+	if err != nil {
+		return 0, err
+	}
+
+	ret, err := decodeHostResult(res)
+	if err != nil {
+		return 0, err
+	}
+
+	if ret == nil {
+		if len(fn.sig.ReturnTypes) != 0 {
+			return 0, errInvalidReturnValue
+		}
+		return 0, nil
+	}
+
+	if len(fn.sig.ReturnTypes) != 1 || ret.Type != valueType(fn.sig.ReturnTypes[0]) {
+		return 0, errInvalidReturnValue
+	}
+
+	return int64(ret.Bits), nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package sandbox runs the WebAssembly modules instantiated by a runtime with the
+// ext_sandbox_* host functions, such as the smart contracts of pallet-contracts.
+//
+// The modules are run by the life interpreter rather than by wasmer. The wasmer bindings
+// used by the runtime (go-ext-wasm 0.3) can only import host functions which are cgo exported
+// C functions, so their signatures are fixed at build time, and the host function cannot tell
+// which import of the module it is called for. The imports of a sandboxed module are only
+// known when ext_sandbox_instantiate is called, each of them being resolved against the
+// environment definition passed by the runtime and forwarded to its dispatch thunk with the
+// index of the host function. life resolves the imports of a module at instantiation with
+// an import resolver, which allows this. The memory of a sandboxed module is limited to
+// maxMemoryPages by the configuration of the life VM, whether it is created by the module or
+// imported from the runtime.
+//
+// The wasm decoder of wagon, which life is built on, is used to read the imports and exports of
+// the modules. The runtime itself keeps running on wasmer, and ExportDispatchThunks only adds
+// export entries to its export section, the other sections being left as is.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/perlin-network/life/exec"
+)
+
+// The results returned to the runtime by the sandbox host functions
+const (
+	ErrOK          int32 = 0
+	ErrExecution   int32 = -1
+	ErrModule      int32 = -2
+	ErrOutOfBounds int32 = -3
+)
+
+const (
+	// noMaximum is passed as the maximum of a memory without maximum
+	noMaximum = math.MaxUint32
+	// maxMemoryPages is the limit of the size of sandboxed memories, 64MiB
+	maxMemoryPages = 1024
+)
+
+var (
+	errInvalidMemoryIndex   = errors.New("invalid sandbox memory index")
+	errInvalidInstanceIndex = errors.New("invalid sandbox instance index")
+	errInvalidMemoryLimits  = errors.New("invalid sandbox memory limits")
+	errOutOfBounds          = errors.New("sandbox memory access out of bounds")
+)
+
+// Supervisor is the runtime instantiating sandboxed modules. The host functions imported by
+// the modules are implemented by the runtime, and called through its dispatch thunk.
+type Supervisor interface {
+	// Dispatch calls the dispatch thunk of the runtime with the SCALE encoded arguments of the
+	// host function, and returns the SCALE encoded result of the host function
+	Dispatch(thunk uint32, args []byte, state, function uint32) ([]byte, error)
+}
+
+// Memory is a linear memory created by the runtime, which is imported by sandboxed modules
+type Memory struct {
+	data    []byte
+	maximum uint32 // in pages
+}
+
+// Get returns a copy of the given range of the memory
+func (m *Memory) Get(offset, length uint32) ([]byte, error) {
+	end := uint64(offset) + uint64(length)
+	if end > uint64(len(m.data)) {
+		return nil, errOutOfBounds
+	}
+
+	out := make([]byte, length)
+	copy(out, m.data[offset:end])
+	return out, nil
+}
+
+// Set writes the data to the memory at the given offset
+func (m *Memory) Set(offset uint32, data []byte) error {
+	end := uint64(offset) + uint64(len(data))
+	if end > uint64(len(m.data)) {
+		return errOutOfBounds
+	}
+
+	copy(m.data[offset:end], data)
+	return nil
+}
+
+func (m *Memory) pages() int {
+	return len(m.data) / exec.DefaultPageSize
+}
+
+// Store holds the memories and instances created by a runtime instance
+type Store struct {
+	sync.Mutex
+	supervisor Supervisor
+	memories   []*Memory
+	instances  []*Instance
+}
+
+// NewStore returns a new Store for the given runtime
+func NewStore(supervisor Supervisor) *Store {
+	return &Store{
+		supervisor: supervisor,
+	}
+}
+
+// NewMemory creates a memory with the given initial and maximum number of pages,
+// and returns its index
+func (s *Store) NewMemory(initial, maximum uint32) (uint32, error) {
+	if maximum == noMaximum || maximum > maxMemoryPages {
+		maximum = maxMemoryPages
+	}
+
+	if initial > maximum {
+		return 0, fmt.Errorf("%w: initial %d, maximum %d", errInvalidMemoryLimits, initial, maximum)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.memories = append(s.memories, &Memory{
+		data:    make([]byte, int(initial)*exec.DefaultPageSize),
+		maximum: maximum,
+	})
+	return uint32(len(s.memories) - 1), nil
+}
+
+// Memory returns the memory with the given index
+func (s *Store) Memory(idx uint32) (*Memory, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if int(idx) >= len(s.memories) || s.memories[idx] == nil {
+		return nil, fmt.Errorf("%w: %d", errInvalidMemoryIndex, idx)
+	}
+
+	return s.memories[idx], nil
+}
+
+// TeardownMemory removes the memory with the given index
+func (s *Store) TeardownMemory(idx uint32) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if int(idx) >= len(s.memories) || s.memories[idx] == nil {
+		return fmt.Errorf("%w: %d", errInvalidMemoryIndex, idx)
+	}
+
+	s.memories[idx] = nil
+	return nil
+}
+
+// Instantiate instantiates the module, whose imports are resolved with the SCALE encoded
+// environment definition, and returns the index of the instance. The start function of the
+// module is called with the given state.
+func (s *Store) Instantiate(thunk uint32, code, envDef []byte, state uint32) (uint32, error) {
+	env, err := s.decodeEnvironment(envDef)
+	if err != nil {
+		return 0, err
+	}
+
+	instance, err := newInstance(s.supervisor, thunk, code, env, state)
+	if err != nil {
+		return 0, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.instances = append(s.instances, instance)
+	return uint32(len(s.instances) - 1), nil
+}
+
+// Instance returns the instance with the given index
+func (s *Store) Instance(idx uint32) (*Instance, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if int(idx) >= len(s.instances) || s.instances[idx] == nil {
+		return nil, fmt.Errorf("%w: %d", errInvalidInstanceIndex, idx)
+	}
+
+	return s.instances[idx], nil
+}
+
+// TeardownInstance removes the instance with the given index
+func (s *Store) TeardownInstance(idx uint32) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if int(idx) >= len(s.instances) || s.instances[idx] == nil {
+		return fmt.Errorf("%w: %d", errInvalidInstanceIndex, idx)
+	}
+
+	s.instances[idx] = nil
+	return nil
+}
+
+// Clear removes all the memories and instances of the store
+func (s *Store) Clear() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.memories = nil
+	s.instances = nil
+}
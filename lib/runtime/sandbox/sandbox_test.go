@@ -0,0 +1,302 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/go-interpreter/wagon/wasm/leb128"
+	"github.com/stretchr/testify/require"
+)
+
+type supervisorFunc func(thunk uint32, args []byte, state, function uint32) ([]byte, error)
+
+func (f supervisorFunc) Dispatch(thunk uint32, args []byte, state, function uint32) ([]byte, error) {
+	return f(thunk, args, state, function)
+}
+
+func wasmVec(entries ...[]byte) []byte {
+	out := leb128.AppendUleb128(nil, uint64(len(entries)))
+	for _, e := range entries {
+		out = append(out, e...)
+	}
+	return out
+}
+
+func wasmSection(id byte, entries ...[]byte) []byte {
+	payload := wasmVec(entries...)
+	out := leb128.AppendUleb128([]byte{id}, uint64(len(payload)))
+	return append(out, payload...)
+}
+
+func wasmName(name string) []byte {
+	return appendName(nil, name)
+}
+
+func wasmModule(sections ...[]byte) []byte {
+	out := append([]byte{}, wasmHeader...)
+	for _, s := range sections {
+		out = append(out, s...)
+	}
+	return out
+}
+
+func join(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// newTestModule returns a module importing the env.add function and the env.memory memory, which
+// exports call_add calling env.add with its arguments, and load reading the first word of memory
+func newTestModule() []byte {
+	const i32 = 0x7f
+	return wasmModule(
+		wasmSection(sectionType,
+			[]byte{0x60, 2, i32, i32, 1, i32},
+			[]byte{0x60, 0, 1, i32},
+		),
+		wasmSection(sectionImport,
+			join(wasmName("env"), wasmName("add"), []byte{externalFunction, 0}),
+			join(wasmName("env"), wasmName("memory"), []byte{externalMemory, 0, 1}),
+		),
+		wasmSection(sectionFunction, []byte{0}, []byte{1}),
+		wasmSection(sectionExport,
+			join(wasmName("call_add"), []byte{externalFunction, 1}),
+			join(wasmName("load"), []byte{externalFunction, 2}),
+		),
+		wasmSection(10,
+			[]byte{8, 0, 0x20, 0, 0x20, 1, 0x10, 0, 0x0b},
+			[]byte{7, 0, 0x41, 0, 0x28, 2, 0, 0x0b},
+		),
+		wasmSection(11, []byte{0, opI32Const, 0, opEnd, 4, 42, 0, 0, 0}),
+	)
+}
+
+func newTestEnvironment(t *testing.T, function, memory uint32) []byte {
+	t.Helper()
+
+	entry := func(module, field string, kind byte, idx uint32) []byte {
+		m, err := scale.Marshal([]byte(module))
+		require.NoError(t, err)
+		f, err := scale.Marshal([]byte(field))
+		require.NoError(t, err)
+
+		i := make([]byte, 4)
+		binary.LittleEndian.PutUint32(i, idx)
+		return join(m, f, []byte{kind}, i)
+	}
+
+	length, err := scale.Marshal(uint(2))
+	require.NoError(t, err)
+
+	return join(length,
+		entry("env", "add", externFunction, function),
+		entry("env", "memory", externMemory, memory),
+	)
+}
+
+// addSupervisor returns the sum of the two i32 arguments of the host function
+func addSupervisor(thunk uint32, args []byte, state, function uint32) ([]byte, error) {
+	values, err := DecodeValues(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) != 2 {
+		return nil, errors.New("invalid arguments")
+	}
+
+	sum := Value{Type: I32, Bits: uint64(uint32(values[0].Bits + values[1].Bits))}
+	return append([]byte{0}, EncodeReturnValue(&sum)...), nil
+}
+
+func TestStore_Memory(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore(nil)
+	idx, err := s.NewMemory(1, noMaximum)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), idx)
+
+	m, err := s.Memory(idx)
+	require.NoError(t, err)
+	require.Equal(t, uint32(maxMemoryPages), m.maximum)
+
+	err = m.Set(10, []byte{1, 2, 3})
+	require.NoError(t, err)
+
+	data, err := m.Get(9, 5)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 1, 2, 3, 0}, data)
+
+	_, err = m.Get(65535, 2)
+	require.ErrorIs(t, err, errOutOfBounds)
+	err = m.Set(65535, []byte{1, 2})
+	require.ErrorIs(t, err, errOutOfBounds)
+
+	_, err = s.NewMemory(2, 1)
+	require.ErrorIs(t, err, errInvalidMemoryLimits)
+
+	err = s.TeardownMemory(idx)
+	require.NoError(t, err)
+	_, err = s.Memory(idx)
+	require.ErrorIs(t, err, errInvalidMemoryIndex)
+	err = s.TeardownMemory(idx)
+	require.ErrorIs(t, err, errInvalidMemoryIndex)
+}
+
+func TestStore_Instantiate(t *testing.T) {
+	t.Parallel()
+
+	type dispatch struct {
+		thunk, state, function uint32
+	}
+
+	var calls []dispatch
+	s := NewStore(supervisorFunc(func(thunk uint32, args []byte, state, function uint32) ([]byte, error) {
+		calls = append(calls, dispatch{thunk: thunk, state: state, function: function})
+		return addSupervisor(thunk, args, state, function)
+	}))
+
+	memIdx, err := s.NewMemory(1, noMaximum)
+	require.NoError(t, err)
+
+	idx, err := s.Instantiate(4, newTestModule(), newTestEnvironment(t, 7, memIdx), 1)
+	require.NoError(t, err)
+
+	instance, err := s.Instance(idx)
+	require.NoError(t, err)
+
+	ret, err := instance.Invoke("call_add", []Value{{Type: I32, Bits: 2}, {Type: I32, Bits: 3}}, 9)
+	require.NoError(t, err)
+	require.Equal(t, &Value{Type: I32, Bits: 5}, ret)
+	require.Equal(t, []dispatch{{thunk: 4, state: 9, function: 7}}, calls)
+
+	// the data segment is written to the imported memory
+	ret, err = instance.Invoke("load", nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, &Value{Type: I32, Bits: 42}, ret)
+
+	m, err := s.Memory(memIdx)
+	require.NoError(t, err)
+	err = m.Set(0, []byte{7, 0, 0, 0})
+	require.NoError(t, err)
+
+	ret, err = instance.Invoke("load", nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, &Value{Type: I32, Bits: 7}, ret)
+
+	_, err = instance.Invoke("call_add", []Value{{Type: I32, Bits: 2}}, 0)
+	require.ErrorIs(t, err, errSignatureMismatch)
+	_, err = instance.Invoke("call_add", []Value{{Type: I64, Bits: 2}, {Type: I32, Bits: 3}}, 0)
+	require.ErrorIs(t, err, errSignatureMismatch)
+	_, err = instance.Invoke("missing", nil, 0)
+	require.ErrorIs(t, err, errExportNotFound)
+
+	err = s.TeardownInstance(idx)
+	require.NoError(t, err)
+	_, err = s.Instance(idx)
+	require.ErrorIs(t, err, errInvalidInstanceIndex)
+}
+
+func TestStore_Instantiate_HostError(t *testing.T) {
+	t.Parallel()
+
+	fail := true
+	s := NewStore(supervisorFunc(func(thunk uint32, args []byte, state, function uint32) ([]byte, error) {
+		if fail {
+			return []byte{1}, nil
+		}
+		return addSupervisor(thunk, args, state, function)
+	}))
+
+	memIdx, err := s.NewMemory(1, noMaximum)
+	require.NoError(t, err)
+
+	idx, err := s.Instantiate(0, newTestModule(), newTestEnvironment(t, 0, memIdx), 0)
+	require.NoError(t, err)
+
+	instance, err := s.Instance(idx)
+	require.NoError(t, err)
+
+	args := []Value{{Type: I32, Bits: 2}, {Type: I32, Bits: 3}}
+	_, err = instance.Invoke("call_add", args, 0)
+	require.Error(t, err)
+
+	// the instance can be invoked again after a trap
+	fail = false
+	ret, err := instance.Invoke("call_add", args, 0)
+	require.NoError(t, err)
+	require.Equal(t, &Value{Type: I32, Bits: 5}, ret)
+}
+
+func TestStore_Instantiate_InvalidEnvironment(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore(nil)
+	memIdx, err := s.NewMemory(1, noMaximum)
+	require.NoError(t, err)
+
+	length, err := scale.Marshal(uint(0))
+	require.NoError(t, err)
+
+	_, err = s.Instantiate(0, newTestModule(), length, 0)
+	require.ErrorIs(t, err, errUnresolvedImport)
+
+	_, err = s.Instantiate(0, newTestModule(), newTestEnvironment(t, 0, memIdx+1), 0)
+	require.ErrorIs(t, err, errInvalidMemoryIndex)
+
+	_, err = s.Instantiate(0, newTestModule(), []byte{4, 1}, 0)
+	require.ErrorIs(t, err, errInvalidEnvironment)
+
+	_, err = s.Instantiate(0, []byte{1, 2, 3}, newTestEnvironment(t, 0, memIdx), 0)
+	require.Error(t, err)
+}
+
+func TestValues(t *testing.T) {
+	t.Parallel()
+
+	values := []Value{
+		{Type: I32, Bits: 1},
+		{Type: I64, Bits: 1 << 40},
+		{Type: F32, Bits: 0x3f800000},
+		{Type: F64, Bits: 0x3ff0000000000000},
+	}
+
+	enc, err := EncodeValues(values)
+	require.NoError(t, err)
+	require.Equal(t, []byte{16, 0, 1, 0, 0, 0}, enc[:6])
+
+	res, err := DecodeValues(enc)
+	require.NoError(t, err)
+	require.Equal(t, values, res)
+
+	_, err = DecodeValues([]byte{4, 9, 0, 0, 0, 0})
+	require.ErrorIs(t, err, errInvalidValueType)
+	_, err = DecodeValues([]byte{4, 0, 0})
+	require.Error(t, err)
+
+	require.Equal(t, []byte{0}, EncodeReturnValue(nil))
+	require.Equal(t, []byte{1, 0, 1, 0, 0, 0}, EncodeReturnValue(&values[0]))
+
+	ret, err := decodeHostResult([]byte{0, 0})
+	require.NoError(t, err)
+	require.Nil(t, ret)
+
+	ret, err = decodeHostResult([]byte{0, 1, 0, 1, 0, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, &values[0], ret)
+
+	_, err = decodeHostResult([]byte{1})
+	require.ErrorIs(t, err, errHostFunction)
+	_, err = decodeHostResult([]byte{0, 1, 0, 1, 0, 0, 0, 0})
+	require.ErrorIs(t, err, errInvalidReturnValue)
+}
@@ -0,0 +1,417 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/go-interpreter/wagon/wasm"
+	"github.com/go-interpreter/wagon/wasm/leb128"
+)
+
+// dispatchThunkPrefix is the prefix of the names of the exports added to the runtime for
+// the functions of its table which can be used as dispatch thunks
+const dispatchThunkPrefix = "sandbox_dispatch_thunk_"
+
+// the ids of the sections of a module
+const (
+	sectionType     = 1
+	sectionImport   = 2
+	sectionFunction = 3
+	sectionExport   = 7
+	sectionElement  = 9
+)
+
+// the kinds of imports and exports of a module
+const (
+	externalFunction = 0
+	externalTable    = 1
+	externalMemory   = 2
+	externalGlobal   = 3
+)
+
+var (
+	errInvalidModule     = errors.New("invalid wasm module")
+	errUnsupportedModule = errors.New("unsupported wasm module")
+
+	wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+)
+
+// DispatchThunkExport returns the name of the export added by ExportDispatchThunks for
+// the function at the given index of the table of the runtime
+func DispatchThunkExport(tableIndex uint32) string {
+	return fmt.Sprintf("%s%d", dispatchThunkPrefix, tableIndex)
+}
+
+type section struct {
+	id      byte
+	payload []byte
+}
+
+type funcType struct {
+	params, results []byte
+}
+
+func (t funcType) isDispatchThunk() bool {
+	i32, i64 := byte(wasm.ValueTypeI32), byte(wasm.ValueTypeI64)
+	return bytes.Equal(t.params, []byte{i32, i32, i32, i32}) && bytes.Equal(t.results, []byte{i64})
+}
+
+// ExportDispatchThunks returns the runtime code with an export for each function of its table
+// whose signature is the one of a dispatch thunk. The runtime passes the dispatch thunk to
+// ext_sandbox_instantiate as an index of its table, which cannot be called from the host
+// otherwise.
+func ExportDispatchThunks(code []byte) ([]byte, error) {
+	sections, err := readSections(code)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		types         []funcType
+		funcTypes     []uint32
+		importedFuncs uint32
+		exports       []byte
+		exportNames   []string
+		exportIdx     = -1
+		elements      []byte
+	)
+
+	for i, s := range sections {
+		r := bytes.NewReader(s.payload)
+		switch s.id {
+		case sectionType:
+			types, err = readTypes(r)
+		case sectionImport:
+			importedFuncs, err = countImportedFunctions(r)
+		case sectionFunction:
+			funcTypes, err = readVarUint32s(r)
+		case sectionExport:
+			exportIdx = i
+			exports = s.payload
+			exportNames, err = readExportNames(r)
+		case sectionElement:
+			elements = s.payload
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: section %d: %s", errInvalidModule, s.id, err)
+		}
+	}
+
+	if elements == nil {
+		return code, nil
+	}
+
+	table, err := readElements(bytes.NewReader(elements))
+	if err != nil {
+		return nil, fmt.Errorf("%w: section %d: %s", errInvalidModule, sectionElement, err)
+	}
+
+	indices := make([]uint32, 0, len(table))
+	for tableIdx := range table {
+		indices = append(indices, tableIdx)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	exported := make(map[string]struct{}, len(exportNames))
+	for _, name := range exportNames {
+		exported[name] = struct{}{}
+	}
+
+	var added []byte
+	count := uint32(len(exportNames))
+	for _, tableIdx := range indices {
+		funcIdx := table[tableIdx]
+		if funcIdx < importedFuncs || int(funcIdx-importedFuncs) >= len(funcTypes) {
+			continue
+		}
+
+		typeIdx := funcTypes[funcIdx-importedFuncs]
+		if int(typeIdx) >= len(types) || !types[typeIdx].isDispatchThunk() {
+			continue
+		}
+
+		// the code may already have been rewritten
+		name := DispatchThunkExport(tableIdx)
+		if _, ok := exported[name]; ok {
+			continue
+		}
+
+		added = appendName(added, name)
+		added = append(added, externalFunction)
+		added = leb128.AppendUleb128(added, uint64(funcIdx))
+		count++
+	}
+
+	if count == uint32(len(exportNames)) {
+		return code, nil
+	}
+
+	payload := leb128.AppendUleb128(nil, uint64(count))
+	if exports != nil {
+		// the entries follow the count of the existing exports
+		r := bytes.NewReader(exports)
+		_, _ = leb128.ReadVarUint32(r)
+		payload = append(payload, exports[len(exports)-r.Len():]...)
+	}
+	payload = append(payload, added...)
+	exportSection := section{id: sectionExport, payload: payload}
+
+	if exportIdx >= 0 {
+		sections[exportIdx] = exportSection
+	} else {
+		// sections are ordered by id, except custom sections
+		pos := len(sections)
+		for i, s := range sections {
+			if s.id > sectionExport {
+				pos = i
+				break
+			}
+		}
+		sections = append(sections[:pos], append([]section{exportSection}, sections[pos:]...)...)
+	}
+
+	out := append([]byte{}, wasmHeader...)
+	for _, s := range sections {
+		out = append(out, s.id)
+		out = leb128.AppendUleb128(out, uint64(len(s.payload)))
+		out = append(out, s.payload...)
+	}
+
+	return out, nil
+}
+
+func readSections(code []byte) ([]section, error) {
+	if !bytes.HasPrefix(code, wasmHeader) {
+		return nil, fmt.Errorf("%w: invalid header", errInvalidModule)
+	}
+
+	r := bytes.NewReader(code[len(wasmHeader):])
+	var sections []section
+	for r.Len() > 0 {
+		id, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		size, err := leb128.ReadVarUint32(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidModule, err)
+		}
+
+		if int(size) > r.Len() {
+			return nil, fmt.Errorf("%w: section %d is too large", errInvalidModule, id)
+		}
+
+		payload := make([]byte, size)
+		_, _ = r.Read(payload)
+		sections = append(sections, section{id: id, payload: payload})
+	}
+
+	return sections, nil
+}
+
+func readTypes(r *bytes.Reader) ([]funcType, error) {
+	count, err := leb128.ReadVarUint32(r)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]funcType, count)
+	for i := range types {
+		form, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if form != 0x60 {
+			return nil, fmt.Errorf("invalid function type form %d", form)
+		}
+
+		if types[i].params, err = readBytes(r); err != nil {
+			return nil, err
+		}
+		if types[i].results, err = readBytes(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return types, nil
+}
+
+func countImportedFunctions(r *bytes.Reader) (uint32, error) {
+	count, err := leb128.ReadVarUint32(r)
+	if err != nil {
+		return 0, err
+	}
+
+	var funcs uint32
+	for i := uint32(0); i < count; i++ {
+		// module and field names
+		for j := 0; j < 2; j++ {
+			if _, err = readBytes(r); err != nil {
+				return 0, err
+			}
+		}
+
+		kind, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		switch kind {
+		case externalFunction:
+			funcs++
+			_, err = leb128.ReadVarUint32(r)
+		case externalTable:
+			if _, err = r.ReadByte(); err == nil {
+				err = skipLimits(r)
+			}
+		case externalMemory:
+			err = skipLimits(r)
+		case externalGlobal:
+			_, err = r.Seek(2, io.SeekCurrent)
+		default:
+			err = fmt.Errorf("invalid import kind %d", kind)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return funcs, nil
+}
+
+// readExportNames returns the names of the entries of the export section
+func readExportNames(r *bytes.Reader) ([]string, error) {
+	count, err := leb128.ReadVarUint32(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(count) > r.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	names := make([]string, count)
+	for i := range names {
+		name, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		names[i] = string(name)
+
+		// kind and index
+		if _, err = r.ReadByte(); err != nil {
+			return nil, err
+		}
+		if _, err = leb128.ReadVarUint32(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
+
+// readElements returns the function indices of the table initialised by the element segments
+func readElements(r *bytes.Reader) (map[uint32]uint32, error) {
+	count, err := leb128.ReadVarUint32(r)
+	if err != nil {
+		return nil, err
+	}
+
+	table := make(map[uint32]uint32)
+	for i := uint32(0); i < count; i++ {
+		flags, err := leb128.ReadVarUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		if flags != 0 {
+			return nil, fmt.Errorf("%w: element segment flags %d", errUnsupportedModule, flags)
+		}
+
+		op, err := r.ReadByte()
+		if err != nil || op != opI32Const {
+			return nil, fmt.Errorf("%w: element segment offset is not a constant", errUnsupportedModule)
+		}
+
+		offset, err := leb128.ReadVarint32(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if end, err := r.ReadByte(); err != nil || end != opEnd {
+			return nil, fmt.Errorf("%w: element segment offset is not a constant", errUnsupportedModule)
+		}
+
+		funcs, err := readVarUint32s(r)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, f := range funcs {
+			table[uint32(offset)+uint32(j)] = f
+		}
+	}
+
+	return table, nil
+}
+
+func skipLimits(r *bytes.Reader) error {
+	flags, err := leb128.ReadVarUint32(r)
+	if err != nil {
+		return err
+	}
+
+	if _, err = leb128.ReadVarUint32(r); err != nil {
+		return err
+	}
+
+	if flags&1 == 1 {
+		_, err = leb128.ReadVarUint32(r)
+	}
+	return err
+}
+
+func readVarUint32s(r *bytes.Reader) ([]uint32, error) {
+	count, err := leb128.ReadVarUint32(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(count) > r.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	values := make([]uint32, count)
+	for i := range values {
+		if values[i], err = leb128.ReadVarUint32(r); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	length, err := leb128.ReadVarUint32(r)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, length)
+	if _, err = io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func appendName(b []byte, name string) []byte {
+	b = leb128.AppendUleb128(b, uint64(len(name)))
+	return append(b, name...)
+}
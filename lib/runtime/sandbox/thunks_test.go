@@ -0,0 +1,93 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-interpreter/wagon/wasm"
+	"github.com/stretchr/testify/require"
+)
+
+// newThunksTestModule returns a module whose table holds a function with the signature of a
+// dispatch thunk at index 1, and a function with another signature at index 2
+func newThunksTestModule(withExports bool) []byte {
+	const i32, i64 = 0x7f, 0x7e
+
+	sections := [][]byte{
+		wasmSection(sectionType,
+			[]byte{0x60, 4, i32, i32, i32, i32, 1, i64},
+			[]byte{0x60, 0, 0},
+		),
+		wasmSection(sectionImport, join(wasmName("env"), wasmName("f"), []byte{externalFunction, 1})),
+		wasmSection(sectionFunction, []byte{0}, []byte{1}),
+		wasmSection(4, []byte{0x70, 0, 3}),
+	}
+
+	if withExports {
+		sections = append(sections, wasmSection(sectionExport, join(wasmName("main"), []byte{externalFunction, 2})))
+	}
+
+	sections = append(sections,
+		wasmSection(sectionElement, []byte{0, opI32Const, 1, opEnd, 2, 1, 2}),
+		wasmSection(10,
+			[]byte{4, 0, 0x42, 0, 0x0b},
+			[]byte{2, 0, 0x0b},
+		),
+	)
+
+	return wasmModule(sections...)
+}
+
+func TestExportDispatchThunks(t *testing.T) {
+	t.Parallel()
+
+	for _, withExports := range []bool{true, false} {
+		code, err := ExportDispatchThunks(newThunksTestModule(withExports))
+		require.NoError(t, err)
+
+		m, err := wasm.DecodeModule(bytes.NewReader(code))
+		require.NoError(t, err)
+
+		export, ok := m.Export.Entries[DispatchThunkExport(1)]
+		require.True(t, ok)
+		require.Equal(t, wasm.ExternalFunction, export.Kind)
+		require.Equal(t, uint32(1), export.Index)
+
+		_, ok = m.Export.Entries[DispatchThunkExport(2)]
+		require.False(t, ok)
+
+		_, ok = m.Export.Entries["main"]
+		require.Equal(t, withExports, ok)
+	}
+}
+
+func TestExportDispatchThunks_Rewritten(t *testing.T) {
+	t.Parallel()
+
+	code, err := ExportDispatchThunks(newThunksTestModule(true))
+	require.NoError(t, err)
+
+	// the exports of the dispatch thunks are not added again
+	res, err := ExportDispatchThunks(code)
+	require.NoError(t, err)
+	require.Equal(t, code, res)
+
+	m, err := wasm.DecodeModule(bytes.NewReader(res))
+	require.NoError(t, err)
+	require.Len(t, m.Export.Entries, 2)
+}
+
+func TestExportDispatchThunks_NoTable(t *testing.T) {
+	t.Parallel()
+
+	code := newTestModule()
+	res, err := ExportDispatchThunks(code)
+	require.NoError(t, err)
+	require.Equal(t, code, res)
+
+	_, err = ExportDispatchThunks([]byte{1, 2, 3})
+	require.ErrorIs(t, err, errInvalidModule)
+}
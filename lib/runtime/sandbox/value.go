@@ -0,0 +1,173 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sandbox
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	"github.com/go-interpreter/wagon/wasm"
+)
+
+// ValueType is the type of a Value, its index in the SCALE encoding of a Value
+type ValueType byte
+
+// The types of the values passed to and returned by sandboxed functions
+const (
+	I32 ValueType = iota
+	I64
+	F32
+	F64
+)
+
+var (
+	errInvalidValueType   = errors.New("invalid value type")
+	errInvalidReturnValue = errors.New("invalid return value")
+	errHostFunction       = errors.New("host function returned an error")
+)
+
+// Value is a value passed to or returned by a sandboxed function
+type Value struct {
+	Type ValueType
+	// Bits holds the value, floats are stored as their IEEE 754 bits
+	Bits uint64
+}
+
+func (v Value) size() int {
+	if v.Type == I32 || v.Type == F32 {
+		return 4
+	}
+	return 8
+}
+
+// Encode returns the SCALE encoding of the Value
+func (v Value) Encode() []byte {
+	enc := make([]byte, 1+v.size())
+	enc[0] = byte(v.Type)
+	if v.size() == 4 {
+		binary.LittleEndian.PutUint32(enc[1:], uint32(v.Bits))
+	} else {
+		binary.LittleEndian.PutUint64(enc[1:], v.Bits)
+	}
+	return enc
+}
+
+func decodeValue(r *bytes.Reader) (Value, error) {
+	t, err := r.ReadByte()
+	if err != nil {
+		return Value{}, err
+	}
+
+	v := Value{Type: ValueType(t)}
+	if v.Type > F64 {
+		return Value{}, fmt.Errorf("%w: %d", errInvalidValueType, t)
+	}
+
+	buf := make([]byte, v.size())
+	if _, err = io.ReadFull(r, buf); err != nil {
+		return Value{}, err
+	}
+
+	if len(buf) == 4 {
+		v.Bits = uint64(binary.LittleEndian.Uint32(buf))
+	} else {
+		v.Bits = binary.LittleEndian.Uint64(buf)
+	}
+	return v, nil
+}
+
+// DecodeValues decodes the SCALE encoded list of values
+func DecodeValues(in []byte) ([]Value, error) {
+	r := bytes.NewReader(in)
+
+	var length uint
+	if err := scale.NewDecoder(r).Decode(&length); err != nil {
+		return nil, err
+	}
+
+	values := make([]Value, length)
+	for i := range values {
+		v, err := decodeValue(r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode value %d: %w", i, err)
+		}
+		values[i] = v
+	}
+
+	return values, nil
+}
+
+// EncodeValues returns the SCALE encoding of the list of values
+func EncodeValues(values []Value) ([]byte, error) {
+	enc, err := scale.Marshal(uint(len(values)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range values {
+		enc = append(enc, v.Encode()...)
+	}
+	return enc, nil
+}
+
+// EncodeReturnValue returns the SCALE encoding of the value returned by a sandboxed
+// function, which is either nothing or a single value
+func EncodeReturnValue(v *Value) []byte {
+	if v == nil {
+		return []byte{0}
+	}
+	return append([]byte{1}, v.Encode()...)
+}
+
+// decodeHostResult decodes the result of a host function returned by the dispatch thunk
+// of the supervisor, which is either a return value or an error
+func decodeHostResult(in []byte) (*Value, error) {
+	switch {
+	case len(in) == 1 && in[0] == 1:
+		return nil, errHostFunction
+	case len(in) == 2 && in[0] == 0 && in[1] == 0:
+		return nil, nil
+	case len(in) > 2 && in[0] == 0 && in[1] == 1:
+		r := bytes.NewReader(in[2:])
+		v, err := decodeValue(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Len() != 0 {
+			return nil, errInvalidReturnValue
+		}
+		return &v, nil
+	default:
+		return nil, errInvalidReturnValue
+	}
+}
+
+// valueType returns the ValueType of the WebAssembly value type
+func valueType(t wasm.ValueType) ValueType {
+	switch t {
+	case wasm.ValueTypeI64:
+		return I64
+	case wasm.ValueTypeF32:
+		return F32
+	case wasm.ValueTypeF64:
+		return F64
+	default:
+		return I32
+	}
+}
+
+// newValue returns the Value of the given type held by the register of the interpreter
+func newValue(t wasm.ValueType, reg int64) Value {
+	v := Value{Type: valueType(t), Bits: uint64(reg)}
+	if v.size() == 4 {
+		v.Bits = uint64(uint32(reg))
+	}
+	return v
+}
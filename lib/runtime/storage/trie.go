@@ -8,6 +8,7 @@
 	"sort"
 	"sync"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/trie"
 )
@@ -18,6 +19,10 @@ type TrieState struct {
 	t       *trie.Trie
 	oldTrie *trie.Trie // this is the trie before BeginStorageTransaction is called. set to nil if it isn't called
 	lock    sync.RWMutex
+
+	// indexOperations are the transaction index operations requested by the runtime.
+	// They are not reverted by RollbackStorageTransaction.
+	indexOperations []types.IndexOperation
 }
 
 // NewTrieState returns a new TrieState with the given trie
@@ -93,6 +98,28 @@ func (s *TrieState) Root() (common.Hash, error) {
 	return s.t.Hash()
 }
 
+// RootWithVersion returns the trie's root hash, where the values of the
+// nodes modified since the last commit are encoded with the state version given.
+func (s *TrieState) RootWithVersion(version trie.Version) (common.Hash, error) {
+	return s.t.HashWithVersion(version)
+}
+
+// AddIndexOperation adds a transaction index operation requested by the runtime
+func (s *TrieState) AddIndexOperation(op types.IndexOperation) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.indexOperations = append(s.indexOperations, op)
+}
+
+// IndexOperations returns the transaction index operations requested by the runtime
+func (s *TrieState) IndexOperations() []types.IndexOperation {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	ops := make([]types.IndexOperation, len(s.indexOperations))
+	copy(ops, s.indexOperations)
+	return ops
+}
+
 // Has returns whether or not a key exists
 func (s *TrieState) Has(key []byte) bool {
 	return s.Get(key) != nil
@@ -289,3 +316,21 @@ func (s *TrieState) GetDeletedNodeHashes() (hashesSet map[common.Hash]struct{})
 	defer s.lock.RUnlock()
 	return s.t.GetDeletedNodeHashes()
 }
+
+// GetInsertedValueHashes returns the hashes of the values stored apart from
+// their nodes that were inserted into the state trie since the last block produced,
+// with the number of nodes holding each value.
+func (s *TrieState) GetInsertedValueHashes() (hashes map[common.Hash]uint32, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.t.GetInsertedValueHashes()
+}
+
+// GetDeletedValueHashes returns the hashes of the values stored apart from
+// their nodes that were deleted from the state trie since the last block produced,
+// with the number of times each value was deleted.
+func (s *TrieState) GetDeletedValueHashes() (hashes map[common.Hash]uint32) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.t.GetDeletedValueHashes()
+}
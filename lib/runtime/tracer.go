@@ -0,0 +1,240 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"sync"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+)
+
+// TraceEventType is the type of an event recorded while tracing a runtime execution
+type TraceEventType string
+
+const (
+	// TraceHostCall is recorded when the runtime calls a host function
+	TraceHostCall TraceEventType = "host_call"
+	// TraceStorageGet is recorded when a storage value is read
+	TraceStorageGet TraceEventType = "storage_get"
+	// TraceStorageSet is recorded when a storage value is written
+	TraceStorageSet TraceEventType = "storage_set"
+	// TraceStorageDelete is recorded when a storage value is deleted
+	TraceStorageDelete TraceEventType = "storage_delete"
+	// TraceStorageClearPrefix is recorded when the storage values under a prefix are deleted
+	TraceStorageClearPrefix TraceEventType = "storage_clear_prefix"
+	// TraceStorageNextKey is recorded when the next storage key is read
+	TraceStorageNextKey TraceEventType = "storage_next_key"
+	// TraceStorageRoot is recorded when the storage root is calculated
+	TraceStorageRoot TraceEventType = "storage_root"
+	// TraceStorageTransaction is recorded when a storage transaction starts, is committed or rolled back
+	TraceStorageTransaction TraceEventType = "storage_transaction"
+	// TraceSignatureVerify is recorded when the runtime asks to verify a signature
+	TraceSignatureVerify TraceEventType = "signature_verify"
+	// TraceAllocate is recorded when memory is allocated
+	TraceAllocate TraceEventType = "allocate"
+	// TraceDeallocate is recorded when memory is deallocated
+	TraceDeallocate TraceEventType = "deallocate"
+)
+
+// TraceEvent is an event recorded while tracing a runtime execution. Only the fields
+// relevant to the event type are set, byte values are hex encoded.
+type TraceEvent struct {
+	Type TraceEventType `json:"type"`
+	// Name is the host function name for host calls, the signature scheme for
+	// signature verifications and the operation for storage transactions
+	Name  string `json:"name,omitempty"`
+	Child string `json:"child,omitempty"`
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+	// Message is the signed message of a signature verification
+	Message string `json:"message,omitempty"`
+	Ptr     uint32 `json:"ptr,omitempty"`
+	Size    uint32 `json:"size,omitempty"`
+}
+
+// Tracer records the events of a runtime execution in the order they happen
+type Tracer struct {
+	sync.Mutex
+	events []TraceEvent
+}
+
+// NewTracer returns a new Tracer
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Record records the event. It does nothing if the tracer is nil, so it
+// can be called whether tracing is enabled or not.
+func (t *Tracer) Record(event TraceEvent) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	t.events = append(t.events, event)
+}
+
+// Events returns the recorded events
+func (t *Tracer) Events() []TraceEvent {
+	t.Lock()
+	defer t.Unlock()
+
+	events := make([]TraceEvent, len(t.events))
+	copy(events, t.events)
+	return events
+}
+
+// hexOrEmpty hex encodes the given bytes, it returns an empty string for nil bytes
+// so the field is omitted for values which don't exist
+func hexOrEmpty(b []byte) string {
+	if b == nil {
+		return ""
+	}
+
+	return common.BytesToHex(b)
+}
+
+// TracedStorage wraps a Storage and records its reads and writes to a Tracer
+type TracedStorage struct {
+	Storage
+	tracer *Tracer
+}
+
+// NewTracedStorage returns a Storage recording the reads and writes of the given storage
+func NewTracedStorage(s Storage, tracer *Tracer) *TracedStorage {
+	return &TracedStorage{
+		Storage: s,
+		tracer:  tracer,
+	}
+}
+
+// Set sets the value of the key and records it
+func (s *TracedStorage) Set(key, value []byte) {
+	s.tracer.Record(TraceEvent{Type: TraceStorageSet, Key: hexOrEmpty(key), Value: hexOrEmpty(value)})
+	s.Storage.Set(key, value)
+}
+
+// Get returns the value of the key and records it
+func (s *TracedStorage) Get(key []byte) []byte {
+	value := s.Storage.Get(key)
+	s.tracer.Record(TraceEvent{Type: TraceStorageGet, Key: hexOrEmpty(key), Value: hexOrEmpty(value)})
+	return value
+}
+
+// Root returns the storage root and records it
+func (s *TracedStorage) Root() (common.Hash, error) {
+	root, err := s.Storage.Root()
+	s.tracer.Record(TraceEvent{Type: TraceStorageRoot, Value: root.String()})
+	return root, err
+}
+
+// SetChild sets the child trie at the given key and records its root
+func (s *TracedStorage) SetChild(keyToChild []byte, child *trie.Trie) error {
+	s.tracer.Record(TraceEvent{Type: TraceStorageSet, Child: hexOrEmpty(keyToChild), Value: child.MustHash().String()})
+	return s.Storage.SetChild(keyToChild, child)
+}
+
+// SetChildStorage sets the value of the key in the child trie and records it
+func (s *TracedStorage) SetChildStorage(keyToChild, key, value []byte) error {
+	s.tracer.Record(TraceEvent{
+		Type:  TraceStorageSet,
+		Child: hexOrEmpty(keyToChild),
+		Key:   hexOrEmpty(key),
+		Value: hexOrEmpty(value),
+	})
+	return s.Storage.SetChildStorage(keyToChild, key, value)
+}
+
+// GetChildStorage returns the value of the key in the child trie and records it
+func (s *TracedStorage) GetChildStorage(keyToChild, key []byte) ([]byte, error) {
+	value, err := s.Storage.GetChildStorage(keyToChild, key)
+	s.tracer.Record(TraceEvent{
+		Type:  TraceStorageGet,
+		Child: hexOrEmpty(keyToChild),
+		Key:   hexOrEmpty(key),
+		Value: hexOrEmpty(value),
+	})
+	return value, err
+}
+
+// Delete deletes the key and records it
+func (s *TracedStorage) Delete(key []byte) {
+	s.tracer.Record(TraceEvent{Type: TraceStorageDelete, Key: hexOrEmpty(key)})
+	s.Storage.Delete(key)
+}
+
+// DeleteChild deletes the child trie and records it
+func (s *TracedStorage) DeleteChild(keyToChild []byte) {
+	s.tracer.Record(TraceEvent{Type: TraceStorageDelete, Child: hexOrEmpty(keyToChild)})
+	s.Storage.DeleteChild(keyToChild)
+}
+
+// DeleteChildLimit deletes the keys of the child trie up to the limit and records it
+func (s *TracedStorage) DeleteChildLimit(keyToChild []byte, limit *[]byte) (uint32, bool, error) {
+	s.tracer.Record(TraceEvent{Type: TraceStorageDelete, Child: hexOrEmpty(keyToChild)})
+	return s.Storage.DeleteChildLimit(keyToChild, limit)
+}
+
+// ClearChildStorage deletes the key in the child trie and records it
+func (s *TracedStorage) ClearChildStorage(keyToChild, key []byte) error {
+	s.tracer.Record(TraceEvent{Type: TraceStorageDelete, Child: hexOrEmpty(keyToChild), Key: hexOrEmpty(key)})
+	return s.Storage.ClearChildStorage(keyToChild, key)
+}
+
+// NextKey returns the next key after the given key and records it
+func (s *TracedStorage) NextKey(key []byte) []byte {
+	next := s.Storage.NextKey(key)
+	s.tracer.Record(TraceEvent{Type: TraceStorageNextKey, Key: hexOrEmpty(key), Value: hexOrEmpty(next)})
+	return next
+}
+
+// ClearPrefixInChild deletes the keys with the prefix in the child trie and records it
+func (s *TracedStorage) ClearPrefixInChild(keyToChild, prefix []byte) error {
+	s.tracer.Record(TraceEvent{Type: TraceStorageClearPrefix, Child: hexOrEmpty(keyToChild), Key: hexOrEmpty(prefix)})
+	return s.Storage.ClearPrefixInChild(keyToChild, prefix)
+}
+
+// GetChildNextKey returns the next key after the given key in the child trie and records it
+func (s *TracedStorage) GetChildNextKey(keyToChild, key []byte) ([]byte, error) {
+	next, err := s.Storage.GetChildNextKey(keyToChild, key)
+	s.tracer.Record(TraceEvent{
+		Type:  TraceStorageNextKey,
+		Child: hexOrEmpty(keyToChild),
+		Key:   hexOrEmpty(key),
+		Value: hexOrEmpty(next),
+	})
+	return next, err
+}
+
+// ClearPrefix deletes the keys with the prefix and records it
+func (s *TracedStorage) ClearPrefix(prefix []byte) error {
+	s.tracer.Record(TraceEvent{Type: TraceStorageClearPrefix, Key: hexOrEmpty(prefix)})
+	return s.Storage.ClearPrefix(prefix)
+}
+
+// ClearPrefixLimit deletes the keys with the prefix up to the limit and records it
+func (s *TracedStorage) ClearPrefixLimit(prefix []byte, limit uint32) (uint32, bool) {
+	s.tracer.Record(TraceEvent{Type: TraceStorageClearPrefix, Key: hexOrEmpty(prefix)})
+	return s.Storage.ClearPrefixLimit(prefix, limit)
+}
+
+// BeginStorageTransaction starts a storage transaction and records it
+func (s *TracedStorage) BeginStorageTransaction() {
+	s.tracer.Record(TraceEvent{Type: TraceStorageTransaction, Name: "begin"})
+	s.Storage.BeginStorageTransaction()
+}
+
+// CommitStorageTransaction commits the storage transaction and records it
+func (s *TracedStorage) CommitStorageTransaction() {
+	s.tracer.Record(TraceEvent{Type: TraceStorageTransaction, Name: "commit"})
+	s.Storage.CommitStorageTransaction()
+}
+
+// RollbackStorageTransaction rolls back the storage transaction and records it
+func (s *TracedStorage) RollbackStorageTransaction() {
+	s.tracer.Record(TraceEvent{Type: TraceStorageTransaction, Name: "rollback"})
+	s.Storage.RollbackStorageTransaction()
+}
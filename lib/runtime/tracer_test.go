@@ -0,0 +1,70 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mapStorage is a minimal Storage implementation for the tests
+type mapStorage struct {
+	Storage
+	values map[string][]byte
+}
+
+func (s *mapStorage) Set(key, value []byte) {
+	s.values[string(key)] = value
+}
+
+func (s *mapStorage) Get(key []byte) []byte {
+	return s.values[string(key)]
+}
+
+func (s *mapStorage) Delete(key []byte) {
+	delete(s.values, string(key))
+}
+
+func TestTracer_NilRecord(t *testing.T) {
+	var tracer *Tracer
+	require.NotPanics(t, func() {
+		tracer.Record(TraceEvent{Type: TraceHostCall, Name: "ext_storage_get_version_1"})
+	})
+}
+
+func TestTracedStorage(t *testing.T) {
+	tracer := NewTracer()
+	storage := NewTracedStorage(&mapStorage{values: make(map[string][]byte)}, tracer)
+
+	storage.Set([]byte{1}, []byte{2})
+	require.Equal(t, []byte{2}, storage.Get([]byte{1}))
+	storage.Delete([]byte{1})
+	require.Nil(t, storage.Get([]byte{1}))
+
+	expected := []TraceEvent{
+		{Type: TraceStorageSet, Key: "0x01", Value: "0x02"},
+		{Type: TraceStorageGet, Key: "0x01", Value: "0x02"},
+		{Type: TraceStorageDelete, Key: "0x01"},
+		{Type: TraceStorageGet, Key: "0x01"},
+	}
+	require.Equal(t, expected, tracer.Events())
+}
+
+func TestTracer_Allocator(t *testing.T) {
+	tracer := NewTracer()
+	allocator := NewAllocator(newMemoryMock(1<<16), 0)
+	allocator.SetTracer(tracer)
+
+	ptr, err := allocator.Allocate(1)
+	require.NoError(t, err)
+	err = allocator.Deallocate(ptr)
+	require.NoError(t, err)
+
+	expected := []TraceEvent{
+		{Type: TraceAllocate, Ptr: ptr, Size: 1},
+		{Type: TraceDeallocate, Ptr: ptr},
+	}
+	require.Equal(t, expected, tracer.Events())
+}
@@ -9,6 +9,7 @@
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
 )
 
 // NodeStorageType type to identify offchain storage type
@@ -57,6 +58,8 @@ type InstanceConfig struct {
 	Network     BasicNetwork
 	Transaction TransactionState
 	CodeHash    common.Hash
+	// Tracer records the host function calls of the instance, tracing is disabled if nil
+	Tracer *Tracer
 }
 
 // Context is the context for the wasm interpreter's imported functions
@@ -70,6 +73,9 @@ type Context struct {
 	Transaction     TransactionState
 	SigVerifier     *crypto.SignatureVerifier
 	OffchainHTTPSet *offchain.HTTPSet
+	Tracer          *Tracer
+	// Sandbox holds the modules instantiated by the runtime with ext_sandbox_instantiate
+	Sandbox *sandbox.Store
 }
 
 // NewValidateTransactionError returns an error based on a return value from TaggedTransactionQueueValidateTransaction
@@ -27,6 +27,18 @@ type APIItem struct {
 	Ver  uint32
 }
 
+// APIVersion returns the version of the runtime API with the given id, and
+// false if the runtime doesn't implement it.
+func APIVersion(v Version, id [8]byte) (uint32, bool) {
+	for _, item := range v.APIItems() {
+		if item.Name == id {
+			return item.Ver, true
+		}
+	}
+
+	return 0, false
+}
+
 // LegacyVersionData is the runtime version info returned by legacy runtimes
 type LegacyVersionData struct {
 	specName         []byte
@@ -8,6 +8,8 @@
 	"strings"
 
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -80,6 +82,62 @@ func (in *Instance) BabeConfiguration() (*types.BabeConfiguration, error) {
 	return bc, nil
 }
 
+// BabeGenerateKeyOwnershipProof returns the proof that the key of the given BABE authority
+// belongs to a validator of the session of the given slot. It returns nil if the runtime
+// cannot generate the proof, ie. if the key doesn't belong to a validator of the session.
+func (in *Instance) BabeGenerateKeyOwnershipProof(slot uint64, authorityID [sr25519.PublicKeyLength]byte) (
+	types.OpaqueKeyOwnershipProof, error) {
+	encSlot, err := scale.Marshal(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := in.exec(runtime.BabeAPIGenerateKeyOwnershipProof, append(encSlot, authorityID[:]...))
+	if err != nil {
+		return nil, err
+	}
+
+	var proof *[]byte
+	err = scale.Unmarshal(res, &proof)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode key ownership proof: %w", err)
+	}
+
+	if proof == nil {
+		return nil, nil
+	}
+
+	return *proof, nil
+}
+
+// BabeSubmitReportEquivocationUnsignedExtrinsic submits an unsigned extrinsic reporting
+// the given BABE equivocation to the transaction pool.
+func (in *Instance) BabeSubmitReportEquivocationUnsignedExtrinsic(proof types.BabeEquivocationProof,
+	keyOwnershipProof types.OpaqueKeyOwnershipProof) error {
+	encProof, err := scale.Marshal(proof)
+	if err != nil {
+		return fmt.Errorf("cannot encode equivocation proof: %w", err)
+	}
+
+	encKeyOwnershipProof, err := scale.Marshal([]byte(keyOwnershipProof))
+	if err != nil {
+		return fmt.Errorf("cannot encode key ownership proof: %w", err)
+	}
+
+	res, err := in.exec(runtime.BabeAPISubmitReportEquivocationUnsignedExtrinsic,
+		append(encProof, encKeyOwnershipProof...))
+	if err != nil {
+		return err
+	}
+
+	// the runtime returns an Option<()>, which is None if the report wasn't submitted
+	if len(res) == 0 || res[0] != 1 {
+		return runtime.ErrEquivocationReportNotSubmitted
+	}
+
+	return nil
+}
+
 // GrandpaAuthorities returns the genesis authorities from the runtime
 func (in *Instance) GrandpaAuthorities() ([]types.Authority, error) {
 	ret, err := in.exec(runtime.GrandpaAuthorities, []byte{})
@@ -96,6 +154,62 @@ func (in *Instance) GrandpaAuthorities() ([]types.Authority, error) {
 	return types.GrandpaAuthoritiesRawToAuthorities(gar)
 }
 
+// GrandpaGenerateKeyOwnershipProof returns the proof that the key of the given GRANDPA authority
+// belongs to a validator of the session of the given authority set. It returns nil if the runtime
+// cannot generate the proof, ie. if the key doesn't belong to a validator of the session.
+func (in *Instance) GrandpaGenerateKeyOwnershipProof(setID uint64, authorityID ed25519.PublicKeyBytes) (
+	types.OpaqueKeyOwnershipProof, error) {
+	encSetID, err := scale.Marshal(setID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := in.exec(runtime.GrandpaAPIGenerateKeyOwnershipProof, append(encSetID, authorityID[:]...))
+	if err != nil {
+		return nil, err
+	}
+
+	var proof *[]byte
+	err = scale.Unmarshal(res, &proof)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode key ownership proof: %w", err)
+	}
+
+	if proof == nil {
+		return nil, nil
+	}
+
+	return *proof, nil
+}
+
+// GrandpaSubmitReportEquivocationUnsignedExtrinsic submits an unsigned extrinsic reporting
+// the given GRANDPA equivocation to the transaction pool.
+func (in *Instance) GrandpaSubmitReportEquivocationUnsignedExtrinsic(proof types.GrandpaEquivocationProof,
+	keyOwnershipProof types.OpaqueKeyOwnershipProof) error {
+	encProof, err := scale.Marshal(proof)
+	if err != nil {
+		return fmt.Errorf("cannot encode equivocation proof: %w", err)
+	}
+
+	encKeyOwnershipProof, err := scale.Marshal([]byte(keyOwnershipProof))
+	if err != nil {
+		return fmt.Errorf("cannot encode key ownership proof: %w", err)
+	}
+
+	res, err := in.exec(runtime.GrandpaAPISubmitReportEquivocationUnsignedExtrinsic,
+		append(encProof, encKeyOwnershipProof...))
+	if err != nil {
+		return err
+	}
+
+	// the runtime returns an Option<()>, which is None if the report wasn't submitted
+	if len(res) == 0 || res[0] != 1 {
+		return runtime.ErrEquivocationReportNotSubmitted
+	}
+
+	return nil
+}
+
 // InitializeBlock calls runtime API function Core_initialise_block
 func (in *Instance) InitializeBlock(header *types.Header) error {
 	encodedHeader, err := scale.Marshal(*header)
@@ -148,19 +262,9 @@ func (in *Instance) ExecuteBlock(block *types.Block) ([]byte, error) {
 		}
 	}
 
-	b.Header.Digest = types.NewDigest()
-
-	// remove seal digest only
-	for _, d := range block.Header.Digest.Types {
-		switch d.Value().(type) {
-		case types.SealDigest:
-			continue
-		default:
-			err = b.Header.Digest.Add(d.Value())
-			if err != nil {
-				return nil, err
-			}
-		}
+	err = removeSeal(&b)
+	if err != nil {
+		return nil, err
 	}
 
 	bdEnc, err := b.Encode()
@@ -171,6 +275,63 @@ func (in *Instance) ExecuteBlock(block *types.Block) ([]byte, error) {
 	return in.Exec(runtime.CoreExecuteBlock, bdEnc)
 }
 
+// CheckInherents calls runtime API function BlockBuilder_check_inherents, which checks the
+// inherents of the given block against the given inherents data
+func (in *Instance) CheckInherents(block *types.Block, inherentsData *types.InherentsData) (
+	*types.CheckInherentsResult, error) {
+	// copy block since we're going to modify it
+	b, err := block.DeepCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	err = removeSeal(&b)
+	if err != nil {
+		return nil, err
+	}
+
+	encBlock, err := b.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	encData, err := inherentsData.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := in.exec(runtime.BlockBuilderCheckInherents, append(encBlock, encData...))
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(types.CheckInherentsResult)
+	err = scale.Unmarshal(res, result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode check inherents result: %w", err)
+	}
+
+	return result, nil
+}
+
+// removeSeal removes the seal digest from the header of the given block
+func removeSeal(block *types.Block) error {
+	digest := types.NewDigest()
+	for _, d := range block.Header.Digest.Types {
+		if _, ok := d.Value().(types.SealDigest); ok {
+			continue
+		}
+
+		err := digest.Add(d.Value())
+		if err != nil {
+			return err
+		}
+	}
+
+	block.Header.Digest = digest
+	return nil
+}
+
 // DecodeSessionKeys decodes the given public session keys. Returns a list of raw public keys including their key type.
 func (in *Instance) DecodeSessionKeys(enc []byte) ([]byte, error) {
 	return in.exec(runtime.DecodeSessionKeys, enc)
@@ -196,7 +357,16 @@ func (in *Instance) PaymentQueryInfo(ext []byte) (*types.TransactionPaymentQuery
 	return i, nil
 }
 
-func (in *Instance) CheckInherents()      {} //nolint:revive
+// OffchainWorker runs the offchain worker of the runtime for the given block header
+func (in *Instance) OffchainWorker(header *types.Header) error {
+	encHeader, err := scale.Marshal(*header)
+	if err != nil {
+		return fmt.Errorf("cannot encode header: %w", err)
+	}
+
+	_, err = in.exec(runtime.OffchainWorkerAPI, encHeader)
+	return err
+}
+
 func (in *Instance) RandomSeed()          {} //nolint:revive
-func (in *Instance) OffchainWorker()      {} //nolint:revive
 func (in *Instance) GenerateSessionKeys() {} //nolint:revive
@@ -10,6 +10,7 @@
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -476,6 +477,47 @@ func TestInstance_ExecuteBlock_NodeRuntime(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestInstance_CheckInherents_NodeRuntime(t *testing.T) {
+	// the slot duration of the node runtime is 3000ms, and the timestamp set in the
+	// test block is the current unix time in seconds
+	const slotDuration = 3000
+	slot := uint64(time.Now().Unix()) / slotDuration
+
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+	block := runtime.InitializeRuntimeToTest(t, instance, common.Hash{})
+
+	// reset state back to parent state before checking
+	parentState, err := storage.NewTrieState(nil)
+	require.NoError(t, err)
+	instance.SetContextStorage(parentState)
+
+	block.Header.Digest = types.NewDigest()
+
+	idata := types.NewInherentsData()
+	err = idata.SetInt64Inherent(types.Timstap0, uint64(time.Now().UnixMilli()))
+	require.NoError(t, err)
+	err = idata.SetInt64Inherent(types.Babeslot, slot)
+	require.NoError(t, err)
+
+	res, err := instance.CheckInherents(block, idata)
+	require.NoError(t, err)
+	require.Equal(t, &types.CheckInherentsResult{Okay: true}, res)
+
+	// the timestamp of the block doesn't match the slot
+	idata = types.NewInherentsData()
+	err = idata.SetInt64Inherent(types.Timstap0, uint64(time.Now().UnixMilli()))
+	require.NoError(t, err)
+	err = idata.SetInt64Inherent(types.Babeslot, slot+1)
+	require.NoError(t, err)
+
+	res, err = instance.CheckInherents(block, idata)
+	require.NoError(t, err)
+	require.False(t, res.Okay)
+	require.True(t, res.FatalError)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, types.Babeslot, res.Errors[0].Key[:])
+}
+
 func TestInstance_ExecuteBlock_GossamerRuntime(t *testing.T) {
 	t.Skip() // TODO: this fails with "syscall frame is no longer valid" (#1026)
 	gen, err := genesis.NewGenesisFromJSONRaw("../../../chain/gssmr/genesis.json")
@@ -47,6 +47,7 @@
 // extern int64_t ext_default_child_storage_next_key_version_1(void *context, int64_t a, int64_t b);
 // extern int64_t ext_default_child_storage_read_version_1(void *context, int64_t a, int64_t b, int64_t c, int32_t d);
 // extern int64_t ext_default_child_storage_root_version_1(void *context, int64_t a);
+// extern int64_t ext_default_child_storage_root_version_2(void *context, int64_t a, int32_t b);
 // extern void ext_default_child_storage_set_version_1(void *context, int64_t a, int64_t b, int64_t c);
 // extern void ext_default_child_storage_storage_kill_version_1(void *context, int64_t a);
 // extern int32_t ext_default_child_storage_storage_kill_version_2(void *context, int64_t a, int64_t b);
@@ -78,6 +79,10 @@
 // extern void ext_offchain_sleep_until_version_1(void *context, int64_t a);
 // extern int64_t ext_offchain_http_request_start_version_1(void *context, int64_t a, int64_t b, int64_t c);
 // extern int64_t ext_offchain_http_request_add_header_version_1(void *context, int32_t a, int64_t k, int64_t v);
+// extern int64_t ext_offchain_http_request_write_body_version_1(void *context, int32_t a, int64_t b, int64_t c);
+// extern int64_t ext_offchain_http_response_wait_version_1(void *context, int64_t a, int64_t b);
+// extern int64_t ext_offchain_http_response_headers_version_1(void *context, int32_t a);
+// extern int64_t ext_offchain_http_response_read_body_version_1(void *context, int32_t a, int64_t b, int64_t c);
 //
 // extern void ext_storage_append_version_1(void *context, int64_t a, int64_t b);
 // extern int64_t ext_storage_changes_root_version_1(void *context, int64_t a);
@@ -91,6 +96,7 @@
 // extern int64_t ext_storage_read_version_1(void *context, int64_t a, int64_t b, int32_t c);
 // extern void ext_storage_rollback_transaction_version_1(void *context);
 // extern int64_t ext_storage_root_version_1(void *context);
+// extern int64_t ext_storage_root_version_2(void *context, int32_t a);
 // extern void ext_storage_set_version_1(void *context, int64_t a, int64_t b);
 // extern void ext_storage_start_transaction_version_1(void *context);
 //
@@ -99,15 +105,16 @@
 import "C"
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/big"
 	"math/rand"
-	"reflect"
 	"time"
 	"unsafe"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	rtype "github.com/ChainSafe/gossamer/lib/common/types"
@@ -116,6 +123,8 @@
 	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/lib/trie"
@@ -127,6 +136,7 @@
 //export ext_logging_log_version_1
 func ext_logging_log_version_1(context unsafe.Pointer, level C.int32_t, targetData, msgData C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_logging_log_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	target := string(asMemorySlice(instanceContext, targetData))
@@ -151,71 +161,213 @@ func ext_logging_log_version_1(context unsafe.Pointer, level C.int32_t, targetDa
 //export ext_logging_max_level_version_1
 func ext_logging_max_level_version_1(context unsafe.Pointer) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_logging_max_level_version_1")
 	return 4
 }
 
 //export ext_transaction_index_index_version_1
-func ext_transaction_index_index_version_1(context unsafe.Pointer, a, b, c C.int32_t) {
+func ext_transaction_index_index_version_1(context unsafe.Pointer, extrinsic, size, hashPtr C.int32_t) {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
+	traceHostCall(context, "ext_transaction_index_index_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	storage := instanceContext.Data().(*runtime.Context).Storage
+	memory := instanceContext.Memory().Data()
+
+	storage.AddIndexOperation(types.IndexOperation{
+		Extrinsic: uint32(extrinsic),
+		Hash:      common.BytesToHash(memory[hashPtr : hashPtr+32]),
+		Size:      uint32(size),
+	})
 }
 
 //export ext_transaction_index_renew_version_1
-func ext_transaction_index_renew_version_1(context unsafe.Pointer, a, b C.int32_t) {
+func ext_transaction_index_renew_version_1(context unsafe.Pointer, extrinsic, hashPtr C.int32_t) {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
+	traceHostCall(context, "ext_transaction_index_renew_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	storage := instanceContext.Data().(*runtime.Context).Storage
+	memory := instanceContext.Memory().Data()
+
+	storage.AddIndexOperation(types.IndexOperation{
+		Extrinsic: uint32(extrinsic),
+		Hash:      common.BytesToHash(memory[hashPtr : hashPtr+32]),
+		Renew:     true,
+	})
 }
 
 //export ext_sandbox_instance_teardown_version_1
-func ext_sandbox_instance_teardown_version_1(context unsafe.Pointer, a C.int32_t) {
+func ext_sandbox_instance_teardown_version_1(context unsafe.Pointer, instanceIdx C.int32_t) {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
+	traceHostCall(context, "ext_sandbox_instance_teardown_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	err := runtimeCtx.Sandbox.TeardownInstance(uint32(instanceIdx))
+	if err != nil {
+		logger.Errorf("failed to teardown sandbox instance: %s", err)
+	}
 }
 
 //export ext_sandbox_instantiate_version_1
-func ext_sandbox_instantiate_version_1(context unsafe.Pointer, a C.int32_t, x, y C.int64_t, z C.int32_t) C.int32_t {
+func ext_sandbox_instantiate_version_1(context unsafe.Pointer, dispatchThunk C.int32_t,
+	wasmCodeSpan, envDefSpan C.int64_t, statePtr C.int32_t) C.int32_t {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
-	return 0
+	traceHostCall(context, "ext_sandbox_instantiate_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	// the spans are copied, the memory of the runtime may grow during the start function
+	code := append([]byte{}, asMemorySlice(instanceContext, wasmCodeSpan)...)
+	envDef := append([]byte{}, asMemorySlice(instanceContext, envDefSpan)...)
+
+	idx, err := runtimeCtx.Sandbox.Instantiate(uint32(dispatchThunk), code, envDef, uint32(statePtr))
+	if err != nil {
+		logger.Debugf("failed to instantiate sandbox module: %s", err)
+		return C.int32_t(sandbox.ErrModule)
+	}
+
+	return C.int32_t(idx)
 }
 
 //export ext_sandbox_invoke_version_1
-func ext_sandbox_invoke_version_1(context unsafe.Pointer, a C.int32_t, x, y C.int64_t, z, d, e C.int32_t) C.int32_t {
+func ext_sandbox_invoke_version_1(context unsafe.Pointer, instanceIdx C.int32_t, exportNameSpan, argsSpan C.int64_t,
+	returnValPtr, returnValLen, statePtr C.int32_t) C.int32_t {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
-	return 0
+	traceHostCall(context, "ext_sandbox_invoke_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	instance, err := runtimeCtx.Sandbox.Instance(uint32(instanceIdx))
+	if err != nil {
+		logger.Errorf("failed to invoke sandbox function: %s", err)
+		return C.int32_t(sandbox.ErrExecution)
+	}
+
+	name := string(asMemorySlice(instanceContext, exportNameSpan))
+	args, err := sandbox.DecodeValues(asMemorySlice(instanceContext, argsSpan))
+	if err != nil {
+		logger.Errorf("failed to decode sandbox function arguments: %s", err)
+		return C.int32_t(sandbox.ErrExecution)
+	}
+
+	ret, err := instance.Invoke(name, args, uint32(statePtr))
+	if err != nil {
+		logger.Debugf("failed to invoke sandbox function %s: %s", name, err)
+		return C.int32_t(sandbox.ErrExecution)
+	}
+
+	enc := sandbox.EncodeReturnValue(ret)
+	if len(enc) > int(returnValLen) {
+		logger.Errorf("return value of sandbox function %s does not fit in the buffer", name)
+		return C.int32_t(sandbox.ErrExecution)
+	}
+
+	memory := instanceContext.Memory().Data()
+	if int(uint32(returnValPtr))+len(enc) > len(memory) {
+		logger.Errorf("return value buffer of sandbox function %s is out of bounds", name)
+		return C.int32_t(sandbox.ErrExecution)
+	}
+
+	copy(memory[uint32(returnValPtr):], enc)
+	return C.int32_t(sandbox.ErrOK)
 }
 
 //export ext_sandbox_memory_get_version_1
-func ext_sandbox_memory_get_version_1(context unsafe.Pointer, a, z, d, e C.int32_t) C.int32_t {
+func ext_sandbox_memory_get_version_1(context unsafe.Pointer, memoryIdx, offset, bufPtr, bufLen C.int32_t) C.int32_t {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
-	return 0
+	traceHostCall(context, "ext_sandbox_memory_get_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	m, err := runtimeCtx.Sandbox.Memory(uint32(memoryIdx))
+	if err != nil {
+		logger.Errorf("failed to get sandbox memory: %s", err)
+		return C.int32_t(sandbox.ErrOutOfBounds)
+	}
+
+	data, err := m.Get(uint32(offset), uint32(bufLen))
+	if err != nil {
+		return C.int32_t(sandbox.ErrOutOfBounds)
+	}
+
+	memory := instanceContext.Memory().Data()
+	if int(uint32(bufPtr))+len(data) > len(memory) {
+		return C.int32_t(sandbox.ErrOutOfBounds)
+	}
+
+	copy(memory[uint32(bufPtr):], data)
+	return C.int32_t(sandbox.ErrOK)
 }
 
 //export ext_sandbox_memory_new_version_1
-func ext_sandbox_memory_new_version_1(context unsafe.Pointer, a, z C.int32_t) C.int32_t {
+func ext_sandbox_memory_new_version_1(context unsafe.Pointer, initial, maximum C.int32_t) C.int32_t {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
-	return 0
+	traceHostCall(context, "ext_sandbox_memory_new_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	idx, err := runtimeCtx.Sandbox.NewMemory(uint32(initial), uint32(maximum))
+	if err != nil {
+		logger.Debugf("failed to create sandbox memory: %s", err)
+		return C.int32_t(sandbox.ErrModule)
+	}
+
+	return C.int32_t(idx)
 }
 
 //export ext_sandbox_memory_set_version_1
-func ext_sandbox_memory_set_version_1(context unsafe.Pointer, a, z, d, e C.int32_t) C.int32_t {
+func ext_sandbox_memory_set_version_1(context unsafe.Pointer, memoryIdx, offset, valPtr, valLen C.int32_t) C.int32_t {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
-	return 0
+	traceHostCall(context, "ext_sandbox_memory_set_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	m, err := runtimeCtx.Sandbox.Memory(uint32(memoryIdx))
+	if err != nil {
+		logger.Errorf("failed to set sandbox memory: %s", err)
+		return C.int32_t(sandbox.ErrOutOfBounds)
+	}
+
+	memory := instanceContext.Memory().Data()
+	start, end := uint64(uint32(valPtr)), uint64(uint32(valPtr))+uint64(uint32(valLen))
+	if end > uint64(len(memory)) {
+		return C.int32_t(sandbox.ErrOutOfBounds)
+	}
+
+	err = m.Set(uint32(offset), memory[start:end])
+	if err != nil {
+		return C.int32_t(sandbox.ErrOutOfBounds)
+	}
+
+	return C.int32_t(sandbox.ErrOK)
 }
 
 //export ext_sandbox_memory_teardown_version_1
-func ext_sandbox_memory_teardown_version_1(context unsafe.Pointer, a C.int32_t) {
+func ext_sandbox_memory_teardown_version_1(context unsafe.Pointer, memoryIdx C.int32_t) {
 	logger.Trace("executing...")
-	logger.Warn("unimplemented")
+	traceHostCall(context, "ext_sandbox_memory_teardown_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	err := runtimeCtx.Sandbox.TeardownMemory(uint32(memoryIdx))
+	if err != nil {
+		logger.Errorf("failed to teardown sandbox memory: %s", err)
+	}
 }
 
 //export ext_crypto_ed25519_generate_version_1
 func ext_crypto_ed25519_generate_version_1(context unsafe.Pointer, keyTypeID C.int32_t, seedSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_crypto_ed25519_generate_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -269,6 +421,7 @@ func ext_crypto_ed25519_generate_version_1(context unsafe.Pointer, keyTypeID C.i
 //export ext_crypto_ed25519_public_keys_version_1
 func ext_crypto_ed25519_public_keys_version_1(context unsafe.Pointer, keyTypeID C.int32_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_crypto_ed25519_public_keys_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -318,6 +471,7 @@ func ext_crypto_ed25519_public_keys_version_1(context unsafe.Pointer, keyTypeID
 //export ext_crypto_ed25519_sign_version_1
 func ext_crypto_ed25519_sign_version_1(context unsafe.Pointer, keyTypeID, key C.int32_t, msg C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_crypto_ed25519_sign_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -368,6 +522,7 @@ func ext_crypto_ed25519_sign_version_1(context unsafe.Pointer, keyTypeID, key C.
 //export ext_crypto_ed25519_verify_version_1
 func ext_crypto_ed25519_verify_version_1(context unsafe.Pointer, sig C.int32_t, msg C.int64_t, key C.int32_t) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_crypto_ed25519_verify_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
@@ -376,6 +531,7 @@ func ext_crypto_ed25519_verify_version_1(context unsafe.Pointer, sig C.int32_t,
 	signature := memory[sig : sig+64]
 	message := asMemorySlice(instanceContext, msg)
 	pubKeyData := memory[key : key+32]
+	traceSignatureVerify(instanceContext, "ed25519", pubKeyData, message, signature)
 
 	pubKey, err := ed25519.NewPublicKey(pubKeyData)
 	if err != nil {
@@ -406,6 +562,7 @@ func ext_crypto_ed25519_verify_version_1(context unsafe.Pointer, sig C.int32_t,
 //export ext_crypto_secp256k1_ecdsa_recover_version_1
 func ext_crypto_secp256k1_ecdsa_recover_version_1(context unsafe.Pointer, sig, msg C.int32_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_crypto_secp256k1_ecdsa_recover_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -443,12 +600,14 @@ func ext_crypto_secp256k1_ecdsa_recover_version_1(context unsafe.Pointer, sig, m
 //export ext_crypto_secp256k1_ecdsa_recover_version_2
 func ext_crypto_secp256k1_ecdsa_recover_version_2(context unsafe.Pointer, sig, msg C.int32_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_crypto_secp256k1_ecdsa_recover_version_2")
 	return ext_crypto_secp256k1_ecdsa_recover_version_1(context, sig, msg)
 }
 
 //export ext_crypto_ecdsa_verify_version_2
 func ext_crypto_ecdsa_verify_version_2(context unsafe.Pointer, sig C.int32_t, msg C.int64_t, key C.int32_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_crypto_ecdsa_verify_version_2")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
@@ -457,6 +616,7 @@ func ext_crypto_ecdsa_verify_version_2(context unsafe.Pointer, sig C.int32_t, ms
 	message := asMemorySlice(instanceContext, msg)
 	signature := memory[sig : sig+64]
 	pubKey := memory[key : key+33]
+	traceSignatureVerify(instanceContext, "ecdsa", pubKey, message, signature)
 
 	pub := new(secp256k1.PublicKey)
 	err := pub.Decode(pubKey)
@@ -497,6 +657,7 @@ func ext_crypto_ecdsa_verify_version_2(context unsafe.Pointer, sig C.int32_t, ms
 //export ext_crypto_secp256k1_ecdsa_recover_compressed_version_1
 func ext_crypto_secp256k1_ecdsa_recover_compressed_version_1(context unsafe.Pointer, sig, msg C.int32_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_crypto_secp256k1_ecdsa_recover_compressed_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -529,12 +690,14 @@ func ext_crypto_secp256k1_ecdsa_recover_compressed_version_1(context unsafe.Poin
 //export ext_crypto_secp256k1_ecdsa_recover_compressed_version_2
 func ext_crypto_secp256k1_ecdsa_recover_compressed_version_2(context unsafe.Pointer, sig, msg C.int32_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_crypto_secp256k1_ecdsa_recover_compressed_version_2")
 	return ext_crypto_secp256k1_ecdsa_recover_compressed_version_1(context, sig, msg)
 }
 
 //export ext_crypto_sr25519_generate_version_1
 func ext_crypto_sr25519_generate_version_1(context unsafe.Pointer, keyTypeID C.int32_t, seedSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_crypto_sr25519_generate_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -587,6 +750,7 @@ func ext_crypto_sr25519_generate_version_1(context unsafe.Pointer, keyTypeID C.i
 //export ext_crypto_sr25519_public_keys_version_1
 func ext_crypto_sr25519_public_keys_version_1(context unsafe.Pointer, keyTypeID C.int32_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_crypto_sr25519_public_keys_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -636,6 +800,7 @@ func ext_crypto_sr25519_public_keys_version_1(context unsafe.Pointer, keyTypeID
 //export ext_crypto_sr25519_sign_version_1
 func ext_crypto_sr25519_sign_version_1(context unsafe.Pointer, keyTypeID, key C.int32_t, msg C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_crypto_sr25519_sign_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
 	memory := instanceContext.Memory().Data()
@@ -682,13 +847,14 @@ func ext_crypto_sr25519_sign_version_1(context unsafe.Pointer, keyTypeID, key C.
 //export ext_crypto_sr25519_verify_version_1
 func ext_crypto_sr25519_verify_version_1(context unsafe.Pointer, sig C.int32_t, msg C.int64_t, key C.int32_t) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_crypto_sr25519_verify_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
-	sigVerifier := instanceContext.Data().(*runtime.Context).SigVerifier
 
 	message := asMemorySlice(instanceContext, msg)
 	signature := memory[sig : sig+64]
+	traceSignatureVerify(instanceContext, "sr25519", memory[key:key+32], message, signature)
 
 	pub, err := sr25519.NewPublicKey(memory[key : key+32])
 	if err != nil {
@@ -700,17 +866,7 @@ func ext_crypto_sr25519_verify_version_1(context unsafe.Pointer, sig C.int32_t,
 		"pub=%s message=0x%x signature=0x%x",
 		pub.Hex(), message, signature)
 
-	if sigVerifier.IsStarted() {
-		signature := crypto.SignatureInfo{
-			PubKey:     pub.Encode(),
-			Sign:       signature,
-			Msg:        message,
-			VerifyFunc: sr25519.VerifySignature,
-		}
-		sigVerifier.Add(&signature)
-		return 1
-	}
-
+	// the signature isn't added to the batch, since this version never fails
 	if ok, err := pub.VerifyDeprecated(message, signature); err != nil || !ok {
 		logger.Debugf("failed to validate signature: %s", err)
 		// this fails at block 3876, which seems to be expected, based on discussions
@@ -724,6 +880,7 @@ func ext_crypto_sr25519_verify_version_1(context unsafe.Pointer, sig C.int32_t,
 //export ext_crypto_sr25519_verify_version_2
 func ext_crypto_sr25519_verify_version_2(context unsafe.Pointer, sig C.int32_t, msg C.int64_t, key C.int32_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_crypto_sr25519_verify_version_2")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
@@ -731,6 +888,7 @@ func ext_crypto_sr25519_verify_version_2(context unsafe.Pointer, sig C.int32_t,
 
 	message := asMemorySlice(instanceContext, msg)
 	signature := memory[sig : sig+64]
+	traceSignatureVerify(instanceContext, "sr25519", memory[key:key+32], message, signature)
 
 	pub, err := sr25519.NewPublicKey(memory[key : key+32])
 	if err != nil {
@@ -765,23 +923,44 @@ func ext_crypto_sr25519_verify_version_2(context unsafe.Pointer, sig C.int32_t,
 //export ext_crypto_start_batch_verify_version_1
 func ext_crypto_start_batch_verify_version_1(context unsafe.Pointer) {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_crypto_start_batch_verify_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	sigVerifier := instanceContext.Data().(*runtime.Context).SigVerifier
+
+	if sigVerifier.IsStarted() {
+		logger.Error("batch verification is already started")
+		return
+	}
 
-	// TODO: fix and re-enable signature verification (#1405)
-	// beginBatchVerify(context)
+	sigVerifier.Start()
 }
 
 //export ext_crypto_finish_batch_verify_version_1
 func ext_crypto_finish_batch_verify_version_1(context unsafe.Pointer) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_crypto_finish_batch_verify_version_1")
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	sigVerifier := instanceContext.Data().(*runtime.Context).SigVerifier
+
+	if !sigVerifier.IsStarted() {
+		logger.Error("batch verification is not started")
+		return 0
+	}
+
+	if !sigVerifier.Finish() {
+		logger.Debug("failed to verify batch of signatures")
+		return 0
+	}
 
-	// TODO: fix and re-enable signature verification (#1405)
-	// return finishBatchVerify(context)
 	return 1
 }
 
 //export ext_trie_blake2_256_root_version_1
 func ext_trie_blake2_256_root_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_trie_blake2_256_root_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
@@ -826,6 +1005,7 @@ type kv struct {
 //export ext_trie_blake2_256_ordered_root_version_1
 func ext_trie_blake2_256_ordered_root_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_trie_blake2_256_ordered_root_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
@@ -874,6 +1054,7 @@ func ext_trie_blake2_256_ordered_root_version_1(context unsafe.Pointer, dataSpan
 //export ext_trie_blake2_256_verify_proof_version_1
 func ext_trie_blake2_256_verify_proof_version_1(context unsafe.Pointer, rootSpan C.int32_t, proofSpan, keySpan, valueSpan C.int64_t) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_trie_blake2_256_verify_proof_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 
@@ -908,6 +1089,7 @@ func ext_trie_blake2_256_verify_proof_version_1(context unsafe.Pointer, rootSpan
 //export ext_misc_print_hex_version_1
 func ext_misc_print_hex_version_1(context unsafe.Pointer, dataSpan C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_misc_print_hex_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -915,8 +1097,9 @@ func ext_misc_print_hex_version_1(context unsafe.Pointer, dataSpan C.int64_t) {
 }
 
 //export ext_misc_print_num_version_1
-func ext_misc_print_num_version_1(_ unsafe.Pointer, data C.int64_t) {
+func ext_misc_print_num_version_1(context unsafe.Pointer, data C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_misc_print_num_version_1")
 
 	logger.Debugf("num: %d", int64(data))
 }
@@ -924,6 +1107,7 @@ func ext_misc_print_num_version_1(_ unsafe.Pointer, data C.int64_t) {
 //export ext_misc_print_utf8_version_1
 func ext_misc_print_utf8_version_1(context unsafe.Pointer, dataSpan C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_misc_print_utf8_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -933,6 +1117,7 @@ func ext_misc_print_utf8_version_1(context unsafe.Pointer, dataSpan C.int64_t) {
 //export ext_misc_runtime_version_version_1
 func ext_misc_runtime_version_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_misc_runtime_version_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -976,6 +1161,7 @@ func ext_misc_runtime_version_version_1(context unsafe.Pointer, dataSpan C.int64
 //export ext_default_child_storage_read_version_1
 func ext_default_child_storage_read_version_1(context unsafe.Pointer, childStorageKey, key, valueOut C.int64_t, offset C.int32_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_read_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -1006,6 +1192,7 @@ func ext_default_child_storage_read_version_1(context unsafe.Pointer, childStora
 //export ext_default_child_storage_clear_version_1
 func ext_default_child_storage_clear_version_1(context unsafe.Pointer, childStorageKey, keySpan C.int64_t) {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_clear_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
@@ -1023,6 +1210,7 @@ func ext_default_child_storage_clear_version_1(context unsafe.Pointer, childStor
 //export ext_default_child_storage_clear_prefix_version_1
 func ext_default_child_storage_clear_prefix_version_1(context unsafe.Pointer, childStorageKey, prefixSpan C.int64_t) {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_clear_prefix_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
@@ -1040,6 +1228,7 @@ func ext_default_child_storage_clear_prefix_version_1(context unsafe.Pointer, ch
 //export ext_default_child_storage_exists_version_1
 func ext_default_child_storage_exists_version_1(context unsafe.Pointer, childStorageKey, key C.int64_t) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_exists_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -1058,6 +1247,7 @@ func ext_default_child_storage_exists_version_1(context unsafe.Pointer, childSto
 //export ext_default_child_storage_get_version_1
 func ext_default_child_storage_get_version_1(context unsafe.Pointer, childStorageKey, key C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_get_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -1080,6 +1270,7 @@ func ext_default_child_storage_get_version_1(context unsafe.Pointer, childStorag
 //export ext_default_child_storage_next_key_version_1
 func ext_default_child_storage_next_key_version_1(context unsafe.Pointer, childStorageKey, key C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_next_key_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -1102,7 +1293,27 @@ func ext_default_child_storage_next_key_version_1(context unsafe.Pointer, childS
 //export ext_default_child_storage_root_version_1
 func ext_default_child_storage_root_version_1(context unsafe.Pointer, childStorageKey C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_root_version_1")
+
+	return defaultChildStorageRoot(context, childStorageKey, trie.V0)
+}
+
+//export ext_default_child_storage_root_version_2
+func ext_default_child_storage_root_version_2(context unsafe.Pointer, childStorageKey C.int64_t,
+	stateVersion C.int32_t) C.int64_t {
+	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_root_version_2")
+
+	version, err := trie.ParseVersion(uint32(stateVersion))
+	if err != nil {
+		logger.Errorf("failed parsing state version: %s", err)
+		return 0
+	}
 
+	return defaultChildStorageRoot(context, childStorageKey, version)
+}
+
+func defaultChildStorageRoot(context unsafe.Pointer, childStorageKey C.int64_t, version trie.Version) C.int64_t {
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
 
@@ -1112,7 +1323,7 @@ func ext_default_child_storage_root_version_1(context unsafe.Pointer, childStora
 		return 0
 	}
 
-	childRoot, err := child.Hash()
+	childRoot, err := child.HashWithVersion(version)
 	if err != nil {
 		logger.Errorf("failed to encode child root: %s", err)
 		return 0
@@ -1130,6 +1341,7 @@ func ext_default_child_storage_root_version_1(context unsafe.Pointer, childStora
 //export ext_default_child_storage_set_version_1
 func ext_default_child_storage_set_version_1(context unsafe.Pointer, childStorageKeySpan, keySpan, valueSpan C.int64_t) {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_set_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
@@ -1152,6 +1364,7 @@ func ext_default_child_storage_set_version_1(context unsafe.Pointer, childStorag
 //export ext_default_child_storage_storage_kill_version_1
 func ext_default_child_storage_storage_kill_version_1(context unsafe.Pointer, childStorageKeySpan C.int64_t) {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_storage_kill_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
@@ -1164,6 +1377,7 @@ func ext_default_child_storage_storage_kill_version_1(context unsafe.Pointer, ch
 //export ext_default_child_storage_storage_kill_version_2
 func ext_default_child_storage_storage_kill_version_2(context unsafe.Pointer, childStorageKeySpan, lim C.int64_t) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_storage_kill_version_2")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
@@ -1204,6 +1418,7 @@ func (someRemain) Index() uint {
 //export ext_default_child_storage_storage_kill_version_3
 func ext_default_child_storage_storage_kill_version_3(context unsafe.Pointer, childStorageKeySpan, lim C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_default_child_storage_storage_kill_version_3")
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
 	storage := ctx.Storage
@@ -1256,6 +1471,7 @@ func ext_default_child_storage_storage_kill_version_3(context unsafe.Pointer, ch
 //export ext_allocator_free_version_1
 func ext_allocator_free_version_1(context unsafe.Pointer, addr C.int32_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_allocator_free_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
 
@@ -1269,6 +1485,7 @@ func ext_allocator_free_version_1(context unsafe.Pointer, addr C.int32_t) {
 //export ext_allocator_malloc_version_1
 func ext_allocator_malloc_version_1(context unsafe.Pointer, size C.int32_t) C.int32_t {
 	logger.Tracef("executing with size %d...", int64(size))
+	traceHostCall(context, "ext_allocator_malloc_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
@@ -1286,6 +1503,7 @@ func ext_allocator_malloc_version_1(context unsafe.Pointer, size C.int32_t) C.in
 //export ext_hashing_blake2_128_version_1
 func ext_hashing_blake2_128_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_hashing_blake2_128_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -1312,6 +1530,7 @@ func ext_hashing_blake2_128_version_1(context unsafe.Pointer, dataSpan C.int64_t
 //export ext_hashing_blake2_256_version_1
 func ext_hashing_blake2_256_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_hashing_blake2_256_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -1336,6 +1555,7 @@ func ext_hashing_blake2_256_version_1(context unsafe.Pointer, dataSpan C.int64_t
 //export ext_hashing_keccak_256_version_1
 func ext_hashing_keccak_256_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_hashing_keccak_256_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -1360,6 +1580,7 @@ func ext_hashing_keccak_256_version_1(context unsafe.Pointer, dataSpan C.int64_t
 //export ext_hashing_sha2_256_version_1
 func ext_hashing_sha2_256_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_hashing_sha2_256_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -1379,6 +1600,7 @@ func ext_hashing_sha2_256_version_1(context unsafe.Pointer, dataSpan C.int64_t)
 //export ext_hashing_twox_256_version_1
 func ext_hashing_twox_256_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_hashing_twox_256_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -1403,6 +1625,7 @@ func ext_hashing_twox_256_version_1(context unsafe.Pointer, dataSpan C.int64_t)
 //export ext_hashing_twox_128_version_1
 func ext_hashing_twox_128_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_hashing_twox_128_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	data := asMemorySlice(instanceContext, dataSpan)
 
@@ -1428,6 +1651,7 @@ func ext_hashing_twox_128_version_1(context unsafe.Pointer, dataSpan C.int64_t)
 //export ext_hashing_twox_64_version_1
 func ext_hashing_twox_64_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_hashing_twox_64_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -1454,6 +1678,7 @@ func ext_hashing_twox_64_version_1(context unsafe.Pointer, dataSpan C.int64_t) C
 //export ext_offchain_index_set_version_1
 func ext_offchain_index_set_version_1(context unsafe.Pointer, keySpan, valueSpan C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_offchain_index_set_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
 
@@ -1471,17 +1696,15 @@ func ext_offchain_index_set_version_1(context unsafe.Pointer, keySpan, valueSpan
 //export ext_offchain_local_storage_clear_version_1
 func ext_offchain_local_storage_clear_version_1(context unsafe.Pointer, kind C.int32_t, key C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_offchain_local_storage_clear_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
 
 	storageKey := asMemorySlice(instanceContext, key)
 
-	memory := instanceContext.Memory().Data()
-	kindInt := binary.LittleEndian.Uint32(memory[kind : kind+4])
-
 	var err error
 
-	switch runtime.NodeStorageType(kindInt) {
+	switch runtime.NodeStorageType(kind) {
 	case runtime.NodeStorageTypePersistent:
 		err = runtimeCtx.NodeStorage.PersistentStorage.Del(storageKey)
 	case runtime.NodeStorageTypeLocal:
@@ -1496,6 +1719,7 @@ func ext_offchain_local_storage_clear_version_1(context unsafe.Pointer, kind C.i
 //export ext_offchain_is_validator_version_1
 func ext_offchain_is_validator_version_1(context unsafe.Pointer) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_is_validator_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -1508,6 +1732,7 @@ func ext_offchain_is_validator_version_1(context unsafe.Pointer) C.int32_t {
 //export ext_offchain_local_storage_compare_and_set_version_1
 func ext_offchain_local_storage_compare_and_set_version_1(context unsafe.Pointer, kind C.int32_t, key, oldValue, newValue C.int64_t) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_local_storage_compare_and_set_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -1529,16 +1754,32 @@ func ext_offchain_local_storage_compare_and_set_version_1(context unsafe.Pointer
 		return 0
 	}
 
-	oldVal := asMemorySlice(instanceContext, oldValue)
+	// the old value is an optional value, where None means the key should not be set
+	var oldVal *[]byte
+	err = scale.Unmarshal(asMemorySlice(instanceContext, oldValue), &oldVal)
+	if err != nil {
+		logger.Errorf("failed to decode old value: %s", err)
+		return 0
+	}
+
+	if oldVal == nil && storedValue != nil || oldVal != nil && !bytes.Equal(storedValue, *oldVal) {
+		return 0
+	}
+
 	newVal := asMemorySlice(instanceContext, newValue)
-	if reflect.DeepEqual(storedValue, oldVal) {
-		cp := make([]byte, len(newVal))
-		copy(cp, newVal)
+	cp := make([]byte, len(newVal))
+	copy(cp, newVal)
+
+	switch runtime.NodeStorageType(kind) {
+	case runtime.NodeStorageTypePersistent:
+		err = runtimeCtx.NodeStorage.PersistentStorage.Put(storageKey, cp)
+	case runtime.NodeStorageTypeLocal:
 		err = runtimeCtx.NodeStorage.LocalStorage.Put(storageKey, cp)
-		if err != nil {
-			logger.Errorf("failed to set value in storage: %s", err)
-			return 0
-		}
+	}
+
+	if err != nil {
+		logger.Errorf("failed to set value in storage: %s", err)
+		return 0
 	}
 
 	return 1
@@ -1547,6 +1788,7 @@ func ext_offchain_local_storage_compare_and_set_version_1(context unsafe.Pointer
 //export ext_offchain_local_storage_get_version_1
 func ext_offchain_local_storage_get_version_1(context unsafe.Pointer, kind C.int32_t, key C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_local_storage_get_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -1577,6 +1819,7 @@ func ext_offchain_local_storage_get_version_1(context unsafe.Pointer, kind C.int
 //export ext_offchain_local_storage_set_version_1
 func ext_offchain_local_storage_set_version_1(context unsafe.Pointer, kind C.int32_t, key, value C.int64_t) {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_local_storage_set_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -1601,6 +1844,7 @@ func ext_offchain_local_storage_set_version_1(context unsafe.Pointer, kind C.int
 //export ext_offchain_network_state_version_1
 func ext_offchain_network_state_version_1(context unsafe.Pointer) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_network_state_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
 	if runtimeCtx.Network == nil {
@@ -1631,6 +1875,7 @@ func ext_offchain_network_state_version_1(context unsafe.Pointer) C.int64_t {
 //export ext_offchain_random_seed_version_1
 func ext_offchain_random_seed_version_1(context unsafe.Pointer) C.int32_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_random_seed_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	seed := make([]byte, 32)
@@ -1648,41 +1893,69 @@ func ext_offchain_random_seed_version_1(context unsafe.Pointer) C.int32_t {
 //export ext_offchain_submit_transaction_version_1
 func ext_offchain_submit_transaction_version_1(context unsafe.Pointer, data C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_submit_transaction_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	// the extrinsic is kept encoded, as it is in the transaction pool
 	extBytes := asMemorySlice(instanceContext, data)
+	extrinsic := make([]byte, len(extBytes))
+	copy(extrinsic, extBytes)
 
-	var extrinsic []byte
-	err := scale.Unmarshal(extBytes, &extrinsic)
-	if err != nil {
-		logger.Errorf("failed to decode extrinsic data: %s", err)
+	result := scale.NewResult(nil, nil)
+	resultMode := scale.OK
+
+	if runtimeCtx.Transaction == nil {
+		logger.Error("cannot submit transaction: transaction state is not set")
+		resultMode = scale.Err
+	} else if submitter, ok := runtimeCtx.Transaction.(runtime.TransactionSubmitter); ok {
+		err := submitter.SubmitTransaction(extrinsic)
+		if err != nil {
+			logger.Debugf("failed to submit transaction: %s", err)
+			resultMode = scale.Err
+		}
+	} else {
+		// the validity is unknown here, it's up to the transaction state to validate the extrinsic
+		txv := transaction.NewValidity(0, [][]byte{{}}, [][]byte{{}}, 0, false)
+		vtx := transaction.NewValidTransaction(extrinsic, txv)
+		runtimeCtx.Transaction.AddToPool(vtx)
 	}
 
-	// validate the transaction
-	txv := transaction.NewValidity(0, [][]byte{{}}, [][]byte{{}}, 0, false)
-	vtx := transaction.NewValidTransaction(extrinsic, txv)
+	err := result.Set(resultMode, nil)
+	if err != nil {
+		logger.Errorf("failed to set the result data: %s", err)
+		return C.int64_t(0)
+	}
 
-	runtimeCtx := instanceContext.Data().(*runtime.Context)
-	runtimeCtx.Transaction.AddToPool(vtx)
+	enc, err := scale.Marshal(result)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the result: %s", err)
+		return C.int64_t(0)
+	}
 
-	ptr, err := toWasmMemoryOptional(instanceContext, nil)
+	ptr, err := toWasmMemory(instanceContext, enc)
 	if err != nil {
-		logger.Errorf("failed to allocate memory: %s", err)
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return C.int64_t(0)
 	}
+
 	return C.int64_t(ptr)
 }
 
 //export ext_offchain_timestamp_version_1
-func ext_offchain_timestamp_version_1(_ unsafe.Pointer) C.int64_t {
+func ext_offchain_timestamp_version_1(context unsafe.Pointer) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_offchain_timestamp_version_1")
 
 	now := time.Now().Unix()
 	return C.int64_t(now)
 }
 
 //export ext_offchain_sleep_until_version_1
-func ext_offchain_sleep_until_version_1(_ unsafe.Pointer, deadline C.int64_t) {
+func ext_offchain_sleep_until_version_1(context unsafe.Pointer, deadline C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_offchain_sleep_until_version_1")
 
 	dur := time.Until(time.UnixMilli(int64(deadline)))
 	if dur > 0 {
@@ -1693,6 +1966,7 @@ func ext_offchain_sleep_until_version_1(_ unsafe.Pointer, deadline C.int64_t) {
 //export ext_offchain_http_request_start_version_1
 func ext_offchain_http_request_start_version_1(context unsafe.Pointer, methodSpan, uriSpan, metaSpan C.int64_t) C.int64_t { // skipcq: RVV-B0012
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_http_request_start_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -1735,6 +2009,7 @@ func ext_offchain_http_request_start_version_1(context unsafe.Pointer, methodSpa
 //export ext_offchain_http_request_add_header_version_1
 func ext_offchain_http_request_add_header_version_1(context unsafe.Pointer, reqID C.int32_t, nameSpan, valueSpan C.int64_t) C.int64_t {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_http_request_add_header_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	name := asMemorySlice(instanceContext, nameSpan)
@@ -1746,7 +2021,13 @@ func ext_offchain_http_request_add_header_version_1(context unsafe.Pointer, reqI
 	result := scale.NewResult(nil, nil)
 	resultMode := scale.OK
 
-	err := offchainReq.AddHeader(string(name), string(value))
+	var err error
+	if offchainReq == nil {
+		err = offchain.ErrInvalidRequestID
+	} else {
+		err = offchainReq.AddHeader(string(name), string(value))
+	}
+
 	if err != nil {
 		logger.Errorf("failed to add request header: %s", err)
 		resultMode = scale.Err
@@ -1773,6 +2054,190 @@ func ext_offchain_http_request_add_header_version_1(context unsafe.Pointer, reqI
 	return C.int64_t(ptr)
 }
 
+//export ext_offchain_http_request_write_body_version_1
+func ext_offchain_http_request_write_body_version_1(context unsafe.Pointer, reqID C.int32_t, chunkSpan, deadlineSpan C.int64_t) C.int64_t { // skipcq: RVV-B0012
+	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_http_request_write_body_version_1")
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	deadline, err := toDeadline(instanceContext, deadlineSpan)
+	if err != nil {
+		logger.Errorf("failed to decode deadline: %s", err)
+		return C.int64_t(0)
+	}
+
+	chunk := asMemorySlice(instanceContext, chunkSpan)
+	err = runtimeCtx.OffchainHTTPSet.WriteBody(int16(reqID), chunk, deadline)
+	if err != nil {
+		logger.Debugf("failed to write request body: %s", err)
+	}
+
+	ptr, err := toWasmMemory(instanceContext, encodeHTTPResult(nil, err))
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return C.int64_t(0)
+	}
+
+	return C.int64_t(ptr)
+}
+
+//export ext_offchain_http_response_wait_version_1
+func ext_offchain_http_response_wait_version_1(context unsafe.Pointer, idsSpan, deadlineSpan C.int64_t) C.int64_t {
+	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_http_response_wait_version_1")
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	var ids []int16
+	err := scale.Unmarshal(asMemorySlice(instanceContext, idsSpan), &ids)
+	if err != nil {
+		logger.Errorf("failed to decode request ids: %s", err)
+		return C.int64_t(0)
+	}
+
+	deadline, err := toDeadline(instanceContext, deadlineSpan)
+	if err != nil {
+		logger.Errorf("failed to decode deadline: %s", err)
+		return C.int64_t(0)
+	}
+
+	statuses := runtimeCtx.OffchainHTTPSet.Wait(ids, deadline)
+
+	enc, err := encodeHTTPRequestStatuses(statuses)
+	if err != nil {
+		logger.Errorf("failed to encode request statuses: %s", err)
+		return C.int64_t(0)
+	}
+
+	ptr, err := toWasmMemory(instanceContext, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return C.int64_t(0)
+	}
+
+	return C.int64_t(ptr)
+}
+
+//export ext_offchain_http_response_headers_version_1
+func ext_offchain_http_response_headers_version_1(context unsafe.Pointer, reqID C.int32_t) C.int64_t {
+	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_http_response_headers_version_1")
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	headers := runtimeCtx.OffchainHTTPSet.ResponseHeaders(int16(reqID))
+	if headers == nil {
+		headers = []offchain.ResponseHeader{}
+	}
+
+	enc, err := scale.Marshal(headers)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the headers: %s", err)
+		return C.int64_t(0)
+	}
+
+	ptr, err := toWasmMemory(instanceContext, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return C.int64_t(0)
+	}
+
+	return C.int64_t(ptr)
+}
+
+//export ext_offchain_http_response_read_body_version_1
+func ext_offchain_http_response_read_body_version_1(context unsafe.Pointer, reqID C.int32_t, bufferSpan, deadlineSpan C.int64_t) C.int64_t { // skipcq: RVV-B0012
+	logger.Debug("executing...")
+	traceHostCall(context, "ext_offchain_http_response_read_body_version_1")
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	deadline, err := toDeadline(instanceContext, deadlineSpan)
+	if err != nil {
+		logger.Errorf("failed to decode deadline: %s", err)
+		return C.int64_t(0)
+	}
+
+	// the body is read straight into the buffer of the runtime
+	buffer := asMemorySlice(instanceContext, bufferSpan)
+	n, err := runtimeCtx.OffchainHTTPSet.ReadBody(int16(reqID), buffer, deadline)
+	if err != nil {
+		logger.Debugf("failed to read response body: %s", err)
+	}
+
+	read := make([]byte, 4)
+	binary.LittleEndian.PutUint32(read, uint32(n))
+
+	ptr, err := toWasmMemory(instanceContext, encodeHTTPResult(read, err))
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return C.int64_t(0)
+	}
+
+	return C.int64_t(ptr)
+}
+
+// toDeadline decodes the optional deadline given by the runtime as a timestamp in milliseconds
+func toDeadline(context wasm.InstanceContext, deadlineSpan C.int64_t) (*time.Time, error) {
+	var timestamp *uint64
+	err := scale.Unmarshal(asMemorySlice(context, deadlineSpan), &timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	if timestamp == nil {
+		return nil, nil
+	}
+
+	deadline := time.UnixMilli(int64(*timestamp))
+	return &deadline, nil
+}
+
+// encodeHTTPResult encodes a Result<T, HttpError> given the encoded value and the offchain http error
+func encodeHTTPResult(value []byte, err error) []byte {
+	if err == nil {
+		return append([]byte{0}, value...)
+	}
+
+	// the HttpError variants have explicit discriminants
+	code := byte(2) // IoError
+	switch {
+	case errors.Is(err, offchain.ErrDeadlineReached):
+		code = 1
+	case errors.Is(err, offchain.ErrInvalidRequestID):
+		code = 3
+	}
+
+	return []byte{1, code}
+}
+
+// encodeHTTPRequestStatuses encodes the statuses as a Vec<HttpRequestStatus>
+func encodeHTTPRequestStatuses(statuses []offchain.RequestStatus) ([]byte, error) {
+	enc, err := scale.Marshal(big.NewInt(int64(len(statuses))))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, status := range statuses {
+		switch {
+		case status.Err == nil:
+			code := make([]byte, 2)
+			binary.LittleEndian.PutUint16(code, status.Code)
+			enc = append(enc, 3)
+			enc = append(enc, code...)
+		case errors.Is(status.Err, offchain.ErrDeadlineReached):
+			enc = append(enc, 0)
+		case errors.Is(status.Err, offchain.ErrInvalidRequestID):
+			enc = append(enc, 2)
+		default:
+			enc = append(enc, 1)
+		}
+	}
+
+	return enc, nil
+}
+
 func storageAppend(storage runtime.Storage, key, valueToAppend []byte) error {
 	nextLength := big.NewInt(1)
 	var valueRes []byte
@@ -1821,6 +2286,7 @@ func storageAppend(storage runtime.Storage, key, valueToAppend []byte) error {
 //export ext_storage_append_version_1
 func ext_storage_append_version_1(context unsafe.Pointer, keySpan, valueSpan C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_append_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
 	storage := ctx.Storage
@@ -1843,6 +2309,7 @@ func ext_storage_append_version_1(context unsafe.Pointer, keySpan, valueSpan C.i
 //export ext_storage_changes_root_version_1
 func ext_storage_changes_root_version_1(context unsafe.Pointer, parentHashSpan C.int64_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_changes_root_version_1")
 	logger.Debug("returning None")
 
 	instanceContext := wasm.IntoInstanceContext(context)
@@ -1859,6 +2326,7 @@ func ext_storage_changes_root_version_1(context unsafe.Pointer, parentHashSpan C
 //export ext_storage_clear_version_1
 func ext_storage_clear_version_1(context unsafe.Pointer, keySpan C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_clear_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
 	storage := ctx.Storage
@@ -1872,6 +2340,7 @@ func ext_storage_clear_version_1(context unsafe.Pointer, keySpan C.int64_t) {
 //export ext_storage_clear_prefix_version_1
 func ext_storage_clear_prefix_version_1(context unsafe.Pointer, prefixSpan C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_clear_prefix_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
 	storage := ctx.Storage
@@ -1888,6 +2357,7 @@ func ext_storage_clear_prefix_version_1(context unsafe.Pointer, prefixSpan C.int
 //export ext_storage_clear_prefix_version_2
 func ext_storage_clear_prefix_version_2(context unsafe.Pointer, prefixSpan, lim C.int64_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_clear_prefix_version_2")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
@@ -1933,6 +2403,7 @@ func ext_storage_clear_prefix_version_2(context unsafe.Pointer, prefixSpan, lim
 //export ext_storage_exists_version_1
 func ext_storage_exists_version_1(context unsafe.Pointer, keySpan C.int64_t) C.int32_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_exists_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
 
@@ -1950,6 +2421,7 @@ func ext_storage_exists_version_1(context unsafe.Pointer, keySpan C.int64_t) C.i
 //export ext_storage_get_version_1
 func ext_storage_get_version_1(context unsafe.Pointer, keySpan C.int64_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_get_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -1973,6 +2445,7 @@ func ext_storage_get_version_1(context unsafe.Pointer, keySpan C.int64_t) C.int6
 //export ext_storage_next_key_version_1
 func ext_storage_next_key_version_1(context unsafe.Pointer, keySpan C.int64_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_next_key_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -1996,6 +2469,7 @@ func ext_storage_next_key_version_1(context unsafe.Pointer, keySpan C.int64_t) C
 //export ext_storage_read_version_1
 func ext_storage_read_version_1(context unsafe.Pointer, keySpan, valueOut C.int64_t, offset C.int32_t) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_read_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -2034,11 +2508,30 @@ func ext_storage_read_version_1(context unsafe.Pointer, keySpan, valueOut C.int6
 //export ext_storage_root_version_1
 func ext_storage_root_version_1(context unsafe.Pointer) C.int64_t {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_root_version_1")
+
+	return storageRoot(context, trie.V0)
+}
+
+//export ext_storage_root_version_2
+func ext_storage_root_version_2(context unsafe.Pointer, stateVersion C.int32_t) C.int64_t {
+	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_root_version_2")
+
+	version, err := trie.ParseVersion(uint32(stateVersion))
+	if err != nil {
+		logger.Errorf("failed parsing state version: %s", err)
+		return 0
+	}
+
+	return storageRoot(context, version)
+}
 
+func storageRoot(context unsafe.Pointer, version trie.Version) C.int64_t {
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
 
-	root, err := storage.Root()
+	root, err := storage.RootWithVersion(version)
 	if err != nil {
 		logger.Errorf("failed to get storage root: %s", err)
 		return 0
@@ -2058,6 +2551,7 @@ func ext_storage_root_version_1(context unsafe.Pointer) C.int64_t {
 //export ext_storage_set_version_1
 func ext_storage_set_version_1(context unsafe.Pointer, keySpan, valueSpan C.int64_t) {
 	logger.Trace("executing...")
+	traceHostCall(context, "ext_storage_set_version_1")
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	ctx := instanceContext.Data().(*runtime.Context)
@@ -2078,6 +2572,7 @@ func ext_storage_set_version_1(context unsafe.Pointer, keySpan, valueSpan C.int6
 //export ext_storage_start_transaction_version_1
 func ext_storage_start_transaction_version_1(context unsafe.Pointer) {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_storage_start_transaction_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	instanceContext.Data().(*runtime.Context).Storage.BeginStorageTransaction()
 }
@@ -2085,6 +2580,7 @@ func ext_storage_start_transaction_version_1(context unsafe.Pointer) {
 //export ext_storage_rollback_transaction_version_1
 func ext_storage_rollback_transaction_version_1(context unsafe.Pointer) {
 	logger.Debug("executing...")
+	traceHostCall(context, "ext_storage_rollback_transaction_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	instanceContext.Data().(*runtime.Context).Storage.RollbackStorageTransaction()
 }
@@ -2092,6 +2588,7 @@ func ext_storage_rollback_transaction_version_1(context unsafe.Pointer) {
 //export ext_storage_commit_transaction_version_1
 func ext_storage_commit_transaction_version_1(context unsafe.Pointer) {
 	logger.Debug("[ext_storage_commit_transaction_version_1] executing...")
+	traceHostCall(context, "ext_storage_commit_transaction_version_1")
 	instanceContext := wasm.IntoInstanceContext(context)
 	instanceContext.Data().(*runtime.Context).Storage.CommitStorageTransaction()
 }
@@ -2123,6 +2620,29 @@ func toWasmMemory(context wasm.InstanceContext, data []byte) (int64, error) {
 	return runtime.PointerAndSizeToInt64(int32(out), int32(size)), nil
 }
 
+// traceHostCall records the host function call if tracing is enabled for the instance
+func traceHostCall(context unsafe.Pointer, name string) {
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+	runtimeCtx.Tracer.Record(runtime.TraceEvent{Type: runtime.TraceHostCall, Name: name})
+}
+
+// traceSignatureVerify records the signature verification if tracing is enabled for the instance
+func traceSignatureVerify(context wasm.InstanceContext, scheme string, pubKey, message, signature []byte) {
+	runtimeCtx := context.Data().(*runtime.Context)
+	if runtimeCtx.Tracer == nil {
+		return
+	}
+
+	runtimeCtx.Tracer.Record(runtime.TraceEvent{
+		Type:    runtime.TraceSignatureVerify,
+		Name:    scheme,
+		Key:     common.BytesToHex(pubKey),
+		Value:   common.BytesToHex(signature),
+		Message: common.BytesToHex(message),
+	})
+}
+
 // Copy a byte slice of a fixed size to wasm memory and return resulting pointer
 func toWasmMemorySized(context wasm.InstanceContext, data []byte, size uint32) (uint32, error) {
 	if int(size) != len(data) {
@@ -2329,6 +2849,10 @@ func ImportsNodeRuntime() (*wasm.Imports, error) { //nolint:gocyclo
 	if err != nil {
 		return nil, err
 	}
+	_, err = imports.Append("ext_default_child_storage_root_version_2", ext_default_child_storage_root_version_2, C.ext_default_child_storage_root_version_2)
+	if err != nil {
+		return nil, err
+	}
 	_, err = imports.Append("ext_default_child_storage_set_version_1", ext_default_child_storage_set_version_1, C.ext_default_child_storage_set_version_1)
 	if err != nil {
 		return nil, err
@@ -2453,6 +2977,22 @@ func ImportsNodeRuntime() (*wasm.Imports, error) { //nolint:gocyclo
 	if err != nil {
 		return nil, err
 	}
+	_, err = imports.Append("ext_offchain_http_request_write_body_version_1", ext_offchain_http_request_write_body_version_1, C.ext_offchain_http_request_write_body_version_1)
+	if err != nil {
+		return nil, err
+	}
+	_, err = imports.Append("ext_offchain_http_response_wait_version_1", ext_offchain_http_response_wait_version_1, C.ext_offchain_http_response_wait_version_1)
+	if err != nil {
+		return nil, err
+	}
+	_, err = imports.Append("ext_offchain_http_response_headers_version_1", ext_offchain_http_response_headers_version_1, C.ext_offchain_http_response_headers_version_1)
+	if err != nil {
+		return nil, err
+	}
+	_, err = imports.Append("ext_offchain_http_response_read_body_version_1", ext_offchain_http_response_read_body_version_1, C.ext_offchain_http_response_read_body_version_1)
+	if err != nil {
+		return nil, err
+	}
 	_, err = imports.Append("ext_sandbox_instance_teardown_version_1", ext_sandbox_instance_teardown_version_1, C.ext_sandbox_instance_teardown_version_1)
 	if err != nil {
 		return nil, err
@@ -2530,6 +3070,10 @@ func ImportsNodeRuntime() (*wasm.Imports, error) { //nolint:gocyclo
 	if err != nil {
 		return nil, err
 	}
+	_, err = imports.Append("ext_storage_root_version_2", ext_storage_root_version_2, C.ext_storage_root_version_2)
+	if err != nil {
+		return nil, err
+	}
 	_, err = imports.Append("ext_storage_set_version_1", ext_storage_set_version_1, C.ext_storage_set_version_1)
 	if err != nil {
 		return nil, err
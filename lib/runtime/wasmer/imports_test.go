@@ -6,6 +6,7 @@
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"net/http"
 	"os"
 	"sort"
@@ -22,6 +23,7 @@
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
 	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/trie"
 	"github.com/ChainSafe/gossamer/pkg/scale"
@@ -259,6 +261,51 @@ func Test_ext_offchain_local_storage_clear_version_1_Local(t *testing.T) {
 	require.Nil(t, val)
 }
 
+func Test_ext_offchain_local_storage_compare_and_set_version_1(t *testing.T) {
+	t.Parallel()
+	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME)
+
+	testkey := []byte("key1")
+	err := inst.NodeStorage().PersistentStorage.Put(testkey, []byte{1})
+	require.NoError(t, err)
+
+	encKind, err := scale.Marshal(int32(runtime.NodeStorageTypePersistent))
+	require.NoError(t, err)
+	encKey, err := scale.Marshal(testkey)
+	require.NoError(t, err)
+	encNewValue, err := scale.Marshal([]byte{3})
+	require.NoError(t, err)
+
+	wrongOldValue := []byte{2}
+	encOldValue, err := scale.Marshal(&wrongOldValue)
+	require.NoError(t, err)
+
+	args := append(append(append(encKind, encKey...), encOldValue...), encNewValue...)
+	ret, err := inst.Exec("rtm_ext_offchain_local_storage_compare_and_set_version_1", args)
+	require.NoError(t, err)
+
+	var set bool
+	err = scale.Unmarshal(ret, &set)
+	require.NoError(t, err)
+	require.False(t, set)
+
+	oldValue := []byte{1}
+	encOldValue, err = scale.Marshal(&oldValue)
+	require.NoError(t, err)
+
+	args = append(append(append(encKind, encKey...), encOldValue...), encNewValue...)
+	ret, err = inst.Exec("rtm_ext_offchain_local_storage_compare_and_set_version_1", args)
+	require.NoError(t, err)
+
+	err = scale.Unmarshal(ret, &set)
+	require.NoError(t, err)
+	require.True(t, set)
+
+	val, err := inst.NodeStorage().PersistentStorage.Get(testkey)
+	require.NoError(t, err)
+	require.Equal(t, []byte{3}, val)
+}
+
 func Test_ext_offchain_http_request_start_version_1(t *testing.T) {
 	t.Parallel()
 	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME)
@@ -381,6 +428,28 @@ func Test_ext_offchain_http_request_add_header(t *testing.T) {
 	}
 }
 
+func Test_encodeHTTPResult(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []byte{0, 5, 0, 0, 0}, encodeHTTPResult([]byte{5, 0, 0, 0}, nil))
+	require.Equal(t, []byte{1, 1}, encodeHTTPResult(nil, offchain.ErrDeadlineReached))
+	require.Equal(t, []byte{1, 2}, encodeHTTPResult(nil, fmt.Errorf("%w: refused", offchain.ErrIO)))
+	require.Equal(t, []byte{1, 3}, encodeHTTPResult(nil, offchain.ErrInvalidRequestID))
+}
+
+func Test_encodeHTTPRequestStatuses(t *testing.T) {
+	t.Parallel()
+
+	enc, err := encodeHTTPRequestStatuses([]offchain.RequestStatus{
+		{Code: http.StatusOK},
+		{Err: offchain.ErrDeadlineReached},
+		{Err: fmt.Errorf("%w: refused", offchain.ErrIO)},
+		{Err: offchain.ErrInvalidRequestID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{4 << 2, 3, 200, 0, 0, 1, 2}, enc)
+}
+
 func Test_ext_storage_clear_prefix_version_1_hostAPI(t *testing.T) {
 	t.Parallel()
 	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME)
@@ -14,6 +14,7 @@
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
 	"github.com/ChainSafe/gossamer/lib/trie"
 
 	"github.com/ChainSafe/gossamer/lib/crypto"
@@ -46,6 +47,7 @@ type Config struct {
 // Instance represents a v0.8 runtime go-wasmer instance
 type Instance struct {
 	vm       wasm.Instance
+	module   wasm.Module // shared with the clones of the instance, so it's never closed
 	ctx      *runtime.Context
 	version  runtime.Version
 	imports  func() (*wasm.Imports, error)
@@ -105,7 +107,42 @@ func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 
 	logger.Patch(log.SetLevel(cfg.LogLvl), log.SetCallerFunc(true))
 
-	imports, err := cfg.Imports()
+	module, err := compileModule(code)
+	if err != nil {
+		return nil, err
+	}
+
+	var storage runtime.Storage = cfg.Storage
+	if cfg.Tracer != nil && cfg.Storage != nil {
+		storage = runtime.NewTracedStorage(cfg.Storage, cfg.Tracer)
+	}
+
+	runtimeCtx := &runtime.Context{
+		Storage:         storage,
+		Keystore:        cfg.Keystore,
+		Validator:       cfg.Role == byte(4),
+		NodeStorage:     cfg.NodeStorage,
+		Network:         cfg.Network,
+		Transaction:     cfg.Transaction,
+		SigVerifier:     crypto.NewSignatureVerifier(logger),
+		OffchainHTTPSet: offchain.NewHTTPSet(),
+		Tracer:          cfg.Tracer,
+	}
+
+	inst, err := newInstanceFromModule(module, cfg.Imports, runtimeCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	inst.codeHash = cfg.CodeHash
+	inst.version, _ = inst.Version()
+	return inst, nil
+}
+
+// newInstanceFromModule instantiates the compiled module with the given context.
+func newInstanceFromModule(module wasm.Module, importsFunc func() (*wasm.Imports, error),
+	runtimeCtx *runtime.Context) (*Instance, error) {
+	imports, err := importsFunc()
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +161,7 @@ func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 	}
 
 	// Instantiates the WebAssembly module.
-	instance, err := wasm.NewInstanceWithImports(code, imports)
+	instance, err := module.InstantiateWithImports(imports)
 	if err != nil {
 		return nil, err
 	}
@@ -139,33 +176,55 @@ func NewInstance(code []byte, cfg *Config) (*Instance, error) {
 	}
 
 	allocator := runtime.NewAllocator(instance.Memory, heapBase)
-
-	runtimeCtx := &runtime.Context{
-		Storage:         cfg.Storage,
-		Allocator:       allocator,
-		Keystore:        cfg.Keystore,
-		Validator:       cfg.Role == byte(4),
-		NodeStorage:     cfg.NodeStorage,
-		Network:         cfg.Network,
-		Transaction:     cfg.Transaction,
-		SigVerifier:     crypto.NewSignatureVerifier(logger),
-		OffchainHTTPSet: offchain.NewHTTPSet(),
-	}
+	allocator.SetTracer(runtimeCtx.Tracer)
+	runtimeCtx.Allocator = allocator
 
 	logger.Debugf("NewInstance called with runtimeCtx: %v", runtimeCtx)
 	instance.SetContextData(runtimeCtx)
 
 	inst := &Instance{
-		vm:       instance,
-		ctx:      runtimeCtx,
-		imports:  cfg.Imports,
-		codeHash: cfg.CodeHash,
+		vm:      instance,
+		module:  module,
+		ctx:     runtimeCtx,
+		imports: importsFunc,
 	}
+	runtimeCtx.Sandbox = sandbox.NewStore(&sandboxSupervisor{instance: inst})
 
-	inst.version, _ = inst.Version()
 	return inst, nil
 }
 
+// Clone returns a new instance running the same code as the instance. The new instance shares
+// the compiled module of the instance, but it has its own memory and context.
+func (in *Instance) Clone() (runtime.Instance, error) {
+	in.Lock()
+	defer in.Unlock()
+
+	if in.isClosed {
+		return nil, errors.New("instance is stopped")
+	}
+
+	runtimeCtx := &runtime.Context{
+		Storage:         in.ctx.Storage,
+		Keystore:        in.ctx.Keystore,
+		Validator:       in.ctx.Validator,
+		NodeStorage:     in.ctx.NodeStorage,
+		Network:         in.ctx.Network,
+		Transaction:     in.ctx.Transaction,
+		SigVerifier:     crypto.NewSignatureVerifier(logger),
+		OffchainHTTPSet: offchain.NewHTTPSet(),
+		Tracer:          in.ctx.Tracer,
+	}
+
+	clone, err := newInstanceFromModule(in.module, in.imports, runtimeCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	clone.codeHash = in.codeHash
+	clone.version = in.version
+	return clone, nil
+}
+
 // decompressWasm decompresses a Wasm blob that may or may not be compressed with zstd
 // ref: https://github.com/paritytech/substrate/blob/master/primitives/maybe-compressed-blob/src/lib.rs
 func decompressWasm(code []byte) ([]byte, error) {
@@ -241,8 +300,13 @@ func (in *Instance) setupInstanceVM(code []byte) error {
 		return err
 	}
 
+	in.module, err = compileModule(code)
+	if err != nil {
+		return err
+	}
+
 	// Instantiates the WebAssembly module.
-	in.vm, err = wasm.NewInstanceWithImports(code, imports)
+	in.vm, err = in.module.InstantiateWithImports(imports)
 	if err != nil {
 		return err
 	}
@@ -265,6 +329,10 @@ func (in *Instance) setupInstanceVM(code []byte) error {
 func (in *Instance) SetContextStorage(s runtime.Storage) {
 	in.Lock()
 	defer in.Unlock()
+
+	if in.ctx.Tracer != nil {
+		s = runtime.NewTracedStorage(s, in.ctx.Tracer)
+	}
 	in.ctx.Storage = s
 }
 
@@ -339,6 +407,9 @@ func (in *Instance) malloc(size uint32) (uint32, error) {
 
 func (in *Instance) clear() {
 	in.ctx.Allocator.Clear()
+	in.ctx.Sandbox.Clear()
+	// a batch left unfinished by the call is discarded
+	in.ctx.SigVerifier.Reset()
 }
 
 // NodeStorage to get reference to runtime node service
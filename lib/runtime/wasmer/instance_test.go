@@ -6,6 +6,7 @@
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/lib/runtime"
@@ -27,6 +28,38 @@ func TestConcurrentRuntimeCalls(t *testing.T) {
 	}()
 }
 
+func TestInstance_Clone(t *testing.T) {
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+
+	clone, err := instance.Clone()
+	require.NoError(t, err)
+	require.Equal(t, instance.GetCodeHash(), clone.GetCodeHash())
+
+	expected, err := instance.Version()
+	require.NoError(t, err)
+
+	// the clone runs the same code in its own memory, so it can be called concurrently
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for _, in := range []runtime.Instance{instance, clone} {
+		go func(in runtime.Instance) {
+			defer wg.Done()
+			_, err := in.Exec(runtime.CoreVersion, []byte{})
+			require.NoError(t, err)
+		}(in)
+	}
+	wg.Wait()
+
+	// the compiled module is shared, stopping the clone leaves the instance usable
+	clone.Stop()
+	version, err := instance.Version()
+	require.NoError(t, err)
+	require.Equal(t, expected, version)
+
+	_, err = clone.Clone()
+	require.Error(t, err)
+}
+
 func TestPointerSize(t *testing.T) {
 	in := int64(8) + int64(32)<<32
 	ptr, length := runtime.Int64ToPointerAndSize(in)
@@ -0,0 +1,342 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wasmer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	wasm "github.com/wasmerio/go-ext-wasm/wasmer"
+)
+
+// DefaultModuleCacheSize is the default number of compiled modules kept in a module cache
+const DefaultModuleCacheSize = 8
+
+const (
+	wasmerModulePath     = "github.com/wasmerio/go-ext-wasm"
+	moduleCacheExtension = ".module"
+	moduleCacheTmpPrefix = "tmp-"
+
+	// moduleCacheFormatVersion is the version of the transformation of the runtime code before
+	// it is compiled, it must be increased when exportDispatchThunks changes the code differently
+	// so that the modules compiled from the previous transformation are not loaded
+	moduleCacheFormatVersion = 1
+)
+
+var (
+	// moduleCacheMagic prefixes the files of the module cache
+	moduleCacheMagic = []byte("gssmr-wasmer-module")
+
+	errInvalidCachedModule = errors.New("invalid cached module")
+
+	moduleCache     *ModuleCache
+	moduleCacheLock sync.RWMutex
+)
+
+// ModuleCache stores the compiled modules of runtimes in a directory, so that
+// the runtime code is not compiled again when an instance of it is created.
+// The modules are keyed by the hash of their code, the version of wasmer
+// which compiled them and the version of the transformation of the code, and
+// the least recently used modules are evicted once the cache holds more than
+// its size.
+type ModuleCache struct {
+	dir     string
+	size    int
+	version string
+	sync.Mutex
+}
+
+// NewModuleCache creates a module cache of the given size in the given directory
+func NewModuleCache(dir string, size int) (*ModuleCache, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("invalid module cache size %d", size)
+	}
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create module cache directory: %w", err)
+	}
+
+	err = removeTmpFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot remove temporary files of module cache: %w", err)
+	}
+
+	return &ModuleCache{
+		dir:     dir,
+		size:    size,
+		version: fmt.Sprintf("%s-v%d", wasmerVersion(), moduleCacheFormatVersion),
+	}, nil
+}
+
+// removeTmpFiles removes the temporary files left in the module cache directory by a node
+// which stopped while storing a module
+func removeTmpFiles(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), moduleCacheTmpPrefix) {
+			continue
+		}
+
+		logger.Debugf("removing temporary file %s of module cache", entry.Name())
+		err = os.Remove(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetModuleCache sets the module cache used to create instances, the runtime
+// code is compiled on each instance creation if the module cache is nil.
+func SetModuleCache(cache *ModuleCache) {
+	moduleCacheLock.Lock()
+	defer moduleCacheLock.Unlock()
+	moduleCache = cache
+}
+
+func getModuleCache() *ModuleCache {
+	moduleCacheLock.RLock()
+	defer moduleCacheLock.RUnlock()
+	return moduleCache
+}
+
+// wasmerVersion returns the version of the wasmer bindings the node is built with,
+// since modules compiled by a version of wasmer cannot be used by another version.
+func wasmerVersion() string {
+	version := "unknown"
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != wasmerModulePath {
+			continue
+		}
+
+		version = dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Path + "@" + dep.Replace.Version
+		}
+		break
+	}
+
+	// the version is part of file names
+	return strings.NewReplacer("/", "_", "\\", "_", "@", "_").Replace(version)
+}
+
+// compileModule compiles the given code with the exports of its sandbox dispatch thunks, or
+// loads its compiled module from the module cache
+func compileModule(code []byte) (wasm.Module, error) {
+	cache := getModuleCache()
+	if cache == nil {
+		return wasm.Compile(exportDispatchThunks(code))
+	}
+
+	codeHash, err := common.Blake2bHash(code)
+	if err != nil {
+		return wasm.Module{}, err
+	}
+
+	module, err := cache.load(codeHash)
+	if err == nil {
+		logger.Debugf("loaded compiled module of code hash %s from module cache", codeHash)
+		return module, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		logger.Warnf("failed to load compiled module of code hash %s from module cache: %s", codeHash, err)
+	}
+
+	module, err = wasm.Compile(exportDispatchThunks(code))
+	if err != nil {
+		return wasm.Module{}, err
+	}
+
+	err = cache.store(codeHash, module)
+	if err != nil {
+		logger.Warnf("failed to store compiled module of code hash %s in module cache: %s", codeHash, err)
+	}
+
+	return module, nil
+}
+
+func (c *ModuleCache) path(codeHash common.Hash) string {
+	name := fmt.Sprintf("%x-%s%s", codeHash[:], c.version, moduleCacheExtension)
+	return filepath.Join(c.dir, name)
+}
+
+// load returns the cached module of the given code hash. The cached module is
+// removed if it is invalid.
+func (c *ModuleCache) load(codeHash common.Hash) (wasm.Module, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	fp := c.path(codeHash)
+	data, err := os.ReadFile(filepath.Clean(fp))
+	if err != nil {
+		return wasm.Module{}, err
+	}
+
+	module, err := decodeCachedModule(data)
+	if err != nil {
+		if removeErr := os.Remove(fp); removeErr != nil {
+			logger.Warnf("failed to remove invalid cached module %s: %s", fp, removeErr)
+		}
+		return wasm.Module{}, err
+	}
+
+	// the modification time of a cached module is its last use, for eviction
+	now := time.Now()
+	err = os.Chtimes(fp, now, now)
+	if err != nil {
+		logger.Debugf("failed to update modification time of cached module %s: %s", fp, err)
+	}
+
+	return module, nil
+}
+
+// store writes the given module to the cache, then evicts the least recently
+// used modules if the cache holds more than its size.
+func (c *ModuleCache) store(codeHash common.Hash, module wasm.Module) error {
+	serialised, err := module.Serialize()
+	if err != nil {
+		return err
+	}
+
+	checksum, err := common.Blake2bHash(serialised)
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, 0, len(moduleCacheMagic)+len(checksum)+len(serialised))
+	data = append(data, moduleCacheMagic...)
+	data = append(data, checksum[:]...)
+	data = append(data, serialised...)
+
+	c.Lock()
+	defer c.Unlock()
+
+	// the module is written to a temporary file first, so that a module
+	// being written is never loaded
+	tmp, err := os.CreateTemp(c.dir, moduleCacheTmpPrefix+"*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(codeHash))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return c.evict()
+}
+
+// evict removes the modules compiled by other versions of wasmer or from another
+// version of the transformation of the code, and the least recently used modules
+// beyond the size of the cache.
+func (c *ModuleCache) evict() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	type cachedModule struct {
+		name    string
+		modTime time.Time
+	}
+
+	suffix := "-" + c.version + moduleCacheExtension
+	var modules []cachedModule
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, moduleCacheExtension) {
+			continue
+		}
+
+		if !strings.HasSuffix(name, suffix) {
+			logger.Debugf("removing cached module %s compiled by another version", name)
+			err = os.Remove(filepath.Join(c.dir, name))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		modules = append(modules, cachedModule{name: name, modTime: info.ModTime()})
+	}
+
+	if len(modules) <= c.size {
+		return nil
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].modTime.After(modules[j].modTime)
+	})
+
+	for _, module := range modules[c.size:] {
+		logger.Debugf("evicting cached module %s", module.name)
+		err = os.Remove(filepath.Join(c.dir, module.name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeCachedModule validates the checksum of a cached module, then deserialises it
+func decodeCachedModule(data []byte) (wasm.Module, error) {
+	headerLen := len(moduleCacheMagic) + len(common.Hash{})
+	if len(data) < headerLen || !bytes.HasPrefix(data, moduleCacheMagic) {
+		return wasm.Module{}, fmt.Errorf("%w: invalid header", errInvalidCachedModule)
+	}
+
+	checksum := common.NewHash(data[len(moduleCacheMagic):headerLen])
+	serialised := data[headerLen:]
+
+	expected, err := common.Blake2bHash(serialised)
+	if err != nil {
+		return wasm.Module{}, err
+	}
+
+	if checksum != expected {
+		return wasm.Module{}, fmt.Errorf("%w: checksum mismatch", errInvalidCachedModule)
+	}
+
+	module, err := wasm.DeserializeModule(serialised)
+	if err != nil {
+		return wasm.Module{}, fmt.Errorf("%w: %s", errInvalidCachedModule, err)
+	}
+
+	return module, nil
+}
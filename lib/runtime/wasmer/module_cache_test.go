@@ -0,0 +1,170 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wasmer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// emptyTestModule is a valid module without any section
+	emptyTestModule = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	// customTestModule is a valid module with an empty custom section
+	customTestModule = append(append([]byte{}, emptyTestModule...), 0x00, 0x02, 0x01, 'a')
+)
+
+func newTestModuleCache(t *testing.T, size int) *ModuleCache {
+	t.Helper()
+
+	cache, err := NewModuleCache(t.TempDir(), size)
+	require.NoError(t, err)
+
+	SetModuleCache(cache)
+	t.Cleanup(func() {
+		SetModuleCache(nil)
+	})
+
+	return cache
+}
+
+func TestModuleCache(t *testing.T) {
+	cache := newTestModuleCache(t, DefaultModuleCacheSize)
+
+	_, err := compileModule(emptyTestModule)
+	require.NoError(t, err)
+
+	codeHash := common.MustBlake2bHash(emptyTestModule)
+	fp := cache.path(codeHash)
+	require.FileExists(t, fp)
+
+	_, err = cache.load(codeHash)
+	require.NoError(t, err)
+
+	_, err = compileModule(emptyTestModule)
+	require.NoError(t, err)
+
+	_, err = cache.load(common.Hash{1})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestModuleCache_Invalid(t *testing.T) {
+	cache := newTestModuleCache(t, DefaultModuleCacheSize)
+
+	_, err := compileModule(emptyTestModule)
+	require.NoError(t, err)
+
+	codeHash := common.MustBlake2bHash(emptyTestModule)
+	fp := cache.path(codeHash)
+	data, err := os.ReadFile(fp)
+	require.NoError(t, err)
+
+	// corrupt the serialised module
+	data[len(data)-1]++
+	err = os.WriteFile(fp, data, os.ModePerm)
+	require.NoError(t, err)
+
+	_, err = cache.load(codeHash)
+	require.ErrorIs(t, err, errInvalidCachedModule)
+	require.NoFileExists(t, fp)
+
+	// the module is compiled and cached again
+	_, err = compileModule(emptyTestModule)
+	require.NoError(t, err)
+	require.FileExists(t, fp)
+
+	_, err = cache.load(codeHash)
+	require.NoError(t, err)
+}
+
+func TestModuleCache_Evict(t *testing.T) {
+	cache := newTestModuleCache(t, 1)
+
+	// a module compiled by another version of wasmer
+	staleFp := filepath.Join(cache.dir, "00-stale"+moduleCacheExtension)
+	err := os.WriteFile(staleFp, []byte{}, os.ModePerm)
+	require.NoError(t, err)
+
+	_, err = compileModule(emptyTestModule)
+	require.NoError(t, err)
+	require.NoFileExists(t, staleFp)
+
+	emptyFp := cache.path(common.MustBlake2bHash(emptyTestModule))
+	past := time.Now().Add(-time.Hour)
+	err = os.Chtimes(emptyFp, past, past)
+	require.NoError(t, err)
+
+	// the least recently used module is evicted
+	_, err = compileModule(customTestModule)
+	require.NoError(t, err)
+	require.NoFileExists(t, emptyFp)
+	require.FileExists(t, cache.path(common.MustBlake2bHash(customTestModule)))
+}
+
+func TestModuleCache_FormatVersion(t *testing.T) {
+	cache := newTestModuleCache(t, DefaultModuleCacheSize)
+
+	_, err := compileModule(emptyTestModule)
+	require.NoError(t, err)
+
+	// the module compiled from the code transformed by a previous version is not loaded
+	codeHash := common.MustBlake2bHash(emptyTestModule)
+	previous := &ModuleCache{
+		dir:     cache.dir,
+		size:    cache.size,
+		version: fmt.Sprintf("%s-v%d", wasmerVersion(), moduleCacheFormatVersion-1),
+	}
+	err = os.Rename(cache.path(codeHash), previous.path(codeHash))
+	require.NoError(t, err)
+
+	_, err = cache.load(codeHash)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = compileModule(emptyTestModule)
+	require.NoError(t, err)
+	require.NoFileExists(t, previous.path(codeHash))
+}
+
+func TestNewModuleCache_RemovesTmpFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	// a module being stored when the node stopped
+	tmpFp := filepath.Join(dir, moduleCacheTmpPrefix+"123")
+	err := os.WriteFile(tmpFp, []byte{1}, os.ModePerm)
+	require.NoError(t, err)
+
+	_, err = NewModuleCache(dir, DefaultModuleCacheSize)
+	require.NoError(t, err)
+	require.NoFileExists(t, tmpFp)
+}
+
+func TestModuleCache_NewInstance(t *testing.T) {
+	cache := newTestModuleCache(t, DefaultModuleCacheSize)
+
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+	expected, err := instance.Version()
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(cache.dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// the second instance is created from the cached module
+	instance = NewTestInstance(t, runtime.NODE_RUNTIME)
+	version, err := instance.Version()
+	require.NoError(t, err)
+	require.Equal(t, expected, version)
+}
+
+func TestNewModuleCache_InvalidSize(t *testing.T) {
+	_, err := NewModuleCache(t.TempDir(), 0)
+	require.Error(t, err)
+}
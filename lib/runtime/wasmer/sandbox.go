@@ -0,0 +1,68 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wasmer
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
+)
+
+var _ sandbox.Supervisor = (*sandboxSupervisor)(nil)
+
+// sandboxSupervisor calls the dispatch thunk of the runtime for the host functions of the
+// modules it instantiated with ext_sandbox_instantiate
+type sandboxSupervisor struct {
+	instance *Instance
+}
+
+// Dispatch calls the dispatch thunk at the given index of the table of the runtime, which was
+// exported by exportDispatchThunks. It is called while the runtime is executing a sandboxed
+// function, so the instance is already locked.
+func (s *sandboxSupervisor) Dispatch(thunk uint32, args []byte, state, function uint32) ([]byte, error) {
+	in := s.instance
+	fn, ok := in.vm.Exports[sandbox.DispatchThunkExport(thunk)]
+	if !ok {
+		return nil, fmt.Errorf("cannot find dispatch thunk %d", thunk)
+	}
+
+	ptr, err := in.malloc(uint32(len(args)))
+	if err != nil {
+		return nil, err
+	}
+
+	in.store(args, int32(ptr))
+
+	res, err := fn(int32(ptr), int32(len(args)), int32(state), int32(function))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = in.ctx.Allocator.Deallocate(ptr); err != nil {
+		return nil, err
+	}
+
+	offset, length := runtime.Int64ToPointerAndSize(res.ToI64())
+	out := make([]byte, length)
+	copy(out, in.load(offset, length))
+
+	if err = in.ctx.Allocator.Deallocate(uint32(offset)); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// exportDispatchThunks adds the exports of the dispatch thunks of the runtime used by the
+// sandbox, the code is left as is if it cannot be parsed
+func exportDispatchThunks(code []byte) []byte {
+	out, err := sandbox.ExportDispatchThunks(code)
+	if err != nil {
+		logger.Warnf("cannot export sandbox dispatch thunks, ext_sandbox_* will not be available: %s", err)
+		return code
+	}
+
+	return out
+}
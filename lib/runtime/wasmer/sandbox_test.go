@@ -0,0 +1,100 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wasmer
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/sandbox"
+
+	"github.com/go-interpreter/wagon/wasm"
+	"github.com/go-interpreter/wagon/wasm/leb128"
+	"github.com/stretchr/testify/require"
+)
+
+// testSection is a section of a wasm module
+type testSection struct {
+	id      byte
+	payload []byte
+}
+
+func readTestSections(t *testing.T, code []byte) []testSection {
+	t.Helper()
+
+	r := bytes.NewReader(code[8:])
+	var sections []testSection
+	for r.Len() > 0 {
+		id, err := r.ReadByte()
+		require.NoError(t, err)
+		size, err := leb128.ReadVarUint32(r)
+		require.NoError(t, err)
+
+		payload := make([]byte, size)
+		_, err = r.Read(payload)
+		require.NoError(t, err)
+		sections = append(sections, testSection{id: id, payload: payload})
+	}
+
+	return sections
+}
+
+func TestExportDispatchThunks_NodeRuntime(t *testing.T) {
+	const exportSection = 7
+
+	testRuntimeFilePath, testRuntimeURL := runtime.GetRuntimeVars(runtime.NODE_RUNTIME)
+	err := runtime.GetRuntimeBlob(testRuntimeFilePath, testRuntimeURL)
+	require.NoError(t, err)
+
+	code, err := os.ReadFile(testRuntimeFilePath)
+	require.NoError(t, err)
+
+	rewritten, err := sandbox.ExportDispatchThunks(code)
+	require.NoError(t, err)
+
+	// only the export section is rewritten, the code of the runtime is left as is
+	original, sections := readTestSections(t, code), readTestSections(t, rewritten)
+	require.Len(t, sections, len(original))
+	for i := range original {
+		require.Equal(t, original[i].id, sections[i].id)
+		if original[i].id != exportSection {
+			require.Equal(t, original[i].payload, sections[i].payload)
+		}
+	}
+
+	originalModule, err := wasm.DecodeModule(bytes.NewReader(code))
+	require.NoError(t, err)
+	module, err := wasm.DecodeModule(bytes.NewReader(rewritten))
+	require.NoError(t, err)
+
+	var thunks []string
+	for name, export := range module.Export.Entries {
+		originalExport, ok := originalModule.Export.Entries[name]
+		if ok {
+			require.Equal(t, originalExport, export)
+			continue
+		}
+
+		require.True(t, strings.HasPrefix(name, "sandbox_dispatch_thunk_"), name)
+		thunks = append(thunks, name)
+	}
+	require.Len(t, module.Export.Entries, len(originalModule.Export.Entries)+len(thunks))
+
+	// pallet-contracts instantiates its contracts with the dispatch thunk of the runtime
+	require.NotEmpty(t, thunks)
+
+	// the rewritten runtime is compiled and executed
+	instance := NewTestInstance(t, runtime.NODE_RUNTIME)
+	for _, name := range thunks {
+		_, ok := instance.vm.Exports[name]
+		require.True(t, ok, name)
+	}
+
+	version, err := instance.Version()
+	require.NoError(t, err)
+	require.Equal(t, "node", string(version.SpecName()))
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package signer
+
+import (
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+)
+
+// Local signs in process with a keypair of the local keystore
+type Local struct {
+	keypair crypto.Keypair
+}
+
+var _ VRFSigner = (*Local)(nil)
+
+// NewLocal returns a signer of the given keypair
+func NewLocal(kp crypto.Keypair) *Local {
+	return &Local{keypair: kp}
+}
+
+// Public returns the public key of the keypair
+func (l *Local) Public() crypto.PublicKey {
+	return l.keypair.Public()
+}
+
+// Sign signs the message with the keypair
+func (l *Local) Sign(msg []byte) ([]byte, error) {
+	return l.keypair.Sign(msg)
+}
+
+// VrfSign signs the VRF transcript with the keypair, which must be an sr25519 keypair
+func (l *Local) VrfSign(data *VRFTranscriptData) (
+	[sr25519.VRFOutputLength]byte, [sr25519.VRFProofLength]byte, error) {
+	kp, ok := l.keypair.(*sr25519.Keypair)
+	if !ok {
+		return [sr25519.VRFOutputLength]byte{}, [sr25519.VRFProofLength]byte{}, errNotVRFKey
+	}
+
+	return kp.VrfSign(data.Transcript())
+}
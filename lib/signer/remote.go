@@ -0,0 +1,291 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+)
+
+const (
+	// remoteTimeout bounds the requests to the remote signer, since a slot
+	// claim or a vote signed too late is useless
+	remoteTimeout = 2 * time.Second
+	// maxResponseSize bounds the size of the responses of the remote signer
+	maxResponseSize = 1 << 20
+
+	unixScheme = "unix"
+	// unixBaseURL is the base URL of requests sent over a unix socket, its host is ignored
+	unixBaseURL = "http://signer"
+
+	publicKeysPath = "/public_keys/"
+	signPath       = "/sign"
+	vrfSignPath    = "/vrf_sign"
+)
+
+// hexBytes is encoded in JSON as a 0x prefixed hex string
+type hexBytes []byte
+
+func (b hexBytes) MarshalText() ([]byte, error) {
+	return []byte(common.BytesToHex(b)), nil
+}
+
+func (b *hexBytes) UnmarshalText(text []byte) error {
+	decoded, err := common.HexToBytes(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+	return nil
+}
+
+type publicKeysResponse struct {
+	PublicKeys []hexBytes `json:"public_keys"`
+}
+
+type signRequest struct {
+	KeyType   keystore.Name `json:"key_type"`
+	PublicKey hexBytes      `json:"public_key"`
+	Message   hexBytes      `json:"message"`
+}
+
+type signResponse struct {
+	Signature hexBytes `json:"signature"`
+}
+
+type transcriptItem struct {
+	Label string   `json:"label"`
+	Data  hexBytes `json:"data"`
+}
+
+type vrfSignRequest struct {
+	KeyType         keystore.Name    `json:"key_type"`
+	PublicKey       hexBytes         `json:"public_key"`
+	TranscriptLabel string           `json:"transcript_label"`
+	TranscriptItems []transcriptItem `json:"transcript_items"`
+}
+
+type vrfSignResponse struct {
+	Output hexBytes `json:"output"`
+	Proof  hexBytes `json:"proof"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Client is a client of a remote signer, which holds the keys of the authority in a separate
+// process. The remote signer is reached over HTTP, or over HTTP on a unix socket for endpoints
+// of the form unix:///path/to/socket.
+type Client struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewClient returns a client of the remote signer at the given endpoint
+func NewClient(endpoint string) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer endpoint: %w", err)
+	}
+
+	switch u.Scheme {
+	case unixScheme:
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid remote signer endpoint %s: no socket path", endpoint)
+		}
+
+		socket := u.Path
+		dialer := &net.Dialer{}
+		return &Client{
+			client: &http.Client{
+				Timeout: remoteTimeout,
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						return dialer.DialContext(ctx, unixScheme, socket)
+					},
+				},
+			},
+			baseURL: unixBaseURL,
+		}, nil
+	case "http", "https":
+		return &Client{
+			client:  &http.Client{Timeout: remoteTimeout},
+			baseURL: strings.TrimSuffix(endpoint, "/"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid remote signer endpoint %s: unsupported scheme %q", endpoint, u.Scheme)
+	}
+}
+
+// PublicKeys returns the public keys of the given key type held by the remote signer
+func (c *Client) PublicKeys(keyType keystore.Name) ([]crypto.PublicKey, error) {
+	resp := new(publicKeysResponse)
+	err := c.do(http.MethodGet, publicKeysPath+string(keyType), nil, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	pubs := make([]crypto.PublicKey, len(resp.PublicKeys))
+	for i, enc := range resp.PublicKeys {
+		pubs[i], err = decodePublicKey(keystore.DetermineKeyType(string(keyType)), enc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidResult, err)
+		}
+	}
+
+	return pubs, nil
+}
+
+// Signer returns a signer of the key of the given type and public key held by the remote signer
+func (c *Client) Signer(keyType keystore.Name, pub crypto.PublicKey) *Remote {
+	return &Remote{
+		client:  c,
+		keyType: keyType,
+		public:  pub,
+	}
+}
+
+func (c *Client) do(method, path string, req, resp interface{}) error {
+	var body io.Reader
+	if req != nil {
+		enc, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(enc)
+	}
+
+	httpReq, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errRemoteSigner, err)
+	}
+	defer httpResp.Body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: %s", errRemoteSigner, err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		errResp := new(errorResponse)
+		if json.Unmarshal(data, errResp) != nil || errResp.Error == "" {
+			errResp.Error = httpResp.Status
+		}
+		return fmt.Errorf("%w: %s", errRemoteSigner, errResp.Error)
+	}
+
+	err = json.Unmarshal(data, resp)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidResult, err)
+	}
+
+	return nil
+}
+
+// Remote signs with a key held by a remote signer. The signatures returned by the remote
+// signer are verified before they are used.
+type Remote struct {
+	client  *Client
+	keyType keystore.Name
+	public  crypto.PublicKey
+}
+
+var _ VRFSigner = (*Remote)(nil)
+
+// Public returns the public key of the remote key
+func (r *Remote) Public() crypto.PublicKey {
+	return r.public
+}
+
+// Sign signs the message with the remote key
+func (r *Remote) Sign(msg []byte) ([]byte, error) {
+	resp := new(signResponse)
+	err := r.client.do(http.MethodPost, signPath, &signRequest{
+		KeyType:   r.keyType,
+		PublicKey: r.public.Encode(),
+		Message:   msg,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := r.public.Verify(msg, resp.Signature)
+	if err != nil || !ok {
+		return nil, fmt.Errorf("%w: invalid signature", errInvalidResult)
+	}
+
+	return resp.Signature, nil
+}
+
+// VrfSign signs the VRF transcript with the remote key, which must be an sr25519 key
+func (r *Remote) VrfSign(data *VRFTranscriptData) (
+	out [sr25519.VRFOutputLength]byte, proof [sr25519.VRFProofLength]byte, err error) {
+	pub, ok := r.public.(*sr25519.PublicKey)
+	if !ok {
+		return out, proof, errNotVRFKey
+	}
+
+	req := &vrfSignRequest{
+		KeyType:         r.keyType,
+		PublicKey:       pub.Encode(),
+		TranscriptLabel: data.Label,
+		TranscriptItems: make([]transcriptItem, len(data.Items)),
+	}
+	for i, item := range data.Items {
+		req.TranscriptItems[i] = transcriptItem{Label: item.Label, Data: item.Data}
+	}
+
+	resp := new(vrfSignResponse)
+	err = r.client.do(http.MethodPost, vrfSignPath, req, resp)
+	if err != nil {
+		return out, proof, err
+	}
+
+	if len(resp.Output) != sr25519.VRFOutputLength || len(resp.Proof) != sr25519.VRFProofLength {
+		return out, proof, fmt.Errorf("%w: invalid VRF output or proof length", errInvalidResult)
+	}
+
+	copy(out[:], resp.Output)
+	copy(proof[:], resp.Proof)
+
+	ok, err = pub.VrfVerify(data.Transcript(), out, proof)
+	if err != nil || !ok {
+		return out, proof, fmt.Errorf("%w: invalid VRF proof", errInvalidResult)
+	}
+
+	return out, proof, nil
+}
+
+func decodePublicKey(keyType crypto.KeyType, enc []byte) (crypto.PublicKey, error) {
+	switch keyType {
+	case crypto.Sr25519Type:
+		return sr25519.NewPublicKey(enc)
+	case crypto.Ed25519Type:
+		return ed25519.NewPublicKey(enc)
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", keyType)
+	}
+}
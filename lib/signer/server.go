@@ -0,0 +1,165 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+)
+
+// maxRequestSize bounds the size of the requests to the server
+const maxRequestSize = 1 << 20
+
+var errKeyNotFound = errors.New("key not found")
+
+// Server serves the keys of a keystore to the clients of a remote signer. It stands in
+// for an external signer in tests, and can be embedded in a separate signing process.
+type Server struct {
+	keystore *keystore.GlobalKeystore
+	mux      *http.ServeMux
+}
+
+// NewServer returns a server signing with the keys of the given keystore
+func NewServer(ks *keystore.GlobalKeystore) *Server {
+	s := &Server{
+		keystore: ks,
+		mux:      http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc(publicKeysPath, s.handlePublicKeys)
+	s.mux.HandleFunc(signPath, s.handleSign)
+	s.mux.HandleFunc(vrfSignPath, s.handleVrfSign)
+	return s
+}
+
+// ServeHTTP serves the requests of a remote signer client
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handlePublicKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	ks, err := s.keystore.GetKeystore([]byte(strings.TrimPrefix(r.URL.Path, publicKeysPath)))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	resp := &publicKeysResponse{PublicKeys: []hexBytes{}}
+	for _, pub := range ks.PublicKeys() {
+		resp.PublicKeys = append(resp.PublicKeys, pub.Encode())
+	}
+
+	writeResponse(w, resp)
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	req := new(signRequest)
+	if !readRequest(w, r, req) {
+		return
+	}
+
+	kp, err := s.keypair(req.KeyType, req.PublicKey)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	sig, err := kp.Sign(req.Message)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeResponse(w, &signResponse{Signature: sig})
+}
+
+func (s *Server) handleVrfSign(w http.ResponseWriter, r *http.Request) {
+	req := new(vrfSignRequest)
+	if !readRequest(w, r, req) {
+		return
+	}
+
+	kp, err := s.keypair(req.KeyType, req.PublicKey)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	data := NewVRFTranscriptData(req.TranscriptLabel)
+	for _, item := range req.TranscriptItems {
+		data.AppendMessage(item.Label, item.Data)
+	}
+
+	out, proof, err := NewLocal(kp).VrfSign(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeResponse(w, &vrfSignResponse{Output: out[:], Proof: proof[:]})
+}
+
+// keypair returns the keypair of the keystore of the given key type with the given public key
+func (s *Server) keypair(keyType keystore.Name, enc []byte) (crypto.Keypair, error) {
+	ks, err := s.keystore.GetKeystore([]byte(keyType))
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := decodePublicKey(ks.Type(), enc)
+	if err != nil {
+		return nil, err
+	}
+
+	kp := ks.GetKeypair(pub)
+	if kp == nil {
+		return nil, fmt.Errorf("%w: %s in keystore %s", errKeyNotFound, pub.Hex(), keyType)
+	}
+
+	return kp, nil
+}
+
+func readRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return false
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+
+	err = json.Unmarshal(data, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+
+	return true
+}
+
+func writeResponse(w http.ResponseWriter, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package signer
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+
+	"github.com/gtank/merlin"
+)
+
+var (
+	errNotVRFKey     = errors.New("key cannot sign VRF transcripts")
+	errRemoteSigner  = errors.New("remote signer error")
+	errInvalidResult = errors.New("invalid result from remote signer")
+)
+
+// Signer signs messages with the key of an authority, the key is either in the
+// local keystore or in a remote signer
+type Signer interface {
+	Public() crypto.PublicKey
+	Sign(msg []byte) ([]byte, error)
+}
+
+// VRFSigner is a Signer of an sr25519 key which also signs VRF transcripts,
+// it is used to claim BABE slots
+type VRFSigner interface {
+	Signer
+	VrfSign(data *VRFTranscriptData) ([sr25519.VRFOutputLength]byte, [sr25519.VRFProofLength]byte, error)
+}
+
+// VRFTranscriptData is the data of a merlin transcript, so that a transcript can be
+// sent to a remote signer.
+// see: https://github.com/paritytech/substrate/blob/master/primitives/core/src/sr25519.rs
+type VRFTranscriptData struct {
+	Label string              `json:"label"`
+	Items []VRFTranscriptItem `json:"items"`
+}
+
+// VRFTranscriptItem is a message appended to a transcript
+type VRFTranscriptItem struct {
+	Label string `json:"label"`
+	Data  []byte `json:"data"`
+}
+
+// NewVRFTranscriptData returns the data of a transcript with the given label
+func NewVRFTranscriptData(label string) *VRFTranscriptData {
+	return &VRFTranscriptData{Label: label}
+}
+
+// AppendMessage appends a message to the transcript data
+func (d *VRFTranscriptData) AppendMessage(label string, data []byte) {
+	d.Items = append(d.Items, VRFTranscriptItem{Label: label, Data: data})
+}
+
+// AppendUint64 appends a little endian encoded uint64 to the transcript data
+func (d *VRFTranscriptData) AppendUint64(label string, n uint64) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, n)
+	d.AppendMessage(label, buf)
+}
+
+// Transcript returns the transcript of the data
+func (d *VRFTranscriptData) Transcript() *merlin.Transcript {
+	t := merlin.NewTranscript(d.Label)
+	for _, item := range d.Items {
+		t.AppendMessage([]byte(item.Label), item.Data)
+	}
+	return t
+}
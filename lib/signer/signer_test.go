@@ -0,0 +1,201 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package signer
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+
+	"github.com/gtank/merlin"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeystore(t *testing.T) *keystore.GlobalKeystore {
+	t.Helper()
+
+	ks := keystore.NewGlobalKeystore()
+
+	srKr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	err = ks.Babe.Insert(srKr.Alice())
+	require.NoError(t, err)
+
+	edKr, err := keystore.NewEd25519Keyring()
+	require.NoError(t, err)
+	err = ks.Gran.Insert(edKr.Alice())
+	require.NoError(t, err)
+
+	return ks
+}
+
+func newTestTranscriptData() *VRFTranscriptData {
+	data := NewVRFTranscriptData("BABE")
+	data.AppendUint64("slot number", 1)
+	data.AppendUint64("current epoch", 2)
+	data.AppendMessage("chain randomness", make([]byte, 32))
+	return data
+}
+
+func TestVRFTranscriptData_Transcript(t *testing.T) {
+	expected := merlin.NewTranscript("BABE")
+	crypto.AppendUint64(expected, []byte("slot number"), 1)
+	crypto.AppendUint64(expected, []byte("current epoch"), 2)
+	expected.AppendMessage([]byte("chain randomness"), make([]byte, 32))
+
+	transcript := newTestTranscriptData().Transcript()
+	require.Equal(t, expected.ExtractBytes([]byte("test"), 32), transcript.ExtractBytes([]byte("test"), 32))
+}
+
+func TestLocal(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	s := NewLocal(kp)
+	require.Equal(t, kp.Public(), s.Public())
+
+	msg := []byte("noot")
+	sig, err := s.Sign(msg)
+	require.NoError(t, err)
+	ok, err := kp.Public().Verify(msg, sig)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	data := newTestTranscriptData()
+	out, proof, err := s.VrfSign(data)
+	require.NoError(t, err)
+	ok, err = kp.Public().(*sr25519.PublicKey).VrfVerify(data.Transcript(), out, proof)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	edKp, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+	_, _, err = NewLocal(edKp).VrfSign(data)
+	require.ErrorIs(t, err, errNotVRFKey)
+}
+
+func testRemote(t *testing.T, client *Client, ks *keystore.GlobalKeystore) {
+	t.Helper()
+
+	pubs, err := client.PublicKeys(keystore.BabeName)
+	require.NoError(t, err)
+	require.Len(t, pubs, 1)
+	require.Equal(t, ks.Babe.PublicKeys()[0].Encode(), pubs[0].Encode())
+
+	babeSigner := client.Signer(keystore.BabeName, pubs[0])
+	require.Equal(t, pubs[0], babeSigner.Public())
+
+	msg := []byte("noot")
+	sig, err := babeSigner.Sign(msg)
+	require.NoError(t, err)
+	ok, err := pubs[0].Verify(msg, sig)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	data := newTestTranscriptData()
+	out, proof, err := babeSigner.VrfSign(data)
+	require.NoError(t, err)
+	ok, err = pubs[0].(*sr25519.PublicKey).VrfVerify(data.Transcript(), out, proof)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	pubs, err = client.PublicKeys(keystore.GranName)
+	require.NoError(t, err)
+	require.Len(t, pubs, 1)
+	require.Equal(t, ks.Gran.PublicKeys()[0].Encode(), pubs[0].Encode())
+
+	granSigner := client.Signer(keystore.GranName, pubs[0])
+	sig, err = granSigner.Sign(msg)
+	require.NoError(t, err)
+	ok, err = pubs[0].Verify(msg, sig)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, _, err = granSigner.VrfSign(data)
+	require.ErrorIs(t, err, errNotVRFKey)
+
+	// the remote signer does not hold the key
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	_, err = client.Signer(keystore.BabeName, kp.Public()).Sign(msg)
+	require.ErrorIs(t, err, errRemoteSigner)
+
+	_, err = client.PublicKeys("noot")
+	require.ErrorIs(t, err, errRemoteSigner)
+}
+
+func TestRemote_HTTP(t *testing.T) {
+	ks := newTestKeystore(t)
+	server := httptest.NewServer(NewServer(ks))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+
+	testRemote(t, client, ks)
+}
+
+func TestRemote_Unix(t *testing.T) {
+	ks := newTestKeystore(t)
+
+	socket := filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: NewServer(ks)}
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	client, err := NewClient("unix://" + socket)
+	require.NoError(t, err)
+
+	testRemote(t, client, ks)
+}
+
+func TestRemote_InvalidResult(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	// a remote signer signing with another key
+	other, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case signPath:
+			writeResponse(w, &signResponse{Signature: make([]byte, 64)})
+		case vrfSignPath:
+			out, proof, err := NewLocal(other).VrfSign(newTestTranscriptData())
+			require.NoError(t, err)
+			writeResponse(w, &vrfSignResponse{Output: out[:], Proof: proof[:]})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+
+	s := client.Signer(keystore.BabeName, kp.Public())
+	_, err = s.Sign([]byte("noot"))
+	require.ErrorIs(t, err, errInvalidResult)
+
+	_, _, err = s.VrfSign(newTestTranscriptData())
+	require.ErrorIs(t, err, errInvalidResult)
+}
+
+func TestNewClient_Invalid(t *testing.T) {
+	for _, endpoint := range []string{"ftp://localhost", "unix://", "localhost:8000", "::"} {
+		_, err := NewClient(endpoint)
+		require.Error(t, err, endpoint)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package transaction
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// DefaultBanDuration is how long a transaction stays banned after being removed from the pool
+const DefaultBanDuration = 30 * time.Minute
+
+// BanList is a thread safe set of temporarily banned transaction hashes
+type BanList struct {
+	duration time.Duration
+	banned   map[common.Hash]time.Time // maps a transaction hash to the time its ban expires
+	mu       sync.Mutex
+
+	now func() time.Time
+}
+
+// NewBanList returns a new BanList where each ban lasts for the given duration
+func NewBanList(duration time.Duration) *BanList {
+	return &BanList{
+		duration: duration,
+		banned:   make(map[common.Hash]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Ban bans the given transaction hashes, resetting the ban window of already banned hashes
+func (b *BanList) Ban(hashes ...common.Hash) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.pruneExpired(now)
+
+	expiry := now.Add(b.duration)
+	for _, hash := range hashes {
+		b.banned[hash] = expiry
+	}
+}
+
+// IsBanned returns true if the transaction hash is currently banned
+func (b *BanList) IsBanned(hash common.Hash) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expiry, ok := b.banned[hash]
+	if !ok {
+		return false
+	}
+
+	if !b.now().Before(expiry) {
+		delete(b.banned, hash)
+		return false
+	}
+
+	return true
+}
+
+// Len returns the number of hashes in the ban list, including expired ones not pruned yet
+func (b *BanList) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.banned)
+}
+
+func (b *BanList) pruneExpired(now time.Time) {
+	for hash, expiry := range b.banned {
+		if !now.Before(expiry) {
+			delete(b.banned, hash)
+		}
+	}
+}
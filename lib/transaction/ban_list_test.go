@@ -0,0 +1,48 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBanList(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	b := NewBanList(time.Minute)
+	b.now = func() time.Time { return now }
+
+	hashA := common.Hash{1}
+	hashB := common.Hash{2}
+
+	b.Ban(hashA)
+	require.True(t, b.IsBanned(hashA))
+	require.False(t, b.IsBanned(hashB))
+
+	now = now.Add(30 * time.Second)
+	b.Ban(hashB)
+	require.True(t, b.IsBanned(hashA))
+	require.True(t, b.IsBanned(hashB))
+
+	// hashA ban expires
+	now = now.Add(30 * time.Second)
+	require.False(t, b.IsBanned(hashA))
+	require.True(t, b.IsBanned(hashB))
+	require.Equal(t, 1, b.Len())
+
+	// banning again resets the window
+	b.Ban(hashB)
+	now = now.Add(45 * time.Second)
+	require.True(t, b.IsBanned(hashB))
+
+	// expired entries are pruned on ban
+	now = now.Add(time.Minute)
+	b.Ban(hashA)
+	require.Equal(t, 1, b.Len())
+	require.False(t, b.IsBanned(hashB))
+}
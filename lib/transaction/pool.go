@@ -63,6 +63,13 @@ func (p *Pool) Remove(hash common.Hash) {
 	transactionPoolGauge.Set(float64(len(p.transactions)))
 }
 
+// Get returns the transaction with the given hash, or nil if the pool doesn't contain it
+func (p *Pool) Get(hash common.Hash) *ValidTransaction {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.transactions[hash]
+}
+
 // Len return the current length of the pool
 func (p *Pool) Len() int {
 	p.mu.Lock()
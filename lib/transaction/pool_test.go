@@ -48,8 +48,13 @@ func TestPool(t *testing.T) {
 	})
 	require.Equal(t, tests, transactions)
 
+	for i, h := range hashes {
+		require.Equal(t, tests[i], p.Get(h))
+	}
+
 	for _, h := range hashes {
 		p.Remove(h)
 	}
 	require.Equal(t, 0, len(p.Transactions()))
+	require.Nil(t, p.Get(hashes[0]))
 }
@@ -109,6 +109,24 @@ func (spq *PriorityQueue) RemoveExtrinsic(ext types.Extrinsic) {
 	delete(spq.txs, hash)
 }
 
+// RemoveByHash removes the transaction with the given hash from the queue and returns it,
+// or nil if the queue doesn't contain it
+func (spq *PriorityQueue) RemoveByHash(hash common.Hash) *ValidTransaction {
+	spq.Lock()
+	defer spq.Unlock()
+
+	item, ok := spq.txs[hash]
+	if !ok {
+		return nil
+	}
+
+	heap.Remove(&spq.pq, item.index)
+	delete(spq.txs, hash)
+
+	transactionQueueGauge.Set(float64(spq.pq.Len()))
+	return item.data
+}
+
 // Push inserts a valid transaction with priority p into the queue
 func (spq *PriorityQueue) Push(txn *ValidTransaction) (common.Hash, error) {
 	spq.Lock()
@@ -6,6 +6,8 @@
 import (
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestPriorityQueue(t *testing.T) {
@@ -246,3 +248,30 @@ func TestRemoveExtrinsic(t *testing.T) {
 		t.Fatalf("Fail: got %v expected %v", res, tests[1])
 	}
 }
+
+func TestRemoveByHash(t *testing.T) {
+	tests := []*ValidTransaction{
+		{
+			Extrinsic: []byte("rats"),
+			Validity:  &Validity{Priority: 5},
+		},
+		{
+			Extrinsic: []byte("arecool"),
+			Validity:  &Validity{Priority: 4},
+		},
+	}
+
+	pq := NewPriorityQueue()
+
+	for _, node := range tests {
+		pq.Push(node)
+	}
+
+	removed := pq.RemoveByHash(tests[0].Extrinsic.Hash())
+	require.Equal(t, tests[0], removed)
+	require.Nil(t, pq.RemoveByHash(tests[0].Extrinsic.Hash()))
+	require.Equal(t, 1, pq.Len())
+
+	res := pq.Pop()
+	require.Equal(t, tests[1], res)
+}
@@ -56,6 +56,11 @@ func (t *Trie) store(db chaindb.Batch, n Node) error {
 		return err
 	}
 
+	err = storeHashedValue(db, n)
+	if err != nil {
+		return err
+	}
+
 	switch n.Type() {
 	case node.BranchType, node.BranchWithValueType:
 		branch := n.(*node.Branch)
@@ -85,22 +90,39 @@ func (t *Trie) loadFromProof(rawProof [][]byte, rootHash []byte) error {
 	}
 
 	proofHashToNode := make(map[string]Node, len(rawProof))
+	// proofHashToValue holds all the proof entries by their blake2b hash,
+	// since the values encoded as their hash with the state version 1
+	// are proof entries of their own.
+	proofHashToValue := make(map[string][]byte, len(rawProof))
+	// undecodable holds the indexes of the proof entries which cannot be
+	// decoded as nodes, which must be values of nodes of the proof.
+	var undecodable []int
+	valueHashes := make([]string, len(rawProof))
 
 	for i, rawNode := range rawProof {
+		digest, err := common.Blake2bHash(rawNode)
+		if err != nil {
+			return fmt.Errorf("cannot hash proof entry at index %d: %w", i, err)
+		}
+		valueHashes[i] = common.BytesToHex(digest[:])
+		proofHashToValue[valueHashes[i]] = rawNode
+
 		decodedNode, err := node.Decode(bytes.NewReader(rawNode))
 		if err != nil {
-			return fmt.Errorf("%w: at index %d: 0x%x",
-				ErrDecodeNode, i, rawNode)
+			undecodable = append(undecodable, i)
+			continue
+		}
+
+		// the node is not re-encoded to compute its hash, since
+		// its hashed value may not be part of the proof.
+		hash := digest[:]
+		if len(rawNode) < 32 {
+			hash = rawNode
 		}
 
 		const dirty = false
 		decodedNode.SetDirty(dirty)
-		decodedNode.SetEncodingAndHash(rawNode, nil)
-
-		_, hash, err := decodedNode.EncodeAndHash()
-		if err != nil {
-			return fmt.Errorf("cannot encode and hash node at index %d: %w", i, err)
-		}
+		decodedNode.SetEncodingAndHash(rawNode, hash)
 
 		proofHash := common.BytesToHex(hash)
 		proofHashToNode[proofHash] = decodedNode
@@ -111,6 +133,35 @@ func (t *Trie) loadFromProof(rawProof [][]byte, rootHash []byte) error {
 		}
 	}
 
+	referencedValues := make(map[string]struct{})
+	for _, decodedNode := range proofHashToNode {
+		if !decodedNode.IsHashedValue() {
+			continue
+		}
+
+		valueHash := common.BytesToHex(decodedNode.GetValue())
+		value, ok := proofHashToValue[valueHash]
+		if !ok {
+			// the value is not part of the proof, so it is
+			// cleared to not be mistaken for the value itself.
+			decodedNode.SetValue(nil)
+			continue
+		}
+		referencedValues[valueHash] = struct{}{}
+		decodedNode.SetValue(value)
+	}
+
+	for _, i := range undecodable {
+		if _, ok := referencedValues[valueHashes[i]]; !ok {
+			return fmt.Errorf("%w: at index %d: 0x%x",
+				ErrDecodeNode, i, rawProof[i])
+		}
+	}
+
+	if t.root == nil {
+		return fmt.Errorf("%w: root 0x%x not found in proof", ErrEmptyTrieRoot, rootHash)
+	}
+
 	t.loadProof(proofHashToNode, t.root)
 
 	return nil
@@ -168,6 +219,11 @@ func (t *Trie) Load(db chaindb.Database, rootHash common.Hash) error {
 	t.root.SetDirty(false)
 	t.root.SetEncodingAndHash(encodedNode, rootHashBytes)
 
+	err = loadHashedValue(db, t.root)
+	if err != nil {
+		return fmt.Errorf("cannot load value of root node: %w", err)
+	}
+
 	return t.load(db, t.root)
 }
 
@@ -201,6 +257,11 @@ func (t *Trie) load(db chaindb.Database, n Node) error {
 		decodedNode.SetEncodingAndHash(encodedNode, hash)
 		branch.Children[i] = decodedNode
 
+		err = loadHashedValue(db, decodedNode)
+		if err != nil {
+			return fmt.Errorf("cannot load value of node with hash 0x%x: %w", hash, err)
+		}
+
 		err = t.load(db, decodedNode)
 		if err != nil {
 			return fmt.Errorf("cannot load child at index %d with hash 0x%x: %w", i, hash, err)
@@ -215,11 +276,9 @@ func (t *Trie) load(db chaindb.Database, n Node) error {
 			return fmt.Errorf("failed to load child trie with root hash=0x%x: %w", value, err)
 		}
 
-		err = t.PutChild(value, childTrie)
-		if err != nil {
-			return fmt.Errorf("failed to insert child trie with root hash=0x%x into main trie: %w",
-				childTrie.root.GetHash(), err)
-		}
+		// the child trie root is already stored at key, so only register
+		// the child trie without modifying the main trie.
+		t.childTries[common.NewHash(value)] = childTrie
 	}
 
 	return nil
@@ -308,7 +367,7 @@ func getFromDB(db chaindb.Database, n Node, key []byte) (
 	leaf, ok := n.(*node.Leaf)
 	if ok {
 		if bytes.Equal(leaf.Key, key) {
-			return leaf.Value, nil
+			return getValueFromDB(db, leaf)
 		}
 		return nil, nil
 	}
@@ -316,7 +375,7 @@ func getFromDB(db chaindb.Database, n Node, key []byte) (
 	branch := n.(*node.Branch)
 	// Key is equal to the key of this branch or is empty
 	if len(key) == 0 || bytes.Equal(branch.Key, key) {
-		return branch.Value, nil
+		return getValueFromDB(db, branch)
 	}
 
 	commonPrefixLength := lenCommonPrefix(branch.Key, key)
@@ -354,6 +413,59 @@ func getFromDB(db chaindb.Database, n Node, key []byte) (
 	// Note: do not wrap error since it's called recursively.
 }
 
+// getValueFromDB returns the value of the node given, reading it
+// from the database if it is encoded as its hash in the node.
+func getValueFromDB(db chaindb.Database, n Node) (value []byte, err error) {
+	if !n.IsHashedValue() {
+		return n.GetValue(), nil
+	}
+
+	value, err = db.Get(n.GetValue())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot find value with hash 0x%x in database: %w",
+			n.GetValue(), err)
+	}
+
+	return value, nil
+}
+
+// loadHashedValue replaces the hash of the value of the node given
+// by the value read from the database, if the value of the node
+// is encoded as its hash.
+func loadHashedValue(db chaindb.Database, n Node) (err error) {
+	if !n.IsHashedValue() {
+		return nil
+	}
+
+	value, err := getValueFromDB(db, n)
+	if err != nil {
+		return err
+	}
+
+	n.SetValue(value)
+	return nil
+}
+
+// storeHashedValue puts the value of the node given in the database
+// with its hash as key, if the value of the node is encoded as its hash.
+// Note the stored values are tracked apart from the inserted and deleted
+// node hashes, with a count for each value since a value can be shared
+// by several nodes.
+func storeHashedValue(db chaindb.Batch, n Node) (err error) {
+	if !n.IsHashedValue() {
+		return nil
+	}
+
+	value := n.GetValue()
+	hash, err := common.Blake2bHash(value)
+	if err != nil {
+		return fmt.Errorf("cannot hash value: %w", err)
+	}
+
+	return db.Put(hash[:], value)
+}
+
 // WriteDirty writes all dirty nodes to the database and sets them to clean
 func (t *Trie) WriteDirty(db chaindb.Database) error {
 	batch := db.NewBatch()
@@ -395,6 +507,13 @@ func (t *Trie) writeDirty(db chaindb.Batch, n Node) error {
 			hash, err)
 	}
 
+	err = storeHashedValue(db, n)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot store value of node with hash 0x%x: %w",
+			hash, err)
+	}
+
 	switch n.Type() {
 	case node.BranchType, node.BranchWithValueType:
 	default: // not a branch
@@ -499,3 +618,65 @@ func (t *Trie) GetDeletedNodeHashes() (hashesSet map[common.Hash]struct{}) {
 	}
 	return hashesSet
 }
+
+// GetInsertedValueHashes returns the hashes of the values stored
+// separately from their nodes, as their hash, for all the nodes that
+// were inserted in the state trie since the last snapshot, along with
+// the number of nodes holding each value.
+func (t *Trie) GetInsertedValueHashes() (hashes map[common.Hash]uint32, err error) {
+	hashes = make(map[common.Hash]uint32)
+	err = t.getInsertedValueHashes(t.root, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+func (t *Trie) getInsertedValueHashes(n Node, hashes map[common.Hash]uint32) (err error) {
+	if n == nil || !n.IsDirty() {
+		return nil
+	}
+
+	if n.IsHashedValue() {
+		hash, err := common.Blake2bHash(n.GetValue())
+		if err != nil {
+			return fmt.Errorf("cannot hash value: %w", err)
+		}
+		hashes[hash]++
+	}
+
+	switch n.Type() {
+	case node.BranchType, node.BranchWithValueType:
+	default: // not a branch
+		return nil
+	}
+
+	branch := n.(*node.Branch)
+
+	for _, child := range branch.Children {
+		if child == nil {
+			continue
+		}
+
+		err := t.getInsertedValueHashes(child, hashes)
+		if err != nil {
+			// Note: do not wrap error since this is called recursively.
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetDeletedValueHashes returns the hashes of the values stored
+// separately from their nodes which were deleted from the trie since
+// the last snapshot was made, along with the number of times each
+// value was deleted.
+// The returned map is a copy of the internal map to prevent data races.
+func (t *Trie) GetDeletedValueHashes() (hashes map[common.Hash]uint32) {
+	hashes = make(map[common.Hash]uint32, len(t.deletedValues))
+	for k, count := range t.deletedValues {
+		hashes[k] = count
+	}
+	return hashes
+}
@@ -9,6 +9,7 @@
 	"testing"
 
 	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -75,6 +76,30 @@ func TestTrie_DatabaseStoreAndLoad(t *testing.T) {
 	}
 }
 
+func TestTrie_DatabaseStoreAndLoad_WithChildTrie(t *testing.T) {
+	child := NewEmptyTrie()
+	child.Put([]byte("childkey"), []byte("childvalue-that-is-longer-than-32-bytes"))
+
+	trie := NewEmptyTrie()
+	trie.Put([]byte("noot"), []byte("washere-and-is-longer-than-32-bytes"))
+	err := trie.PutChild([]byte("child"), child)
+	require.NoError(t, err)
+
+	db := newTestDB(t)
+	err = trie.Store(db)
+	require.NoError(t, err)
+
+	res := NewEmptyTrie()
+	err = res.Load(db, trie.MustHash())
+	require.NoError(t, err)
+	require.Equal(t, trie.MustHash(), res.MustHash())
+	require.Equal(t, trie.Entries(), res.Entries())
+
+	loadedChild, err := res.GetChild([]byte("child"))
+	require.NoError(t, err)
+	require.Equal(t, child.MustHash(), loadedChild.MustHash())
+}
+
 func TestTrie_WriteDirty_Put(t *testing.T) {
 	cases := [][]Test{
 		{
@@ -387,3 +412,81 @@ func TestTrie_GetFromDB(t *testing.T) {
 		}
 	}
 }
+
+func TestTrie_DatabaseStoreAndLoad_HashedValues(t *testing.T) {
+	longValue := bytes.Repeat([]byte{1}, MaxInlineValue+1)
+
+	trie := NewEmptyTrie()
+	trie.Put([]byte{0x01, 0x35}, longValue)
+	trie.Put([]byte{0x01, 0x35, 0x79}, []byte("penguin"))
+	trie.Put([]byte{0x01, 0x35, 0x7}, bytes.Repeat([]byte{2}, 100))
+	trie.Put([]byte{0xf2}, longValue)
+
+	rootHash, err := trie.HashWithVersion(V1)
+	require.NoError(t, err)
+
+	db := newTestDB(t)
+	err = trie.Store(db)
+	require.NoError(t, err)
+
+	res := NewEmptyTrie()
+	err = res.Load(db, rootHash)
+	require.NoError(t, err)
+	require.Equal(t, rootHash, res.MustHash())
+	require.Equal(t, trie.Entries(), res.Entries())
+
+	for key, value := range trie.Entries() {
+		val, err := GetFromDB(db, rootHash, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value, val)
+	}
+
+	res.Put([]byte{0xf2, 0x3}, longValue)
+	rootHash, err = res.HashWithVersion(V1)
+	require.NoError(t, err)
+
+	err = res.WriteDirty(db)
+	require.NoError(t, err)
+
+	val, err := GetFromDB(db, rootHash, []byte{0xf2, 0x3})
+	require.NoError(t, err)
+	require.Equal(t, longValue, val)
+}
+
+func TestTrie_GetValueHashes(t *testing.T) {
+	longValue := bytes.Repeat([]byte{1}, MaxInlineValue+1)
+	otherValue := bytes.Repeat([]byte{2}, MaxInlineValue+1)
+	longHash, err := common.Blake2bHash(longValue)
+	require.NoError(t, err)
+	otherHash, err := common.Blake2bHash(otherValue)
+	require.NoError(t, err)
+
+	trie := NewEmptyTrie()
+	trie.Put([]byte{0x01, 0x35}, longValue)
+	trie.Put([]byte{0x01, 0x35, 0x79}, []byte("penguin"))
+	trie.Put([]byte{0xf2}, longValue)
+
+	_, err = trie.HashWithVersion(V1)
+	require.NoError(t, err)
+
+	// the value is held by two nodes
+	inserted, err := trie.GetInsertedValueHashes()
+	require.NoError(t, err)
+	require.Equal(t, map[common.Hash]uint32{longHash: 2}, inserted)
+
+	db := newTestDB(t)
+	err = trie.WriteDirty(db)
+	require.NoError(t, err)
+
+	trie = trie.Snapshot()
+	trie.Put([]byte{0xf2}, otherValue)
+	trie.Delete([]byte{0x01, 0x35})
+
+	_, err = trie.HashWithVersion(V1)
+	require.NoError(t, err)
+
+	inserted, err = trie.GetInsertedValueHashes()
+	require.NoError(t, err)
+	require.Equal(t, map[common.Hash]uint32{otherHash: 1}, inserted)
+	require.Equal(t, map[common.Hash]uint32{longHash: 2}, trie.GetDeletedValueHashes())
+}
@@ -8,6 +8,7 @@
 
 	"github.com/ChainSafe/gossamer/internal/trie/node"
 	"github.com/ChainSafe/gossamer/internal/trie/record"
+	"github.com/ChainSafe/gossamer/lib/common"
 )
 
 var _ recorder = (*record.Recorder)(nil)
@@ -32,6 +33,9 @@ func find(parent Node, key []byte, recorder recorder) error {
 	switch parent.Type() {
 	case node.BranchType, node.BranchWithValueType:
 	default: // not a branch
+		if bytes.Equal(parent.(*node.Leaf).Key, key) {
+			return recordHashedValue(parent, recorder)
+		}
 		return nil
 	}
 
@@ -41,7 +45,7 @@ func find(parent Node, key []byte, recorder recorder) error {
 
 	// found the value at this node
 	if bytes.Equal(b.Key, key) || len(key) == 0 {
-		return nil
+		return recordHashedValue(b, recorder)
 	}
 
 	// did not find value
@@ -51,3 +55,21 @@ func find(parent Node, key []byte, recorder recorder) error {
 
 	return find(b.Children[key[length]], key[length+1:], recorder)
 }
+
+// recordHashedValue records the value of the node given if
+// it is encoded as its hash, since it is then not part of
+// the encoding of the node.
+func recordHashedValue(n Node, recorder recorder) error {
+	if !n.IsHashedValue() {
+		return nil
+	}
+
+	value := n.GetValue()
+	hash, err := common.Blake2bHash(value)
+	if err != nil {
+		return err
+	}
+
+	recorder.Record(hash[:], value)
+	return nil
+}
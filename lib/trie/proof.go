@@ -11,6 +11,7 @@
 
 	"github.com/ChainSafe/chaindb"
 	"github.com/ChainSafe/gossamer/internal/trie/codec"
+	"github.com/ChainSafe/gossamer/internal/trie/node"
 	"github.com/ChainSafe/gossamer/internal/trie/record"
 	"github.com/ChainSafe/gossamer/lib/common"
 )
@@ -30,22 +31,32 @@
 
 	// ErrLoadFromProof ...
 	ErrLoadFromProof = errors.New("failed to build the proof trie")
+
+	// ErrIncompleteProof is returned when the path of a key goes through a node or a hashed value
+	// which is not part of the proof
+	ErrIncompleteProof = errors.New("proof does not contain the full path of the key")
 )
 
 // GenerateProof receive the keys to proof, the trie root and a reference to database
 func GenerateProof(root []byte, keys [][]byte, db chaindb.Database) ([][]byte, error) {
-	trackedProofs := make(map[string][]byte)
-
 	proofTrie := NewEmptyTrie()
 	if err := proofTrie.Load(db, common.BytesToHash(root)); err != nil {
 		return nil, err
 	}
 
+	return proofTrie.GenerateProof(keys)
+}
+
+// GenerateProof returns the encoded nodes on the path of each of the given keys
+// for a trie already held in memory
+func (t *Trie) GenerateProof(keys [][]byte) ([][]byte, error) {
+	trackedProofs := make(map[string][]byte)
+
 	for _, k := range keys {
 		nk := codec.KeyLEToNibbles(k)
 
 		recorder := record.NewRecorder()
-		err := findAndRecord(proofTrie, nk, recorder)
+		err := findAndRecord(t, nk, recorder)
 		if err != nil {
 			return nil, err
 		}
@@ -66,6 +77,74 @@ func GenerateProof(root []byte, keys [][]byte, db chaindb.Database) ([][]byte, e
 	return proofs, nil
 }
 
+// GetFromProof returns the value stored at the given key in the partial trie built from the proof.
+// It returns a nil value if the proof shows that the key is not in the trie, and ErrIncompleteProof
+// if the path of the key goes through a node or a hashed value missing from the proof.
+func GetFromProof(proof [][]byte, root, key []byte) ([]byte, error) {
+	proofTrie := NewEmptyTrie()
+	if err := proofTrie.loadFromProof(proof, root); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrLoadFromProof, err)
+	}
+
+	return retrieveFromProof(proofTrie.root, codec.KeyLEToNibbles(key))
+}
+
+// retrieveFromProof returns the value stored at the given key nibbles in a trie loaded from a
+// proof. The children missing from the proof are left by the node decoding as leaves holding
+// only their hash, and the hashed values missing from the proof are left as nil values.
+func retrieveFromProof(parent Node, key []byte) (value []byte, err error) {
+	if leaf, ok := parent.(*node.Leaf); ok && leaf.Encoding == nil {
+		// nodes encoded in less than 32 bytes are inlined in their parent
+		// in place of their hash, so they are decoded from it.
+		if len(leaf.HashDigest) >= 32 {
+			return nil, fmt.Errorf("%w: node 0x%x is missing", ErrIncompleteProof, leaf.HashDigest)
+		}
+
+		parent, err = node.Decode(bytes.NewReader(leaf.HashDigest))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode inlined node 0x%x: %w", leaf.HashDigest, err)
+		}
+		parent.SetEncodingAndHash(leaf.HashDigest, leaf.HashDigest)
+	}
+
+	switch p := parent.(type) {
+	case *node.Branch:
+		if bytes.Equal(p.Key, key) {
+			return proofNodeValue(p)
+		}
+
+		length := lenCommonPrefix(p.Key, key)
+		if length < len(p.Key) {
+			return nil, nil
+		}
+
+		child := p.Children[key[length]]
+		if child == nil {
+			return nil, nil
+		}
+
+		return retrieveFromProof(child, key[length+1:])
+	case *node.Leaf:
+		if !bytes.Equal(p.Key, key) {
+			return nil, nil
+		}
+
+		return proofNodeValue(p)
+	default:
+		return nil, nil
+	}
+}
+
+// proofNodeValue returns the value of a node loaded from a proof
+func proofNodeValue(n Node) ([]byte, error) {
+	value := n.GetValue()
+	if n.IsHashedValue() && value == nil {
+		return nil, fmt.Errorf("%w: hashed value is missing", ErrIncompleteProof)
+	}
+
+	return value, nil
+}
+
 // Pair holds the key and value to check while verifying the proof
 type Pair struct{ Key, Value []byte }
 
@@ -4,6 +4,7 @@
 package trie
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ChainSafe/chaindb"
@@ -158,3 +159,92 @@ func TestVerifyProof_ShouldReturnTrueWithouCompareValues(t *testing.T) {
 	require.True(t, v)
 	require.NoError(t, err)
 }
+
+func TestGetFromProof(t *testing.T) {
+	t.Parallel()
+
+	entries := []Pair{
+		{Key: []byte("alpha"), Value: make([]byte, 32)},
+		{Key: []byte("bravo"), Value: []byte("bravo")},
+		{Key: []byte("do"), Value: []byte("verb")},
+		{Key: []byte("dog"), Value: []byte("puppy")},
+		{Key: []byte("doge"), Value: make([]byte, 32)},
+		{Key: []byte("horse"), Value: []byte("stallion")},
+		{Key: []byte("house"), Value: []byte("building")},
+	}
+
+	keys := [][]byte{
+		[]byte("dog"),
+	}
+
+	root, proof, _ := testGenerateProof(t, entries, keys)
+
+	value, err := GetFromProof(proof, root, []byte("dog"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("puppy"), value)
+
+	// the proof shows that "dot" is not in the trie
+	value, err = GetFromProof(proof, root, []byte("dot"))
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	// the path of "alpha" is not part of the proof
+	_, err = GetFromProof(proof, root, []byte("alpha"))
+	require.ErrorIs(t, err, ErrIncompleteProof)
+
+	_, err = GetFromProof(proof, make([]byte, 32), []byte("dog"))
+	require.ErrorIs(t, err, ErrLoadFromProof)
+}
+
+func TestGetFromProof_rootOnly(t *testing.T) {
+	t.Parallel()
+
+	trie := NewEmptyTrie()
+	for i := 0; i < 64; i++ {
+		trie.Put([]byte{byte(i)}, rand32Bytes())
+	}
+
+	root := trie.MustHash()
+	encRoot, _, err := trie.root.EncodeAndHash()
+	require.NoError(t, err)
+
+	// a proof holding only the root node does not prove that a key below it is absent
+	_, err = GetFromProof([][]byte{encRoot}, root.ToBytes(), []byte{7})
+	require.ErrorIs(t, err, ErrIncompleteProof)
+
+	// but it proves that there are no keys below its missing children
+	value, err := GetFromProof([][]byte{encRoot}, root.ToBytes(), []byte{0xff})
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
+func TestProofGeneration_HashedValues(t *testing.T) {
+	t.Parallel()
+
+	expectedValue := bytes.Repeat([]byte{1}, MaxInlineValue+1)
+
+	trie := NewEmptyTrie()
+	trie.Put([]byte("cat"), bytes.Repeat([]byte{2}, MaxInlineValue+1))
+	trie.Put([]byte("catapulta"), rand32Bytes())
+	trie.Put([]byte("catapora"), expectedValue)
+	trie.Put([]byte("dog"), rand32Bytes())
+
+	hash, err := trie.HashWithVersion(V1)
+	require.NoError(t, err)
+
+	proof, err := trie.GenerateProof([][]byte{[]byte("catapora")})
+	require.NoError(t, err)
+
+	v, err := VerifyProof(proof, hash.ToBytes(), []Pair{
+		{Key: []byte("catapora"), Value: expectedValue},
+	})
+	require.NoError(t, err)
+	require.True(t, v)
+
+	// the value of "cat" is not part of the proof
+	_, err = GetFromProof(proof, hash.ToBytes(), []byte("cat"))
+	require.ErrorIs(t, err, ErrIncompleteProof)
+
+	_, err = GetFromProof(append(proof, []byte{0xff, 0xff}), hash.ToBytes(), []byte("catapora"))
+	require.ErrorIs(t, err, ErrLoadFromProof)
+}
@@ -24,6 +24,9 @@ type Trie struct {
 	root        Node
 	childTries  map[common.Hash]*Trie // Used to store the child tries.
 	deletedKeys map[common.Hash]struct{}
+	// deletedValues is the number of times each hashed value was deleted
+	// from the trie since the last snapshot, it is nil until a value is deleted.
+	deletedValues map[common.Hash]uint32
 }
 
 // NewEmptyTrie creates a trie with a nil root
@@ -80,6 +83,7 @@ func (t *Trie) maybeUpdateGeneration(n Node) Node {
 		if len(oldNodeHash) > 0 {
 			hash := common.BytesToHash(oldNodeHash)
 			t.deletedKeys[hash] = struct{}{}
+			t.recordDeletedValue(n)
 		}
 		return newNode
 	}
@@ -87,6 +91,24 @@ func (t *Trie) maybeUpdateGeneration(n Node) Node {
 	return n
 }
 
+// recordDeletedValue counts the value of the given node as deleted
+// if it is stored separately from the node, as its hash.
+func (t *Trie) recordDeletedValue(n Node) {
+	if !n.IsHashedValue() {
+		return
+	}
+
+	hash, err := common.Blake2bHash(n.GetValue())
+	if err != nil {
+		return
+	}
+
+	if t.deletedValues == nil {
+		t.deletedValues = make(map[common.Hash]uint32)
+	}
+	t.deletedValues[hash]++
+}
+
 // DeepCopy deep copies the trie and returns
 // the copy.
 func (t *Trie) DeepCopy() (trieCopy *Trie) {
@@ -105,6 +127,13 @@ func (t *Trie) DeepCopy() (trieCopy *Trie) {
 		}
 	}
 
+	if t.deletedValues != nil {
+		trieCopy.deletedValues = make(map[common.Hash]uint32, len(t.deletedValues))
+		for k, count := range t.deletedValues {
+			trieCopy.deletedValues[k] = count
+		}
+	}
+
 	if t.childTries != nil {
 		trieCopy.childTries = make(map[common.Hash]*Trie, len(t.childTries))
 		for hash, trie := range t.childTries {
@@ -161,6 +190,34 @@ func (t *Trie) Hash() (common.Hash, error) {
 	return common.Blake2bHash(buffer.Bytes()) // TODO optimisation: use hashers sync pools
 }
 
+// HashWithVersion returns the hashed root of the trie, where the values
+// of the nodes modified since they were last written to the database are
+// encoded according to the state version given.
+func (t *Trie) HashWithVersion(version Version) (common.Hash, error) {
+	setValueHashing(t.root, version)
+	return t.Hash()
+}
+
+// setValueHashing sets whether the values of the dirty nodes of the
+// subtrie rooted at the given node are encoded as their hash,
+// according to the state version given.
+func setValueHashing(n Node, version Version) {
+	if n == nil || !n.IsDirty() {
+		return
+	}
+
+	n.SetHashedValue(version.ShouldHashValue(n.GetValue()))
+
+	branch, ok := n.(*node.Branch)
+	if !ok {
+		return
+	}
+
+	for _, child := range branch.Children {
+		setValueHashing(child, version)
+	}
+}
+
 // Entries returns all the key-value pairs in the trie as a map of keys to values
 // where the keys are encoded in Little Endian.
 func (t *Trie) Entries() map[string][]byte {
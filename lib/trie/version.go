@@ -0,0 +1,57 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package trie
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxInlineValue is the maximum size of a value inlined in its node
+// with the state version 1. Larger values are encoded as their hash.
+const MaxInlineValue = 32
+
+// ErrVersionInvalid is returned when the state version is not valid.
+var ErrVersionInvalid = errors.New("invalid state version")
+
+// Version is the state trie version which dictates
+// how values are encoded in the trie nodes.
+type Version uint8
+
+const (
+	// V0 is the state trie version 0 where values are always
+	// inlined in their node.
+	V0 Version = iota
+	// V1 is the state trie version 1 where values larger than
+	// 32 bytes are encoded as their hash in their node.
+	V1
+)
+
+// ParseVersion returns the state version corresponding to the
+// version number given, as passed by the runtime.
+func ParseVersion(v uint32) (version Version, err error) {
+	switch Version(v) {
+	case V0, V1:
+		return Version(v), nil
+	default:
+		return 0, fmt.Errorf("%w: %d", ErrVersionInvalid, v)
+	}
+}
+
+func (v Version) String() string {
+	switch v {
+	case V0:
+		return "v0"
+	case V1:
+		return "v1"
+	default:
+		panic(fmt.Sprintf("version %d not supported", uint8(v)))
+	}
+}
+
+// ShouldHashValue returns true if the value given should be
+// encoded as its hash in its node for this state version.
+func (v Version) ShouldHashValue(value []byte) bool {
+	return v == V1 && len(value) > MaxInlineValue
+}
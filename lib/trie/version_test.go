@@ -0,0 +1,58 @@
+// Copyright 2021 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseVersion(t *testing.T) {
+	t.Parallel()
+
+	version, err := ParseVersion(1)
+	require.NoError(t, err)
+	assert.Equal(t, V1, version)
+
+	_, err = ParseVersion(2)
+	assert.ErrorIs(t, err, ErrVersionInvalid)
+	assert.EqualError(t, err, "invalid state version: 2")
+}
+
+func Test_Trie_HashWithVersion(t *testing.T) {
+	t.Parallel()
+
+	shortValue := bytes.Repeat([]byte{1}, MaxInlineValue)
+	longValue := bytes.Repeat([]byte{1}, MaxInlineValue+1)
+
+	newTrie := func(value []byte) *Trie {
+		trie := NewEmptyTrie()
+		trie.Put([]byte{1, 2}, value)
+		trie.Put([]byte{1, 2, 3}, value)
+		return trie
+	}
+
+	// values up to 32 bytes are inlined with both versions
+	v0, err := newTrie(shortValue).HashWithVersion(V0)
+	require.NoError(t, err)
+	v1, err := newTrie(shortValue).HashWithVersion(V1)
+	require.NoError(t, err)
+	assert.Equal(t, v0, v1)
+
+	v0, err = newTrie(longValue).HashWithVersion(V0)
+	require.NoError(t, err)
+	assert.Equal(t, newTrie(longValue).MustHash(), v0)
+
+	trie := newTrie(longValue)
+	v1, err = trie.HashWithVersion(V1)
+	require.NoError(t, err)
+	assert.NotEqual(t, v0, v1)
+	assert.True(t, trie.RootNode().IsHashedValue())
+
+	// the trie is hashed with the version it was last hashed with
+	assert.Equal(t, v1, trie.MustHash())
+}